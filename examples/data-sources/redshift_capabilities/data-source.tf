@@ -0,0 +1,6 @@
+data "redshift_capabilities" "this" {}
+
+resource "redshift_role" "analyst" {
+  count = data.redshift_capabilities.this.roles ? 1 : 0
+  name  = "analyst"
+}
@@ -0,0 +1,10 @@
+data "redshift_current" "current" {
+}
+
+# Use the connected user as owner without hard-coding its name
+resource "redshift_default_privileges" "tables" {
+  group       = "analysts"
+  owner       = data.redshift_current.current.current_user
+  object_type = "table"
+  privileges  = ["select"]
+}
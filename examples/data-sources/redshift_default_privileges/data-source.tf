@@ -0,0 +1,12 @@
+data "redshift_default_privileges" "etl" {
+  owner = "etl_user"
+}
+
+# Prints the import IDs of the existing default privileges of etl_user
+output "default_privileges_import_ids" {
+  value = [for entry in data.redshift_default_privileges.etl.entries : entry.id]
+}
+
+# Lists the default privileges of all owners, e.g. to find grantees several owners define defaults for
+data "redshift_default_privileges" "all" {
+}
@@ -0,0 +1,14 @@
+data "redshift_privileges" "analyst" {
+  grantee      = "analyst"
+  grantee_type = "role"
+}
+
+# Fails the plan if the read-only role can write to any table
+check "analyst_is_read_only" {
+  assert {
+    condition = alltrue([
+      for table in data.redshift_privileges.analyst.tables : length(setsubtract(table.privileges, ["select"])) == 0
+    ])
+    error_message = "The analyst role must only hold SELECT on tables."
+  }
+}
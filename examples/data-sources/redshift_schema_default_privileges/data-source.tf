@@ -0,0 +1,11 @@
+data "redshift_schema_default_privileges" "analysts_tables" {
+  group       = "analysts"
+  owner       = "etl_user"
+  schema      = "sales"
+  object_type = "table"
+}
+
+# Whether the default privileges of etl_user in sales allow analysts to read new tables
+output "analysts_can_read_new_tables" {
+  value = contains(data.redshift_schema_default_privileges.analysts_tables.privileges, "select")
+}
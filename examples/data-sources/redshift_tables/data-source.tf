@@ -0,0 +1,14 @@
+data "redshift_tables" "reporting" {
+  schema     = "reporting"
+  table_type = "TABLE"
+}
+
+resource "redshift_grant" "analysts" {
+  for_each = toset([for t in data.redshift_tables.reporting.tables : t.name])
+
+  group       = "analysts"
+  schema      = "reporting"
+  object_type = "table"
+  objects     = [each.value]
+  privileges  = ["select"]
+}
@@ -0,0 +1,13 @@
+# The connection is only established when a resource or data source needs it,
+# so the provider can be configured with values that are known after apply.
+resource "aws_redshiftserverless_workgroup" "example" {
+  namespace_name = "example"
+  workgroup_name = "example"
+}
+
+provider "redshift" {
+  host     = aws_redshiftserverless_workgroup.example.endpoint[0].address
+  port     = aws_redshiftserverless_workgroup.example.endpoint[0].port
+  username = var.redshift_user
+  password = var.redshift_password
+}
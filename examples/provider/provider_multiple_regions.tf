@@ -0,0 +1,29 @@
+# A provider configuration targets a single region. Use one aliased provider
+# per region and select it on each resource.
+provider "redshift" {
+  alias    = "eu"
+  database = var.redshift_database
+  data_api {
+    workgroup_name = var.redshift_workgroup_eu
+    region         = "eu-central-1"
+  }
+}
+
+provider "redshift" {
+  alias    = "us"
+  database = var.redshift_database
+  data_api {
+    workgroup_name = var.redshift_workgroup_us
+    region         = "us-east-1"
+  }
+}
+
+resource "redshift_user" "analyst_eu" {
+  provider = redshift.eu
+  name     = "analyst"
+}
+
+resource "redshift_user" "analyst_us" {
+  provider = redshift.us
+  name     = "analyst"
+}
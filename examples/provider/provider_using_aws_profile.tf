@@ -0,0 +1,10 @@
+# The AWS SDK default credential chain is used unless a profile or static keys are set.
+# The same attributes are supported in the data_api block.
+provider "redshift" {
+  host     = var.redshift_host
+  username = var.redshift_user
+  temporary_credentials {
+    cluster_identifier = "my-cluster"
+    profile            = "analytics-account"
+  }
+}
@@ -0,0 +1,4 @@
+# The URL is used as is, e.g. as stored in a secret manager
+provider "redshift" {
+  connection_url = var.redshift_connection_url
+}
@@ -8,3 +8,20 @@ provider "redshift" {
     }
   }
 }
+
+# Roles can be chained, each one is assumed with the credentials of the previous one
+provider "redshift" {
+  alias    = "chained"
+  host     = var.redshift_host
+  username = var.redshift_user
+  temporary_credentials {
+    cluster_identifier = "my-cluster"
+    assume_role {
+      arn = "arn:aws:iam::012345678901:role/jump-role"
+    }
+    assume_role {
+      arn              = "arn:aws:iam::109876543210:role/redshift-role"
+      duration_seconds = 3600
+    }
+  }
+}
@@ -0,0 +1,3 @@
+# Import an assumerole grant with role;<iam_role>;<privileges>;<user|group|role>;<grantee>.
+
+terraform import redshift_assumerole_grant.grant "role;arn:aws:iam::123456789012:role/myrole;copy,unload;group;analysts"
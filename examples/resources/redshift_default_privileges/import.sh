@@ -0,0 +1,4 @@
+# Import default privileges with <gn|un|rn>:<grantee>_<sn:<schema>|ss:<schema>,...|noschema>_on:<owner>_ot:<object type>.
+# The redshift_default_privileges data source lists the IDs of the existing default privileges of an owner.
+
+terraform import redshift_default_privileges.analysts gn:analysts_sn:reporting_on:etl_user_ot:table
@@ -11,3 +11,26 @@ resource "redshift_default_privileges" "user" {
   object_type = "table"
   privileges  = ["select", "update", "insert", "delete", "drop", "references"]
 }
+
+# Default privileges of the user the provider is connected as, which needn't be a superuser.
+resource "redshift_default_privileges" "own" {
+  group       = "analysts"
+  object_type = "table"
+  privileges  = ["select"]
+}
+
+resource "redshift_default_privileges" "role_functions" {
+  role        = "etl_runner"
+  owner       = "root"
+  object_type = "function"
+  privileges  = ["execute"]
+}
+
+# The same default privileges in the schemas of several teams.
+resource "redshift_default_privileges" "team_schemas" {
+  group       = "analysts"
+  schemas     = ["team_a", "team_b", "team_c"]
+  owner       = "root"
+  object_type = "table"
+  privileges  = ["select"]
+}
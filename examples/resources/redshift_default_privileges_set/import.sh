@@ -0,0 +1,4 @@
+# Import default privileges with <gn|un|rn>:<grantee>_<sn:<schema>|noschema>_on:<owner>_ot:<object types>,
+# where the object types are sorted and separated by commas.
+
+terraform import redshift_default_privileges_set.analysts rn:analysts_sn:reporting_on:etl_user_ot:function,table
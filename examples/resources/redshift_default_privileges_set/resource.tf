@@ -0,0 +1,15 @@
+resource "redshift_default_privileges_set" "analysts" {
+  role   = "analysts"
+  owner  = "etl_user"
+  schema = "reporting"
+
+  object_types {
+    object_type = "table"
+    privileges  = ["select"]
+  }
+
+  object_types {
+    object_type = "function"
+    privileges  = ["execute"]
+  }
+}
@@ -21,6 +21,33 @@ resource "redshift_grant" "user" {
   privileges  = ["execute"]
 }
 
+# Granting execute on all procedures of a schema (GRANT EXECUTE ON ALL PROCEDURES IN SCHEMA)
+resource "redshift_grant" "procedures" {
+  group       = "etl"
+  schema      = "my_schema"
+  object_type = "procedure"
+  objects     = []
+  privileges  = ["execute"]
+}
+
+# Granting the same privileges to several grantees at once
+resource "redshift_grant" "readers" {
+  schema      = "my_schema"
+  object_type = "table"
+  objects     = []
+  privileges  = ["select"]
+
+  grantees {
+    type = "group"
+    name = "analysts"
+  }
+
+  grantees {
+    type = "role"
+    name = "reporting"
+  }
+}
+
 # Granting permission to PUBLIC (GRANT ... TO PUBLIC)
 resource "redshift_grant" "public" {
   group       = "public" // "public" or "PUBLIC" (it is case insensitive for this case) here indicates we want grant TO PUBLIC, not "public" group which cannot even be created in Redshift (keyword).
@@ -28,3 +55,51 @@ resource "redshift_grant" "public" {
   object_type = "schema"
   privileges  = ["usage"]
 }
+
+# Granting usage on every schema, e.g. for a read-only analyst role
+resource "redshift_grant" "all_schemas" {
+  role        = "analyst"
+  object_type = "schema"
+  all_schemas = true
+  privileges  = ["usage"]
+}
+
+# Granting usage on languages, e.g. for Python UDFs (languages are never schema-qualified)
+resource "redshift_grant" "languages" {
+  group       = "analysts"
+  object_type = "language"
+  objects     = ["plpythonu"]
+  privileges  = ["usage"]
+}
+
+# Granting usage on a language to everyone
+resource "redshift_grant" "languages_public" {
+  group       = "public"
+  object_type = "language"
+  objects     = ["plpythonu"]
+  privileges  = ["usage"]
+}
+
+# Granting permissions on Lambda UDFs requires usage on the exfunc language and execute on the external functions
+resource "redshift_grant" "exfunc" {
+  role        = "analyst"
+  object_type = "language"
+  objects     = ["exfunc"]
+  privileges  = ["usage"]
+}
+
+resource "redshift_grant" "external_function" {
+  role        = "analyst"
+  schema      = "my_schema"
+  object_type = "external_function"
+  objects     = ["my_lambda_udf(varchar)"]
+  privileges  = ["execute"]
+}
+
+# Granting usage on a database created from a datashare
+resource "redshift_grant" "datashare_database" {
+  role        = "analyst"
+  database    = redshift_database.from_datashare.name
+  object_type = "database"
+  privileges  = ["usage"]
+}
@@ -0,0 +1,3 @@
+# Import the membership of users in a group with <group>/<user1>,<user2>.
+
+terraform import redshift_group_membership.simple "some_group_name/user1,user2"
@@ -0,0 +1,4 @@
+# Import a masking policy attachment with <policy>;<schema>;<table>;<columns>;<user|role|public>[;<grantee>],
+# multiple columns are separated by commas.
+
+terraform import redshift_masking_attachment.analysts "mask_credit_card;sales;customers;credit_card;role;analysts"
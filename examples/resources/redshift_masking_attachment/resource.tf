@@ -0,0 +1,9 @@
+resource "redshift_masking_attachment" "analysts" {
+  policy_name  = redshift_masking_policy.mask_credit_card.name
+  schema       = "sales"
+  table        = "customers"
+  columns      = ["credit_card"]
+  grantee_type = "ROLE"
+  grantee_name = "analysts"
+  priority     = 10
+}
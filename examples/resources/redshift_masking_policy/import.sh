@@ -0,0 +1,3 @@
+# Import a masking policy with its name.
+
+terraform import redshift_masking_policy.mask_credit_card mask_credit_card
@@ -0,0 +1,10 @@
+resource "redshift_masking_policy" "mask_credit_card" {
+  name = "mask_credit_card"
+
+  with {
+    name = "credit_card"
+    type = "VARCHAR(256)"
+  }
+
+  using = "SUBSTRING(credit_card, 1, 4) || '-XXXX-XXXX-XXXX'"
+}
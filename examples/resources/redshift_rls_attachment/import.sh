@@ -0,0 +1,3 @@
+# Import an RLS policy attachment with <policy>;<schema>;<table>;<user|role|public>[;<grantee>].
+
+terraform import redshift_rls_attachment.analysts "own_department;hr;employees;role;analysts"
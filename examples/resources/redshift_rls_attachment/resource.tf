@@ -0,0 +1,7 @@
+resource "redshift_rls_attachment" "analysts" {
+  policy_name  = redshift_rls_policy.own_department.name
+  schema       = "hr"
+  table        = "employees"
+  grantee_type = "ROLE"
+  grantee_name = "analysts"
+}
@@ -0,0 +1,3 @@
+# Import an RLS policy with its name.
+
+terraform import redshift_rls_policy.own_department own_department
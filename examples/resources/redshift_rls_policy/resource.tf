@@ -0,0 +1,10 @@
+resource "redshift_rls_policy" "own_department" {
+  name = "own_department"
+
+  with {
+    name = "department"
+    type = "VARCHAR(256)"
+  }
+
+  using = "department = current_user"
+}
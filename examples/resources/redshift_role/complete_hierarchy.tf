@@ -15,9 +15,10 @@ resource "redshift_role" "readwrite" {
   name = "readwrite_role"
 }
 
-# Advanced role: Full access
+# Advanced role: Full access, including managing users
 resource "redshift_role" "admin" {
-  name = "admin_role"
+  name               = "admin_role"
+  system_permissions = ["CREATE USER", "ALTER USER", "DROP USER"]
 }
 
 # =============================================================================
@@ -0,0 +1,5 @@
+# Import a role grant with role:<role_name>:<user|role>:<grant_to_name>.
+# Colons in names, e.g. of roles from an identity provider, are encoded as %3A and percent signs as %25.
+
+terraform import redshift_role_grant.grant "role:analyst:user:alice"
+terraform import redshift_role_grant.idp "role:awsidp%3Arole/analyst:user:alice"
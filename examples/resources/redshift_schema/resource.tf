@@ -4,9 +4,10 @@ resource "redshift_user" "owner" {
 
 # Internal schema
 resource "redshift_schema" "schema" {
-  name  = "my_schema"
-  owner = redshift_user.owner.name
-  quota = 150
+  name    = "my_schema"
+  owner   = redshift_user.owner.name
+  quota   = 150
+  comment = "Sales data, maintained by the finance team"
 }
 
 # External schema using AWS Glue Data Catalog
@@ -0,0 +1,12 @@
+resource "redshift_sql" "customer_churn_model" {
+  create_sql  = <<-SQL
+    CREATE MODEL analytics.customer_churn
+    FROM (SELECT age, plan, monthly_charges, churned FROM analytics.customer_activity)
+    TARGET churned
+    FUNCTION predict_customer_churn
+    IAM_ROLE default
+    SETTINGS (S3_BUCKET 'my-redshift-ml-bucket')
+  SQL
+  destroy_sql = "DROP MODEL analytics.customer_churn"
+  read_sql    = "SELECT model_name FROM stv_ml_model_info WHERE schema_name = 'analytics' AND model_name = 'customer_churn'"
+}
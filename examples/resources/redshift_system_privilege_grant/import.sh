@@ -0,0 +1,3 @@
+# Import a system privilege grant with <grantee_type>:<privilege>:<grantee_name>.
+
+terraform import redshift_system_privilege_grant.grant "role:CREATE USER:user_admins"
@@ -0,0 +1,14 @@
+resource "redshift_role" "admins" {
+  for_each = toset(["user_admins", "platform_admins"])
+
+  name = each.key
+
+  # system_permissions must not be set, it would revoke the privileges granted below
+}
+
+resource "redshift_system_privilege_grant" "create_user" {
+  for_each = redshift_role.admins
+
+  privilege    = "CREATE USER"
+  grantee_name = each.value.name
+}
@@ -8,3 +8,13 @@ resource "redshift_user" "user_with_unrestricted_syslog" {
   name          = "user_syslog"
   syslog_access = "UNRESTRICTED"
 }
+
+resource "redshift_user" "etl" {
+  name = "etl"
+
+  config_parameters = {
+    query_group       = "etl"
+    search_path       = "$user, staging, public"
+    statement_timeout = "3600000"
+  }
+}
@@ -0,0 +1,3 @@
+# Import the roles granted to a user with the user name. All roles granted to the user are managed afterwards.
+
+terraform import redshift_user_role.john "john"
@@ -0,0 +1,4 @@
+resource "redshift_user_role" "john" {
+  user  = "john"
+  roles = ["analyst", "etl"]
+}
@@ -3,14 +3,109 @@
 package redshift
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
+// TestMain runs the sweepers instead of the tests with -sweep, e.g. go test ./redshift -v -sweep=all.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// sweepPrefixes are the name prefixes of the objects created by acceptance tests.
+var sweepPrefixes = []string{"tf_acc", "acc_test"}
+
+func init() {
+	// Schemas go first, they may belong to the users and their objects hold privileges of users, groups and roles.
+	resource.AddTestSweepers("redshift_schema", &resource.Sweeper{
+		Name: "redshift_schema",
+		F:    sweepObjects("schema", "SELECT nspname FROM pg_namespace", "DROP SCHEMA %s CASCADE"),
+	})
+	resource.AddTestSweepers("redshift_role", &resource.Sweeper{
+		Name:         "redshift_role",
+		Dependencies: []string{"redshift_schema"},
+		F:            sweepObjects("role", "SELECT role_name FROM svv_roles", "DROP ROLE %s FORCE"),
+	})
+	resource.AddTestSweepers("redshift_group", &resource.Sweeper{
+		Name:         "redshift_group",
+		Dependencies: []string{"redshift_schema"},
+		F:            sweepObjects("group", "SELECT groname FROM pg_group", "DROP GROUP %s"),
+	})
+	resource.AddTestSweepers("redshift_user", &resource.Sweeper{
+		Name:         "redshift_user",
+		Dependencies: []string{"redshift_schema", "redshift_role", "redshift_group"},
+		F:            sweepObjects("user", "SELECT usename FROM pg_user", "DROP USER %s"),
+	})
+}
+
+// sweepConnection connects like the acceptance tests do, with the provider configured from the environment.
+func sweepConnection() (*DBConnection, error) {
+	provider := Provider()
+	if diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		return nil, fmt.Errorf("could not configure the provider: %v", diags)
+	}
+	return provider.Meta().(*Client).Connect()
+}
+
+// sweepObjects returns a sweeper dropping the objects listed by query whose names start with one of sweepPrefixes.
+// The objects which can't be dropped are reported after trying all of them.
+func sweepObjects(kind, query, dropStatement string) func(string) error {
+	return func(string) error {
+		db, err := sweepConnection()
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.Query(query)
+		if err != nil {
+			return fmt.Errorf("could not list %ss: %w", kind, err)
+		}
+		var names []string
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return fmt.Errorf("could not list %ss: %w", kind, err)
+			}
+			if hasSweepPrefix(name) {
+				names = append(names, name)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("could not list %ss: %w", kind, err)
+		}
+
+		var errs []error
+		for _, name := range names {
+			log.Printf("[INFO] sweeping %s %s", kind, name)
+			if _, err := db.Exec(fmt.Sprintf(dropStatement, pq.QuoteIdentifier(name))); err != nil {
+				errs = append(errs, fmt.Errorf("could not drop %s %s: %w", kind, name, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func hasSweepPrefix(name string) bool {
+	for _, prefix := range sweepPrefixes {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // Get the value of an environment variable, or skip the
 // current test if the variable is not set.
 func getEnvOrSkip(key string, t *testing.T) string {
@@ -0,0 +1,108 @@
+package redshift
+
+import (
+	"context"
+	"maps"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	awsProfileAttr         = "profile"
+	awsAccessKeyIDAttr     = "access_key_id"
+	awsSecretAccessKeyAttr = "secret_access_key"
+	awsSessionTokenAttr    = "session_token"
+)
+
+// awsCredentials selects the credentials used for AWS API calls, the zero value uses the default credential chain.
+type awsCredentials struct {
+	Profile         string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// withAwsCredentialsSchema adds the attributes selecting AWS credentials to the schema of the nested block at path, e.g. data_api.0.
+func withAwsCredentialsSchema(s map[string]*schema.Schema, path string) map[string]*schema.Schema {
+	maps.Copy(s, map[string]*schema.Schema{
+		awsProfileAttr: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The name of the AWS shared config profile to use. Defaults to the default credential chain of the AWS SDK.",
+		},
+		awsAccessKeyIDAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The AWS access key ID to use instead of the default credential chain.",
+			RequiredWith: []string{path + awsSecretAccessKeyAttr},
+		},
+		awsSecretAccessKeyAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			Description:  "The AWS secret access key belonging to `access_key_id`.",
+			RequiredWith: []string{path + awsAccessKeyIDAttr},
+		},
+		awsSessionTokenAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			Description:  "The AWS session token to use with temporary `access_key_id` and `secret_access_key`.",
+			RequiredWith: []string{path + awsAccessKeyIDAttr},
+		},
+	})
+	return s
+}
+
+func getAwsCredentialsFromResourceData(d *schema.ResourceData, path string) awsCredentials {
+	return awsCredentials{
+		Profile:         d.Get(path + awsProfileAttr).(string),
+		AccessKeyID:     d.Get(path + awsAccessKeyIDAttr).(string),
+		SecretAccessKey: d.Get(path + awsSecretAccessKeyAttr).(string),
+		SessionToken:    d.Get(path + awsSessionTokenAttr).(string),
+	}
+}
+
+// getAwsCredentialsFromParams returns the credentials added to a connection string by awsCredentials.params.
+func getAwsCredentialsFromParams(params url.Values) awsCredentials {
+	return awsCredentials{
+		Profile:         params.Get(awsProfileAttr),
+		AccessKeyID:     params.Get(awsAccessKeyIDAttr),
+		SecretAccessKey: params.Get(awsSecretAccessKeyAttr),
+		SessionToken:    params.Get(awsSessionTokenAttr),
+	}
+}
+
+// params returns the credentials as connection string parameters, unset credentials are left out.
+func (c awsCredentials) params() url.Values {
+	params := url.Values{}
+	for name, value := range map[string]string{
+		awsProfileAttr:         c.Profile,
+		awsAccessKeyIDAttr:     c.AccessKeyID,
+		awsSecretAccessKeyAttr: c.SecretAccessKey,
+		awsSessionTokenAttr:    c.SessionToken,
+	} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	return params
+}
+
+// loadAwsConfig loads the AWS config, using the profile or static keys of creds if they are set.
+func loadAwsConfig(ctx context.Context, creds awsCredentials) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if creds.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(creds.Profile))
+	}
+	if creds.AccessKeyID != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+		))
+	}
+	return config.LoadDefaultConfig(ctx, opts...)
+}
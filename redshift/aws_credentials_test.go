@@ -0,0 +1,108 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	redshiftdatasqldriver "github.com/mmichaelb/redshift-data-sql-driver"
+)
+
+func TestAwsCredentials_ParamsRoundTrip(t *testing.T) {
+	creds := awsCredentials{
+		Profile:         "some-profile",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "some/secret+key",
+		SessionToken:    "some-token",
+	}
+
+	dsn := withAwsCredentialsParams(buildConnStrFromDataApiConfig("some-workgroup", "db", "eu-central-1"), creds)
+	cfg, err := redshiftdatasqldriver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("unexpected error parsing %q: %v", dsn, err)
+	}
+	if got := getAwsCredentialsFromParams(cfg.Params); got != creds {
+		t.Errorf("getAwsCredentialsFromParams() = %+v, want %+v", got, creds)
+	}
+}
+
+func TestAwsCredentials_UnsetKeepsConnStr(t *testing.T) {
+	connStr := buildConnStrFromDataApiConfig("some-workgroup", "db", "eu-central-1")
+	if got := withAwsCredentialsParams(connStr, awsCredentials{}); got != connStr {
+		t.Errorf("withAwsCredentialsParams() = %q, want %q", got, connStr)
+	}
+}
+
+func TestLoadAwsConfig_StaticCredentials(t *testing.T) {
+	cfg, err := loadAwsConfig(context.Background(), awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "some-secret",
+		SessionToken:    "some-token",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error retrieving credentials: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDEXAMPLE" || creds.SecretAccessKey != "some-secret" || creds.SessionToken != "some-token" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+}
+
+func TestLoadAwsConfig_UnknownProfile(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+
+	if _, err := loadAwsConfig(context.Background(), awsCredentials{Profile: "some-profile"}); err == nil {
+		t.Fatal("expected error loading an unknown profile, got nil")
+	}
+}
+
+func TestTemporaryCredentials_EndpointURL(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+
+	var action string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		action = r.Form.Get("Action")
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, `<GetClusterCredentialsResponse xmlns="http://redshift.amazonaws.com/doc/2012-12-01/">
+  <GetClusterCredentialsResult>
+    <DbUser>IAM:some-user</DbUser>
+    <DbPassword>some-password</DbPassword>
+  </GetClusterCredentialsResult>
+  <ResponseMetadata><RequestId>some-request</RequestId></ResponseMetadata>
+</GetClusterCredentialsResponse>`)
+	}))
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"host": "some-host",
+		"temporary_credentials": []interface{}{
+			map[string]interface{}{
+				"cluster_identifier": "some-cluster",
+				"region":             "eu-central-1",
+				"access_key_id":      "AKIDEXAMPLE",
+				"secret_access_key":  "some-secret",
+				"endpoint_url":       server.URL,
+			},
+		},
+	})
+
+	username, password, err := temporaryCredentials("some-user", d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if action != "GetClusterCredentials" {
+		t.Errorf("expected GetClusterCredentials to be sent to the custom endpoint, got action %q", action)
+	}
+	if username != "IAM:some-user" || password != "some-password" {
+		t.Errorf("temporaryCredentials() = %q, %q, want %q, %q", username, password, "IAM:some-user", "some-password")
+	}
+}
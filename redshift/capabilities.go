@@ -0,0 +1,103 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+)
+
+// redshiftCapabilities describes the features of the connected cluster modules may want to branch on.
+type redshiftCapabilities struct {
+	version    string
+	serverless bool
+	svvCatalog bool
+	roles      bool
+}
+
+// capabilityProbes are the queries used to find out the capabilities, split out so they can be stubbed.
+type capabilityProbes struct {
+	version    func() (string, error)
+	serverless func() (bool, error)
+	svvCatalog func() (bool, error)
+	roles      func() error
+}
+
+var redshiftVersionRegexp = regexp.MustCompile(`Redshift (\d+(?:\.\d+)*)`)
+
+// parseRedshiftVersion extracts the Redshift version from the output of version(), e.g.
+// "PostgreSQL 8.0.2 on i686-pc-linux-gnu, compiled by GCC gcc (GCC) 3.4.2 20041017 (Red Hat 3.4.2-6.fc3), Redshift 1.0.77467".
+func parseRedshiftVersion(version string) string {
+	match := redshiftVersionRegexp.FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// Capabilities probes the connected cluster once per provider instance and returns its capabilities.
+// Probes which fail without telling whether a feature is available aren't cached, so they are retried on the next call.
+func (c *Config) Capabilities(db *DBConnection) (redshiftCapabilities, error) {
+	return c.resolveCapabilities(capabilityProbes{
+		version: func() (string, error) {
+			var version string
+			if err := db.QueryRow("SELECT version()").Scan(&version); err != nil {
+				return "", err
+			}
+			return version, nil
+		},
+		serverless: func() (bool, error) {
+			return c.IsServerless(db)
+		},
+		svvCatalog: func() (bool, error) {
+			mode, err := c.resolveCatalogMode(svvCatalogProbe(db))
+			return mode == catalogModeSvv, err
+		},
+		roles: func() error {
+			rows, err := db.Query("SELECT 1 FROM svv_roles LIMIT 1")
+			if err != nil {
+				return err
+			}
+			return rows.Close()
+		},
+	})
+}
+
+func (c *Config) resolveCapabilities(probes capabilityProbes) (redshiftCapabilities, error) {
+	c.capabilitiesMutex.Lock()
+	defer c.capabilitiesMutex.Unlock()
+	if c.resolvedCapabilities != nil {
+		return *c.resolvedCapabilities, nil
+	}
+
+	version, err := probes.version()
+	if err != nil {
+		return redshiftCapabilities{}, fmt.Errorf("could not read Redshift version: %w", err)
+	}
+
+	serverless, err := probes.serverless()
+	if err != nil {
+		return redshiftCapabilities{}, fmt.Errorf("could not check whether Redshift is serverless: %w", err)
+	}
+
+	svvCatalog, err := probes.svvCatalog()
+	if err != nil {
+		return redshiftCapabilities{}, fmt.Errorf("could not check whether the svv catalog views are available: %w", err)
+	}
+
+	capabilities := redshiftCapabilities{
+		version:    parseRedshiftVersion(version),
+		serverless: serverless,
+		svvCatalog: svvCatalog,
+		roles:      true,
+	}
+	if err := probes.roles(); err != nil {
+		if !isRelationUnavailableError(err) {
+			return redshiftCapabilities{}, fmt.Errorf("could not check whether roles are available: %w", err)
+		}
+		log.Printf("[DEBUG] roles are not available: %v", err)
+		capabilities.roles = false
+	}
+
+	c.resolvedCapabilities = &capabilities
+	return capabilities, nil
+}
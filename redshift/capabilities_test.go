@@ -0,0 +1,104 @@
+package redshift
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRedshiftVersion(t *testing.T) {
+	tests := map[string]string{
+		"PostgreSQL 8.0.2 on i686-pc-linux-gnu, compiled by GCC gcc (GCC) 3.4.2 20041017 (Red Hat 3.4.2-6.fc3), Redshift 1.0.77467": "1.0.77467",
+		"PostgreSQL 8.0.2 on i686-pc-linux-gnu": "",
+	}
+
+	for version, expected := range tests {
+		if result := parseRedshiftVersion(version); result != expected {
+			t.Errorf("Expected version of %q to be %q but got %q", version, expected, result)
+		}
+	}
+}
+
+func TestResolveCapabilities(t *testing.T) {
+	probes := 0
+	stubbed := capabilityProbes{
+		version: func() (string, error) {
+			probes++
+			return "PostgreSQL 8.0.2 on i686-pc-linux-gnu, Redshift 1.0.77467", nil
+		},
+		serverless: func() (bool, error) { return true, nil },
+		svvCatalog: func() (bool, error) { return true, nil },
+		roles:      func() error { return errors.New(`relation "svv_roles" does not exist`) },
+	}
+
+	cfg := NewConfig(proxyDriverName, "", "db", 1)
+	expected := redshiftCapabilities{
+		version:    "1.0.77467",
+		serverless: true,
+		svvCatalog: true,
+		roles:      false,
+	}
+
+	// resolve twice to make sure the probe results are cached
+	for i := 0; i < 2; i++ {
+		capabilities, err := cfg.resolveCapabilities(stubbed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if capabilities != expected {
+			t.Errorf("Expected capabilities %+v but got %+v", expected, capabilities)
+		}
+	}
+	if probes != 1 {
+		t.Errorf("Expected 1 probe but got %d", probes)
+	}
+}
+
+func TestResolveCapabilities_ProbeError(t *testing.T) {
+	stubbed := capabilityProbes{
+		version:    func() (string, error) { return "", errors.New("connection refused") },
+		serverless: func() (bool, error) { return false, nil },
+		svvCatalog: func() (bool, error) { return false, nil },
+		roles:      func() error { return nil },
+	}
+
+	cfg := NewConfig(proxyDriverName, "", "db", 1)
+	if _, err := cfg.resolveCapabilities(stubbed); err == nil {
+		t.Fatal("Expected an error when probing fails")
+	}
+	if cfg.resolvedCapabilities != nil {
+		t.Error("Expected failed probes not to be cached")
+	}
+}
+
+func TestResolveCapabilities_TransientProbeErrors(t *testing.T) {
+	lostConnection := errors.New("read tcp 10.0.0.1:5439: read: connection reset by peer")
+	version := func() (string, error) { return "PostgreSQL 8.0.2 on i686-pc-linux-gnu, Redshift 1.0.77467", nil }
+	serverless := func() (bool, error) { return false, nil }
+
+	tests := map[string]capabilityProbes{
+		"svv catalog": {
+			version:    version,
+			serverless: serverless,
+			svvCatalog: func() (bool, error) { return false, lostConnection },
+			roles:      func() error { return nil },
+		},
+		"roles": {
+			version:    version,
+			serverless: serverless,
+			svvCatalog: func() (bool, error) { return true, nil },
+			roles:      func() error { return lostConnection },
+		},
+	}
+
+	for name, stubbed := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := NewConfig(proxyDriverName, "", "db", 1)
+			if _, err := cfg.resolveCapabilities(stubbed); err == nil {
+				t.Fatal("Expected an error when probing fails")
+			}
+			if cfg.resolvedCapabilities != nil {
+				t.Error("Expected failed probes not to be cached")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package redshift
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+const (
+	catalogModePg   = "pg"
+	catalogModeSvv  = "svv"
+	catalogModeAuto = "auto"
+
+	defaultCatalogMode = catalogModeAuto
+
+	// svvCatalogProbeQuery is used in auto mode to find out whether the svv_* views can be read.
+	svvCatalogProbeQuery = "SELECT 1 FROM svv_redshift_schemas LIMIT 1"
+)
+
+var catalogModes = []string{
+	catalogModePg,
+	catalogModeSvv,
+	catalogModeAuto,
+}
+
+// catalogQuery holds the same catalog read expressed once against the pg_* catalog tables
+// and once against the svv_* system views. Both variants must take the same arguments
+// and return the same columns.
+type catalogQuery struct {
+	pg  string
+	svv string
+}
+
+func (q catalogQuery) forMode(mode string) string {
+	if mode == catalogModePg {
+		return q.pg
+	}
+	return q.svv
+}
+
+// ResolveCatalogMode returns the catalog mode (pg or svv) reads should use.
+// In auto mode the availability of the svv_* views is probed once per provider instance.
+func (c *Config) ResolveCatalogMode(db *DBConnection) string {
+	mode, err := c.resolveCatalogMode(svvCatalogProbe(db))
+	if err != nil {
+		log.Printf("[WARN] could not check whether the svv catalog views are available, using the pg catalog for now: %v", err)
+	}
+	return mode
+}
+
+func svvCatalogProbe(db *DBConnection) func() error {
+	return func() error {
+		rows, err := db.Query(svvCatalogProbeQuery)
+		if err != nil {
+			return err
+		}
+		return rows.Close()
+	}
+}
+
+// resolveCatalogMode returns the catalog mode, probing the svv views in auto mode. Only the outcome of
+// a probe which tells whether the views are available is cached, on other errors, e.g. a lost connection,
+// the pg catalog is used along with the error and the views are probed again the next time.
+func (c *Config) resolveCatalogMode(probe func() error) (string, error) {
+	switch c.CatalogMode {
+	case catalogModePg, catalogModeSvv:
+		return c.CatalogMode, nil
+	}
+
+	c.catalogModeMutex.Lock()
+	defer c.catalogModeMutex.Unlock()
+	if c.resolvedCatalogMode != "" {
+		return c.resolvedCatalogMode, nil
+	}
+
+	err := probe()
+	switch {
+	case err == nil:
+		c.resolvedCatalogMode = catalogModeSvv
+	case isRelationUnavailableError(err):
+		log.Printf("[DEBUG] svv catalog views are not available, falling back to pg catalog: %v", err)
+		c.resolvedCatalogMode = catalogModePg
+	default:
+		return catalogModePg, err
+	}
+
+	return c.resolvedCatalogMode, nil
+}
+
+// isRelationUnavailableError returns whether err tells that a relation doesn't exist or can't be read
+// by the user. Errors of the Data API driver, which doesn't return *pq.Error, are classified by their message.
+func isRelationUnavailableError(err error) bool {
+	if isPqErrorWithCode(err, pqErrorCodeUndefinedTable) || isPqErrorWithCode(err, pgErrorCodeInsufficientPrivileges) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "does not exist") || strings.Contains(msg, "permission denied")
+}
+
+// catalogQuery returns the variant of query matching the catalog mode of the connection.
+func (db *DBConnection) catalogQuery(query catalogQuery) string {
+	return query.forMode(db.client.config.ResolveCatalogMode(db))
+}
+
+// queryer is implemented by both *transaction and *DBConnection.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+var listSchemasQuery = catalogQuery{
+	pg:  "SELECT nspname FROM pg_namespace WHERE nspowner != 1 OR nspname = 'public'",
+	svv: "SELECT schema_name FROM svv_redshift_schemas WHERE database_name = current_database() AND (schema_owner != 1 OR schema_name = 'public')",
+}
+
+// listSchemas returns the names of all local schemas which are not owned by the system, including public.
+func listSchemas(q queryer, query string) ([]string, error) {
+	rows, err := q.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("could not list schemas: %w", err)
+	}
+
+	var schemaNames []string
+	for rows.Next() {
+		var schemaName string
+		if err := rows.Scan(&schemaName); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		schemaNames = append(schemaNames, schemaName)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return schemaNames, nil
+}
@@ -0,0 +1,140 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+func TestResolveCatalogMode(t *testing.T) {
+	svvAvailable := func() error { return nil }
+	svvMissing := func() error { return errors.New(`relation "svv_redshift_schemas" does not exist`) }
+	svvDenied := func() error { return &pq.Error{Code: pgErrorCodeInsufficientPrivileges} }
+	probeFailed := func() error { return errors.New("read tcp 10.0.0.1:5439: read: connection reset by peer") }
+
+	tests := map[string]struct {
+		catalogMode    string
+		probe          func() error
+		expectedMode   string
+		expectedProbes int
+		expectedErr    bool
+	}{
+		"pg mode does not probe": {
+			catalogMode:    catalogModePg,
+			probe:          svvAvailable,
+			expectedMode:   catalogModePg,
+			expectedProbes: 0,
+		},
+		"svv mode does not probe": {
+			catalogMode:    catalogModeSvv,
+			probe:          svvMissing,
+			expectedMode:   catalogModeSvv,
+			expectedProbes: 0,
+		},
+		"auto mode with svv views available": {
+			catalogMode:    catalogModeAuto,
+			probe:          svvAvailable,
+			expectedMode:   catalogModeSvv,
+			expectedProbes: 1,
+		},
+		"auto mode without svv views": {
+			catalogMode:    catalogModeAuto,
+			probe:          svvMissing,
+			expectedMode:   catalogModePg,
+			expectedProbes: 1,
+		},
+		"auto mode without access to svv views": {
+			catalogMode:    catalogModeAuto,
+			probe:          svvDenied,
+			expectedMode:   catalogModePg,
+			expectedProbes: 1,
+		},
+		"auto mode with failing probe": {
+			catalogMode:    catalogModeAuto,
+			probe:          probeFailed,
+			expectedMode:   catalogModePg,
+			expectedProbes: 2,
+			expectedErr:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := NewConfig(proxyDriverName, "", "db", 1)
+			cfg.CatalogMode = tt.catalogMode
+
+			probes := 0
+			probe := func() error {
+				probes++
+				return tt.probe()
+			}
+
+			// resolve twice to make sure only the result of successful probes is cached
+			for i := 0; i < 2; i++ {
+				mode, err := cfg.resolveCatalogMode(probe)
+				if mode != tt.expectedMode {
+					t.Errorf("Expected catalog mode to be %q but got %q", tt.expectedMode, mode)
+				}
+				if (err != nil) != tt.expectedErr {
+					t.Errorf("Expected error %t but got %v", tt.expectedErr, err)
+				}
+			}
+			if probes != tt.expectedProbes {
+				t.Errorf("Expected %d probes but got %d", tt.expectedProbes, probes)
+			}
+		})
+	}
+}
+
+func TestCatalogQueryForMode(t *testing.T) {
+	query := catalogQuery{
+		pg:  "SELECT nspname FROM pg_namespace",
+		svv: "SELECT schema_name FROM svv_redshift_schemas",
+	}
+
+	if got := query.forMode(catalogModePg); got != query.pg {
+		t.Errorf("Expected pg query but got %q", got)
+	}
+	if got := query.forMode(catalogModeSvv); got != query.svv {
+		t.Errorf("Expected svv query but got %q", got)
+	}
+}
+
+func TestGetConfigFromResourceData_CatalogMode(t *testing.T) {
+	unsetAndSetEnvVars(t, "REDSHIFT_CATALOG_MODE")
+	fakeTemporaryCredentialsResolver := func(username string, d *schema.ResourceData) (string, string, error) {
+		return "", "", nil
+	}
+
+	for _, mode := range catalogModes {
+		t.Run(mode, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+				"host":         "some-host",
+				"password":     "some-pw",
+				"catalog_mode": mode,
+			})
+			cfg, err := getConfigFromResourceData(context.Background(), d, fakeTemporaryCredentialsResolver)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.CatalogMode != mode {
+				t.Errorf("Expected catalog mode to be %q but got %q", mode, cfg.CatalogMode)
+			}
+		})
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"host":     "some-host",
+		"password": "some-pw",
+	})
+	cfg, err := getConfigFromResourceData(context.Background(), d, fakeTemporaryCredentialsResolver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.CatalogMode != defaultCatalogMode {
+		t.Errorf("Expected default catalog mode to be %q but got %q", defaultCatalogMode, cfg.CatalogMode)
+	}
+}
@@ -1,9 +1,16 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/lib/pq"
 )
 
 var (
@@ -11,18 +18,50 @@ var (
 	dbRegistry     = make(map[string]*DBConnection, 1)
 )
 
+const (
+	defaultConnectRetries = 3
+
+	// maxConnectRetryBackoff bounds the exponential backoff between connection attempts.
+	maxConnectRetryBackoff = 30 * time.Second
+)
+
+// connectRetryBackoff is the wait before the first retry of a failed connection, it doubles with every retry.
+var connectRetryBackoff = time.Second
+
 type Config struct {
 	DriverName string
 	ConnStr    string
 	Database   string
 	MaxConns   int
 
+	// Target describes the server and database connected to in error messages, it must not contain credentials.
+	Target string
+
+	// MaxIdleConns is the number of idle connections kept open, see defaultMaxIdleConns.
+	MaxIdleConns int
+	// ConnMaxLifetime is the maximum time a connection is reused, zero means forever.
+	ConnMaxLifetime time.Duration
+	// ConnectRetries is the number of times establishing a connection is retried after a transient error.
+	ConnectRetries int
+
+	// CatalogMode controls whether reads use the pg_* catalog tables or the svv_* system views (pg, svv or auto).
+	CatalogMode string
+
+	// StatementLabels enables prepending a comment naming the resource to the statements issued by resources.
+	StatementLabels bool
+
 	serverlessCheckMutex *sync.Mutex
 	isServerless         bool
 	checkedForServerless bool
 
 	usernameRetrievalMutex *sync.Mutex
 	retrievedUsername      string
+
+	catalogModeMutex    *sync.Mutex
+	resolvedCatalogMode string
+
+	capabilitiesMutex    *sync.Mutex
+	resolvedCapabilities *redshiftCapabilities
 }
 
 func NewConfig(driverName, connStr, database string, maxConns int) *Config {
@@ -32,31 +71,145 @@ func NewConfig(driverName, connStr, database string, maxConns int) *Config {
 		Database:   database,
 		MaxConns:   maxConns,
 
+		MaxIdleConns: defaultMaxIdleConns(maxConns),
+
+		CatalogMode: defaultCatalogMode,
+
 		serverlessCheckMutex:   &sync.Mutex{},
 		usernameRetrievalMutex: &sync.Mutex{},
+		catalogModeMutex:       &sync.Mutex{},
+		capabilitiesMutex:      &sync.Mutex{},
 	}
 }
 
+// defaultMaxIdleConns keeps at most two idle connections, and never more than maxConns.
+func defaultMaxIdleConns(maxConns int) int {
+	if maxConns > 0 && maxConns < 2 {
+		return maxConns
+	}
+	return 2
+}
+
 // Client struct holding connection string
 type Client struct {
 	config Config
 
 	db *sql.DB
+
+	// statementLabel is prepended to the statements issued through this client, see labelStatement.
+	statementLabel string
+
+	// ctx bounds the statements issued through this client, e.g. by the timeouts of the resource
+	// the client was copied for. Nil means the statements are not bounded.
+	ctx context.Context
+
+	// grants caches the privileges read by grant resources for the lifetime of the client.
+	grants *grantPrivilegesCache
+
+	// deferred resolves the configuration on the first Connect() for clients created with newDeferredClient.
+	deferred *deferredConfig
+
+	// validateAtPlan enables checks against the database in CustomizeDiff. It is kept outside of the
+	// deferred configuration, so the configuration isn't resolved during plan unless it is enabled.
+	validateAtPlan bool
+}
+
+// deferredConfig resolves the configuration of a client once it is needed. It is shared by
+// the copies of a client, e.g. the ones carrying a statement label.
+type deferredConfig struct {
+	mutex   sync.Mutex
+	resolve func(context.Context) (*Config, error)
+	client  *Client
 }
 
 type DBConnection struct {
 	*sql.DB
 
 	client *Client
+
+	// ids caches catalog IDs by name for as long as the connection pool is registered.
+	ids *idCache
+
+	// warnings are reported along with the result of the resource operation the connection was copied for.
+	warnings diag.Diagnostics
+}
+
+// warn adds a warning which is shown once the resource operation finished, e.g. about state read from the
+// catalog which is unlikely to be what was configured. Warnings of connections not copied for an operation
+// are only logged.
+func (db *DBConnection) warn(summary, detail string) {
+	log.Printf("[WARN] %s: %s", summary, detail)
+	db.warnings = append(db.warnings, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  summary,
+		Detail:   detail,
+	})
+}
+
+// context returns the context the statements issued through this client run with.
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // NewClient returns client config for the specified database.
 func (c *Config) NewClient() *Client {
 	return &Client{
 		config: *c,
+		grants: newGrantPrivilegesCache(),
+	}
+}
+
+// newDeferredClient returns a client which calls resolve on its first Connect(), so the provider
+// can be configured with values which are unknown until apply, e.g. the host of a workgroup
+// which is created in the same plan. Failed resolutions are retried by the next Connect().
+func newDeferredClient(resolve func(context.Context) (*Config, error)) *Client {
+	return &Client{
+		grants:   newGrantPrivilegesCache(),
+		deferred: &deferredConfig{resolve: resolve},
 	}
 }
 
+// resolved returns the client with the configuration resolved, which is c itself unless it is deferred.
+// The configuration is resolved with ctx, the context of the operation which needs it first.
+func (c *Client) resolved(ctx context.Context) (*Client, error) {
+	if c.deferred == nil {
+		return c, nil
+	}
+	c.deferred.mutex.Lock()
+	defer c.deferred.mutex.Unlock()
+	if c.deferred.client == nil {
+		cfg, err := c.deferred.resolve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not configure the Redshift connection: %w", err)
+		}
+		client := cfg.NewClient()
+		client.grants = c.grants
+		client.validateAtPlan = c.validateAtPlan
+		c.deferred.client = client
+	}
+	return c.deferred.client, nil
+}
+
+// statementLabelsEnabled reports whether statement labels are enabled, a configuration which
+// can't be resolved is reported by Connect() instead.
+func (c *Client) statementLabelsEnabled() bool {
+	client, err := c.resolved(c.context())
+	if err != nil {
+		return false
+	}
+	return client.config.StatementLabels
+}
+
+func (c *Client) grantPrivileges() *grantPrivilegesCache {
+	if c == nil {
+		return nil
+	}
+	return c.grants
+}
+
 func (c *Config) IsServerless(db *DBConnection) (bool, error) {
 	if c.serverlessCheckMutex == nil {
 		c.serverlessCheckMutex = &sync.Mutex{}
@@ -119,6 +272,21 @@ func (c *Config) GetUsername(db *DBConnection) (string, error) {
 // Callers must return their database resources. Use of QueryRow() or Exec() is encouraged.
 // Query() must have their rows.Close()'ed.
 func (c *Client) Connect() (*DBConnection, error) {
+	return c.ConnectContext(c.context())
+}
+
+// ConnectContext is Connect bound by ctx, which is usually the context of the operation of a resource.
+// It bounds checking the registered connection pool and setting up a new one, so a hung server doesn't
+// block canceling the operation.
+func (c *Client) ConnectContext(ctx context.Context) (*DBConnection, error) {
+	if c.deferred != nil {
+		client, err := c.resolved(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return client.ConnectContext(ctx)
+	}
+
 	dbRegistryLock.Lock()
 	defer dbRegistryLock.Unlock()
 
@@ -126,24 +294,36 @@ func (c *Client) Connect() (*DBConnection, error) {
 	driverName := c.config.DriverName
 	conn, found := dbRegistry[dsn]
 
-	if !found || conn.Ping() != nil {
+	if !found || conn.PingContext(ctx) != nil {
 		db, err := sql.Open(driverName, dsn)
 		if err != nil {
 			return nil, fmt.Errorf("error creating Redshift driver instance (driver: %q): %w", driverName, err)
 		}
 
-		// We don't want to retain connection
-		// So when we connect on a specific database which might be managed by terraform,
-		// we don't keep opened connection in case of the db has to be dropped in the plan.
-		db.SetMaxIdleConns(0)
+		// Idle connections to a database which might be managed by terraform prevent it from
+		// being dropped in the plan, so only a few of them are kept (none with max_idle_connections = 0).
+		db.SetMaxIdleConns(c.config.MaxIdleConns)
 		db.SetMaxOpenConns(c.config.MaxConns)
+		db.SetConnMaxLifetime(c.config.ConnMaxLifetime)
 
+		// The registered connection outlives the operation it is set up for, so it must not keep
+		// the statement label and context of it.
+		registeredClient := *c
+		registeredClient.statementLabel = ""
+		registeredClient.ctx = nil
 		conn = &DBConnection{
-			db,
-			c,
+			DB:     db,
+			client: &registeredClient,
+			ids:    newIDCache(),
 		}
 
-		_, err = c.config.GetUsername(conn)
+		// Errors of the connection itself would otherwise only surface with the first query of a resource.
+		if err := conn.pingWithRetries(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		_, err = registeredClient.config.GetUsername(conn)
 		if err != nil {
 			return nil, fmt.Errorf("error retrieving username from Redshift database (driver: %q): %w", driverName, err)
 		}
@@ -154,6 +334,66 @@ func (c *Client) Connect() (*DBConnection, error) {
 	return conn, nil
 }
 
+// pingWithRetries pings the database, retrying transient errors up to ConnectRetries times with an exponential backoff.
+// This is distinct from ResourceRetryOnPQErrors, which retries the statements of resources.
+func (db *DBConnection) pingWithRetries(ctx context.Context) error {
+	retries := db.client.config.ConnectRetries
+	for attempt := 0; ; attempt++ {
+		err := db.ping(ctx)
+		if err == nil || attempt >= retries || !isRetryableConnectError(err) || ctx.Err() != nil {
+			return err
+		}
+		backoff := min(connectRetryBackoff<<attempt, maxConnectRetryBackoff)
+		log.Printf("[WARN] %v, retrying in %s (%d/%d)", err, backoff, attempt+1, retries)
+		if err := sleepContext(ctx, backoff); err != nil {
+			return err
+		}
+	}
+}
+
+// isRetryableConnectError returns whether a connection error might be transient, e.g. a DNS failure or a
+// serverless workgroup which is resuming. Rejected credentials and missing databases are not retried.
+func isRetryableConnectError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		class := string(pqErr.Code.Class())
+		return class != "28" && class != "3D"
+	}
+	return true
+}
+
+// ping opens a connection, so a misconfigured or unreachable server is reported when connecting.
+func (db *DBConnection) ping(ctx context.Context) error {
+	if err := db.PingContext(ctx); err != nil {
+		target := db.client.config.Target
+		if target == "" {
+			target = db.client.config.Database
+		}
+		return fmt.Errorf("could not connect to Redshift at %s (driver: %q), check the connection settings of the provider: %w", target, db.client.config.DriverName, err)
+	}
+	return nil
+}
+
+// resetConnection drops the registered connection pool of the client, so that the next
+// Connect() opens a new one. The old pool is not closed as other resources might still
+// use it, but it no longer keeps idle connections around.
+func (c *Client) resetConnection() {
+	if c.deferred != nil {
+		if client, err := c.resolved(c.context()); err == nil {
+			client.resetConnection()
+		}
+		return
+	}
+
+	dbRegistryLock.Lock()
+	defer dbRegistryLock.Unlock()
+
+	if conn, found := dbRegistry[c.config.ConnStr]; found {
+		conn.SetMaxIdleConns(0)
+		delete(dbRegistry, c.config.ConnStr)
+	}
+}
+
 func (c *Client) Close() {
 	if c.db != nil {
 		c.db.Close()
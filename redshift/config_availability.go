@@ -0,0 +1,302 @@
+package redshift
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const waitForAvailableAttr = "wait_for_available"
+
+// availabilityPollInterval is the wait between two checks of the status of a workgroup or cluster.
+var availabilityPollInterval = 10 * time.Second
+
+// availabilityCheck returns the status of a workgroup or cluster and whether it accepts connections.
+type availabilityCheck func(ctx context.Context) (status string, available bool, err error)
+
+func waitForAvailableSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Default:      0,
+		Description:  "Maximum time in seconds to wait for the workgroup or cluster to become available before the first connection, e.g. while it is resuming after being paused. Zero (the default) connects without checking its status.",
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+}
+
+// waitForAvailable calls check until the workgroup or cluster named by target is available, for at most timeout.
+func waitForAvailable(ctx context.Context, target string, timeout time.Duration, check availabilityCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status := "unknown"
+	for {
+		currentStatus, available, err := check(ctx)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return fmt.Errorf("%s is still %s after waiting %s for it to become available", target, status, timeout)
+		case err != nil:
+			return fmt.Errorf("could not check the status of %s: %w", target, err)
+		case available:
+			return nil
+		}
+		status = currentStatus
+		log.Printf("[INFO] %s is %s, waiting %s for it to become available", target, status, availabilityPollInterval)
+		if err := sleepContext(ctx, availabilityPollInterval); err != nil {
+			return fmt.Errorf("%s is still %s after waiting %s for it to become available", target, status, timeout)
+		}
+	}
+}
+
+// waitForDataApiAvailable waits for the workgroup or cluster of the data_api block if wait_for_available is set.
+func waitForDataApiAvailable(ctx context.Context, d *schema.ResourceData) error {
+	timeout := time.Duration(d.Get("data_api.0."+waitForAvailableAttr).(int)) * time.Second
+	if timeout == 0 {
+		return nil
+	}
+
+	cfg, err := loadAwsConfig(ctx, getAwsCredentialsFromResourceData(d, "data_api.0."))
+	if err != nil {
+		return err
+	}
+	cfg.Region = d.Get("data_api.0.region").(string)
+
+	if clusterIdentifier, ok := d.GetOk("data_api.0.cluster_identifier"); ok {
+		return waitForAvailable(ctx, fmt.Sprintf("cluster %s", clusterIdentifier), timeout, clusterAvailability(redshift.NewFromConfig(cfg), clusterIdentifier.(string)))
+	}
+	workgroupName := d.Get("data_api.0.workgroup_name").(string)
+	endpoint, err := serverlessEndpoint(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return waitForAvailable(ctx, fmt.Sprintf("workgroup %s", workgroupName), timeout, workgroupAvailability(cfg, endpoint, workgroupName))
+}
+
+// waitForTemporaryCredentialsClusterAvailable waits for the cluster of the temporary_credentials block if wait_for_available is set.
+func waitForTemporaryCredentialsClusterAvailable(ctx context.Context, d *schema.ResourceData) error {
+	timeout := time.Duration(d.Get("temporary_credentials.0."+waitForAvailableAttr).(int)) * time.Second
+	if timeout == 0 {
+		return nil
+	}
+
+	sdkClient, err := redshiftSdkClient(d)
+	if err != nil {
+		return err
+	}
+	clusterIdentifier := d.Get("temporary_credentials.0.cluster_identifier").(string)
+	return waitForAvailable(ctx, fmt.Sprintf("cluster %s", clusterIdentifier), timeout, clusterAvailability(sdkClient, clusterIdentifier))
+}
+
+// clusterDescriber is the part of the Redshift API client used to check the status of a cluster.
+type clusterDescriber interface {
+	DescribeClusters(ctx context.Context, params *redshift.DescribeClustersInput, optFns ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error)
+}
+
+func clusterAvailability(client clusterDescriber, clusterIdentifier string) availabilityCheck {
+	return func(ctx context.Context) (string, bool, error) {
+		output, err := client.DescribeClusters(ctx, &redshift.DescribeClustersInput{
+			ClusterIdentifier: aws.String(clusterIdentifier),
+		})
+		if err != nil {
+			return "", false, err
+		}
+		if len(output.Clusters) == 0 {
+			return "", false, fmt.Errorf("cluster %s not found", clusterIdentifier)
+		}
+		status := aws.ToString(output.Clusters[0].ClusterStatus)
+		return status, status == "available", nil
+	}
+}
+
+// serverlessSdkID is the service ID of the Redshift Serverless API, which names its endpoint settings,
+// e.g. AWS_ENDPOINT_URL_REDSHIFT_SERVERLESS.
+const serverlessSdkID = "Redshift Serverless"
+
+// serverlessEndpoint returns the endpoint of the Redshift Serverless API the way the SDK clients resolve it:
+// a custom endpoint of the service or of all services if one is configured, otherwise the regional endpoint
+// in the partition of the region, which is the FIPS endpoint if FIPS endpoints are enabled.
+func serverlessEndpoint(ctx context.Context, cfg aws.Config) (string, error) {
+	useFIPS := false
+	for _, source := range cfg.ConfigSources {
+		if provider, ok := source.(interface {
+			GetServiceBaseEndpoint(context.Context, string) (string, bool, error)
+		}); ok {
+			endpoint, found, err := provider.GetServiceBaseEndpoint(ctx, serverlessSdkID)
+			if err != nil {
+				return "", fmt.Errorf("could not resolve the %s endpoint: %w", serverlessSdkID, err)
+			}
+			if found {
+				return endpoint, nil
+			}
+		}
+		if provider, ok := source.(interface {
+			GetUseFIPSEndpoint(context.Context) (aws.FIPSEndpointState, bool, error)
+		}); ok && !useFIPS {
+			state, found, err := provider.GetUseFIPSEndpoint(ctx)
+			if err != nil {
+				return "", fmt.Errorf("could not resolve the %s endpoint: %w", serverlessSdkID, err)
+			}
+			useFIPS = found && state == aws.FIPSEndpointStateEnabled
+		}
+	}
+	if cfg.BaseEndpoint != nil {
+		return aws.ToString(cfg.BaseEndpoint), nil
+	}
+
+	host := "redshift-serverless"
+	if useFIPS {
+		host += "-fips"
+	}
+	return fmt.Sprintf("https://%s.%s.%s", host, cfg.Region, partitionDNSSuffix(cfg.Region)), nil
+}
+
+// partitionDNSSuffix returns the DNS suffix of the AWS partition the region belongs to.
+func partitionDNSSuffix(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "amazonaws.com.cn"
+	case strings.HasPrefix(region, "us-isob-"):
+		return "sc2s.sgov.gov"
+	case strings.HasPrefix(region, "us-iso-"):
+		return "c2s.ic.gov"
+	default:
+		return "amazonaws.com"
+	}
+}
+
+// workgroupAvailability checks the status of a serverless workgroup with the GetWorkgroup action of the
+// Redshift Serverless API at endpoint. The provider doesn't depend on the SDK client of the API, so the
+// action is called with the HTTP client, credentials and retryer of cfg, like the SDK clients do.
+func workgroupAvailability(cfg aws.Config, endpoint, workgroupName string) availabilityCheck {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = awshttp.NewBuildableClient()
+	}
+	var retryer aws.Retryer = retry.NewStandard()
+	if cfg.Retryer != nil {
+		retryer = cfg.Retryer()
+	}
+
+	return func(ctx context.Context) (string, bool, error) {
+		var status string
+		for attempt := 1; ; attempt++ {
+			var err error
+			status, err = getWorkgroupStatus(ctx, httpClient, endpoint, cfg, workgroupName)
+			if err == nil {
+				break
+			}
+			if attempt >= retryer.MaxAttempts() || !retryer.IsErrorRetryable(err) {
+				return "", false, err
+			}
+			delay, delayErr := retryer.RetryDelay(attempt, err)
+			if delayErr != nil {
+				return "", false, err
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return "", false, err
+			}
+		}
+		return strings.ToLower(status), status == "AVAILABLE", nil
+	}
+}
+
+// getWorkgroupStatus calls the GetWorkgroup action once and returns the status of the workgroup.
+func getWorkgroupStatus(ctx context.Context, httpClient aws.HTTPClient, endpoint string, cfg aws.Config, workgroupName string) (string, error) {
+	body, err := json.Marshal(map[string]string{"workgroupName": workgroupName})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "RedshiftServerless.GetWorkgroup")
+
+	credentials, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve AWS credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), "redshift-serverless", cfg.Region, time.Now()); err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", newServerlessAPIError(resp.StatusCode, respBody)
+	}
+
+	var output struct {
+		Workgroup struct {
+			Status string `json:"status"`
+		} `json:"workgroup"`
+	}
+	if err := json.Unmarshal(respBody, &output); err != nil {
+		return "", fmt.Errorf("could not parse the GetWorkgroup response: %w", err)
+	}
+	return output.Workgroup.Status, nil
+}
+
+// serverlessAPIError is an error response of the Redshift Serverless API. It reports its HTTP status code
+// and error code like the errors of the SDK clients, so retryers can tell transient errors apart.
+type serverlessAPIError struct {
+	statusCode int
+	code       string
+	message    string
+}
+
+func newServerlessAPIError(statusCode int, body []byte) *serverlessAPIError {
+	var response struct {
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	apiErr := &serverlessAPIError{statusCode: statusCode, message: strings.TrimSpace(string(body))}
+	if err := json.Unmarshal(body, &response); err == nil {
+		// The type is the shape of the error, optionally qualified by its namespace.
+		apiErr.code = response.Type[strings.LastIndex(response.Type, "#")+1:]
+		if response.Message != "" {
+			apiErr.message = response.Message
+		}
+	}
+	return apiErr
+}
+
+func (e *serverlessAPIError) Error() string {
+	if e.code == "" {
+		return fmt.Sprintf("GetWorkgroup failed with status %d: %s", e.statusCode, e.message)
+	}
+	return fmt.Sprintf("GetWorkgroup failed with status %d: %s: %s", e.statusCode, e.code, e.message)
+}
+
+func (e *serverlessAPIError) HTTPStatusCode() int {
+	return e.statusCode
+}
+
+func (e *serverlessAPIError) ErrorCode() string {
+	return e.code
+}
@@ -0,0 +1,207 @@
+package redshift
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/aws/aws-sdk-go-v2/service/redshift/types"
+)
+
+func TestWaitForAvailable(t *testing.T) {
+	defer func(interval time.Duration) { availabilityPollInterval = interval }(availabilityPollInterval)
+	availabilityPollInterval = time.Millisecond
+
+	calls := 0
+	resuming := func(context.Context) (string, bool, error) {
+		calls++
+		return "resuming", calls == 3, nil
+	}
+	if err := waitForAvailable(context.Background(), "cluster my-cluster", time.Minute, resuming); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected the status to be checked 3 times, got %d", calls)
+	}
+
+	paused := func(context.Context) (string, bool, error) {
+		return "paused", false, nil
+	}
+	err := waitForAvailable(context.Background(), "cluster my-cluster", 20*time.Millisecond, paused)
+	if err == nil || !strings.Contains(err.Error(), "cluster my-cluster is still paused after waiting 20ms") {
+		t.Errorf("expected a timeout error naming the status, got: %v", err)
+	}
+
+	failing := func(context.Context) (string, bool, error) {
+		return "", false, io.ErrUnexpectedEOF
+	}
+	err = waitForAvailable(context.Background(), "workgroup my-workgroup", time.Minute, failing)
+	if err == nil || !strings.Contains(err.Error(), "could not check the status of workgroup my-workgroup") {
+		t.Errorf("expected the error of the check, got: %v", err)
+	}
+}
+
+type fakeClusterDescriber struct {
+	statuses []string
+}
+
+func (f *fakeClusterDescriber) DescribeClusters(_ context.Context, params *redshift.DescribeClustersInput, _ ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error) {
+	status := f.statuses[0]
+	f.statuses = f.statuses[1:]
+	return &redshift.DescribeClustersOutput{
+		Clusters: []types.Cluster{{ClusterIdentifier: params.ClusterIdentifier, ClusterStatus: aws.String(status)}},
+	}, nil
+}
+
+func TestClusterAvailability(t *testing.T) {
+	check := clusterAvailability(&fakeClusterDescriber{statuses: []string{"resuming", "available"}}, "my-cluster")
+
+	for _, expected := range []struct {
+		status    string
+		available bool
+	}{{"resuming", false}, {"available", true}} {
+		status, available, err := check(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != expected.status || available != expected.available {
+			t.Errorf("clusterAvailability() = %q, %t, want %q, %t", status, available, expected.status, expected.available)
+		}
+	}
+}
+
+func TestWorkgroupAvailability(t *testing.T) {
+	status := "MODIFYING"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target := r.Header.Get("X-Amz-Target"); target != "RedshiftServerless.GetWorkgroup" {
+			t.Errorf("unexpected X-Amz-Target %q", target)
+		}
+		if auth := r.Header.Get("Authorization"); !strings.Contains(auth, "/eu-central-1/redshift-serverless/aws4_request") {
+			t.Errorf("expected the request to be signed for redshift-serverless in eu-central-1, got %q", auth)
+		}
+		var input map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input["workgroupName"] != "my-workgroup" {
+			t.Errorf("unexpected request body %v (%v)", input, err)
+		}
+		if status == "" {
+			http.Error(w, `{"__type":"ResourceNotFoundException"}`, http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"workgroup":{"workgroupName":"my-workgroup","status":"` + status + `"}}`))
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      "eu-central-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  server.Client(),
+	}
+	check := workgroupAvailability(cfg, server.URL, "my-workgroup")
+
+	if got, available, err := check(context.Background()); err != nil || got != "modifying" || available {
+		t.Errorf("workgroupAvailability() = %q, %t, %v, want modifying, false", got, available, err)
+	}
+
+	status = "AVAILABLE"
+	if got, available, err := check(context.Background()); err != nil || got != "available" || !available {
+		t.Errorf("workgroupAvailability() = %q, %t, %v, want available, true", got, available, err)
+	}
+
+	status = ""
+	if _, _, err := check(context.Background()); err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Errorf("expected the error of the API, got: %v", err)
+	}
+}
+
+func TestWorkgroupAvailability_RetriesTransientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			http.Error(w, `{"__type":"com.amazonaws.redshiftserverless#ThrottlingException","message":"Rate exceeded"}`, http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"workgroup":{"workgroupName":"my-workgroup","status":"AVAILABLE"}}`))
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      "eu-central-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  server.Client(),
+		Retryer: func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return time.Millisecond, nil })
+			})
+		},
+	}
+
+	if status, available, err := workgroupAvailability(cfg, server.URL, "my-workgroup")(context.Background()); err != nil || status != "available" || !available {
+		t.Errorf("workgroupAvailability() = %q, %t, %v, want available, true", status, available, err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the throttled request to be retried once, got %d requests", requests)
+	}
+}
+
+func TestServerlessEndpoint(t *testing.T) {
+	tests := map[string]struct {
+		cfg      aws.Config
+		env      map[string]string
+		expected string
+	}{
+		"commercial region": {
+			cfg:      aws.Config{Region: "eu-central-1"},
+			expected: "https://redshift-serverless.eu-central-1.amazonaws.com",
+		},
+		"china region": {
+			cfg:      aws.Config{Region: "cn-north-1"},
+			expected: "https://redshift-serverless.cn-north-1.amazonaws.com.cn",
+		},
+		"fips endpoint": {
+			cfg: aws.Config{
+				Region:        "us-gov-west-1",
+				ConfigSources: []interface{}{config.EnvConfig{UseFIPSEndpoint: aws.FIPSEndpointStateEnabled}},
+			},
+			expected: "https://redshift-serverless-fips.us-gov-west-1.amazonaws.com",
+		},
+		"custom endpoint for all services": {
+			cfg:      aws.Config{Region: "eu-central-1", BaseEndpoint: aws.String("https://vpce.example.com")},
+			expected: "https://vpce.example.com",
+		},
+		"custom endpoint of the service": {
+			cfg: aws.Config{
+				Region:        "eu-central-1",
+				BaseEndpoint:  aws.String("https://vpce.example.com"),
+				ConfigSources: []interface{}{config.EnvConfig{}},
+			},
+			env:      map[string]string{"AWS_ENDPOINT_URL_REDSHIFT_SERVERLESS": "https://serverless.example.com"},
+			expected: "https://serverless.example.com",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			endpoint, err := serverlessEndpoint(context.Background(), tt.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if endpoint != tt.expected {
+				t.Errorf("serverlessEndpoint() = %q, want %q", endpoint, tt.expected)
+			}
+		})
+	}
+}
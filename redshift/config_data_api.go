@@ -1,18 +1,44 @@
 package redshift
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-
-	_ "github.com/mmichaelb/redshift-data-sql-driver"
+	redshiftdatasqldriver "github.com/mmichaelb/redshift-data-sql-driver"
 )
 
 const redshiftDataDriverName = "redshift-data"
 
+func init() {
+	redshiftdatasqldriver.RedshiftDataClientConstructor = newRedshiftDataClient
+}
+
+// newRedshiftDataClient creates the Data API client of the driver with the AWS credentials
+// passed as parameters of the connection string, see withAwsCredentialsParams.
+func newRedshiftDataClient(ctx context.Context, cfg *redshiftdatasqldriver.RedshiftDataConfig) (redshiftdatasqldriver.RedshiftDataClient, error) {
+	awsCfg, err := loadAwsConfig(ctx, getAwsCredentialsFromParams(cfg.Params))
+	if err != nil {
+		return nil, err
+	}
+	return redshiftdata.NewFromConfig(awsCfg, cfg.RedshiftDataOptFns...), nil
+}
+
+// withAwsCredentialsParams adds the AWS credentials to the connection string of the Data API driver,
+// which keeps unknown parameters for the client constructor.
+func withAwsCredentialsParams(connStr string, creds awsCredentials) string {
+	if params := creds.params(); len(params) > 0 {
+		return connStr + "&" + params.Encode()
+	}
+	return connStr
+}
+
 func NewDataApiConfig(workgroupName, database, awsRegion string, maxConns int) *Config {
 	connStr := buildConnStrFromDataApiConfig(workgroupName, database, awsRegion)
-	return NewConfig(redshiftDataDriverName, connStr, database, maxConns)
+	cfg := NewConfig(redshiftDataDriverName, connStr, database, maxConns)
+	cfg.Target = fmt.Sprintf("workgroup %s/%s in %s", workgroupName, database, awsRegion)
+	return cfg
 }
 
 func buildConnStrFromDataApiConfig(workgroupName, database, awsRegion string) string {
@@ -27,7 +53,9 @@ func NewDataApiClusterConfig(clusterIdentifier, username, database, awsRegion st
 		return nil, fmt.Errorf("data_api configuration with cluster_identifier requires username to be set")
 	}
 	connStr := buildConnStrFromDataApiClusterConfig(clusterIdentifier, username, database, awsRegion)
-	return NewConfig(redshiftDataDriverName, connStr, database, maxConns), nil
+	cfg := NewConfig(redshiftDataDriverName, connStr, database, maxConns)
+	cfg.Target = fmt.Sprintf("cluster %s/%s in %s", clusterIdentifier, database, awsRegion)
+	return cfg, nil
 }
 
 func buildConnStrFromDataApiClusterConfig(clusterIdentifier, username, database, awsRegion string) string {
@@ -37,7 +65,19 @@ func buildConnStrFromDataApiClusterConfig(clusterIdentifier, username, database,
 	)
 }
 
-func getConfigFromDataApiResourceData(d *schema.ResourceData, database string) (*Config, error) {
+func getConfigFromDataApiResourceData(ctx context.Context, d *schema.ResourceData, database string) (*Config, error) {
+	cfg, err := getDataApiConfig(d, database)
+	if err != nil {
+		return nil, err
+	}
+	if err := waitForDataApiAvailable(ctx, d); err != nil {
+		return nil, err
+	}
+	cfg.ConnStr = withAwsCredentialsParams(cfg.ConnStr, getAwsCredentialsFromResourceData(d, "data_api.0."))
+	return cfg, nil
+}
+
+func getDataApiConfig(d *schema.ResourceData, database string) (*Config, error) {
 	workgroupName, workgroupNameOk := d.GetOk("data_api.0.workgroup_name")
 	clusterIdentifier, clusterIdentifierOk := d.GetOk("data_api.0.cluster_identifier")
 	region, regionOk := d.GetOk("data_api.0.region")
@@ -1,8 +1,11 @@
 package redshift
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestBuildConnStrFromDataApiClusterConfig(t *testing.T) {
@@ -41,3 +44,65 @@ func TestBuildConnStrFromDataApiWorkgroupConfig_Unchanged(t *testing.T) {
 		t.Errorf("buildConnStrFromDataApiConfig() = %q, want %q", got, want)
 	}
 }
+
+func TestGetConfigFromDataApiResourceData_Region(t *testing.T) {
+	tests := map[string]struct {
+		dataApi  map[string]interface{}
+		envVars  map[string]string
+		expected string
+	}{
+		"workgroup with configured region": {
+			dataApi:  map[string]interface{}{"workgroup_name": "some-workgroup", "region": "eu-central-1"},
+			expected: "workgroup(some-workgroup)/db?region=eu-central-1&transactionMode=non-transactional&requestMode=blocking",
+		},
+		"cluster with configured region": {
+			dataApi:  map[string]interface{}{"cluster_identifier": "some-cluster", "username": "some-user", "region": "ap-southeast-2"},
+			expected: "some-user@cluster(some-cluster)/db?region=ap-southeast-2&transactionMode=non-transactional&requestMode=blocking",
+		},
+		"workgroup with region from AWS_REGION": {
+			dataApi:  map[string]interface{}{"workgroup_name": "some-workgroup"},
+			envVars:  map[string]string{"AWS_REGION": "eu-west-1"},
+			expected: "workgroup(some-workgroup)/db?region=eu-west-1&transactionMode=non-transactional&requestMode=blocking",
+		},
+		"workgroup with region from AWS_DEFAULT_REGION": {
+			dataApi:  map[string]interface{}{"workgroup_name": "some-workgroup"},
+			envVars:  map[string]string{"AWS_DEFAULT_REGION": "us-east-2"},
+			expected: "workgroup(some-workgroup)/db?region=us-east-2&transactionMode=non-transactional&requestMode=blocking",
+		},
+		"workgroup with profile": {
+			dataApi:  map[string]interface{}{"workgroup_name": "some-workgroup", "region": "eu-central-1", "profile": "some-profile"},
+			expected: "workgroup(some-workgroup)/db?region=eu-central-1&transactionMode=non-transactional&requestMode=blocking&profile=some-profile",
+		},
+		"cluster with static credentials": {
+			dataApi: map[string]interface{}{
+				"cluster_identifier": "some-cluster",
+				"username":           "some-user",
+				"region":             "eu-central-1",
+				"access_key_id":      "AKIDEXAMPLE",
+				"secret_access_key":  "some/secret+key",
+			},
+			expected: "some-user@cluster(some-cluster)/db?region=eu-central-1&transactionMode=non-transactional&requestMode=blocking&access_key_id=AKIDEXAMPLE&secret_access_key=some%2Fsecret%2Bkey",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			unsetAndSetEnvVars(t, "AWS_REGION", "AWS_DEFAULT_REGION", "REDSHIFT_HOST")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+				"database": "db",
+				"data_api": []interface{}{tt.dataApi},
+			})
+			cfg, err := getConfigFromDataApiResourceData(context.Background(), d, "db")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.ConnStr != tt.expected {
+				t.Errorf("cfg.ConnStr = %q, want %q", cfg.ConnStr, tt.expected)
+			}
+		})
+	}
+}
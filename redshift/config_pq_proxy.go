@@ -2,15 +2,17 @@ package redshift
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/redshift"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
@@ -20,16 +22,78 @@ import (
 
 type temporaryCredentialsResolverFunc func(username string, d *schema.ResourceData) (string, string, error)
 
-func NewPqConfig(host, database, username, password string, port int, sslMode string, maxConns int) *Config {
-	connStr := buildConnStrFromPqConfig(host, database, username, password, port, sslMode)
-	return NewConfig(proxyDriverName, connStr, database, maxConns)
+// sslModes are the sslmode values understood by lib/pq, ordered from the weakest to the strictest.
+var sslModes = []string{
+	"disable",
+	"allow",
+	"prefer",
+	"require",
+	"verify-ca",
+	"verify-full",
 }
 
-func buildConnStrFromPqConfig(host, database, username, password string, port int, sslMode string) string {
+func NewPqConfig(host, database, username, password string, port int, sslMode, applicationName string, statementTimeout int, maxConns int) *Config {
+	connStr := buildConnStrFromPqConfig(host, database, username, password, port, sslMode, applicationName, statementTimeout)
+	cfg := NewConfig(proxyDriverName, connStr, database, maxConns)
+	cfg.Target = fmt.Sprintf("%s:%d/%s", host, port, database)
+	return cfg
+}
+
+// NewPqConfigFromURL returns the configuration connecting with a full connection URL, which is used as is.
+func NewPqConfigFromURL(connectionURL string, maxConns int) (*Config, error) {
+	u, err := parseConnectionURL(connectionURL)
+	if err != nil {
+		return nil, err
+	}
+	database := strings.TrimPrefix(u.Path, "/")
+	if database == "" {
+		return nil, fmt.Errorf("connection_url must contain the database to connect to, e.g. postgres://host:5439/dev")
+	}
+	cfg := NewConfig(proxyDriverName, connectionURL, database, maxConns)
+	cfg.Target = fmt.Sprintf("%s/%s", u.Host, database)
+	return cfg, nil
+}
+
+// parseConnectionURL parses a postgres:// or postgresql:// connection URL. The URL itself is left out of
+// errors, as it usually contains the password.
+func parseConnectionURL(connectionURL string) (*url.URL, error) {
+	u, err := url.Parse(connectionURL)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("connection_url can't be parsed: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, fmt.Errorf("connection_url must use the postgres or postgresql scheme, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("connection_url must contain the host to connect to")
+	}
+	return u, nil
+}
+
+func validateConnectionURL(v interface{}, _ string) (ws []string, errs []error) {
+	if _, err := parseConnectionURL(v.(string)); err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
+
+// buildConnStrFromPqConfig returns the connection URL for lib/pq. A statementTimeout above zero is passed as
+// statement_timeout parameter, which lib/pq sets for the session of every connection.
+func buildConnStrFromPqConfig(host, database, username, password string, port int, sslMode, applicationName string, statementTimeout int) string {
 	params := map[string]string{}
 
 	params["sslmode"] = sslMode
 	params["connect_timeout"] = "180"
+	if applicationName != "" {
+		params["application_name"] = applicationName
+	}
+	if statementTimeout > 0 {
+		params["statement_timeout"] = strconv.Itoa(statementTimeout)
+	}
 
 	var paramsArray []string
 	for key, value := range params {
@@ -48,17 +112,22 @@ func buildConnStrFromPqConfig(host, database, username, password string, port in
 	)
 }
 
-func getConfigFromPqResourceData(d *schema.ResourceData, database string, maxConnections int, temporaryCredentialsResolver temporaryCredentialsResolverFunc) (*Config, error) {
+func getConfigFromPqResourceData(ctx context.Context, d *schema.ResourceData, database string, maxConnections int, temporaryCredentialsResolver temporaryCredentialsResolverFunc) (*Config, error) {
 	var err error
 	var password string
 	host := d.Get("host").(string)
 	username := d.Get("username").(string)
 	port := d.Get("port").(int)
 	sslMode := d.Get("sslmode").(string)
+	applicationName := d.Get("application_name").(string)
+	statementTimeout := d.Get("statement_timeout").(int)
 	log.Printf("[DEBUG] using username %q for authentication\n", username)
 	_, useTemporaryCredentials := d.GetOk("temporary_credentials")
 	if useTemporaryCredentials {
 		log.Println("[DEBUG] using temporary credentials authentication")
+		if err := waitForTemporaryCredentialsClusterAvailable(ctx, d); err != nil {
+			return nil, err
+		}
 		username, password, err = temporaryCredentialsResolver(username, d)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve temporary credentials: %w", err)
@@ -68,7 +137,7 @@ func getConfigFromPqResourceData(d *schema.ResourceData, database string, maxCon
 		log.Println("[DEBUG] using password authentication")
 		password = d.Get("password").(string)
 	}
-	return NewPqConfig(host, database, username, password, port, sslMode, maxConnections), nil
+	return NewPqConfig(host, database, username, password, port, sslMode, applicationName, statementTimeout, maxConnections), nil
 }
 
 // temporaryCredentials gets temporary credentials using GetClusterCredentials
@@ -118,32 +187,65 @@ func temporaryCredentials(username string, d *schema.ResourceData) (string, stri
 }
 
 func redshiftSdkClient(d *schema.ResourceData) (*redshift.Client, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	cfg, err := loadAwsConfig(context.TODO(), getAwsCredentialsFromResourceData(d, "temporary_credentials.0."))
 	if err != nil {
 		return nil, err
 	}
 
 	if region := d.Get("temporary_credentials.0.region").(string); region != "" {
 		cfg.Region = region
+	} else if region := regionFromHost(d.Get("host").(string)); region != "" {
+		log.Printf("[DEBUG] using region %s of host for temporary credentials", region)
+		cfg.Region = region
 	}
 
-	if _, ok := d.GetOk("temporary_credentials.0.assume_role"); ok {
-		var parsedRoleArn string
-		if roleArn, ok := d.GetOk("temporary_credentials.0.assume_role.0.arn"); ok {
-			parsedRoleArn = roleArn.(string)
+	// Roles are chained, each one is assumed with the credentials of the previous one.
+	for _, rawRole := range d.Get("temporary_credentials.0.assume_role").([]interface{}) {
+		role := rawRole.(map[string]interface{})
+		roleArn := role["arn"].(string)
+		log.Printf("[DEBUG] Assuming role provided in configuration: [%s]", roleArn)
+		stsClient := sts.NewFromConfig(cfg, func(options *sts.Options) {
+			options.BaseEndpoint = endpointURL(d, "temporary_credentials.0.sts_endpoint_url")
+		})
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, roleArn, assumeRoleOptions(role)))
+	}
+	return redshift.NewFromConfig(cfg, func(options *redshift.Options) {
+		options.BaseEndpoint = endpointURL(d, "temporary_credentials.0.endpoint_url")
+	}), nil
+}
+
+// redshiftHostRegion matches the region of Redshift endpoints, e.g. my-cluster.abc123.eu-central-1.redshift.amazonaws.com.
+var redshiftHostRegion = regexp.MustCompile(`\.([a-z]{2}(?:-[a-z]+)+-\d+)\.redshift(?:-serverless)?\.amazonaws\.com(?:\.cn)?\.?$`)
+
+// regionFromHost returns the region of a Redshift endpoint, or an empty string for other hosts, e.g. IP addresses or custom DNS names.
+func regionFromHost(host string) string {
+	match := redshiftHostRegion.FindStringSubmatch(strings.ToLower(host))
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// endpointURL returns the endpoint configured by attr, or nil to use the default endpoint of the region.
+func endpointURL(d *schema.ResourceData, attr string) *string {
+	if endpoint := d.Get(attr).(string); endpoint != "" {
+		return aws.String(endpoint)
+	}
+	return nil
+}
+
+// assumeRoleOptions returns the options for assuming the role of an assume_role block.
+func assumeRoleOptions(role map[string]interface{}) func(*stscreds.AssumeRoleOptions) {
+	return func(options *stscreds.AssumeRoleOptions) {
+		options.Duration = time.Duration(defaultTemporaryCredentialsAssumeRoleDurationInSeconds) * time.Second
+		if duration, ok := role["duration_seconds"].(int); ok && duration > 0 {
+			options.Duration = time.Duration(duration) * time.Second
 		}
-		log.Printf("[DEBUG] Assuming role provided in configuration: [%s]", parsedRoleArn)
-		opts := func(options *stscreds.AssumeRoleOptions) {
-			options.Duration = time.Duration(defaultTemporaryCredentialsAssumeRoleDurationInSeconds) * time.Second
-			if externalID, ok := d.GetOk("temporary_credentials.0.assume_role.0.external_id"); ok {
-				options.ExternalID = aws.String(externalID.(string))
-			}
-			if sessionName, ok := d.GetOk("temporary_credentials.0.assume_role.0.session_name"); ok {
-				options.RoleSessionName = sessionName.(string)
-			}
+		if externalID, ok := role["external_id"].(string); ok && externalID != "" {
+			options.ExternalID = aws.String(externalID)
+		}
+		if sessionName, ok := role["session_name"].(string); ok && sessionName != "" {
+			options.RoleSessionName = sessionName
 		}
-		stsClient := sts.NewFromConfig(cfg)
-		cfg.Credentials = stscreds.NewAssumeRoleProvider(stsClient, parsedRoleArn, opts)
 	}
-	return redshift.NewFromConfig(cfg), nil
 }
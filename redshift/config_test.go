@@ -0,0 +1,155 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestClientConnect_UnreachableServer(t *testing.T) {
+	config := NewPqConfig("127.0.0.1", "mydb", "myuser", "secret", 1, "disable", "", 0, 1)
+
+	_, err := config.NewClient().Connect()
+	if err == nil {
+		t.Fatal("expected error when the server is unreachable, got nil")
+	}
+	if !strings.Contains(err.Error(), "127.0.0.1:1/mydb") {
+		t.Errorf("expected error to mention the target, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected error not to contain the password, got: %v", err)
+	}
+}
+
+func TestNewConfig_Target(t *testing.T) {
+	cfg, err := NewDataApiClusterConfig("my-cluster", "myuser", "mydb", "us-east-1", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]struct {
+		config *Config
+		want   string
+	}{
+		"pq":               {NewPqConfig("example.com", "mydb", "myuser", "secret", 5439, "require", "", 0, 1), "example.com:5439/mydb"},
+		"data api":         {NewDataApiConfig("my-workgroup", "mydb", "eu-central-1", 1), "workgroup my-workgroup/mydb in eu-central-1"},
+		"data api cluster": {cfg, "cluster my-cluster/mydb in us-east-1"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.config.Target != tt.want {
+				t.Errorf("Target = %q, want %q", tt.config.Target, tt.want)
+			}
+		})
+	}
+}
+
+// refusingDriver fails to open the first connections with err, like a server which is still resuming.
+type refusingDriver struct {
+	failures *atomic.Int32
+	opens    *atomic.Int32
+	err      error
+}
+
+func (d refusingDriver) Open(string) (driver.Conn, error) {
+	d.opens.Add(1)
+	if d.failures.Add(-1) >= 0 {
+		return nil, d.err
+	}
+	return flakyConn{&atomic.Int32{}}, nil
+}
+
+func TestClientConnect_Retries(t *testing.T) {
+	backoff := connectRetryBackoff
+	connectRetryBackoff = time.Millisecond
+	t.Cleanup(func() { connectRetryBackoff = backoff })
+
+	dialErr := errors.New("dial tcp: lookup my-workgroup.example.com: no such host")
+	tests := map[string]struct {
+		failures      int32
+		retries       int
+		err           error
+		expectedOpens int32
+		expectError   bool
+	}{
+		"succeeds after transient errors": {failures: 2, retries: 3, err: dialErr, expectedOpens: 3},
+		"gives up after retries":          {failures: 5, retries: 2, err: dialErr, expectedOpens: 3, expectError: true},
+		"retries disabled":                {failures: 1, retries: 0, err: dialErr, expectedOpens: 1, expectError: true},
+		"rejected credentials":            {failures: 1, retries: 3, err: &pq.Error{Code: "28P01"}, expectedOpens: 1, expectError: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures, opens := &atomic.Int32{}, &atomic.Int32{}
+			failures.Store(tt.failures)
+			driverName := "redshift-test-refusing-" + t.Name()
+			sql.Register(driverName, refusingDriver{failures: failures, opens: opens, err: tt.err})
+
+			config := NewConfig(driverName, t.Name(), "db", 1)
+			config.ConnectRetries = tt.retries
+			_, err := config.NewClient().Connect()
+			if tt.expectError != (err != nil) {
+				t.Fatalf("expected error: %t, got: %v", tt.expectError, err)
+			}
+			if opens.Load() != tt.expectedOpens {
+				t.Errorf("expected %d connection attempts, got %d", tt.expectedOpens, opens.Load())
+			}
+		})
+	}
+}
+
+func TestClientConnectContext_AbortsRetries(t *testing.T) {
+	backoff := connectRetryBackoff
+	connectRetryBackoff = time.Minute
+	t.Cleanup(func() { connectRetryBackoff = backoff })
+
+	failures, opens := &atomic.Int32{}, &atomic.Int32{}
+	failures.Store(5)
+	driverName := "redshift-test-refusing-" + t.Name()
+	sql.Register(driverName, refusingDriver{failures: failures, opens: opens, err: errors.New("dial tcp: i/o timeout")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	config := NewConfig(driverName, t.Name(), "db", 1)
+	config.ConnectRetries = 3
+	_, err := config.NewClient().ConnectContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be exceeded, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the backoff to be aborted, took %s", elapsed)
+	}
+	if opens.Load() != 1 {
+		t.Errorf("expected 1 connection attempt, got %d", opens.Load())
+	}
+}
+
+func TestIsRetryableConnectError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"network error":        {errors.New("dial tcp 10.0.0.1:5439: connect: connection refused"), true},
+		"server starting":      {&pq.Error{Code: "57P03"}, true},
+		"invalid password":     {&pq.Error{Code: "28P01"}, false},
+		"missing database":     {&pq.Error{Code: "3D000"}, false},
+		"wrapped invalid auth": {fmt.Errorf("could not connect: %w", &pq.Error{Code: "28000"}), false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := isRetryableConnectError(tt.err); actual != tt.expected {
+				t.Errorf("isRetryableConnectError(%v) = %t, want %t", tt.err, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -2,6 +2,11 @@ package redshift
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -14,3 +19,59 @@ func forceNewIfListSizeChanged(key string) schema.CustomizeDiffFunc {
 func listSizeChanged(_ context.Context, old, new, _ interface{}) bool {
 	return len(old.([]interface{})) != len(new.([]interface{}))
 }
+
+// validatePrivilegesDiff rejects privileges which can't be granted on the object type during plan,
+// instead of failing at apply time.
+func validatePrivilegesDiff(privilegesAttr, objectTypeAttr string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if !d.NewValueKnown(privilegesAttr) || !d.NewValueKnown(objectTypeAttr) {
+			return nil
+		}
+
+		objectType := d.Get(objectTypeAttr).(string)
+		privileges := setToStringList(d.Get(privilegesAttr).(*schema.Set))
+		if validatePrivileges(privileges, objectType) {
+			return nil
+		}
+
+		var invalidPrivileges []string
+		for _, privilege := range privileges {
+			if !validatePrivileges([]string{privilege}, objectType) {
+				invalidPrivileges = append(invalidPrivileges, privilege)
+			}
+		}
+		sort.Strings(invalidPrivileges)
+		return fmt.Errorf("invalid privileges %v for object type %q", invalidPrivileges, objectType)
+	}
+}
+
+// validateUserExistsDiff rejects a new or changed user name in attr which isn't a user of the database
+// during plan, if the provider is configured with validate_at_plan. The check is skipped when the
+// database can't be connected to, e.g. because it is created in the same plan.
+func validateUserExistsDiff(attr string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		client, ok := meta.(*Client)
+		if !ok || !client.validateAtPlan || !d.HasChange(attr) || !d.NewValueKnown(attr) {
+			return nil
+		}
+		user := d.Get(attr).(string)
+		if user == "" {
+			return nil
+		}
+
+		db, err := client.ConnectContext(ctx)
+		if err != nil {
+			log.Printf("[WARN] skipping plan-time check of %s %q: %v", attr, user, err)
+			return nil
+		}
+		var exists int
+		err = db.QueryRowContext(ctx, "SELECT 1 FROM pg_user WHERE usename = $1", user).Scan(&exists)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%s %q is not a user of the database", attr, user)
+		case err != nil:
+			return fmt.Errorf("could not check whether %s %q is a user of the database: %w", attr, user, err)
+		}
+		return nil
+	}
+}
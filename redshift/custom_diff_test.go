@@ -0,0 +1,87 @@
+package redshift
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidatePrivilegesDiff(t *testing.T) {
+	tests := map[string]struct {
+		resource    *schema.Resource
+		config      map[string]interface{}
+		expectedErr *regexp.Regexp
+	}{
+		"grant execute on table": {
+			resource:    redshiftGrant(),
+			config:      map[string]interface{}{"user": "alice", "schema": "sales", "object_type": "table", "privileges": []interface{}{"select", "execute"}},
+			expectedErr: regexp.MustCompile(`invalid privileges \[execute\] for object type "table"`),
+		},
+		"grant select on schema": {
+			resource:    redshiftGrant(),
+			config:      map[string]interface{}{"group": "analysts", "schema": "sales", "object_type": "schema", "privileges": []interface{}{"usage", "select"}},
+			expectedErr: regexp.MustCompile(`invalid privileges \[select\] for object type "schema"`),
+		},
+		"grant usage on function": {
+			resource:    redshiftGrant(),
+			config:      map[string]interface{}{"role": "etl", "schema": "sales", "object_type": "function", "privileges": []interface{}{"usage"}},
+			expectedErr: regexp.MustCompile(`invalid privileges \[usage\] for object type "function"`),
+		},
+		"grant usage on external function": {
+			resource:    redshiftGrant(),
+			config:      map[string]interface{}{"role": "etl", "schema": "sales", "object_type": "external_function", "objects": []interface{}{"my_lambda_udf(varchar)"}, "privileges": []interface{}{"usage", "execute"}},
+			expectedErr: regexp.MustCompile(`invalid privileges \[usage\] for object type "external_function"`),
+		},
+		"grant select on database": {
+			resource:    redshiftGrant(),
+			config:      map[string]interface{}{"user": "alice", "object_type": "database", "privileges": []interface{}{"select"}},
+			expectedErr: regexp.MustCompile(`invalid privileges \[select\] for object type "database"`),
+		},
+		"grant nothing on language": {
+			resource: redshiftGrant(),
+			config:   map[string]interface{}{"user": "alice", "object_type": "language", "objects": []interface{}{"plpythonu"}, "privileges": []interface{}{}},
+		},
+		"grant execute on procedure": {
+			resource: redshiftGrant(),
+			config:   map[string]interface{}{"user": "alice", "schema": "sales", "object_type": "procedure", "privileges": []interface{}{"EXECUTE"}},
+		},
+		"grant nothing on table": {
+			resource: redshiftGrant(),
+			config:   map[string]interface{}{"user": "alice", "schema": "sales", "object_type": "table", "privileges": []interface{}{}},
+		},
+		"default privileges execute on tables": {
+			resource:    redshiftDefaultPrivileges(),
+			config:      map[string]interface{}{"group": "analysts", "owner": "etl", "object_type": "table", "privileges": []interface{}{"execute", "usage", "select"}},
+			expectedErr: regexp.MustCompile(`invalid privileges \[execute usage\] for object type "table"`),
+		},
+		"default privileges nothing on functions": {
+			resource: redshiftDefaultPrivileges(),
+			config:   map[string]interface{}{"group": "analysts", "owner": "etl", "object_type": "function", "privileges": []interface{}{}},
+		},
+		"default privileges nothing on procedures": {
+			resource: redshiftDefaultPrivileges(),
+			config:   map[string]interface{}{"group": "analysts", "owner": "etl", "object_type": "procedure", "privileges": []interface{}{}},
+		},
+		"default privileges select on tables": {
+			resource: redshiftDefaultPrivileges(),
+			config:   map[string]interface{}{"group": "analysts", "owner": "etl", "object_type": "table", "privileges": []interface{}{"select", "insert"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tt.resource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			switch {
+			case tt.expectedErr == nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.expectedErr != nil && err == nil:
+				t.Errorf("expected an error matching %q", tt.expectedErr)
+			case tt.expectedErr != nil && !tt.expectedErr.MatchString(err.Error()):
+				t.Errorf("expected an error matching %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package redshift
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	capabilitiesVersionAttr                      = "version"
+	capabilitiesServerlessAttr                   = "serverless"
+	capabilitiesSvvCatalogAttr                   = "svv_catalog"
+	capabilitiesRolesAttr                        = "roles"
+	capabilitiesDefaultPrivilegesObjectTypesAttr = "default_privileges_object_types"
+)
+
+func dataSourceRedshiftCapabilities() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Probes the connected Redshift cluster or workgroup and exposes capability flags, so modules can enable features conditionally and stay portable across Redshift variants. The cluster is probed once per provider instance.
+`,
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftCapabilitiesRead),
+		Schema: map[string]*schema.Schema{
+			capabilitiesVersionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Redshift version, e.g. `1.0.77467`.",
+			},
+			capabilitiesServerlessAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the provider is connected to Redshift Serverless (or a Multi-AZ provisioned cluster, which behaves alike).",
+			},
+			capabilitiesSvvCatalogAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether reads use the `svv_*` system views, see the `catalog_mode` provider setting.",
+			},
+			capabilitiesRolesAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether roles (`redshift_role`, `redshift_role_grant`) are supported.",
+			},
+			capabilitiesDefaultPrivilegesObjectTypesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The object types `redshift_default_privileges` supports.",
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftCapabilitiesRead(db *DBConnection, d *schema.ResourceData) error {
+	capabilities, err := db.client.config.Capabilities(db)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(db.client.config.Database)
+	d.Set(capabilitiesVersionAttr, capabilities.version)
+	d.Set(capabilitiesServerlessAttr, capabilities.serverless)
+	d.Set(capabilitiesSvvCatalogAttr, capabilities.svvCatalog)
+	d.Set(capabilitiesRolesAttr, capabilities.roles)
+	d.Set(capabilitiesDefaultPrivilegesObjectTypesAttr, defaultPrivilegesAllowedObjectTypes)
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package redshift
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftCapabilities(t *testing.T) {
+	config := `
+data "redshift_capabilities" "capabilities" {}
+`
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.redshift_capabilities.capabilities", capabilitiesVersionAttr, regexp.MustCompile(`^\d+(\.\d+)*$`)),
+					resource.TestCheckResourceAttrSet("data.redshift_capabilities.capabilities", capabilitiesServerlessAttr),
+					resource.TestCheckResourceAttrSet("data.redshift_capabilities.capabilities", capabilitiesRolesAttr),
+					resource.TestCheckResourceAttr("data.redshift_capabilities.capabilities", capabilitiesDefaultPrivilegesObjectTypesAttr+".0", "table"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,61 @@
+package redshift
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	currentUserAttr        = "current_user"
+	currentSessionUserAttr = "session_user"
+	currentDatabaseAttr    = "database"
+	currentNamespaceAttr   = "namespace"
+)
+
+func dataSourceRedshiftCurrent() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Gets the user the provider is connected as, the connected database and the namespace of the cluster, e.g. to use the connected user as owner without hard-coding its name.
+`,
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftCurrentRead),
+		Schema: map[string]*schema.Schema{
+			currentUserAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the current user, whose privileges statements are checked against.",
+			},
+			currentSessionUserAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the user who started the session. It only differs from `current_user` after `SET SESSION AUTHORIZATION`.",
+			},
+			currentDatabaseAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the connected database.",
+			},
+			currentNamespaceAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The namespace (unique ID) of the cluster or workgroup, as returned by the `redshift_namespace` data source.",
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftCurrentRead(db *DBConnection, d *schema.ResourceData) error {
+	var currentUser, sessionUser, database, namespace string
+	query := "SELECT current_user, session_user, current_database(), CURRENT_NAMESPACE"
+	if err := db.QueryRow(query).Scan(&currentUser, &sessionUser, &database, &namespace); err != nil {
+		return fmt.Errorf("could not read the current session: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", namespace, database, currentUser))
+	d.Set(currentUserAttr, currentUser)
+	d.Set(currentSessionUserAttr, sessionUser)
+	d.Set(currentDatabaseAttr, database)
+	d.Set(currentNamespaceAttr, namespace)
+
+	return nil
+}
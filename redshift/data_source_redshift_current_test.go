@@ -0,0 +1,31 @@
+package redshift
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftCurrent(t *testing.T) {
+	config := `
+data "redshift_current" "current" {
+}
+`
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_current.current", "current_user", strings.ToLower(os.Getenv("REDSHIFT_USER"))),
+					resource.TestCheckResourceAttrPair("data.redshift_current.current", "session_user", "data.redshift_current.current", "current_user"),
+					resource.TestCheckResourceAttrSet("data.redshift_current.current", "database"),
+					resource.TestMatchResourceAttr("data.redshift_current.current", "namespace", uuidRegex),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,222 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	defaultPrivilegesEntriesAttr     = "entries"
+	defaultPrivilegesEntryIDAttr     = "id"
+	defaultPrivilegesGranteeAttr     = "grantee"
+	defaultPrivilegesGranteeTypeAttr = "grantee_type"
+	defaultPrivilegesGrantOptionAttr = "with_grant_option"
+
+	// defaultPrivilegesAllOwnersID is the ID of the data source when it lists the default privileges of all owners.
+	defaultPrivilegesAllOwnersID = "*"
+)
+
+// defaultPrivilegesCatalogObjectTypes maps the object types of svv_default_privileges to the
+// object types of redshift_default_privileges.
+var defaultPrivilegesCatalogObjectTypes = map[string]string{
+	"RELATION":  "table",
+	"FUNCTION":  "function",
+	"PROCEDURE": "procedure",
+}
+
+// defaultPrivilegesGranteeEntities maps the grantee types of svv_default_privileges to the
+// entity prefixes of default privileges IDs.
+var defaultPrivilegesGranteeEntities = map[string]string{
+	"group": "gn",
+	"user":  "un",
+	"role":  "rn",
+}
+
+func dataSourceRedshiftDefaultPrivileges() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Lists the default privileges defined for an owner, or for all owners, together with the IDs to import them as ` + "`redshift_default_privileges`" + ` resources. This helps adopting existing clusters and finding default privileges several owners define for the same grantee. Only entries of object types supported by the resource are listed.
+`,
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftDefaultPrivilegesRead),
+		Schema: map[string]*schema.Schema{
+			defaultPrivilegesOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the user for which the default privileges are defined. If not set, the default privileges of all owners are listed.",
+			},
+			defaultPrivilegesEntriesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The default privileges, one entry per owner, grantee, schema, object type and grant option, ordered by owner, grantee type, grantee, object type and schema.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						defaultPrivilegesEntryIDAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID to import the entry as `redshift_default_privileges` resource.",
+						},
+						defaultPrivilegesOwnerAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the user for which the default privileges are defined.",
+						},
+						defaultPrivilegesGranteeTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the grantee, one of `user`, `group` or `role`.",
+						},
+						defaultPrivilegesGranteeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the grantee.",
+						},
+						defaultPrivilegesSchemaAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The schema the default privileges apply to, empty if they apply to the entire database.",
+						},
+						defaultPrivilegesObjectTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The object type the default privileges apply to.",
+						},
+						defaultPrivilegesPrivilegesAttr: {
+							Type:        schema.TypeSet,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "The privileges granted by default.",
+						},
+						defaultPrivilegesGrantOptionAttr: {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the privileges are granted with grant option. Such entries can't be imported as `redshift_default_privileges` resources.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// defaultPrivilege is a single privilege row of svv_default_privileges.
+type defaultPrivilege struct {
+	owner           string
+	granteeType     string
+	grantee         string
+	schema          string
+	objectType      string
+	privilege       string
+	withGrantOption bool
+}
+
+func dataSourceRedshiftDefaultPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
+	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := `
+SELECT u.usename, dp.grantee_type, dp.grantee_name, COALESCE(dp.schema_name, ''), dp.object_type, dp.privilege_type, COALESCE(dp.admin_option, false)
+FROM svv_default_privileges dp
+JOIN pg_user u ON u.usesysid = dp.owner_id`
+	var queryArgs []interface{}
+	if ownerName != "" {
+		ownerID, err := getUserIDFromName(tx, ownerName)
+		if err != nil {
+			return fmt.Errorf("failed to get user ID of owner %q: %w", ownerName, err)
+		}
+		query += `
+WHERE dp.owner_id = $1`
+		queryArgs = append(queryArgs, ownerID)
+	}
+	query += `
+ORDER BY u.usename, dp.grantee_type, dp.grantee_name, dp.object_type, dp.schema_name, dp.privilege_type`
+
+	rows, err := tx.Query(query, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to read default privileges: %w", err)
+	}
+	defer rows.Close()
+
+	var privileges []defaultPrivilege
+	for rows.Next() {
+		var privilege defaultPrivilege
+		if err := rows.Scan(&privilege.owner, &privilege.granteeType, &privilege.grantee, &privilege.schema, &privilege.objectType, &privilege.privilege, &privilege.withGrantOption); err != nil {
+			return fmt.Errorf("failed to read default privileges: %w", err)
+		}
+		privileges = append(privileges, privilege)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read default privileges: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	if ownerName == "" {
+		d.SetId(defaultPrivilegesAllOwnersID)
+	} else {
+		d.SetId(ownerName)
+	}
+	d.Set(defaultPrivilegesEntriesAttr, groupDefaultPrivileges(privileges))
+
+	return nil
+}
+
+// groupDefaultPrivileges groups the privileges by owner, grantee, schema, object type and grant option,
+// keeping their order. Privileges which can't be managed by redshift_default_privileges are skipped.
+func groupDefaultPrivileges(privileges []defaultPrivilege) []map[string]interface{} {
+	entries := make([]map[string]interface{}, 0)
+	entriesByID := map[string]map[string]interface{}{}
+	for _, privilege := range privileges {
+		entity, ok := defaultPrivilegesGranteeEntities[strings.ToLower(privilege.granteeType)]
+		objectType := defaultPrivilegesCatalogObjectTypes[privilege.objectType]
+		if !ok || !slices.Contains(defaultPrivilegesAllowedObjectTypes, objectType) {
+			log.Printf("[DEBUG] Skipping default privilege %s on %s for %s %s", privilege.privilege, privilege.objectType, privilege.granteeType, privilege.grantee)
+			continue
+		}
+
+		parsedID := defaultPrivilegesID{
+			entity:          fmt.Sprintf("%s:%s", entity, privilege.grantee),
+			schema:          privilege.schema,
+			owner:           privilege.owner,
+			objectType:      objectType,
+			withGrantOption: privilege.withGrantOption,
+		}
+		id := parsedID.String()
+		// Names containing the separators of the ID format can make the ID ambiguous.
+		if reparsedID, err := parseDefaultPrivilegesID(id); err != nil || !reflect.DeepEqual(reparsedID, parsedID) {
+			log.Printf("[WARN] Skipping default privileges %q, the names can't be expressed in an import ID", id)
+			continue
+		}
+
+		entry, ok := entriesByID[id]
+		if !ok {
+			entry = map[string]interface{}{
+				defaultPrivilegesEntryIDAttr:     id,
+				defaultPrivilegesOwnerAttr:       privilege.owner,
+				defaultPrivilegesGranteeTypeAttr: privilege.granteeType,
+				defaultPrivilegesGranteeAttr:     privilege.grantee,
+				defaultPrivilegesSchemaAttr:      privilege.schema,
+				defaultPrivilegesObjectTypeAttr:  objectType,
+				defaultPrivilegesPrivilegesAttr:  []string{},
+				defaultPrivilegesGrantOptionAttr: privilege.withGrantOption,
+			}
+			entriesByID[id] = entry
+			entries = append(entries, entry)
+		}
+		entry[defaultPrivilegesPrivilegesAttr] = append(entry[defaultPrivilegesPrivilegesAttr].([]string), strings.ToLower(privilege.privilege))
+	}
+
+	return entries
+}
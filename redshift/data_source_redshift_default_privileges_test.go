@@ -0,0 +1,244 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccDataSourceRedshiftDefaultPrivileges_Basic(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	rootUsername := getRootUsername()
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_default_privileges" "group" {
+  group       = redshift_group.group.name
+  owner       = %[2]q
+  object_type = "table"
+  privileges  = ["select", "insert"]
+}
+
+data "redshift_default_privileges" "owner" {
+  owner = %[2]q
+
+  depends_on = [redshift_default_privileges.group]
+}
+`, groupName, rootUsername)
+	expectedID := fmt.Sprintf("gn:%s_noschema_on:%s_ot:table", groupName, rootUsername)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckDefaultPrivilegesDestory(defaultPrivilegesAllSchemasID, 100, "r", groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.redshift_default_privileges.owner", "entries.*", map[string]string{
+						"id":                expectedID,
+						"owner":             rootUsername,
+						"grantee_type":      "group",
+						"grantee":           groupName,
+						"schema":            "",
+						"object_type":       "table",
+						"privileges.#":      "2",
+						"with_grant_option": "false",
+					}),
+				),
+			},
+			{
+				ResourceName:      "redshift_default_privileges.group",
+				ImportState:       true,
+				ImportStateId:     expectedID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGroupDefaultPrivileges(t *testing.T) {
+	privileges := []defaultPrivilege{
+		{owner: "owner", granteeType: "group", grantee: "analysts", objectType: "RELATION", privilege: "INSERT"},
+		{owner: "owner", granteeType: "group", grantee: "analysts", objectType: "RELATION", privilege: "SELECT"},
+		{owner: "owner", granteeType: "group", grantee: "analysts", schema: "sales", objectType: "RELATION", privilege: "SELECT"},
+		{owner: "owner", granteeType: "role", grantee: "etl_role", objectType: "RELATION", privilege: "UPDATE"},
+		{owner: "owner", granteeType: "user", grantee: "john", objectType: "FUNCTION", privilege: "EXECUTE"},
+		{owner: "owner", granteeType: "user", grantee: "john@example.com", objectType: "RELATION", privilege: "SELECT"},
+	}
+
+	entries := groupDefaultPrivileges(privileges)
+
+	expected := []map[string]interface{}{
+		{
+			"id":                "gn:analysts_noschema_on:owner_ot:table",
+			"owner":             "owner",
+			"grantee_type":      "group",
+			"grantee":           "analysts",
+			"schema":            "",
+			"object_type":       "table",
+			"privileges":        []string{"insert", "select"},
+			"with_grant_option": false,
+		},
+		{
+			"id":                "gn:analysts_sn:sales_on:owner_ot:table",
+			"owner":             "owner",
+			"grantee_type":      "group",
+			"grantee":           "analysts",
+			"schema":            "sales",
+			"object_type":       "table",
+			"privileges":        []string{"select"},
+			"with_grant_option": false,
+		},
+		{
+			"id":                "rn:etl_role_noschema_on:owner_ot:table",
+			"owner":             "owner",
+			"grantee_type":      "role",
+			"grantee":           "etl_role",
+			"schema":            "",
+			"object_type":       "table",
+			"privileges":        []string{"update"},
+			"with_grant_option": false,
+		},
+		{
+			"id":                "un:john_noschema_on:owner_ot:function",
+			"owner":             "owner",
+			"grantee_type":      "user",
+			"grantee":           "john",
+			"schema":            "",
+			"object_type":       "function",
+			"privileges":        []string{"execute"},
+			"with_grant_option": false,
+		},
+		{
+			"id":                "un:john@example.com_noschema_on:owner_ot:table",
+			"owner":             "owner",
+			"grantee_type":      "user",
+			"grantee":           "john@example.com",
+			"schema":            "",
+			"object_type":       "table",
+			"privileges":        []string{"select"},
+			"with_grant_option": false,
+		},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected entries\n%v\nbut got\n%v", expected, entries)
+	}
+}
+
+// TestGroupDefaultPrivileges_ImportIDs checks that the emitted IDs import into the entry they were built from.
+func TestGroupDefaultPrivileges_ImportIDs(t *testing.T) {
+	privileges := []defaultPrivilege{
+		{owner: "owner_name", granteeType: "group", grantee: "analysts", schema: "sales_sn:eu", objectType: "RELATION", privilege: "SELECT"},
+		{owner: "owner_name", granteeType: "role", grantee: "etl_role", objectType: "RELATION", privilege: "SELECT"},
+		{owner: "owner_name", granteeType: "user", grantee: "john_doe@example.com", schema: "public", objectType: "RELATION", privilege: "SELECT"},
+	}
+	granteeAttrs := map[string]string{
+		"group": defaultPrivilegesGroupAttr,
+		"role":  defaultPrivilegesRoleAttr,
+		"user":  defaultPrivilegesUserAttr,
+	}
+
+	entries := groupDefaultPrivileges(privileges)
+	// The schema name of the first privilege makes its ID ambiguous, so it is skipped.
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries but got %v", entries)
+	}
+
+	for _, entry := range entries {
+		id := entry[defaultPrivilegesEntryIDAttr].(string)
+		t.Run(id, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{})
+			d.SetId(id)
+
+			imported, err := resourceRedshiftDefaultPrivilegesImport(context.Background(), d, nil)
+			if err != nil {
+				t.Fatalf("unexpected error importing %q: %v", id, err)
+			}
+			importedData := imported[0]
+
+			if grantee := importedData.Get(granteeAttrs[entry[defaultPrivilegesGranteeTypeAttr].(string)]); grantee != entry[defaultPrivilegesGranteeAttr] {
+				t.Errorf("Expected grantee %q but got %q", entry[defaultPrivilegesGranteeAttr], grantee)
+			}
+			if schemaName := importedData.Get(defaultPrivilegesSchemaAttr); schemaName != entry[defaultPrivilegesSchemaAttr] {
+				t.Errorf("Expected schema %q but got %q", entry[defaultPrivilegesSchemaAttr], schemaName)
+			}
+			if owner := importedData.Get(defaultPrivilegesOwnerAttr); owner != "owner_name" {
+				t.Errorf("Expected owner %q but got %q", "owner_name", owner)
+			}
+			if generated := generateDefaultPrivilegesID(importedData); generated != id {
+				t.Errorf("Expected the imported resource to have ID %q but got %q", id, generated)
+			}
+		})
+	}
+}
+
+func TestResourceRedshiftDefaultPrivilegesImport_Invalid(t *testing.T) {
+	for _, id := range []string{
+		"not-an-id",
+		"gn:analysts_noschema_on:owner_ot:table_wgo",
+		"gn:analysts_noschema_on:owner_ot:sequence",
+	} {
+		t.Run(id, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{})
+			d.SetId(id)
+			if _, err := resourceRedshiftDefaultPrivilegesImport(context.Background(), d, nil); err == nil {
+				t.Errorf("Expected importing %q to fail", id)
+			}
+		})
+	}
+}
+
+func TestGroupDefaultPrivileges_OwnersAndGrantOption(t *testing.T) {
+	privileges := []defaultPrivilege{
+		{owner: "admin", granteeType: "group", grantee: "analysts", objectType: "RELATION", privilege: "SELECT"},
+		{owner: "etl", granteeType: "group", grantee: "analysts", objectType: "RELATION", privilege: "SELECT"},
+		{owner: "etl", granteeType: "group", grantee: "analysts", objectType: "RELATION", privilege: "INSERT", withGrantOption: true},
+	}
+
+	entries := groupDefaultPrivileges(privileges)
+
+	expected := []map[string]interface{}{
+		{
+			"id":                "gn:analysts_noschema_on:admin_ot:table",
+			"owner":             "admin",
+			"grantee_type":      "group",
+			"grantee":           "analysts",
+			"schema":            "",
+			"object_type":       "table",
+			"privileges":        []string{"select"},
+			"with_grant_option": false,
+		},
+		{
+			"id":                "gn:analysts_noschema_on:etl_ot:table",
+			"owner":             "etl",
+			"grantee_type":      "group",
+			"grantee":           "analysts",
+			"schema":            "",
+			"object_type":       "table",
+			"privileges":        []string{"select"},
+			"with_grant_option": false,
+		},
+		{
+			"id":                "gn:analysts_noschema_on:etl_ot:table_wgo",
+			"owner":             "etl",
+			"grantee_type":      "group",
+			"grantee":           "analysts",
+			"schema":            "",
+			"object_type":       "table",
+			"privileges":        []string{"insert"},
+			"with_grant_option": true,
+		},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected entries\n%v\nbut got\n%v", expected, entries)
+	}
+}
@@ -7,7 +7,7 @@ import (
 func dataSourceRedshiftNamespace() *schema.Resource {
 	return &schema.Resource{
 		Description: `Gets the cluster namespace (unique ID) of the Amazon Redshift cluster.`,
-		ReadContext: ResourceFunc(dataSourceRedshiftNamespaceRead),
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftNamespaceRead),
 		Schema:      map[string]*schema.Schema{},
 	}
 }
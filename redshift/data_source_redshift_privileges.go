@@ -0,0 +1,206 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	privilegesGranteeAttr     = "grantee"
+	privilegesGranteeTypeAttr = "grantee_type"
+	privilegesDatabasesAttr   = "databases"
+	privilegesSchemasAttr     = "schemas"
+	privilegesTablesAttr      = "tables"
+	privilegesFunctionsAttr   = "functions"
+	privilegesPrivilegesAttr  = "privileges"
+)
+
+func dataSourceRedshiftPrivileges() *schema.Resource {
+	privilegesSchema := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeSet,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+			Description: description,
+		}
+	}
+	computedString := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: description,
+		}
+	}
+
+	return &schema.Resource{
+		Description: `
+Lists all privileges a user, group or role holds on databases, schemas, tables, views, functions and procedures, as reported by the ` + "`svv_*_privileges`" + ` system views. This allows asserting least privilege, e.g. in policy checks, without importing every grant.
+`,
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftPrivilegesRead),
+		Schema: map[string]*schema.Schema{
+			privilegesGranteeAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the user, group or role.",
+			},
+			privilegesGranteeTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The type of the grantee, one of `user`, `group` or `role`.",
+				ValidateFunc: validation.StringInSlice([]string{"user", "group", "role"}, false),
+			},
+			privilegesDatabasesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The privileges on databases, ordered by database.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"database":               computedString("The name of the database."),
+						privilegesPrivilegesAttr: privilegesSchema("The privileges on the database."),
+					},
+				},
+			},
+			privilegesSchemasAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The privileges on schemas, ordered by schema.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schema":                 computedString("The name of the schema."),
+						privilegesPrivilegesAttr: privilegesSchema("The privileges on the schema."),
+					},
+				},
+			},
+			privilegesTablesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The privileges on tables and views, ordered by schema and table.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schema":                 computedString("The name of the schema of the table."),
+						"table":                  computedString("The name of the table or view."),
+						privilegesPrivilegesAttr: privilegesSchema("The privileges on the table."),
+					},
+				},
+			},
+			privilegesFunctionsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The privileges on functions and procedures, ordered by schema, name and arguments.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schema":                 computedString("The name of the schema of the function."),
+						"function":               computedString("The name of the function or procedure."),
+						"arguments":              computedString("The argument types of the function, e.g. `integer, character varying`."),
+						privilegesPrivilegesAttr: privilegesSchema("The privileges on the function."),
+					},
+				},
+			},
+		},
+	}
+}
+
+const granteeAllPrivilegesQuery = `
+SELECT 'database', database_name, '', '', privilege_type
+FROM svv_database_privileges
+WHERE identity_type = $1 AND identity_name = $2
+UNION ALL
+SELECT 'schema', namespace_name, '', '', privilege_type
+FROM svv_schema_privileges
+WHERE identity_type = $1 AND identity_name = $2
+UNION ALL
+SELECT 'table', namespace_name, relation_name, '', privilege_type
+FROM svv_relation_privileges
+WHERE identity_type = $1 AND identity_name = $2
+UNION ALL
+SELECT 'function', namespace_name, function_name, COALESCE(argument_types, ''), privilege_type
+FROM svv_function_privileges
+WHERE identity_type = $1 AND identity_name = $2
+ORDER BY 1, 2, 3, 4, 5`
+
+// granteePrivilege is a single privilege row of granteeAllPrivilegesQuery. For databases, container
+// holds the database name, otherwise the schema name.
+type granteePrivilege struct {
+	kind      string
+	container string
+	name      string
+	args      string
+	privilege string
+}
+
+func dataSourceRedshiftPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
+	grantee := d.Get(privilegesGranteeAttr).(string)
+	granteeType := d.Get(privilegesGranteeTypeAttr).(string)
+
+	rows, err := db.Query(granteeAllPrivilegesQuery, granteeType, grantee)
+	if err != nil {
+		return fmt.Errorf("could not read privileges of %s %q: %w", granteeType, grantee, err)
+	}
+	defer rows.Close()
+
+	var privileges []granteePrivilege
+	for rows.Next() {
+		var privilege granteePrivilege
+		if err := rows.Scan(&privilege.kind, &privilege.container, &privilege.name, &privilege.args, &privilege.privilege); err != nil {
+			return fmt.Errorf("could not read privileges of %s %q: %w", granteeType, grantee, err)
+		}
+		privileges = append(privileges, privilege)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not read privileges of %s %q: %w", granteeType, grantee, err)
+	}
+
+	for attr, entries := range groupGranteePrivileges(privileges) {
+		if err := d.Set(attr, entries); err != nil {
+			return fmt.Errorf("could not set %s: %w", attr, err)
+		}
+	}
+	d.SetId(fmt.Sprintf("%s:%s", granteeType, grantee))
+
+	return nil
+}
+
+// groupGranteePrivileges groups the ordered privilege rows by object, keyed by the attribute of the object kind.
+func groupGranteePrivileges(privileges []granteePrivilege) map[string][]map[string]interface{} {
+	entries := map[string][]map[string]interface{}{
+		privilegesDatabasesAttr: {},
+		privilegesSchemasAttr:   {},
+		privilegesTablesAttr:    {},
+		privilegesFunctionsAttr: {},
+	}
+
+	var previous *granteePrivilege
+	for i, privilege := range privileges {
+		var attr string
+		var entry map[string]interface{}
+		switch privilege.kind {
+		case "database":
+			attr, entry = privilegesDatabasesAttr, map[string]interface{}{"database": privilege.container}
+		case "schema":
+			attr, entry = privilegesSchemasAttr, map[string]interface{}{"schema": privilege.container}
+		case "table":
+			attr, entry = privilegesTablesAttr, map[string]interface{}{"schema": privilege.container, "table": privilege.name}
+		case "function":
+			attr, entry = privilegesFunctionsAttr, map[string]interface{}{"schema": privilege.container, "function": privilege.name, "arguments": privilege.args}
+		default:
+			continue
+		}
+
+		// Rows are ordered, so the privileges of an object are adjacent.
+		sameObject := previous != nil && previous.kind == privilege.kind && previous.container == privilege.container &&
+			previous.name == privilege.name && previous.args == privilege.args
+		if !sameObject {
+			entry[privilegesPrivilegesAttr] = []string{}
+			entries[attr] = append(entries[attr], entry)
+		}
+		last := entries[attr][len(entries[attr])-1]
+		last[privilegesPrivilegesAttr] = append(last[privilegesPrivilegesAttr].([]string), strings.ToLower(privilege.privilege))
+		previous = &privileges[i]
+	}
+
+	return entries
+}
@@ -0,0 +1,106 @@
+package redshift
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftPrivileges_Basic(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_grant" "schema" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  object_type = "schema"
+  privileges  = ["usage", "create"]
+}
+
+resource "redshift_grant" "database" {
+  role        = redshift_role.role.name
+  object_type = "database"
+  privileges  = ["temporary"]
+}
+
+data "redshift_privileges" "role" {
+  grantee      = redshift_role.role.name
+  grantee_type = "role"
+
+  depends_on = [redshift_grant.schema, redshift_grant.database]
+}
+`, roleName, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_privileges.role", "id", fmt.Sprintf("role:%s", roleName)),
+					resource.TestCheckTypeSetElemNestedAttrs("data.redshift_privileges.role", "schemas.*", map[string]string{
+						"schema":       schemaName,
+						"privileges.#": "2",
+					}),
+					resource.TestCheckTypeSetElemAttr("data.redshift_privileges.role", "databases.0.privileges.*", "temp"),
+					resource.TestCheckResourceAttr("data.redshift_privileges.role", "tables.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestGroupGranteePrivileges(t *testing.T) {
+	privileges := []granteePrivilege{
+		{kind: "database", container: "dev", privilege: "TEMP"},
+		{kind: "function", container: "sales", name: "tax", args: "double precision", privilege: "EXECUTE"},
+		{kind: "function", container: "sales", name: "tax", args: "integer", privilege: "EXECUTE"},
+		{kind: "schema", container: "sales", privilege: "CREATE"},
+		{kind: "schema", container: "sales", privilege: "USAGE"},
+		{kind: "table", container: "sales", name: "orders", privilege: "INSERT"},
+		{kind: "table", container: "sales", name: "orders", privilege: "SELECT"},
+		{kind: "table", container: "sales", name: "refunds", privilege: "SELECT"},
+	}
+
+	expected := map[string][]map[string]interface{}{
+		privilegesDatabasesAttr: {
+			{"database": "dev", "privileges": []string{"temp"}},
+		},
+		privilegesSchemasAttr: {
+			{"schema": "sales", "privileges": []string{"create", "usage"}},
+		},
+		privilegesTablesAttr: {
+			{"schema": "sales", "table": "orders", "privileges": []string{"insert", "select"}},
+			{"schema": "sales", "table": "refunds", "privileges": []string{"select"}},
+		},
+		privilegesFunctionsAttr: {
+			{"schema": "sales", "function": "tax", "arguments": "double precision", "privileges": []string{"execute"}},
+			{"schema": "sales", "function": "tax", "arguments": "integer", "privileges": []string{"execute"}},
+		},
+	}
+
+	if got := groupGranteePrivileges(privileges); !reflect.DeepEqual(got, expected) {
+		t.Errorf("groupGranteePrivileges() = %v, want %v", got, expected)
+	}
+}
+
+func TestGroupGranteePrivileges_Empty(t *testing.T) {
+	for attr, entries := range groupGranteePrivileges(nil) {
+		if entries == nil || len(entries) != 0 {
+			t.Errorf("expected %s to be an empty list, got %v", attr, entries)
+		}
+	}
+}
@@ -12,7 +12,7 @@ func dataSourceRedshiftSchema() *schema.Resource {
 		Description: `
 A database contains one or more named schemas. Each schema in a database contains tables and other kinds of named objects. By default, a database has a single schema, which is named PUBLIC. You can use schemas to group database objects under a common name. Schemas are similar to file system directories, except that schemas cannot be nested.
 `,
-		ReadContext: ResourceFunc(dataSourceRedshiftSchemaRead),
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftSchemaRead),
 		Schema: map[string]*schema.Schema{
 			schemaNameAttr: {
 				Type:        schema.TypeString,
@@ -30,7 +30,12 @@ A database contains one or more named schemas. Each schema in a database contain
 			schemaQuotaAttr: {
 				Type:        schema.TypeInt,
 				Computed:    true,
-				Description: "The maximum amount of disk space that the specified schema can use. GB is the default unit of measurement.",
+				Description: "The maximum amount of disk space that the specified schema can use, in the unit of `quota_unit`. `0` means unlimited.",
+			},
+			schemaQuotaUnitAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unit of `quota`, always `MB`, the unit Redshift stores quotas in.",
 			},
 			schemaExternalSchemaAttr: {
 				Type:        schema.TypeList,
@@ -0,0 +1,76 @@
+package redshift
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceRedshiftSchemaDefaultPrivileges() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Looks up the default privileges an owner defines for a grantee on an object type, in a schema or for the entire database. This allows asserting that default privileges are correct without managing them, e.g. while migrating a cluster to Terraform. The ID is the one to import the default privileges as ` + "`redshift_default_privileges`" + ` resource.
+`,
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftSchemaDefaultPrivilegesRead),
+		Schema: map[string]*schema.Schema{
+			defaultPrivilegesSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The schema the default privileges apply to. If not set, the default privileges which apply to the entire database are looked up.",
+			},
+			defaultPrivilegesGroupAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr, defaultPrivilegesRoleAttr},
+				Description:  "The name of the group the default privileges are granted to.",
+			},
+			defaultPrivilegesUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr, defaultPrivilegesRoleAttr},
+				Description:  "The name of the user the default privileges are granted to.",
+			},
+			defaultPrivilegesRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr, defaultPrivilegesRoleAttr},
+				Description:  "The name of the role the default privileges are granted to.",
+			},
+			defaultPrivilegesOwnerAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the user for which the default privileges are defined.",
+			},
+			defaultPrivilegesObjectTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(defaultPrivilegesAllowedObjectTypes, false),
+				Description:  "The Redshift object type the default privileges apply to (one of: " + strings.Join(defaultPrivilegesAllowedObjectTypes, ", ") + ").",
+			},
+			defaultPrivilegesPrivilegesAttr: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The privileges granted by default, empty if the owner defines none for the grantee.",
+			},
+			defaultPrivilegesOtherOwnersAttr: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The other users which define default privileges for the same grantee, schema and object type.",
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftSchemaDefaultPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
+	if err := readDefaultPrivileges(db, d, d.Get(defaultPrivilegesObjectTypeAttr).(string)); err != nil {
+		return err
+	}
+	d.SetId(generateDefaultPrivilegesID(d))
+
+	return nil
+}
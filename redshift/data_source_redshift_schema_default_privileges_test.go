@@ -0,0 +1,69 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftSchemaDefaultPrivileges_Basic(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	rootUsername := getRootUsername()
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_default_privileges" "group" {
+  group       = redshift_group.group.name
+  schema      = redshift_schema.schema.name
+  owner       = %[3]q
+  object_type = "table"
+  privileges  = ["select", "insert"]
+}
+
+data "redshift_schema_default_privileges" "tables" {
+  group       = redshift_group.group.name
+  schema      = redshift_schema.schema.name
+  owner       = %[3]q
+  object_type = "table"
+
+  depends_on = [redshift_default_privileges.group]
+}
+
+data "redshift_schema_default_privileges" "functions" {
+  group       = redshift_group.group.name
+  schema      = redshift_schema.schema.name
+  owner       = %[3]q
+  object_type = "function"
+
+  depends_on = [redshift_default_privileges.group]
+}
+`, groupName, schemaName, rootUsername)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_schema_default_privileges.tables", "id", fmt.Sprintf("gn:%s_sn:%s_on:%s_ot:table", groupName, schemaName, rootUsername)),
+					resource.TestCheckResourceAttrPair("data.redshift_schema_default_privileges.tables", "id", "redshift_default_privileges.group", "id"),
+					resource.TestCheckResourceAttr("data.redshift_schema_default_privileges.tables", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("data.redshift_schema_default_privileges.tables", "privileges.*", "select"),
+					resource.TestCheckTypeSetElemAttr("data.redshift_schema_default_privileges.tables", "privileges.*", "insert"),
+					resource.TestCheckResourceAttr("data.redshift_schema_default_privileges.functions", "privileges.#", "0"),
+				),
+			},
+		},
+	})
+}
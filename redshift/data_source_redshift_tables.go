@@ -0,0 +1,137 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	tablesSchemaAttr    = "schema"
+	tablesTableTypeAttr = "table_type"
+	tablesTablesAttr    = "tables"
+	tablesNameAttr      = "name"
+	tablesTypeAttr      = "type"
+)
+
+var tablesAllowedTableTypes = []string{
+	"TABLE",
+	"VIEW",
+	"EXTERNAL TABLE",
+}
+
+// listTablesQuery lists the relations of schema $2 in database $1. The pg variant only
+// sees the database the provider is connected to and does not include external tables.
+var listTablesQuery = catalogQuery{
+	pg: `
+SELECT c.relname AS table_name, CASE c.relkind WHEN 'v' THEN 'VIEW' ELSE 'TABLE' END AS table_type
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE current_database() = $1
+  AND n.nspname = $2
+  AND c.relkind IN ('r', 'v')
+  AND c.relname NOT LIKE 'mv\_tbl\_\_%'`,
+	svv: `
+SELECT table_name, table_type
+FROM svv_all_tables
+WHERE database_name = $1
+  AND schema_name = $2`,
+}
+
+func dataSourceRedshiftTables() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Lists the tables and views in a schema of the database the provider is connected to. This can be used to drive ` + "`for_each`" + ` grant configurations without hard-coding table lists.
+`,
+		ReadContext: IdempotentResourceFunc(dataSourceRedshiftTablesRead),
+		Schema: map[string]*schema.Schema{
+			tablesSchemaAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the schema to list the tables of.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			tablesTableTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "If set, only tables of this type are returned (one of: " + strings.Join(tablesAllowedTableTypes, ", ") + ").",
+				ValidateFunc: validation.StringInSlice(tablesAllowedTableTypes, false),
+			},
+			tablesTablesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The tables found in the schema, ordered by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						tablesNameAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the table.",
+						},
+						tablesTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the table, e.g. `TABLE`, `VIEW` or `EXTERNAL TABLE`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftTablesRead(db *DBConnection, d *schema.ResourceData) error {
+	schemaName := strings.ToLower(d.Get(tablesSchemaAttr).(string))
+	tableType := d.Get(tablesTableTypeAttr).(string)
+
+	queryArgs := []interface{}{db.client.config.Database, schemaName}
+	var tableTypeFilter string
+	if tableType != "" {
+		tableTypeFilter = "AND table_type = $3"
+		queryArgs = append(queryArgs, tableType)
+	}
+
+	query := fmt.Sprintf(`
+SELECT table_name, table_type
+FROM (%s) t
+WHERE true
+  %s
+ORDER BY table_name`, db.catalogQuery(listTablesQuery), tableTypeFilter)
+
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("could not list tables in schema %q: %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	tables := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		var name, objectType string
+		if err := rows.Scan(&name, &objectType); err != nil {
+			return fmt.Errorf("could not read tables in schema %q: %w", schemaName, err)
+		}
+		tables = append(tables, map[string]interface{}{
+			tablesNameAttr: name,
+			tablesTypeAttr: objectType,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not read tables in schema %q: %w", schemaName, err)
+	}
+
+	d.SetId(generateTablesID(schemaName, tableType))
+	d.Set(tablesTablesAttr, tables)
+
+	return nil
+}
+
+func generateTablesID(schemaName, tableType string) string {
+	if tableType == "" {
+		return schemaName
+	}
+	return fmt.Sprintf("%s_%s", schemaName, strings.ToLower(strings.ReplaceAll(tableType, " ", "_")))
+}
@@ -0,0 +1,93 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/lib/pq"
+)
+
+func TestAccDataSourceRedshiftTables_basic(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_data_tables"), "-", "_")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: `data "redshift_namespace" "ns" {}`,
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						if err := testAccRedshiftGrantCreateSchemaTables(db, schemaName, "tbl_b", "tbl_a"); err != nil {
+							return err
+						}
+						_, err := db.Exec(fmt.Sprintf("CREATE VIEW %[1]s.view_c AS SELECT id FROM %[1]s.tbl_a", pq.QuoteIdentifier(schemaName)))
+						return err
+					})
+				},
+				Config: testAccDataSourceRedshiftTablesConfig(schemaName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_tables.all", "id", schemaName),
+					resource.TestCheckResourceAttr("data.redshift_tables.all", fmt.Sprintf("%s.#", tablesTablesAttr), "3"),
+					resource.TestCheckResourceAttr("data.redshift_tables.all", fmt.Sprintf("%s.0.%s", tablesTablesAttr, tablesNameAttr), "tbl_a"),
+					resource.TestCheckResourceAttr("data.redshift_tables.all", fmt.Sprintf("%s.0.%s", tablesTablesAttr, tablesTypeAttr), "TABLE"),
+					resource.TestCheckResourceAttr("data.redshift_tables.all", fmt.Sprintf("%s.2.%s", tablesTablesAttr, tablesNameAttr), "view_c"),
+					resource.TestCheckResourceAttr("data.redshift_tables.all", fmt.Sprintf("%s.2.%s", tablesTablesAttr, tablesTypeAttr), "VIEW"),
+
+					resource.TestCheckResourceAttr("data.redshift_tables.views", "id", schemaName+"_view"),
+					resource.TestCheckResourceAttr("data.redshift_tables.views", fmt.Sprintf("%s.#", tablesTablesAttr), "1"),
+					resource.TestCheckResourceAttr("data.redshift_tables.views", fmt.Sprintf("%s.0.%s", tablesTablesAttr, tablesNameAttr), "view_c"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceRedshiftTablesConfig(schemaName string) string {
+	return fmt.Sprintf(`
+data "redshift_tables" "all" {
+	%[1]s = %[2]q
+}
+
+data "redshift_tables" "views" {
+	%[1]s = %[2]q
+	%[3]s = "VIEW"
+}
+`, tablesSchemaAttr, schemaName, tablesTableTypeAttr)
+}
+
+func TestGenerateTablesID(t *testing.T) {
+	tests := map[string]struct {
+		schemaName string
+		tableType  string
+		expected   string
+	}{
+		"no table type": {
+			schemaName: "public",
+			expected:   "public",
+		},
+		"table": {
+			schemaName: "public",
+			tableType:  "TABLE",
+			expected:   "public_table",
+		},
+		"external table": {
+			schemaName: "spectrum",
+			tableType:  "EXTERNAL TABLE",
+			expected:   "spectrum_external_table",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if result := generateTablesID(tt.schemaName, tt.tableType); result != tt.expected {
+				t.Errorf("Expected ID to be %q but got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// granteePrivileges holds the privileges of a grantee on a schema and the objects in it,
+// as read in one pass by readGranteePrivileges.
+type granteePrivileges struct {
+	schema *schema.Set
+	// tables holds every table of the schema, with the privileges of the grantee on it.
+	tables map[string]*schema.Set
+	// callables holds the functions and procedures the grantee holds privileges on.
+	callables []callablePrivileges
+}
+
+type callablePrivileges struct {
+	name       string
+	args       string
+	privileges *schema.Set
+}
+
+func newGranteePrivileges() *granteePrivileges {
+	return &granteePrivileges{
+		schema: schema.NewSet(schema.HashString, nil),
+		tables: map[string]*schema.Set{},
+	}
+}
+
+func (p *granteePrivileges) addCallablePrivilege(name, args, privilege string) {
+	for _, callable := range p.callables {
+		if callable.name == name && callable.args == args {
+			callable.privileges.Add(privilege)
+			return
+		}
+	}
+	p.callables = append(p.callables, callablePrivileges{
+		name:       name,
+		args:       args,
+		privileges: schema.NewSet(schema.HashString, []interface{}{privilege}),
+	})
+}
+
+const granteeSchemaPrivilegesQuery = `
+SELECT 'schema', '', '', ssp.privilege_type
+FROM svv_schema_privileges ssp
+WHERE ssp.namespace_name = $1
+AND ssp.identity_type = $2
+AND ssp.identity_name = $3`
+
+// Users and groups hold privileges on tables and callables through the ACLs in pg_class and
+// pg_proc_info, which readTableGrants and readCallableGrants read and cache with
+// readCachedGranteePrivileges, so these parts of the query are only used for roles.
+const granteeObjectPrivilegesQuery = `
+UNION ALL
+SELECT 'table', t.table_name, '', COALESCE(p.privilege_type, '')
+FROM svv_all_tables t
+LEFT JOIN svv_relation_privileges p
+  ON p.relation_name = t.table_name
+  AND p.namespace_name = t.schema_name
+  AND p.identity_type = $2
+  AND p.identity_name = $3
+WHERE t.schema_name = $1
+AND t.database_name = $4
+UNION ALL
+SELECT DISTINCT 'callable', p.function_name, p.argument_types, p.privilege_type
+FROM svv_function_privileges p
+JOIN svv_redshift_functions pr ON pr.function_name = p.function_name AND pr.schema_name = p.namespace_name
+WHERE p.namespace_name = $1
+AND p.identity_type = $2
+AND p.identity_name = $3
+AND pr.database_name = $4`
+
+// readGranteePrivileges reads the privileges of a grantee on a schema and, for roles, on the tables,
+// functions and procedures in it with a single query. All grant resources of the grantee on the schema
+// are read from the same snapshot, which is cached by the client until the next statement changes the database.
+func readGranteePrivileges(db *DBConnection, identityType, identityName, schemaName, databaseName string) (*granteePrivileges, error) {
+	key := strings.Join([]string{identityType, identityName, databaseName, schemaName}, ":")
+	return db.client.grantPrivileges().lookup(key, func() (*granteePrivileges, error) {
+		query := granteeSchemaPrivilegesQuery
+		args := []interface{}{schemaName, identityType, identityName}
+		if identityType == "role" {
+			query += granteeObjectPrivilegesQuery
+			args = append(args, databaseName)
+		}
+
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("error reading privileges of %s %q in schema %q: %w", identityType, identityName, schemaName, err)
+		}
+		defer rows.Close()
+
+		privileges := newGranteePrivileges()
+		for rows.Next() {
+			var kind, name, callableArgs, privilege string
+			if err := rows.Scan(&kind, &name, &callableArgs, &privilege); err != nil {
+				return nil, fmt.Errorf("error reading privileges of %s %q in schema %q: %w", identityType, identityName, schemaName, err)
+			}
+			privilege = strings.ToLower(privilege)
+
+			switch kind {
+			case "schema":
+				privileges.schema.Add(privilege)
+			case "table":
+				if _, ok := privileges.tables[name]; !ok {
+					privileges.tables[name] = schema.NewSet(schema.HashString, nil)
+				}
+				if privilege != "" {
+					privileges.tables[name].Add(privilege)
+				}
+			case "callable":
+				privileges.addCallablePrivilege(name, callableArgs, privilege)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error reading privileges of %s %q in schema %q: %w", identityType, identityName, schemaName, err)
+		}
+
+		return privileges, nil
+	})
+}
+
+// readCachedGranteePrivileges reads the privileges of a user, group or PUBLIC on the objects of a schema with
+// read and caches them like readGranteePrivileges. keyParts identify the kind of objects, the grantee and the schema.
+func readCachedGranteePrivileges(db *DBConnection, keyParts []string, read func() (*granteePrivileges, error)) (*granteePrivileges, error) {
+	return db.client.grantPrivileges().lookup(strings.Join(keyParts, ":"), read)
+}
+
+// grantPrivilegesCache memoizes the privileges read by the grant resources, so the ones of a grantee on a
+// schema share one read. The cache is invalidated whenever a statement of the client may have changed
+// privileges, i.e. on every committed transaction and every statement run outside of one, so reads
+// after a write never see privileges from before it.
+type grantPrivilegesCache struct {
+	mu sync.Mutex
+	// generation is incremented on every invalidation, so reads which started before
+	// a grant was applied don't store privileges which are already outdated.
+	generation int
+	entries    map[string]*granteePrivileges
+}
+
+func newGrantPrivilegesCache() *grantPrivilegesCache {
+	return &grantPrivilegesCache{entries: map[string]*granteePrivileges{}}
+}
+
+// lookup returns the cached privileges for key or calls query to read them. A nil cache always calls query.
+func (c *grantPrivilegesCache) lookup(key string, query func() (*granteePrivileges, error)) (*granteePrivileges, error) {
+	if c == nil {
+		return query()
+	}
+
+	c.mu.Lock()
+	privileges, found := c.entries[key]
+	generation := c.generation
+	c.mu.Unlock()
+	if found {
+		return privileges, nil
+	}
+
+	privileges, err := query()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[key] = privileges
+	}
+	c.mu.Unlock()
+	return privileges, nil
+}
+
+// invalidate removes all cached privileges, it has to be called whenever privileges may have changed.
+func (c *grantPrivilegesCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = map[string]*granteePrivileges{}
+}
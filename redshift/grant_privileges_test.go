@@ -0,0 +1,150 @@
+package redshift
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGrantPrivilegesCache_Lookup(t *testing.T) {
+	cache := newGrantPrivilegesCache()
+	queries := 0
+	query := func() (*granteePrivileges, error) {
+		queries++
+		return newGranteePrivileges(), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.lookup("role:analyst:dev:public", query); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("expected the privileges to be read once, got %d reads", queries)
+	}
+
+	cache.invalidate()
+	if _, err := cache.lookup("role:analyst:dev:public", query); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("expected the privileges to be read again after invalidation, got %d reads", queries)
+	}
+}
+
+func TestGrantPrivilegesCache_LookupConcurrentInvalidation(t *testing.T) {
+	cache := newGrantPrivilegesCache()
+	queries := 0
+
+	// A grant is applied while the privileges are read, so the result must not be cached.
+	_, err := cache.lookup("role:analyst:dev:public", func() (*granteePrivileges, error) {
+		queries++
+		cache.invalidate()
+		return newGranteePrivileges(), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := cache.lookup("role:analyst:dev:public", func() (*granteePrivileges, error) {
+		queries++
+		return newGranteePrivileges(), nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("expected outdated privileges not to be cached, got %d reads", queries)
+	}
+}
+
+func TestGrantPrivilegesCache_LookupError(t *testing.T) {
+	cache := newGrantPrivilegesCache()
+	queryErr := errors.New("permission denied")
+
+	if _, err := cache.lookup("role:analyst:dev:public", func() (*granteePrivileges, error) { return nil, queryErr }); !errors.Is(err, queryErr) {
+		t.Fatalf("expected %v, got %v", queryErr, err)
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("expected a failed read not to be cached, got %v", cache.entries)
+	}
+}
+
+func TestGrantPrivilegesCache_Nil(t *testing.T) {
+	var cache *grantPrivilegesCache
+	queries := 0
+	query := func() (*granteePrivileges, error) {
+		queries++
+		return newGranteePrivileges(), nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.lookup("role:analyst:dev:public", query); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	cache.invalidate()
+	if queries != 2 {
+		t.Errorf("expected a nil cache to read every time, got %d reads", queries)
+	}
+}
+
+func TestGranteePrivileges_AddCallablePrivilege(t *testing.T) {
+	privileges := newGranteePrivileges()
+	privileges.addCallablePrivilege("sales_tax", "integer", "execute")
+	privileges.addCallablePrivilege("sales_tax", "numeric", "execute")
+	privileges.addCallablePrivilege("sales_tax", "integer", "execute")
+
+	if len(privileges.callables) != 2 {
+		t.Fatalf("expected one entry per overload, got %v", privileges.callables)
+	}
+	for _, callable := range privileges.callables {
+		if callable.privileges.Len() != 1 || !callable.privileges.Contains("execute") {
+			t.Errorf("expected %s(%s) to hold execute, got %v", callable.name, callable.args, callable.privileges.List())
+		}
+	}
+}
+
+func TestGrantPrivilegesCache_InvalidatedByWrites(t *testing.T) {
+	client := newTxLoggingClient(t)
+	db, err := client.Connect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	queries := 0
+	lookup := func() {
+		if _, err := client.grantPrivileges().lookup("role:analyst:dev:public", func() (*granteePrivileges, error) {
+			queries++
+			return newGranteePrivileges(), nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	lookup()
+	tx, err := startTransaction(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tx.Exec("REVOKE ALL ON SCHEMA public FROM ROLE analyst"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lookup()
+	if queries != 1 {
+		t.Errorf("expected the cache to be kept until the transaction is committed, got %d reads", queries)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lookup()
+	if queries != 2 {
+		t.Errorf("expected the privileges to be read again after a commit, got %d reads", queries)
+	}
+
+	if _, err := db.Exec("ALTER SCHEMA public OWNER TO analyst"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lookup()
+	if queries != 3 {
+		t.Errorf("expected the privileges to be read again after a statement outside of a transaction, got %d reads", queries)
+	}
+}
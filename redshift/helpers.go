@@ -3,13 +3,17 @@ package redshift
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/aws/smithy-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,6 +24,8 @@ const (
 	pqErrorCodeConcurrent        = "XX000"
 	pqErrorCodeInvalidSchemaName = "3F000"
 	pqErrorCodeDeadlock          = "40P01"
+	pqErrorCodeUndefinedFunction = "42883"
+	pqErrorCodeUndefinedTable    = "42P01"
 	pqErrorCodeFailedTransaction = "25P02"
 	pqErrorDuplicateKeyViolation = "23505"
 
@@ -28,34 +34,6 @@ const (
 	pgErrorCodeInsufficientPrivileges = "42501"
 )
 
-// startTransaction starts a new DB transaction using the provided client.
-func startTransaction(client *Client) (*sql.Tx, error) {
-	db, err := client.Connect()
-	if err != nil {
-		return nil, err
-	}
-
-	txn, err := db.Begin()
-	if err != nil {
-		return nil, fmt.Errorf("could not start transaction: %w", err)
-	}
-
-	return txn, nil
-}
-
-// deferredRollback can be used to rollback a transaction in a defer.
-// It will log an error if it fails
-func deferredRollback(txn *sql.Tx) {
-	err := txn.Rollback()
-	switch {
-	case errors.Is(err, sql.ErrTxDone):
-		// transaction has already been committed or rolled back
-		log.Printf("[DEBUG]: %v", err)
-	case err != nil:
-		log.Printf("[ERR] could not rollback transaction: %v", err)
-	}
-}
-
 // pqQuoteLiteral returns a string literal safe for inclusion in a PostgreSQL
 // query as a parameter.  The resulting string still needs to be wrapped in
 // single quotes in SQL (i.e. fmt.Sprintf(`'%s'`, pqQuoteLiteral("str"))).  See
@@ -66,39 +44,241 @@ func pqQuoteLiteral(in string) string {
 	return in
 }
 
-func getUserIDFromName(tx *sql.Tx, user string) (userID int, err error) {
-	err = tx.QueryRow("SELECT usesysid FROM pg_user WHERE usename = $1", user).Scan(&userID)
-	return
+func getUserIDFromName(tx *transaction, user string) (int, error) {
+	return tx.lookupID(idCacheKindUser, user, func() (userID int, err error) {
+		err = tx.QueryRow("SELECT usesysid FROM pg_user WHERE usename = $1", user).Scan(&userID)
+		return
+	})
+}
+
+// getGroupIDFromName returns the ID of the group, whose name has to be given as stored by Redshift, see identifierCase.
+func getGroupIDFromName(tx *transaction, group string) (int, error) {
+	return tx.lookupID(idCacheKindGroup, group, func() (groupID int, err error) {
+		err = tx.QueryRow("SELECT grosysid FROM pg_group WHERE groname = $1", group).Scan(&groupID)
+		return
+	})
 }
 
+func getSchemaIDFromName(tx *transaction, schemaName string) (int, error) {
+	schemaName = strings.ToLower(schemaName)
+	return tx.lookupID(idCacheKindSchema, schemaName, func() (schemaID int, err error) {
+		err = tx.QueryRow("SELECT oid FROM pg_namespace WHERE nspname = $1", schemaName).Scan(&schemaID)
+		return
+	})
+}
+
+// getRelationKind returns the pg_class.relkind of the relation or an empty string if it does not exist.
+func getRelationKind(tx *transaction, schemaName, relationName string) (string, error) {
+	var relKind string
+	err := tx.QueryRow(`
+SELECT c.relkind
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2`, schemaName, relationName).Scan(&relKind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return relKind, err
+}
+
+var relationKindNames = map[string]string{
+	"r": "table",
+	"v": "view",
+	"m": "materialized view",
+	"S": "sequence",
+	"i": "index",
+	"c": "composite type",
+	"t": "TOAST table",
+}
+
+// checkRelationKind returns an error if a relation of kind relKind cannot be granted on as objectType.
+func checkRelationKind(objectType, relationName, relKind string) error {
+	for _, allowed := range grantObjectTypesCodes[objectType] {
+		if relKind == allowed {
+			return nil
+		}
+	}
+
+	kindName, ok := relationKindNames[relKind]
+	if !ok {
+		kindName = fmt.Sprintf("relation of kind %q", relKind)
+	}
+	return fmt.Errorf("%q is a %s and cannot be used with %s %q", relationName, kindName, grantObjectTypeAttr, objectType)
+}
+
+// ResourceFunc runs fn on a connection with the context of the operation. When the connection was lost, the pool
+// is replaced, but fn isn't run again: the server might have applied its statements before the connection dropped,
+// and running them again, e.g. a CREATE USER, could fail or apply them twice. See IdempotentResourceFunc.
 func ResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
-	return func(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceFunc(fn, false)
+}
+
+// IdempotentResourceFunc is like ResourceFunc, but runs fn once more on a fresh connection when the connection was
+// lost, e.g. because a long apply outlived the server side of pooled connections. It must only be used for reads
+// and operations which can safely be run again, like GRANT and REVOKE statements.
+func IdempotentResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return resourceFunc(fn, true)
+}
+
+func resourceFunc(fn func(*DBConnection, *schema.ResourceData) error, idempotent bool) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 		client := meta.(*Client)
 
-		db, err := client.Connect()
+		db, err := connectResourceDB(ctx, client)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 
-		return diag.FromErr(fn(db, d))
+		err = fn(db, d)
+		if err != nil {
+			// The error might have been caused by IDs of objects dropped or renamed outside of Terraform.
+			db.ids.clear()
+		}
+		if isConnectionLostError(err) {
+			client.resetConnection()
+			if !idempotent {
+				log.Printf("[WARN] Lost connection to Redshift, the operation is not retried: %v", err)
+				return diag.FromErr(err)
+			}
+			log.Printf("[WARN] Lost connection to Redshift, reconnecting and retrying once: %v", err)
+			if db, err = connectResourceDB(ctx, client); err != nil {
+				return diag.FromErr(err)
+			}
+			err = fn(db, d)
+		}
+
+		return append(db.warnings, diag.FromErr(err)...)
 	}
 }
 
+// defaultOperationTimeout is the default timeout of the operations of resources which issue many statements,
+// e.g. a group with privileges in hundreds of schemas. It can be changed with the timeouts block of the resource.
+const defaultOperationTimeout = 20 * time.Minute
+
+// operationTimeouts returns the configurable timeouts of resources which issue many statements.
+// The statements are issued with the context of the operation, so they are canceled once it times out.
+func operationTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultOperationTimeout),
+		Read:   schema.DefaultTimeout(defaultOperationTimeout),
+		Update: schema.DefaultTimeout(defaultOperationTimeout),
+		Delete: schema.DefaultTimeout(defaultOperationTimeout),
+	}
+}
+
+func connectResourceDB(ctx context.Context, client *Client) (*DBConnection, error) {
+	db, err := client.ConnectContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The registered connection is shared, so the label and the context of the operation are
+	// carried by a copy of it and of its client, which has the configuration resolved.
+	resourceClient := *db.client
+	resourceClient.statementLabel = client.statementLabel
+	resourceClient.ctx = ctx
+	resourceDB := *db
+	resourceDB.client = &resourceClient
+	resourceDB.warnings = nil
+	return &resourceDB, nil
+}
+
+// isConnectionLostError reports whether err indicates that the connection to the
+// server was dropped, e.g. by an idle timeout or a cluster restart.
+func isConnectionLostError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	msg := err.Error()
+	for _, pattern := range []string{
+		"connection reset by peer",
+		"server closed the connection unexpectedly",
+		"broken pipe",
+	} {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceRetryOnPQErrors retries fn up to 10 times while it fails with a transient error of either
+// the pq or the Data API driver, returning the last error once the attempts are used up.
 func ResourceRetryOnPQErrors(fn func(*DBConnection, *schema.ResourceData) error) func(*DBConnection, *schema.ResourceData) error {
 	return func(db *DBConnection, d *schema.ResourceData) error {
+		var err error
 		for i := 0; i < 10; i++ {
-			err := fn(db, d)
-			if err == nil {
-				return nil
+			if i > 0 {
+				if err := sleepContext(db.client.context(), time.Duration(i)*time.Second); err != nil {
+					return err
+				}
 			}
 
-			var pqErr *pq.Error
-			if !errors.As(err, &pqErr) || !isRetryablePQError(string(pqErr.Code)) {
+			err = fn(db, d)
+			if err == nil || !isRetryableResourceError(err) {
 				return err
 			}
+		}
+		return err
+	}
+}
+
+// isRetryableResourceError returns whether the statements of a resource failed with an error which might be
+// transient. Errors of the pq driver are classified by their code, all others by isRetryableDataAPIError.
+func isRetryableResourceError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return isRetryablePQError(string(pqErr.Code))
+	}
+	return isRetryableDataAPIError(err)
+}
+
+// dataAPIRetryableErrorCodes are the error codes of the Data API returned when requests are throttled
+// or too many statements or sessions are active.
+var dataAPIRetryableErrorCodes = map[string]bool{
+	"ThrottlingException":               true,
+	"ActiveStatementsExceededException": true,
+	"ActiveSessionsExceededException":   true,
+	"InternalServerException":           true,
+}
+
+// dataAPIRetryableMessages are parts of the messages of statements which failed for transient reasons.
+// The Data API driver only reports the message of failed statements, e.g. of a serverless workgroup
+// which is short of capacity, without an error code.
+var dataAPIRetryableMessages = []string{
+	"throttl",
+	"rate exceeded",
+	"insufficient capacity",
+	"conflict with concurrent transaction",
+}
 
-			time.Sleep(time.Duration(i+1) * time.Second)
+// isRetryableDataAPIError returns whether an error of the Data API driver, which doesn't return *pq.Error,
+// might be transient.
+func isRetryableDataAPIError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && dataAPIRetryableErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, retryableMessage := range dataAPIRetryableMessages {
+		if strings.Contains(message, retryableMessage) {
+			return true
 		}
+	}
+	return false
+}
+
+// sleepContext waits for the given duration, returning early with the error of ctx once it is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
 		return nil
 	}
 }
@@ -117,7 +297,59 @@ func isRetryablePQError(code string) bool {
 }
 
 func isPqErrorWithCode(err error, code string) bool {
-	return string(err.(*pq.Error).Code) == code
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && string(pqErr.Code) == code
+}
+
+// preserveCaseAttr is the argument of resources whose identifiers are case sensitive,
+// i.e. which are used with enable_case_sensitive_identifier turned on.
+const preserveCaseAttr = "preserve_case"
+
+func preserveCaseSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		ForceNew:    true,
+		Default:     false,
+		Description: "Keep the case of the identifiers of this resource. Only needed when the cluster is configured with `enable_case_sensitive_identifier`, otherwise Redshift folds identifiers to lower case and differences in case are ignored.",
+	}
+}
+
+// normalizeIdentifier folds name to lower case like Redshift does for identifiers.
+func normalizeIdentifier(name string) string {
+	return strings.ToLower(name)
+}
+
+// identifierCase returns name as it is stored by Redshift, folded to lower case unless preserveCase is set.
+func identifierCase(name string, preserveCase bool) string {
+	if preserveCase {
+		return name
+	}
+	return normalizeIdentifier(name)
+}
+
+// getIdentifier returns the identifier in attr of d as it is stored by Redshift.
+func getIdentifier(d *schema.ResourceData, attr string) string {
+	return identifierOf(d, d.Get(attr).(string))
+}
+
+// identifierOf returns name as it is stored by Redshift, honoring preserve_case of d.
+func identifierOf(d *schema.ResourceData, name string) string {
+	return identifierCase(name, d.Get(preserveCaseAttr).(bool))
+}
+
+// suppressIdentifierCaseDiff ignores differences in case of identifiers unless preserve_case is set.
+func suppressIdentifierCaseDiff(_, old, new string, d *schema.ResourceData) bool {
+	return identifierOf(d, old) == identifierOf(d, new)
+}
+
+// setDefaultIfUnset sets attr of d to value when the state has no value for it. Arguments with a default
+// are not read back from the database, so state written by an import or by a provider version without
+// the argument would show a change, or even a replacement, in the next plan otherwise.
+func setDefaultIfUnset(d *schema.ResourceData, attr string, value interface{}) {
+	if _, ok := d.GetOkExists(attr); !ok {
+		d.Set(attr, value)
+	}
 }
 
 func splitCsvAndTrim(raw string) ([]string, error) {
@@ -139,10 +371,9 @@ func splitCsvAndTrim(raw string) ([]string, error) {
 	return result, nil
 }
 
+// validatePrivileges reports whether all privileges can be granted on the object type. An empty list is valid
+// for every object type, it revokes all privileges.
 func validatePrivileges(privileges []string, objectType string) bool {
-	if objectType == "language" && len(privileges) == 0 {
-		return false
-	}
 	for _, p := range privileges {
 		switch strings.ToUpper(objectType) {
 		case "SCHEMA":
@@ -167,7 +398,7 @@ func validatePrivileges(privileges []string, objectType string) bool {
 			default:
 				return false
 			}
-		case "PROCEDURE", "FUNCTION":
+		case "PROCEDURE", "FUNCTION", "EXTERNAL_FUNCTION":
 			switch strings.ToUpper(p) {
 			case "EXECUTE":
 				continue
@@ -189,12 +420,39 @@ func validatePrivileges(privileges []string, objectType string) bool {
 	return true
 }
 
-func appendIfTrue(condition bool, item string, list *[]string) {
-	if condition {
-		*list = append(*list, item)
+// normalizePrivilege returns the form privileges are stored in state: lower case, with temporary abbreviated to temp.
+func normalizePrivilege(val interface{}) string {
+	privilege := strings.ToLower(val.(string))
+	if privilege == "temporary" {
+		return "temp"
+	}
+	return privilege
+}
+
+// hashPrivilege hashes privileges by their normalized form, so privileges configured in
+// another case than they are read back don't end up as different set elements.
+func hashPrivilege(val interface{}) int {
+	return schema.HashString(normalizePrivilege(val))
+}
+
+func setToStringList(set *schema.Set) []string {
+	list := make([]string, set.Len())
+	for i, item := range set.List() {
+		list[i] = item.(string)
 	}
+	return list
 }
 
+func listToStringList(list []interface{}) []string {
+	stringList := make([]string, len(list))
+	for i, item := range list {
+		stringList[i] = item.(string)
+	}
+	return stringList
+}
+
+// setToPgIdentList quotes the identifiers and joins them with commas. Identifiers are bare names, if prefix is set
+// they are qualified with it, e.g. with the schema of the objects.
 func setToPgIdentList(identifiers *schema.Set, prefix string) string {
 	quoted := make([]string, identifiers.Len())
 	for i, identifier := range identifiers.List() {
@@ -208,28 +466,50 @@ func setToPgIdentList(identifiers *schema.Set, prefix string) string {
 	return strings.Join(quoted, ",")
 }
 
-// Quoted identifiers somehow does not work for grants/revokes on functions and procedures
-func setToPgIdentListNotQuoted(identifiers *schema.Set, prefix string) string {
-	quoted := make([]string, identifiers.Len())
-	for i, identifier := range identifiers.List() {
-		if prefix == "" {
-			quoted[i] = identifier.(string)
-		} else {
-			quoted[i] = fmt.Sprintf("%s.%s", prefix, identifier.(string))
-		}
-	}
+// callableSignature is a function or procedure as given in the objects of a grant,
+// e.g. myproc(int, varchar). args is only set if the argument types were given.
+type callableSignature struct {
+	name    string
+	args    string
+	hasArgs bool
+}
 
-	return strings.Join(quoted, ",")
+// parseCallableSignature parses a function or procedure as given in the objects of a grant. The name is
+// folded to lower case unless it is enclosed in double quotes or preserveCase is set, as Redshift does with
+// identifiers in SQL, so it matches the name in the catalog.
+func parseCallableSignature(def string, preserveCase bool) callableSignature {
+	name, args, hasArgs := strings.Cut(def, "(")
+	name = strings.TrimSpace(name)
+	if len(name) > 1 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		name = strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	} else {
+		name = identifierCase(name, preserveCase)
+	}
+	return callableSignature{
+		name:    name,
+		args:    strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(args), ")")),
+		hasArgs: hasArgs,
+	}
 }
 
-func stripArgumentsFromCallablesDefinitions(defs *schema.Set) []string {
-	parser := func(name string) string {
-		return strings.Split(name, "(")[0]
+// quoted returns the signature with its (schema-qualified) name quoted, the argument types are kept as given.
+func (c callableSignature) quoted(schemaName string) string {
+	name := pq.QuoteIdentifier(c.name)
+	if schemaName != "" {
+		name = fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), name)
+	}
+	if !c.hasArgs {
+		return name
 	}
+	return fmt.Sprintf("%s(%s)", name, c.args)
+}
 
-	names := make([]string, defs.Len())
-	for _, def := range defs.List() {
-		names = append(names, parser(def.(string)))
+// setToPgCallableList quotes the names of the function or procedure signatures, but not their argument types.
+func setToPgCallableList(defs *schema.Set, schemaName string) string {
+	quoted := make([]string, defs.Len())
+	for i, def := range defs.List() {
+		quoted[i] = parseCallableSignature(def.(string), false).quoted(schemaName)
 	}
-	return names
+
+	return strings.Join(quoted, ",")
 }
@@ -1,7 +1,23 @@
 package redshift
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestValidatePrivileges(t *testing.T) {
@@ -85,11 +101,31 @@ func TestValidatePrivileges(t *testing.T) {
 			objectType: "function",
 			expected:   false,
 		},
+		"empty list for function": {
+			privileges: []string{},
+			objectType: "function",
+			expected:   true,
+		},
+		"valid list for external function": {
+			privileges: []string{"EXECUTE"},
+			objectType: "external_function",
+			expected:   true,
+		},
+		"invalid list for external function": {
+			privileges: []string{"execute", "usage"},
+			objectType: "external_function",
+			expected:   false,
+		},
 		"valid list for procedure": {
 			privileges: []string{"execute"},
 			objectType: "procedure",
 			expected:   true,
 		},
+		"empty list for procedure": {
+			privileges: []string{},
+			objectType: "procedure",
+			expected:   true,
+		},
 		"invalid list for procedure": {
 			privileges: []string{"foo"},
 			objectType: "procedure",
@@ -118,7 +154,7 @@ func TestValidatePrivileges(t *testing.T) {
 		"empty list for language": {
 			privileges: []string{},
 			objectType: "language",
-			expected:   false,
+			expected:   true,
 		},
 	}
 
@@ -132,3 +168,624 @@ func TestValidatePrivileges(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckRelationKind(t *testing.T) {
+	tests := map[string]struct {
+		objectType    string
+		relKind       string
+		expectedError string
+	}{
+		"table as table": {
+			objectType: "table",
+			relKind:    "r",
+		},
+		"view as table": {
+			objectType: "table",
+			relKind:    "v",
+		},
+		"materialized view as table": {
+			objectType: "table",
+			relKind:    "m",
+		},
+		"sequence as table": {
+			objectType:    "table",
+			relKind:       "S",
+			expectedError: `"obj" is a sequence and cannot be used with object_type "table"`,
+		},
+		"unknown kind as table": {
+			objectType:    "table",
+			relKind:       "x",
+			expectedError: `"obj" is a relation of kind "x" and cannot be used with object_type "table"`,
+		},
+		"table as function": {
+			objectType:    "function",
+			relKind:       "r",
+			expectedError: `"obj" is a table and cannot be used with object_type "function"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkRelationKind(tt.objectType, "obj", tt.relKind)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("Expected error %q but got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestParseCallableSignature(t *testing.T) {
+	tests := map[string]struct {
+		def      string
+		expected callableSignature
+		quoted   string
+	}{
+		"without arguments": {
+			def:      "myproc",
+			expected: callableSignature{name: "myproc"},
+			quoted:   `"my_schema"."myproc"`,
+		},
+		"empty arguments": {
+			def:      "myproc()",
+			expected: callableSignature{name: "myproc", hasArgs: true},
+			quoted:   `"my_schema"."myproc"()`,
+		},
+		"overloaded signature": {
+			def:      "myproc(int, varchar)",
+			expected: callableSignature{name: "myproc", args: "int, varchar", hasArgs: true},
+			quoted:   `"my_schema"."myproc"(int, varchar)`,
+		},
+		"quoted mixed case name": {
+			def:      `"MyProc"(int)`,
+			expected: callableSignature{name: "MyProc", args: "int", hasArgs: true},
+			quoted:   `"my_schema"."MyProc"(int)`,
+		},
+		"quoted name with quotes": {
+			def:      `"my""proc"`,
+			expected: callableSignature{name: `my"proc`},
+			quoted:   `"my_schema"."my""proc"`,
+		},
+		"type with modifiers": {
+			def:      "my_func (numeric(10,2))",
+			expected: callableSignature{name: "my_func", args: "numeric(10,2)", hasArgs: true},
+			quoted:   `"my_schema"."my_func"(numeric(10,2))`,
+		},
+		"reserved word": {
+			def:      "order(int)",
+			expected: callableSignature{name: "order", args: "int", hasArgs: true},
+			quoted:   `"my_schema"."order"(int)`,
+		},
+		"mixed case": {
+			def:      "SalesTax(float)",
+			expected: callableSignature{name: "salestax", args: "float", hasArgs: true},
+			quoted:   `"my_schema"."salestax"(float)`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			callable := parseCallableSignature(tt.def, false)
+			if callable != tt.expected {
+				t.Errorf("Expected %+v but got %+v", tt.expected, callable)
+			}
+			if quoted := callable.quoted("my_schema"); quoted != tt.quoted {
+				t.Errorf("Expected quoted signature %q but got %q", tt.quoted, quoted)
+			}
+		})
+	}
+}
+
+func TestMatchesCallableSignatures(t *testing.T) {
+	callables := []callableSignature{
+		{name: "myproc", args: "integer, character varying", hasArgs: true},
+		{name: "other_proc"},
+	}
+
+	tests := map[string]struct {
+		name     string
+		args     string
+		expected bool
+	}{
+		"matching signature":          {name: "myproc", args: "integer, character varying", expected: true},
+		"other overload":              {name: "myproc", args: "double precision", expected: false},
+		"name without arguments":      {name: "other_proc", args: "integer", expected: true},
+		"callable not in the objects": {name: "unrelated", args: "", expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if result := matchesCallableSignatures(callables, tt.name, tt.args); result != tt.expected {
+				t.Errorf("Expected %t but got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsConnectionLostError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil":               {nil, false},
+		"bad connection":    {fmt.Errorf("could not exec: %w", driver.ErrBadConn), true},
+		"unexpected EOF":    {io.ErrUnexpectedEOF, true},
+		"connection reset":  {errors.New("read tcp 10.0.0.1:5439: read: connection reset by peer"), true},
+		"server closed":     {errors.New("pq: server closed the connection unexpectedly"), true},
+		"permission denied": {errors.New("pq: permission denied for schema foo"), false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isConnectionLostError(tt.err); got != tt.expected {
+				t.Errorf("isConnectionLostError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+const flakyDriverName = "redshift-test-flaky"
+
+// flakyDriver fails the first Exec with a lost connection and then succeeds.
+type flakyDriver struct {
+	failures *atomic.Int32
+}
+
+func (d flakyDriver) Open(string) (driver.Conn, error) {
+	return flakyConn{d.failures}, nil
+}
+
+type flakyConn struct {
+	failures *atomic.Int32
+}
+
+func (c flakyConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c flakyConn) Close() error { return nil }
+
+func (c flakyConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (c flakyConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	if c.failures.Add(-1) >= 0 {
+		return nil, errors.New("read tcp 10.0.0.1:5439: read: connection reset by peer")
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c flakyConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &flakyRows{values: []driver.Value{"terraform"}}, nil
+}
+
+type flakyRows struct {
+	values []driver.Value
+}
+
+func (r *flakyRows) Columns() []string { return []string{"current_user"} }
+
+func (r *flakyRows) Close() error { return nil }
+
+func (r *flakyRows) Next(dest []driver.Value) error {
+	if r.values == nil {
+		return io.EOF
+	}
+	copy(dest, r.values)
+	r.values = nil
+	return nil
+}
+
+func TestIdempotentResourceFunc_ReconnectsOnLostConnection(t *testing.T) {
+	failures := &atomic.Int32{}
+	failures.Store(1)
+	sql.Register(flakyDriverName, flakyDriver{failures})
+
+	client := NewConfig(flakyDriverName, t.Name(), "db", 1).NewClient()
+	firstDB, err := client.Connect()
+	if err != nil {
+		t.Fatalf("unexpected error connecting: %v", err)
+	}
+
+	attempts := 0
+	var lastDB *DBConnection
+	fn := IdempotentResourceFunc(func(db *DBConnection, _ *schema.ResourceData) error {
+		attempts++
+		lastDB = db
+		_, err := db.Exec("SELECT 1")
+		return err
+	})
+
+	if diags := fn(context.Background(), nil, client); diags.HasError() {
+		t.Fatalf("expected no error after reconnect, got: %v", diags)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	if lastDB.DB == firstDB.DB {
+		t.Error("expected the retry to use a new connection pool")
+	}
+}
+
+func TestResourceFunc_DoesNotRetryOnLostConnection(t *testing.T) {
+	failures := &atomic.Int32{}
+	failures.Store(1)
+	sql.Register(flakyDriverName+"-not-retried", flakyDriver{failures})
+
+	client := NewConfig(flakyDriverName+"-not-retried", t.Name(), "db", 1).NewClient()
+	firstDB, err := client.Connect()
+	if err != nil {
+		t.Fatalf("unexpected error connecting: %v", err)
+	}
+
+	attempts := 0
+	fn := ResourceFunc(func(db *DBConnection, _ *schema.ResourceData) error {
+		attempts++
+		_, err := db.Exec("CREATE USER john")
+		return err
+	})
+
+	if diags := fn(context.Background(), nil, client); !diags.HasError() {
+		t.Fatal("expected the lost connection to be reported")
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+
+	db, err := client.Connect()
+	if err != nil {
+		t.Fatalf("unexpected error connecting: %v", err)
+	}
+	if db.DB == firstDB.DB {
+		t.Error("expected the connection pool to be replaced")
+	}
+}
+
+func TestResourceFunc_ReportsWarnings(t *testing.T) {
+	client := newTxLoggingClient(t)
+
+	diags := ResourceFunc(func(db *DBConnection, _ *schema.ResourceData) error {
+		db.warn("Something looks off", "details")
+		return nil
+	})(context.Background(), nil, client)
+
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+
+	// The warnings of one operation must not be reported by the next one.
+	if diags := ResourceFunc(func(*DBConnection, *schema.ResourceData) error { return nil })(context.Background(), nil, client); len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diags)
+	}
+}
+
+const blockingDriverName = "redshift-test-blocking"
+
+// blockingDriver blocks every statement of an operation until its context is done.
+type blockingDriver struct{}
+
+func (blockingDriver) Open(string) (driver.Conn, error) {
+	return blockingConn{}, nil
+}
+
+type blockingConn struct{}
+
+func (blockingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (blockingConn) Close() error { return nil }
+
+func (blockingConn) Begin() (driver.Tx, error) {
+	return blockingTx{}, nil
+}
+
+func (blockingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return blockingTx{}, nil
+}
+
+func (blockingConn) ExecContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingConn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if query == "SELECT current_user;" {
+		// Answered, so the connection can be set up before the statements of the operation block.
+		return &flakyRows{values: []driver.Value{"terraform"}}, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type blockingTx struct{}
+
+func (blockingTx) Commit() error   { return nil }
+func (blockingTx) Rollback() error { return nil }
+
+func TestResourceFunc_AbortsOnContextDone(t *testing.T) {
+	sql.Register(blockingDriverName, blockingDriver{})
+	client := NewConfig(blockingDriverName, t.Name(), "db", 1).NewClient()
+
+	tests := map[string]func(*DBConnection, *schema.ResourceData) error{
+		"statement": func(db *DBConnection, _ *schema.ResourceData) error {
+			_, err := db.Exec("SELECT 1")
+			return err
+		},
+		"transaction": func(db *DBConnection, _ *schema.ResourceData) error {
+			tx, err := startTransaction(db.client)
+			if err != nil {
+				return err
+			}
+			defer deferredRollback(tx)
+			_, err = tx.Exec("SELECT 1")
+			return err
+		},
+		"group delete": func(db *DBConnection, _ *schema.ResourceData) error {
+			d := schema.TestResourceDataRaw(t, redshiftGroup().Schema, map[string]interface{}{groupNameAttr: "analysts"})
+			d.SetId("100")
+			return resourceRedshiftGroupDelete(db, d)
+		},
+	}
+
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			done := make(chan diag.Diagnostics)
+			go func() { done <- ResourceFunc(fn)(ctx, nil, client) }()
+
+			select {
+			case diags := <-done:
+				if !diags.HasError() {
+					t.Fatal("expected an error")
+				}
+				if !strings.Contains(diags[0].Summary, context.DeadlineExceeded.Error()) {
+					t.Errorf("expected the deadline to be exceeded, got: %s", diags[0].Summary)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("operation was not aborted when the context was done")
+			}
+		})
+	}
+}
+
+func TestResourceRetryOnPQErrors_AbortsOnContextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	db := &DBConnection{client: &Client{ctx: ctx}}
+
+	attempts := 0
+	fn := ResourceRetryOnPQErrors(func(*DBConnection, *schema.ResourceData) error {
+		attempts++
+		return &pq.Error{Code: pqErrorCodeConcurrent}
+	})
+
+	start := time.Now()
+	if err := fn(db, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be exceeded, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("expected the backoff to be aborted, took %s", elapsed)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestIsRetryableResourceError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"retryable pq error":     {err: &pq.Error{Code: pqErrorCodeConcurrent}, expected: true},
+		"non-retryable pq error": {err: &pq.Error{Code: "42601"}, expected: false},
+		"data api throttling":    {err: fmt.Errorf("execute statement: %w", &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}), expected: true},
+		"data api active statements exceeded": {
+			err:      fmt.Errorf("execute statement: %w", &smithy.GenericAPIError{Code: "ActiveStatementsExceededException"}),
+			expected: true,
+		},
+		"data api validation": {err: fmt.Errorf("execute statement: %w", &smithy.GenericAPIError{Code: "ValidationException", Message: "invalid sql"}), expected: false},
+		"data api short of capacity": {
+			err:      errors.New(`query failed (sql: "GRANT SELECT ON t TO u"): Insufficient capacity to run the query, retry later`),
+			expected: true,
+		},
+		"data api failed query": {err: errors.New(`query failed (sql: "GRANT SELECT ON t TO u"): relation "t" does not exist`), expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := isRetryableResourceError(tt.err); actual != tt.expected {
+				t.Errorf("isRetryableResourceError(%v) = %t, want %t", tt.err, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResourceRetryOnPQErrors_RetriesDataAPIErrors(t *testing.T) {
+	db := &DBConnection{client: &Client{ctx: context.Background()}}
+
+	attempts := 0
+	fn := ResourceRetryOnPQErrors(func(*DBConnection, *schema.ResourceData) error {
+		attempts++
+		if attempts == 1 {
+			return fmt.Errorf("execute statement: %w", &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"})
+		}
+		return nil
+	})
+
+	if err := fn(db, nil); err != nil {
+		t.Errorf("expected the throttled statement to succeed when retried, got: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestSuppressIdentifierCaseDiff(t *testing.T) {
+	tests := map[string]struct {
+		old, new     string
+		preserveCase bool
+		expected     bool
+	}{
+		"same name":                     {old: "my_role", new: "my_role", expected: true},
+		"different case":                {old: "my_role", new: "My_Role", expected: true},
+		"different name":                {old: "my_role", new: "other_role", expected: false},
+		"different case, preserve case": {old: "my_role", new: "My_Role", preserveCase: true, expected: false},
+		"same name, preserve case":      {old: "My_Role", new: "My_Role", preserveCase: true, expected: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftRole().Schema, map[string]interface{}{
+				roleNameAttr:     tt.new,
+				preserveCaseAttr: tt.preserveCase,
+			})
+
+			if actual := suppressIdentifierCaseDiff(roleNameAttr, tt.old, tt.new, d); actual != tt.expected {
+				t.Errorf("Expected suppressIdentifierCaseDiff(%q, %q) to be %t but got %t", tt.old, tt.new, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGetIdentifier(t *testing.T) {
+	tests := map[string]struct {
+		name         string
+		preserveCase bool
+		expected     string
+	}{
+		"lower case":                {name: "my_role", expected: "my_role"},
+		"mixed case":                {name: "My_Role", expected: "my_role"},
+		"mixed case, preserve case": {name: "My_Role", preserveCase: true, expected: "My_Role"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftRoleGrant().Schema, map[string]interface{}{
+				roleGrantRoleNameAttr:    tt.name,
+				roleGrantGrantToTypeAttr: "ROLE",
+				roleGrantGrantToNameAttr: tt.name,
+				preserveCaseAttr:         tt.preserveCase,
+			})
+
+			if actual := getIdentifier(d, roleGrantRoleNameAttr); actual != tt.expected {
+				t.Errorf("Expected role name %q but got %q", tt.expected, actual)
+			}
+			if actual := getIdentifier(d, roleGrantGrantToNameAttr); actual != tt.expected {
+				t.Errorf("Expected grantee name %q but got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// planUpgradedState plans config against state which has no value for the arguments in defaults, like
+// state written by an older provider version, after the defaults were set the way Read sets them.
+func planUpgradedState(t *testing.T, r *schema.Resource, state map[string]string, config map[string]interface{}, defaults map[string]interface{}) *terraform.InstanceDiff {
+	t.Helper()
+
+	d := r.Data(&terraform.InstanceState{ID: "id", Attributes: state})
+	for attr, value := range defaults {
+		if _, ok := state[attr]; ok {
+			t.Fatalf("state must not have a value for %q", attr)
+		}
+		setDefaultIfUnset(d, attr, value)
+	}
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("could not plan: %v", err)
+	}
+	return diff
+}
+
+func TestSetDefaultIfUnset_UpgradedState(t *testing.T) {
+	tests := map[string]struct {
+		resource *schema.Resource
+		state    map[string]string
+		config   map[string]interface{}
+		defaults map[string]interface{}
+	}{
+		"role": {
+			resource: redshiftRole(),
+			state:    map[string]string{roleNameAttr: "my_role", roleSystemPermissionsAttr + ".#": "0"},
+			config:   map[string]interface{}{roleNameAttr: "my_role"},
+			defaults: map[string]interface{}{preserveCaseAttr: false, roleQuotedAttr: false, roleExternalManagedAttr: false, roleCreatedOutsideTerraformAttr: false},
+		},
+		"role grant": {
+			resource: redshiftRoleGrant(),
+			state:    map[string]string{roleGrantRoleNameAttr: "my_role", roleGrantGrantToTypeAttr: "USER", roleGrantGrantToNameAttr: "my_user", roleGrantAdminOptionAttr: "false"},
+			config:   map[string]interface{}{roleGrantRoleNameAttr: "my_role", roleGrantGrantToTypeAttr: "USER", roleGrantGrantToNameAttr: "my_user"},
+			defaults: map[string]interface{}{preserveCaseAttr: false},
+		},
+		"grant on schema": {
+			resource: redshiftGrant(),
+			state: map[string]string{
+				grantGroupAttr: "analysts", grantObjectTypeAttr: "schema", grantSchemaAttr: "analytics",
+				grantPrivilegesAttr + ".#": "1", grantPrivilegesAttr + "." + strconv.Itoa(hashPrivilege("usage")): "usage",
+			},
+			config: map[string]interface{}{
+				grantGroupAttr: "analysts", grantObjectTypeAttr: "schema", grantSchemaAttr: "analytics", grantPrivilegesAttr: []interface{}{"usage"},
+			},
+			defaults: map[string]interface{}{preserveCaseAttr: false, grantAllSchemasAttr: false, grantValidateObjectsExistAttr: true},
+		},
+		"group": {
+			resource: redshiftGroup(),
+			state:    map[string]string{groupNameAttr: "analysts", groupUsersAttr + ".#": "0"},
+			config:   map[string]interface{}{groupNameAttr: "analysts"},
+			defaults: map[string]interface{}{groupAdoptExistingAttr: false, preserveCaseAttr: false},
+		},
+		"group membership": {
+			resource: redshiftGroupMembership(),
+			state: map[string]string{
+				groupNameAttr:         "analysts",
+				groupUsersAttr + ".#": "1", groupUsersAttr + "." + strconv.Itoa(schema.HashString("my_user")): "my_user",
+			},
+			config:   map[string]interface{}{groupNameAttr: "analysts", groupUsersAttr: []interface{}{"my_user"}},
+			defaults: map[string]interface{}{groupMembershipExclusiveAttr: false, preserveCaseAttr: false},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := planUpgradedState(t, tt.resource, tt.state, tt.config, tt.defaults); !diff.Empty() {
+				t.Errorf("Expected no changes but got %#v", diff.Attributes)
+			}
+		})
+	}
+}
+
+func TestSetDefaultIfUnset_KeepsStateValue(t *testing.T) {
+	d := redshiftRoleGrant().Data(&terraform.InstanceState{ID: "id", Attributes: map[string]string{preserveCaseAttr: "true"}})
+
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+
+	if !d.Get(preserveCaseAttr).(bool) {
+		t.Errorf("Expected %q to keep the value from state", preserveCaseAttr)
+	}
+}
+
+func TestNormalizePrivilege(t *testing.T) {
+	tests := map[string]string{
+		"select":    "select",
+		"SELECT":    "select",
+		"Insert":    "insert",
+		"TEMPORARY": "temp",
+		"temp":      "temp",
+	}
+	for privilege, expected := range tests {
+		if actual := normalizePrivilege(privilege); actual != expected {
+			t.Errorf("normalizePrivilege(%q) = %q, want %q", privilege, actual, expected)
+		}
+		if hashPrivilege(privilege) != hashPrivilege(expected) {
+			t.Errorf("expected %q to hash like %q", privilege, expected)
+		}
+	}
+}
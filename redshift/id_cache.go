@@ -0,0 +1,125 @@
+package redshift
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	idCacheKindUser   = "user"
+	idCacheKindGroup  = "group"
+	idCacheKindSchema = "schema"
+)
+
+// idCache memoizes the IDs of users, groups and schemas by name for the lifetime of a
+// connection pool, so resources referring to the same objects don't query the catalog
+// over and over again.
+//
+// Entries are keyed by the lower case name, so invalidating an object removes its ID however
+// the name was spelled. The name is compared exactly on lookup though, a name which only matches
+// an entry ignoring case is queried again, as it may be another object on clusters with case
+// sensitive identifiers.
+type idCache struct {
+	mu  sync.Mutex
+	ids map[string]idCacheEntry
+}
+
+type idCacheEntry struct {
+	kind string
+	name string
+	id   int
+}
+
+func newIDCache() *idCache {
+	return &idCache{ids: map[string]idCacheEntry{}}
+}
+
+func idCacheKey(kind, name string) string {
+	return kind + ":" + strings.ToLower(name)
+}
+
+// lookup returns the cached ID of the object of the given kind and name or calls query to retrieve it.
+// Failed lookups are not cached. A nil cache always calls query.
+func (c *idCache) lookup(kind, name string, query func() (int, error)) (int, error) {
+	if c == nil {
+		return query()
+	}
+	key := idCacheKey(kind, name)
+
+	c.mu.Lock()
+	entry, found := c.ids[key]
+	c.mu.Unlock()
+	if found && entry.name == name {
+		return entry.id, nil
+	}
+
+	id, err := query()
+	if err != nil {
+		c.invalidate(kind, name)
+		return 0, err
+	}
+
+	c.store(kind, name, id)
+	return id, nil
+}
+
+func (c *idCache) store(kind, name string, id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[idCacheKey(kind, name)] = idCacheEntry{kind: kind, name: name, id: id}
+}
+
+// invalidate removes the cached ID of the object of the given kind and name, it has to be
+// called whenever such an object is dropped or renamed.
+func (c *idCache) invalidate(kind, name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, idCacheKey(kind, name))
+}
+
+// clear removes all cached IDs, e.g. after a failed operation whose error might have been
+// caused by IDs of objects which were dropped or renamed outside of Terraform.
+func (c *idCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids = map[string]idCacheEntry{}
+}
+
+// idCacheChanges collects the IDs a transaction looked up after changing the database and the ones
+// it invalidated. They are only applied to the cache of the connection once the transaction commits,
+// so the IDs of objects created, dropped or renamed by a transaction which is rolled back never
+// reach it.
+type idCacheChanges struct {
+	ids         *idCache
+	invalidated []idCacheEntry
+}
+
+func newIDCacheChanges() *idCacheChanges {
+	return &idCacheChanges{ids: newIDCache()}
+}
+
+func (c *idCacheChanges) invalidate(kind, name string) {
+	c.ids.invalidate(kind, name)
+	c.invalidated = append(c.invalidated, idCacheEntry{kind: kind, name: name})
+}
+
+// applyTo removes the invalidated IDs from cache and adds the looked up ones.
+func (c *idCacheChanges) applyTo(cache *idCache) {
+	if cache == nil {
+		return
+	}
+	for _, entry := range c.invalidated {
+		cache.invalidate(entry.kind, entry.name)
+	}
+	c.ids.mu.Lock()
+	defer c.ids.mu.Unlock()
+	for _, entry := range c.ids.ids {
+		cache.store(entry.kind, entry.name, entry.id)
+	}
+}
@@ -0,0 +1,153 @@
+package redshift
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIDCache_Lookup(t *testing.T) {
+	cache := newIDCache()
+	queries := 0
+	query := func() (int, error) {
+		queries++
+		return 100, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		id, err := cache.lookup(idCacheKindUser, "alice", query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != 100 {
+			t.Errorf("expected ID 100, got %d", id)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("expected the catalog to be queried once, got %d queries", queries)
+	}
+
+	// The same name of another kind is a different object.
+	if _, err := cache.lookup(idCacheKindGroup, "alice", query); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("expected a group lookup to query the catalog, got %d queries", queries)
+	}
+
+	cache.invalidate(idCacheKindUser, "alice")
+	if _, err := cache.lookup(idCacheKindUser, "alice", query); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queries != 3 {
+		t.Errorf("expected an invalidated lookup to query the catalog, got %d queries", queries)
+	}
+}
+
+func TestIDCache_LookupError(t *testing.T) {
+	cache := newIDCache()
+	queryErr := errors.New("no such user")
+
+	if _, err := cache.lookup(idCacheKindUser, "bob", func() (int, error) { return 0, queryErr }); !errors.Is(err, queryErr) {
+		t.Fatalf("expected %v, got %v", queryErr, err)
+	}
+
+	id, err := cache.lookup(idCacheKindUser, "bob", func() (int, error) { return 101, nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != 101 {
+		t.Errorf("expected a failed lookup not to be cached, got ID %d", id)
+	}
+}
+
+func TestIDCache_Clear(t *testing.T) {
+	cache := newIDCache()
+	if _, err := cache.lookup(idCacheKindSchema, "public", func() (int, error) { return 2200, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cache.clear()
+
+	id, _ := cache.lookup(idCacheKindSchema, "public", func() (int, error) { return 2201, nil })
+	if id != 2201 {
+		t.Errorf("expected a cleared cache to query the catalog, got ID %d", id)
+	}
+}
+
+func TestIDCache_NameCase(t *testing.T) {
+	cache := newIDCache()
+	if _, err := cache.lookup(idCacheKindUser, "alice", func() (int, error) { return 100, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A name matching a cached one only ignoring case may be another user.
+	id, _ := cache.lookup(idCacheKindUser, "Alice", func() (int, error) { return 101, nil })
+	if id != 101 {
+		t.Errorf("expected a name with another case to query the catalog, got ID %d", id)
+	}
+
+	cache.invalidate(idCacheKindUser, "ALICE")
+	id, _ = cache.lookup(idCacheKindUser, "Alice", func() (int, error) { return 102, nil })
+	if id != 102 {
+		t.Errorf("expected invalidation to ignore the case of the name, got ID %d", id)
+	}
+}
+
+func TestTransaction_LookupID(t *testing.T) {
+	client := newTxLoggingClient(t)
+	db, err := client.Connect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cachedID := func(kind, name string) int {
+		id, _ := db.ids.lookup(kind, name, func() (int, error) { return 0, nil })
+		db.ids.invalidate(kind, name)
+		return id
+	}
+
+	// IDs looked up after a change are dropped when the transaction is rolled back.
+	tx, err := startTransaction(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tx.Exec(`CREATE GROUP "analysts"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id, err := tx.lookupID(idCacheKindGroup, "analysts", func() (int, error) { return 100, nil }); err != nil || id != 100 {
+		t.Fatalf("lookupID() = %d, %v, want 100", id, err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id := cachedID(idCacheKindGroup, "analysts"); id != 0 {
+		t.Errorf("expected the ID looked up in a rolled back transaction not to be cached, got %d", id)
+	}
+
+	// Invalidations and IDs looked up after a change are applied when the transaction commits.
+	if _, err := db.ids.lookup(idCacheKindUser, "alice", func() (int, error) { return 101, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tx, err = startTransaction(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tx.Exec(`ALTER USER "alice" RENAME TO "bob"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tx.invalidateID(idCacheKindUser, "alice")
+	if id, err := tx.lookupID(idCacheKindUser, "bob", func() (int, error) { return 101, nil }); err != nil || id != 101 {
+		t.Fatalf("lookupID() = %d, %v, want 101", id, err)
+	}
+	if id, _ := db.ids.lookup(idCacheKindUser, "alice", func() (int, error) { return 0, nil }); id != 101 {
+		t.Errorf("expected the invalidation to wait for the commit, got ID %d", id)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id := cachedID(idCacheKindUser, "alice"); id != 0 {
+		t.Errorf("expected the renamed user to be invalidated, got ID %d", id)
+	}
+	if id := cachedID(idCacheKindUser, "bob"); id != 101 {
+		t.Errorf("expected the ID looked up in the committed transaction to be cached, got %d", id)
+	}
+}
@@ -1,8 +1,10 @@
 package redshift
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,16 +22,17 @@ const (
 func redshiftAssumeRoleGrant() *schema.Resource {
 	return &schema.Resource{
 		Description: `
-Grants the permission to use an IAM role to a user or a role.
+Grants the permission to use an IAM role to a user, a group or a role.
 
 For more information, see [GRANT documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html).
 `,
 		CreateContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftAssumeRoleGrantCreate)),
-		ReadContext:   ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftAssumeRoleGrantRead)),
+		ReadContext:   IdempotentResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftAssumeRoleGrantRead)),
+		UpdateContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftAssumeRoleGrantUpdate)),
 		DeleteContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftAssumeRoleGrantDelete)),
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceRedshiftAssumeRoleGrantImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -44,19 +47,18 @@ For more information, see [GRANT documentation](https://docs.aws.amazon.com/reds
 				Type:         schema.TypeString,
 				Required:     true,
 				ForceNew:     true,
-				Description:  "The type of principal to grant the role to. Valid values are: 'USER', 'ROLE'.",
-				ValidateFunc: validation.StringInSlice([]string{"USER", "ROLE"}, false),
+				Description:  "The type of principal to grant the role to. Valid values are: 'USER', 'GROUP', 'ROLE'.",
+				ValidateFunc: validation.StringInSlice([]string{"USER", "GROUP", "ROLE"}, false),
 			},
 			assumeRoleGrantGrantToNameAttr: {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "The name of the user, or role to grant this role to.",
+				Description: "The name of the user, group or role to grant this role to.",
 			},
 			assumeRoleGrantPrivilegesAttr: {
 				Type:     schema.TypeSet,
 				Required: true,
-				ForceNew: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 					StateFunc: func(val interface{}) string {
@@ -65,7 +67,7 @@ For more information, see [GRANT documentation](https://docs.aws.amazon.com/reds
 					ValidateFunc: validation.StringInSlice([]string{"copy", "unload", "external function", "create model"}, true),
 				},
 				Set:         schema.HashString,
-				Description: "The list of privileges to apply. See [GRANT command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html) to see what privileges are available. 'ALL' cannot be used in this resource.",
+				Description: "The list of privileges to apply. See [GRANT command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html) to see what privileges are available. 'ALL' cannot be used in this resource. Changing the privileges only grants the added and revokes the removed ones, the kept ones remain usable throughout.",
 			},
 		},
 	}
@@ -87,24 +89,10 @@ func resourceRedshiftAssumeRoleGrantCreate(db *DBConnection, d *schema.ResourceD
 	}
 	defer deferredRollback(tx)
 
-	query := fmt.Sprintf("GRANT ASSUMEROLE ON %s", pq.QuoteLiteral(roleName))
-	switch grantToType {
-	case "USER":
-		query = fmt.Sprintf("%s TO %s",
-			query,
-			pq.QuoteIdentifier(grantToName),
-		)
-	case "ROLE":
-		query = fmt.Sprintf("%s TO ROLE %s",
-			query,
-			pq.QuoteIdentifier(grantToName),
-		)
-	default:
-		return fmt.Errorf("unsupported grant_to_type: %s", grantToType)
+	query, err := createAssumeRoleGrantQuery("GRANT", roleName, grantToType, grantToName, privileges)
+	if err != nil {
+		return err
 	}
-	query = fmt.Sprintf("%s FOR %s", query, strings.Join(privileges, ","))
-
-	log.Printf("[DEBUG] %s\n", query)
 
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("could not grant assumerole: %w", err)
@@ -124,34 +112,11 @@ func resourceRedshiftAssumeRoleGrantRead(db *DBConnection, d *schema.ResourceDat
 	grantToType := d.Get(assumeRoleGrantGrantToTypeAttr).(string)
 	grantToName := d.Get(assumeRoleGrantGrantToNameAttr).(string)
 
-	var query string
-	var copy, unload, externalFunction, createModel bool
-
-	query = `
-		SELECT
-			COALESCE(MAX(CASE WHEN command_type = 'COPY' THEN 1 ELSE 0 END), 0),
-			COALESCE(MAX(CASE WHEN command_type = 'UNLOAD' THEN 1 ELSE 0 END), 0),
-			COALESCE(MAX(CASE WHEN command_type = 'EXFUNC' THEN 1 ELSE 0 END), 0),
-			COALESCE(MAX(CASE WHEN command_type = 'CREATE MODEL' THEN 1 ELSE 0 END), 0)
-		FROM SVV_IAM_PRIVILEGES
-		WHERE iam_arn = $1
-			AND identity_name = $2
-			AND identity_type = LOWER($3)
-		`
-
-	log.Printf("[DEBUG] %s, $1=%s, $2=%s\n", query, roleName, grantToName)
-
-	err := db.QueryRow(query, roleName, grantToName, grantToType).Scan(&copy, &unload, &externalFunction, &createModel)
+	privileges, err := readAssumeRolePrivileges(db, roleName, grantToType, grantToName)
 	if err != nil {
-		return fmt.Errorf("failed to collect privileges: %w", err)
+		return err
 	}
 
-	var privileges []string
-	appendIfTrue(copy, "copy", &privileges)
-	appendIfTrue(unload, "unload", &privileges)
-	appendIfTrue(externalFunction, "external function", &privileges)
-	appendIfTrue(createModel, "create model", &privileges)
-
 	if len(privileges) == 0 {
 		log.Printf("[WARN] Assume role grant for %s to %s %s not found, removing from state", roleName, grantToType, grantToName)
 		d.SetId("")
@@ -178,24 +143,10 @@ func resourceRedshiftAssumeRoleGrantDelete(db *DBConnection, d *schema.ResourceD
 	}
 	defer deferredRollback(tx)
 
-	query := fmt.Sprintf("REVOKE ASSUMEROLE ON %s", pq.QuoteLiteral(roleName))
-	switch grantToType {
-	case "USER":
-		query = fmt.Sprintf("%s FROM %s",
-			query,
-			pq.QuoteIdentifier(grantToName),
-		)
-	case "ROLE":
-		query = fmt.Sprintf("%s FROM ROLE %s",
-			query,
-			pq.QuoteIdentifier(grantToName),
-		)
-	default:
-		return fmt.Errorf("unsupported grant_to_type: %s", grantToType)
+	query, err := createAssumeRoleGrantQuery("REVOKE", roleName, grantToType, grantToName, privileges)
+	if err != nil {
+		return err
 	}
-	query = fmt.Sprintf("%s FOR %s", query, strings.Join(privileges, ","))
-
-	log.Printf("[DEBUG] %s\n", query)
 
 	if _, err := tx.Exec(query); err != nil {
 		// If the role or grantee doesn't exist, the grant is already gone
@@ -217,6 +168,167 @@ func resourceRedshiftAssumeRoleGrantDelete(db *DBConnection, d *schema.ResourceD
 	return nil
 }
 
+func resourceRedshiftAssumeRoleGrantUpdate(db *DBConnection, d *schema.ResourceData) error {
+	roleName := d.Get(assumeRoleGrantRoleNameAttr).(string)
+	grantToType := d.Get(assumeRoleGrantGrantToTypeAttr).(string)
+	grantToName := d.Get(assumeRoleGrantGrantToNameAttr).(string)
+
+	var newPrivileges []string
+	for _, privilege := range setToStringList(d.Get(assumeRoleGrantPrivilegesAttr).(*schema.Set)) {
+		newPrivileges = append(newPrivileges, strings.ToLower(privilege))
+	}
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	// Only the changed scopes are granted and revoked, so the scopes which are kept remain usable throughout.
+	// They are diffed against the catalog, which may differ from the state if the grant was changed meanwhile.
+	currentPrivileges, err := readAssumeRolePrivileges(tx, roleName, grantToType, grantToName)
+	if err != nil {
+		return err
+	}
+	revoked, granted := assumeRolePrivilegesChanges(currentPrivileges, newPrivileges)
+
+	for _, change := range []struct {
+		verb       string
+		privileges []string
+	}{
+		{"REVOKE", revoked},
+		{"GRANT", granted},
+	} {
+		if len(change.privileges) == 0 {
+			continue
+		}
+		query, err := createAssumeRoleGrantQuery(change.verb, roleName, grantToType, grantToName, change.privileges)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("could not update assumerole privileges: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateAssumeRoleGrantID(roleName, strings.Join(newPrivileges, ","), grantToType, grantToName))
+
+	return resourceRedshiftAssumeRoleGrantRead(db, d)
+}
+
+// assumeRoleCommandTypes maps the command types of svv_iam_privileges to the privileges of the resource.
+var assumeRoleCommandTypes = map[string]string{
+	"COPY":         "copy",
+	"UNLOAD":       "unload",
+	"EXFUNC":       "external function",
+	"CREATE MODEL": "create model",
+}
+
+// readAssumeRolePrivileges returns the sorted privileges the grantee holds to use roleName, read from svv_iam_privileges.
+func readAssumeRolePrivileges(q queryer, roleName, grantToType, grantToName string) ([]string, error) {
+	query := `
+		SELECT DISTINCT command_type
+		FROM SVV_IAM_PRIVILEGES
+		WHERE iam_arn = $1
+			AND identity_name = $2
+			AND identity_type = LOWER($3)
+		`
+
+	rows, err := q.Query(query, roleName, grantToName, grantToType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect privileges: %w", err)
+	}
+	defer rows.Close()
+
+	privileges := []string{}
+	for rows.Next() {
+		var commandType string
+		if err := rows.Scan(&commandType); err != nil {
+			return nil, fmt.Errorf("failed to collect privileges: %w", err)
+		}
+		if privilege, ok := assumeRoleCommandTypes[strings.ToUpper(commandType)]; ok {
+			privileges = append(privileges, privilege)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to collect privileges: %w", err)
+	}
+	slices.Sort(privileges)
+
+	return privileges, nil
+}
+
+// assumeRolePrivilegesChanges returns the sorted privileges to revoke and to grant to get from the current to the
+// desired privileges.
+func assumeRolePrivilegesChanges(current, desired []string) (revoked, granted []string) {
+	for _, privilege := range current {
+		if !slices.Contains(desired, privilege) {
+			revoked = append(revoked, privilege)
+		}
+	}
+	for _, privilege := range desired {
+		if !slices.Contains(current, privilege) {
+			granted = append(granted, privilege)
+		}
+	}
+	slices.Sort(revoked)
+	slices.Sort(granted)
+	return revoked, granted
+}
+
+// resourceRedshiftAssumeRoleGrantImport restores the arguments which identify the grant
+// from an ID in the format role;<iam_role>;<privileges>;<grant_to_type>;<grant_to_name>.
+func resourceRedshiftAssumeRoleGrantImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ";")
+	if len(parts) != 5 || parts[0] != "role" {
+		return nil, fmt.Errorf("invalid assumerole grant ID %q, expected role;<iam_role>;<privileges>;<grant_to_type>;<grant_to_name>", d.Id())
+	}
+
+	grantToType := strings.ToUpper(parts[3])
+	if !slices.Contains([]string{"USER", "GROUP", "ROLE"}, grantToType) {
+		return nil, fmt.Errorf("unsupported grant_to_type %q in assumerole grant ID %q", parts[3], d.Id())
+	}
+
+	d.Set(assumeRoleGrantRoleNameAttr, parts[1])
+	d.Set(assumeRoleGrantGrantToTypeAttr, grantToType)
+	d.Set(assumeRoleGrantGrantToNameAttr, parts[4])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// createAssumeRoleGrantQuery returns the statement to GRANT or REVOKE the privileges to use roleName.
+func createAssumeRoleGrantQuery(verb, roleName, grantToType, grantToName string, privileges []string) (string, error) {
+	var grantee string
+	switch grantToType {
+	case "USER":
+		grantee = pq.QuoteIdentifier(grantToName)
+	case "GROUP":
+		grantee = "GROUP " + pq.QuoteIdentifier(grantToName)
+	case "ROLE":
+		grantee = "ROLE " + pq.QuoteIdentifier(grantToName)
+	default:
+		return "", fmt.Errorf("unsupported grant_to_type: %s", grantToType)
+	}
+
+	toOrFrom := "TO"
+	if verb == "REVOKE" {
+		toOrFrom = "FROM"
+	}
+
+	return fmt.Sprintf("%s ASSUMEROLE ON %s %s %s FOR %s",
+		verb,
+		pq.QuoteLiteral(roleName),
+		toOrFrom,
+		grantee,
+		strings.Join(privileges, ","),
+	), nil
+}
+
 func generateAssumeRoleGrantID(roleName, privilege, grantToType, grantToName string) string {
 	return fmt.Sprintf("role;%s;%s;%s;%s",
 		strings.ToLower(roleName),
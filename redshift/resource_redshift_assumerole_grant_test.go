@@ -3,9 +3,11 @@ package redshift
 import (
 	"fmt"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -84,3 +86,209 @@ resource "redshift_assumerole_grant" "grant" {
 		},
 	})
 }
+
+func TestAccRedshiftAssumeRoleGrant_GroupUpdatePrivileges(t *testing.T) {
+	iamRoleArn := os.Getenv("REDSHIFT_IAM_ROLE_ARN")
+	if iamRoleArn == "" {
+		t.Skip("REDSHIFT_IAM_ROLE_ARN not set, skipping acceptance test")
+	}
+	groupName := generateRandomObjectName("acc_test_assume_grant_group")
+
+	configTemplate := `
+resource "redshift_group" "group" {
+	name = %[1]q
+}
+
+resource "redshift_assumerole_grant" "grant" {
+	iam_role      = %[2]q
+	grant_to_type = "GROUP"
+	grant_to_name = redshift_group.group.name
+	privileges    = %[3]s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, groupName, iamRoleArn, `["copy"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_assumerole_grant.grant", "grant_to_type", "GROUP"),
+					testCheckTypeSetElems("redshift_assumerole_grant.grant", "privileges", "copy"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, groupName, iamRoleArn, `["unload", "external function"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckTypeSetElems("redshift_assumerole_grant.grant", "privileges", "unload", "external function"),
+				),
+			},
+			{
+				ResourceName:      "redshift_assumerole_grant.grant",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccRedshiftAssumeRoleGrant_UserAddScope adds a scope in place, the user must keep COPY throughout.
+func TestAccRedshiftAssumeRoleGrant_UserAddScope(t *testing.T) {
+	iamRoleArn := os.Getenv("REDSHIFT_IAM_ROLE_ARN")
+	if iamRoleArn == "" {
+		t.Skip("REDSHIFT_IAM_ROLE_ARN not set, skipping acceptance test")
+	}
+	userName := generateRandomObjectName("acc_test_assume_grant_scope")
+
+	configTemplate := `
+resource "redshift_user" "user" {
+	name = %[1]q
+}
+
+resource "redshift_assumerole_grant" "grant" {
+	iam_role      = %[2]q
+	grant_to_type = "USER"
+	grant_to_name = redshift_user.user.name
+	privileges    = %[3]s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, userName, iamRoleArn, `["COPY"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckTypeSetElems("redshift_assumerole_grant.grant", "privileges", "copy"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, userName, iamRoleArn, `["COPY", "UNLOAD"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckTypeSetElems("redshift_assumerole_grant.grant", "privileges", "copy", "unload"),
+				),
+			},
+		},
+	})
+}
+
+func TestAssumeRoleGrantPrivilegesUpdateInPlace(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "role;arn:aws:iam::123456789012:role/myrole;copy;user;alice",
+		Attributes: map[string]string{
+			assumeRoleGrantRoleNameAttr:    "arn:aws:iam::123456789012:role/myrole",
+			assumeRoleGrantGrantToTypeAttr: "USER",
+			assumeRoleGrantGrantToNameAttr: "alice",
+			"privileges.#":                 "1",
+			"privileges.0":                 "copy",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		assumeRoleGrantRoleNameAttr:    "arn:aws:iam::123456789012:role/myrole",
+		assumeRoleGrantGrantToTypeAttr: "USER",
+		assumeRoleGrantGrantToNameAttr: "alice",
+		assumeRoleGrantPrivilegesAttr:  []interface{}{"copy", "unload"},
+	})
+
+	diff, err := redshiftAssumeRoleGrant().Diff(t.Context(), state, config, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff == nil || diff.Empty() {
+		t.Fatal("expected a diff adding unload")
+	}
+	if diff.RequiresNew() {
+		t.Error("expected the privileges to be updated in place")
+	}
+}
+
+func TestAssumeRolePrivilegesChanges(t *testing.T) {
+	tests := map[string]struct {
+		current, desired []string
+		revoked, granted []string
+	}{
+		"add unload":     {current: []string{"copy"}, desired: []string{"copy", "unload"}, granted: []string{"unload"}},
+		"replace copy":   {current: []string{"copy", "unload"}, desired: []string{"unload", "external function"}, revoked: []string{"copy"}, granted: []string{"external function"}},
+		"unchanged":      {current: []string{"copy"}, desired: []string{"copy"}},
+		"revoked before": {current: []string{}, desired: []string{"create model", "copy"}, granted: []string{"copy", "create model"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			revoked, granted := assumeRolePrivilegesChanges(tt.current, tt.desired)
+			if !slices.Equal(revoked, tt.revoked) || !slices.Equal(granted, tt.granted) {
+				t.Errorf("expected to revoke %v and grant %v, got %v and %v", tt.revoked, tt.granted, revoked, granted)
+			}
+		})
+	}
+}
+
+func TestCreateAssumeRoleGrantQuery(t *testing.T) {
+	arn := "arn:aws:iam::123456789012:role/myrole"
+	tests := map[string]struct {
+		verb        string
+		grantToType string
+		expected    string
+	}{
+		"grant to user": {
+			verb:        "GRANT",
+			grantToType: "USER",
+			expected:    `GRANT ASSUMEROLE ON 'arn:aws:iam::123456789012:role/myrole' TO "principal" FOR copy,unload`,
+		},
+		"grant to group": {
+			verb:        "GRANT",
+			grantToType: "GROUP",
+			expected:    `GRANT ASSUMEROLE ON 'arn:aws:iam::123456789012:role/myrole' TO GROUP "principal" FOR copy,unload`,
+		},
+		"revoke from role": {
+			verb:        "REVOKE",
+			grantToType: "ROLE",
+			expected:    `REVOKE ASSUMEROLE ON 'arn:aws:iam::123456789012:role/myrole' FROM ROLE "principal" FOR copy,unload`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := createAssumeRoleGrantQuery(tt.verb, arn, tt.grantToType, "principal", []string{"copy", "unload"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := createAssumeRoleGrantQuery("GRANT", arn, "PUBLIC", "principal", []string{"copy"}); err == nil {
+		t.Error("expected an error for an unsupported grant_to_type")
+	}
+}
+
+func TestResourceRedshiftAssumeRoleGrantImport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftAssumeRoleGrant().Schema, map[string]interface{}{})
+	d.SetId("role;arn:aws:iam::123456789012:role/myrole;copy,external function;group;analysts")
+
+	if _, err := resourceRedshiftAssumeRoleGrantImport(t.Context(), d, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for attr, expected := range map[string]string{
+		assumeRoleGrantRoleNameAttr:    "arn:aws:iam::123456789012:role/myrole",
+		assumeRoleGrantGrantToTypeAttr: "GROUP",
+		assumeRoleGrantGrantToNameAttr: "analysts",
+	} {
+		if got := d.Get(attr).(string); got != expected {
+			t.Errorf("expected %s to be %q, got %q", attr, expected, got)
+		}
+	}
+
+	for _, id := range []string{"arn:aws:iam::123456789012:role/myrole", "role;arn;copy;public;analysts"} {
+		d.SetId(id)
+		if _, err := resourceRedshiftAssumeRoleGrantImport(t.Context(), d, nil); err == nil {
+			t.Errorf("expected an error importing %q", id)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package redshift
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -28,7 +29,7 @@ func redshiftDatabase() *schema.Resource {
 	return &schema.Resource{
 		Description:   `Defines a local database.`,
 		CreateContext: ResourceFunc(resourceRedshiftDatabaseCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftDatabaseRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftDatabaseRead),
 		UpdateContext: ResourceFunc(resourceRedshiftDatabaseUpdate),
 		DeleteContext: ResourceFunc(resourceRedshiftDatabaseDelete),
 		Importer: &schema.ResourceImporter{
@@ -156,7 +157,6 @@ func resourceRedshiftDatabaseCreateFromDatashare(db *DBConnection, d *schema.Res
 	// eagerly get the resource ID in case the below statements fail for some reason
 	var oid string
 	query = "SELECT oid FROM pg_database WHERE datname = $1"
-	log.Printf("[DEBUG] get oid from database: %s\n", query)
 	if err := db.QueryRow(query, strings.ToLower(dbName)).Scan(&oid); err != nil {
 		return err
 	}
@@ -210,14 +210,12 @@ func resourceRedshiftDatabaseCreateInternal(db *DBConnection, d *schema.Resource
 	if v, ok := d.GetOk(databaseConnLimitAttr); ok {
 		query = fmt.Sprintf("%s CONNECTION LIMIT %d", query, v.(int))
 	}
-	log.Printf("[DEBUG] create database %s: %s\n", dbName, query)
 	if _, err := db.Exec(query); err != nil {
 		return err
 	}
 
 	var oid string
 	query = "SELECT oid FROM pg_database WHERE datname = $1"
-	log.Printf("[DEBUG] get oid from database: %s\n", query)
 	if err := db.QueryRow(query, strings.ToLower(dbName)).Scan(&oid); err != nil {
 		return err
 	}
@@ -248,7 +246,6 @@ LEFT JOIN svv_datashares
 	ON (svv_redshift_databases.database_name = svv_datashares.consumer_database AND svv_redshift_databases.database_type = 'shared' AND svv_datashares.share_type = 'INBOUND')
 WHERE pg_database_info.datid = $1
 `
-	log.Printf("[DEBUG] read database: %s\n", query)
 	err := db.QueryRow(query, d.Id()).Scan(&name, &owner, &connLimit, &databaseType, &shareName, &producerAccount, &producerNamespace)
 
 	if err != nil {
@@ -313,7 +310,7 @@ func resourceRedshiftDatabaseUpdate(db *DBConnection, d *schema.ResourceData) er
 	return resourceRedshiftDatabaseRead(db, d)
 }
 
-func setDatabaseName(tx *sql.Tx, d *schema.ResourceData) error {
+func setDatabaseName(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(databaseNameAttr) {
 		return nil
 	}
@@ -327,7 +324,6 @@ func setDatabaseName(tx *sql.Tx, d *schema.ResourceData) error {
 	}
 
 	query := fmt.Sprintf("ALTER DATABASE %s RENAME TO %s", pq.QuoteIdentifier(oldValue), pq.QuoteIdentifier(newValue))
-	log.Printf("[DEBUG] renaming database %s to %s: %s\n", oldValue, newValue, query)
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating database NAME: %w", err)
 	}
@@ -335,7 +331,7 @@ func setDatabaseName(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setDatabaseOwner(tx *sql.Tx, d *schema.ResourceData) error {
+func setDatabaseOwner(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(databaseOwnerAttr) {
 		return nil
 	}
@@ -344,12 +340,11 @@ func setDatabaseOwner(tx *sql.Tx, d *schema.ResourceData) error {
 	databaseOwner := d.Get(databaseOwnerAttr).(string)
 
 	query := fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(databaseOwner))
-	log.Printf("[DEBUG] changing database owner: %s\n", query)
 	_, err := tx.Exec(query)
 	return err
 }
 
-func setDatabaseConnLimit(tx *sql.Tx, d *schema.ResourceData) error {
+func setDatabaseConnLimit(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(databaseConnLimitAttr) {
 		return nil
 	}
@@ -357,7 +352,6 @@ func setDatabaseConnLimit(tx *sql.Tx, d *schema.ResourceData) error {
 	databaseName := d.Get(databaseNameAttr).(string)
 	connLimit := d.Get(databaseConnLimitAttr).(int)
 	query := fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT %d", pq.QuoteIdentifier(databaseName), connLimit)
-	log.Printf("[DEBUG] changing database connection limit: %s\n", query)
 	_, err := tx.Exec(query)
 	return err
 }
@@ -365,8 +359,17 @@ func setDatabaseConnLimit(tx *sql.Tx, d *schema.ResourceData) error {
 func resourceRedshiftDatabaseDelete(db *DBConnection, d *schema.ResourceData) error {
 	databaseName := d.Get(databaseNameAttr).(string)
 
+	// Redshift doesn't support DROP DATABASE IF EXISTS.
+	var exists int
+	if err := db.QueryRow("SELECT 1 FROM pg_database WHERE datname = $1", databaseName).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] database %q does not exist, already dropped\n", databaseName)
+			return nil
+		}
+		return fmt.Errorf("could not check if database %q exists: %w", databaseName, err)
+	}
+
 	query := fmt.Sprintf("DROP DATABASE %s", pqQuoteLiteral(databaseName))
-	log.Printf("[DEBUG] dropping database %s: %s\n", databaseName, query)
 	_, err := db.Exec(query)
 	return err
 }
@@ -39,6 +39,23 @@ func TestAccResourceRedshiftDatabase_Basic(t *testing.T) {
 	})
 }
 
+func TestAccResourceRedshiftDatabase_DeleteDroppedOutOfBand(t *testing.T) {
+	dbName := generateRandomObjectName("tf_acc_database_dropped")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceRedshiftDatabaseConfigBasic(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeleteAfterDroppedOutOfBand("redshift_database.db", "redshift_database", fmt.Sprintf("DROP DATABASE %s", dbName)),
+				),
+			},
+		},
+	})
+}
+
 func testAccResourceRedshiftDatabaseConfigBasic(dbName string) string {
 	return fmt.Sprintf(`
 resource "redshift_database" "db" {
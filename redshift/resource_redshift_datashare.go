@@ -34,7 +34,7 @@ Note: Data sharing is only supported on certain Redshift instance families,
 such as RA3.
 `,
 		CreateContext: ResourceFunc(resourceRedshiftDatashareCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftDatashareRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftDatashareRead),
 		UpdateContext: ResourceFunc(resourceRedshiftDatashareUpdate),
 		DeleteContext: ResourceFunc(resourceRedshiftDatashareDelete),
 		Importer: &schema.ResourceImporter{
@@ -106,14 +106,12 @@ func resourceRedshiftDatashareCreate(db *DBConnection, d *schema.ResourceData) e
 	shareName := d.Get(dataShareNameAttr).(string)
 
 	query := fmt.Sprintf("CREATE DATASHARE %s SET PUBLICACCESSIBLE = %t", pq.QuoteIdentifier(shareName), d.Get(dataSharePublicAccessibleAttr).(bool))
-	log.Printf("[DEBUG] %s\n", query)
 	if _, err := tx.Exec(query); err != nil {
 		return err
 	}
 
 	var shareId string
 	query = "SELECT share_id FROM SVV_DATASHARES WHERE share_type = 'OUTBOUND' AND share_name = $1"
-	log.Printf("[DEBUG] %s, $1=%s\n", query, strings.ToLower(shareName))
 	if err := tx.QueryRow(query, strings.ToLower(shareName)).Scan(&shareId); err != nil {
 		return err
 	}
@@ -122,7 +120,6 @@ func resourceRedshiftDatashareCreate(db *DBConnection, d *schema.ResourceData) e
 
 	if owner, ownerIsSet := d.GetOk(dataShareOwnerAttr); ownerIsSet {
 		query = fmt.Sprintf("ALTER DATASHARE %s OWNER TO %s", pq.QuoteIdentifier(strings.ToLower(shareName)), pq.QuoteIdentifier(strings.ToLower(owner.(string))))
-		log.Printf("[DEBUG] %s\n", query)
 		_, err = tx.Exec(query)
 		if err != nil {
 			return err
@@ -143,7 +140,7 @@ func resourceRedshiftDatashareCreate(db *DBConnection, d *schema.ResourceData) e
 	return resourceRedshiftDatashareRead(db, d)
 }
 
-func addSchemaToDatashare(tx *sql.Tx, shareName string, schemaName string) error {
+func addSchemaToDatashare(tx *transaction, shareName string, schemaName string) error {
 	err := resourceRedshiftDatashareAddSchema(tx, shareName, schemaName)
 	if err != nil {
 		return err
@@ -156,9 +153,8 @@ func addSchemaToDatashare(tx *sql.Tx, shareName string, schemaName string) error
 	return err
 }
 
-func resourceRedshiftDatashareAddSchema(tx *sql.Tx, shareName string, schemaName string) error {
+func resourceRedshiftDatashareAddSchema(tx *transaction, shareName string, schemaName string) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s ADD SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
-	log.Printf("[DEBUG] %s\n", query)
 	_, err := tx.Exec(query)
 	if err != nil {
 		// if the schema is already in the datashare we get a "duplicate schema" error code. This is fine.
@@ -174,26 +170,23 @@ func resourceRedshiftDatashareAddSchema(tx *sql.Tx, shareName string, schemaName
 		}
 	}
 	query = fmt.Sprintf("ALTER DATASHARE %s SET INCLUDENEW = TRUE FOR SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
-	log.Printf("[DEBUG] %s\n", query)
 	_, err = tx.Exec(query)
 	return err
 }
 
-func resourceRedshiftDatashareAddAllFunctions(tx *sql.Tx, shareName string, schemaName string) error {
+func resourceRedshiftDatashareAddAllFunctions(tx *transaction, shareName string, schemaName string) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s ADD ALL FUNCTIONS IN SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
-	log.Printf("[DEBUG] %s", query)
 	_, err := tx.Exec(query)
 	return err
 }
 
-func resourceRedshiftDatashareAddAllTables(tx *sql.Tx, shareName string, schemaName string) error {
+func resourceRedshiftDatashareAddAllTables(tx *transaction, shareName string, schemaName string) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s ADD ALL TABLES IN SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
-	log.Printf("[DEBUG] %s\n", query)
 	_, err := tx.Exec(query)
 	return err
 }
 
-func removeSchemaFromDatashare(tx *sql.Tx, shareName string, schemaName string) error {
+func removeSchemaFromDatashare(tx *transaction, shareName string, schemaName string) error {
 	err := resourceRedshiftDatashareRemoveAllFunctions(tx, shareName, schemaName)
 	if err != nil {
 		return err
@@ -206,23 +199,20 @@ func removeSchemaFromDatashare(tx *sql.Tx, shareName string, schemaName string)
 	return err
 }
 
-func resourceRedshiftDatashareRemoveAllFunctions(tx *sql.Tx, shareName string, schemaName string) error {
+func resourceRedshiftDatashareRemoveAllFunctions(tx *transaction, shareName string, schemaName string) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s REMOVE ALL FUNCTIONS IN SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
-	log.Printf("[DEBUG] %s\n", query)
 	_, err := tx.Exec(query)
 	return err
 }
 
-func resourceRedshiftDatashareRemoveAllTables(tx *sql.Tx, shareName string, schemaName string) error {
+func resourceRedshiftDatashareRemoveAllTables(tx *transaction, shareName string, schemaName string) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s REMOVE ALL TABLES IN SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
-	log.Printf("[DEBUG] %s\n", query)
 	_, err := tx.Exec(query)
 	return err
 }
 
-func resourceRedshiftDatashareRemoveSchema(tx *sql.Tx, shareName string, schemaName string) error {
+func resourceRedshiftDatashareRemoveSchema(tx *transaction, shareName string, schemaName string) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s REMOVE SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
-	log.Printf("[DEBUG] %s\n", query)
 	_, err := tx.Exec(query)
 	if err != nil {
 		// if the schema is not already in the datashare we get a "datashare does not contain schema" error code. This is fine.
@@ -260,7 +250,6 @@ func resourceRedshiftDatashareRead(db *DBConnection, d *schema.ResourceData) err
 	LEFT JOIN pg_user ON svv_datashares.share_owner = pg_user.usesysid
 	WHERE share_type = 'OUTBOUND'
 	AND share_id = $1`
-	log.Printf("[DEBUG] %s, $1=%s\n", query, d.Id())
 	err = tx.QueryRow(query, d.Id()).Scan(&shareName, &owner, &publicAccessible, &producerAccount, &producerNamespace, &created)
 	if err != nil {
 		return err
@@ -284,7 +273,7 @@ func resourceRedshiftDatashareRead(db *DBConnection, d *schema.ResourceData) err
 	return nil
 }
 
-func readDatashareSchemas(tx *sql.Tx, shareName string, d *schema.ResourceData) error {
+func readDatashareSchemas(tx *transaction, shareName string, d *schema.ResourceData) error {
 	query := `
 	SELECT
 		object_name
@@ -293,7 +282,6 @@ func readDatashareSchemas(tx *sql.Tx, shareName string, d *schema.ResourceData)
 	AND object_type = 'schema'
 	AND share_name = $1
 `
-	log.Printf("[DEBUG] %s, $1=%s\n", query, shareName)
 	rows, err := tx.Query(query, shareName)
 	if err != nil {
 		return err
@@ -338,7 +326,7 @@ func resourceRedshiftDatashareUpdate(db *DBConnection, d *schema.ResourceData) e
 	return resourceRedshiftDatashareRead(db, d)
 }
 
-func setDatashareOwner(tx *sql.Tx, d *schema.ResourceData) error {
+func setDatashareOwner(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(dataShareOwnerAttr) {
 		return nil
 	}
@@ -352,14 +340,13 @@ func setDatashareOwner(tx *sql.Tx, d *schema.ResourceData) error {
 	}
 
 	query := fmt.Sprintf("ALTER DATASHARE %s OWNER TO %s", pq.QuoteIdentifier(shareName), newValue)
-	log.Printf("[DEBUG] %s\n", query)
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating datashare OWNER: %w", err)
 	}
 	return nil
 }
 
-func setDatasharePubliclyAccessble(tx *sql.Tx, d *schema.ResourceData) error {
+func setDatasharePubliclyAccessble(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(dataSharePublicAccessibleAttr) {
 		return nil
 	}
@@ -367,14 +354,13 @@ func setDatasharePubliclyAccessble(tx *sql.Tx, d *schema.ResourceData) error {
 	shareName := d.Get(dataShareNameAttr).(string)
 	newValue := d.Get(dataSharePublicAccessibleAttr).(bool)
 	query := fmt.Sprintf("ALTER DATASHARE %s SET PUBLICACCESSIBLE %t", pq.QuoteIdentifier(shareName), newValue)
-	log.Printf("[DEBUG] %s\n", query)
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating datashare PUBLICACCESSBILE: %w", err)
 	}
 	return nil
 }
 
-func setDatashareSchemas(tx *sql.Tx, d *schema.ResourceData) error {
+func setDatashareSchemas(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(dataShareSchemasAttr) {
 		return nil
 	}
@@ -421,7 +407,6 @@ func resourceRedshiftDatashareDelete(db *DBConnection, d *schema.ResourceData) e
 		return err
 	}
 	query = fmt.Sprintf("DROP DATASHARE %s", pq.QuoteIdentifier(shareName))
-	log.Printf("[DEBUG] %s\n", query)
 	_, err = tx.Exec(query)
 	if err != nil {
 		return err
@@ -3,7 +3,6 @@ package redshift
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -29,7 +28,7 @@ func redshiftDatasharePrivilege() *schema.Resource {
 			"\n"+
 			"Note: Data sharing is only supported on certain instance families, such as RA3.", datasharePrivilegeNamespaceAttr, datasharePrivilegeAccountAttr),
 		CreateContext: ResourceFunc(resourceRedshiftDatasharePrivilegeCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftDatasharePrivilegeRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftDatasharePrivilegeRead),
 		DeleteContext: ResourceFunc(resourceRedshiftDatasharePrivilegeDelete),
 		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
 			// Exactly one of "namespace" or "account" must be specified, however
@@ -114,7 +113,6 @@ func resourceRedshiftDatasharePrivilegeCreate(db *DBConnection, d *schema.Resour
 	} else {
 		return fmt.Errorf("either %s or %s is required", datasharePrivilegeNamespaceAttr, datasharePrivilegeAccountAttr)
 	}
-	log.Printf("[DEBUG] %s\n", query)
 	if _, err := db.Exec(query); err != nil {
 		return err
 	}
@@ -148,7 +146,6 @@ WHERE
 AND
   consumer_namespace = $2`
 
-	log.Printf("[DEBUG] %s\n", query)
 	err := db.QueryRow(query, shareName, consumerNamespace).Scan(&shareDate)
 	if err != nil {
 		return err
@@ -170,7 +167,6 @@ WHERE
 AND
   consumer_account = $2`
 
-	log.Printf("[DEBUG] %s\n", query)
 	err := db.QueryRow(query, shareName, consumerAccount).Scan(&shareDate)
 	if err != nil {
 		return err
@@ -191,7 +187,6 @@ func resourceRedshiftDatasharePrivilegeDelete(db *DBConnection, d *schema.Resour
 	} else if consumerAccountSet {
 		query = fmt.Sprintf("%s ACCOUNT '%s'", query, consumerAccountRaw.(string))
 	}
-	log.Printf("[DEBUG] %s\n", query)
 
 	_, err := db.Exec(query)
 	return err
@@ -1,37 +1,50 @@
 package redshift
 
 import (
-	"database/sql"
-	"errors"
+	"context"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
 )
 
 const (
-	defaultPrivilegesUserAttr       = "user"
-	defaultPrivilegesGroupAttr      = "group"
-	defaultPrivilegesRoleAttr       = "role"
-	defaultPrivilegesOwnerAttr      = "owner"
-	defaultPrivilegesSchemaAttr     = "schema"
-	defaultPrivilegesPrivilegesAttr = "privileges"
-	defaultPrivilegesObjectTypeAttr = "object_type"
+	defaultPrivilegesUserAttr        = "user"
+	defaultPrivilegesGroupAttr       = "group"
+	defaultPrivilegesRoleAttr        = "role"
+	defaultPrivilegesOwnerAttr       = "owner"
+	defaultPrivilegesSchemaAttr      = "schema"
+	defaultPrivilegesSchemasAttr     = "schemas"
+	defaultPrivilegesPrivilegesAttr  = "privileges"
+	defaultPrivilegesObjectTypeAttr  = "object_type"
+	defaultPrivilegesOtherOwnersAttr = "other_owners"
 
 	defaultPrivilegesAllSchemasID = 0
 )
 
 var defaultPrivilegesAllowedObjectTypes = []string{
 	"table",
+	"function",
+	"procedure",
+}
+
+// defaultPrivilegesObjectTypePrivileges lists the privileges which can be granted by default per object type,
+// in the order they are read into the state.
+var defaultPrivilegesObjectTypePrivileges = map[string][]string{
+	"table":     {"select", "update", "insert", "delete", "drop", "references", "truncate", "alter"},
+	"function":  {"execute"},
+	"procedure": {"execute"},
 }
 
 func redshiftDefaultPrivileges() *schema.Resource {
 	return &schema.Resource{
-		Description: `Defines the default set of access privileges to be applied to objects that are created in the future by the specified user. By default, users can change only their own default access privileges. Only a superuser can specify default privileges for other users.`,
-		ReadContext: ResourceFunc(resourceRedshiftDefaultPrivilegesRead),
+		Description: `Defines the default set of access privileges to be applied to objects that are created in the future by the specified user. By default, users can change only their own default access privileges. Only a superuser can specify default privileges for other users. Revokes never cascade: Redshift only supports ` + "`RESTRICT`" + ` with ` + "`ALTER DEFAULT PRIVILEGES`" + `, so privileges the grantees passed on to others are not revoked along with theirs.`,
+		ReadContext: IdempotentResourceFunc(resourceRedshiftDefaultPrivilegesRead),
 		CreateContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesCreate),
 		),
@@ -42,13 +55,31 @@ func redshiftDefaultPrivileges() *schema.Resource {
 		UpdateContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesCreate),
 		),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedshiftDefaultPrivilegesImport,
+		},
+		Timeouts: operationTimeouts(),
+		CustomizeDiff: customdiff.All(
+			validatePrivilegesDiff(defaultPrivilegesPrivilegesAttr, defaultPrivilegesObjectTypeAttr),
+			validateUserExistsDiff(defaultPrivilegesOwnerAttr),
+		),
 
 		Schema: map[string]*schema.Schema{
 			defaultPrivilegesSchemaAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "If set, the specified default privileges are applied to new objects created in the specified schema. In this case, the user or user group that is the target of ALTER DEFAULT PRIVILEGES must have CREATE privilege for the specified schema. Default privileges that are specific to a schema are added to existing global default privileges. By default, default privileges are applied globally to the entire database.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{defaultPrivilegesSchemasAttr},
+				Description:   "If set, the specified default privileges are applied to new objects created in the specified schema. In this case, the user or user group that is the target of ALTER DEFAULT PRIVILEGES must have CREATE privilege for the specified schema. Default privileges that are specific to a schema are added to existing global default privileges. By default, default privileges are applied globally to the entire database.",
+			},
+			defaultPrivilegesSchemasAttr: {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				MinItems:      1,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{defaultPrivilegesSchemaAttr},
+				Description:   "Like `schema`, but applies the same default privileges to new objects created in each of the specified schemas. The privileges are only read as granted if they are granted in all of the schemas, so a schema missing some of them is updated on the next apply. Schemas can be added and removed without recreating the resource.",
 			},
 			defaultPrivilegesGroupAttr: {
 				Type:         schema.TypeString,
@@ -73,9 +104,10 @@ func redshiftDefaultPrivileges() *schema.Resource {
 			},
 			defaultPrivilegesOwnerAttr: {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
-				Description: "The name of the user for which default privileges are defined. Only a superuser can specify default privileges for other users.",
+				Description: "The name of the user for which default privileges are defined, defaults to the user the provider is connected as. Only a superuser can specify default privileges for other users, a user can define its own default privileges without being a superuser.",
 			},
 			defaultPrivilegesObjectTypeAttr: {
 				Type:         schema.TypeString,
@@ -88,20 +120,28 @@ func redshiftDefaultPrivileges() *schema.Resource {
 				Type:     schema.TypeSet,
 				Required: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
-					StateFunc: func(val interface{}) string {
-						return strings.ToLower(val.(string))
-					},
+					Type:      schema.TypeString,
+					StateFunc: normalizePrivilege,
 				},
+				Set:         hashPrivilege,
+				Description: "The list of privileges to apply as default privileges. See [ALTER DEFAULT PRIVILEGES command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_DEFAULT_PRIVILEGES.html) to see what privileges are available to which object type. An empty list revokes all default privileges of the grantee for the object type, the same way for tables, functions and procedures.",
+			},
+			defaultPrivilegesOtherOwnersAttr: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
 				Set:         schema.HashString,
-				Description: "The list of privileges to apply as default privileges. See [ALTER DEFAULT PRIVILEGES command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_DEFAULT_PRIVILEGES.html) to see what privileges are available to which object type.",
+				Description: "The other users which define default privileges for the same grantee, schema and object type. They are not managed by this resource, but apply to the objects these users create. When only other users define default privileges, a warning is shown, as the owner is likely misconfigured.",
 			},
 		},
 	}
 }
 
 func resourceRedshiftDefaultPrivilegesDelete(db *DBConnection, d *schema.ResourceData) error {
-	revokeAlterDefaultQuery := createAlterDefaultsRevokeQuery(d)
+	connectedUser, err := getConnectedUsername(db)
+	if err != nil {
+		return err
+	}
 
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -109,8 +149,10 @@ func resourceRedshiftDefaultPrivilegesDelete(db *DBConnection, d *schema.Resourc
 	}
 	defer deferredRollback(tx)
 
-	if _, err := tx.Exec(revokeAlterDefaultQuery); err != nil {
-		return err
+	for _, schemaName := range defaultPrivilegesSchemaNames(d) {
+		if _, err := tx.Exec(createAlterDefaultsRevokeQuery(d, schemaName, connectedUser)); err != nil {
+			return err
+		}
 	}
 
 	return tx.Commit()
@@ -129,22 +171,40 @@ func resourceRedshiftDefaultPrivilegesCreate(db *DBConnection, d *schema.Resourc
 		return fmt.Errorf(`invalid privileges list %+v for object type %q`, privileges, objectType)
 	}
 
+	connectedUser, err := getConnectedUsername(db)
+	if err != nil {
+		return err
+	}
+	if _, ok := d.GetOk(defaultPrivilegesOwnerAttr); !ok {
+		d.Set(defaultPrivilegesOwnerAttr, connectedUser)
+	}
+
 	tx, err := startTransaction(db.client)
 	if err != nil {
 		return err
 	}
 	defer deferredRollback(tx)
 
-	revokeAlterDefaultQuery := createAlterDefaultsRevokeQuery(d)
-	if _, err := tx.Exec(revokeAlterDefaultQuery); err != nil {
-		return err
+	// Schemas removed from schemas on update keep their default privileges unless they are revoked as well.
+	schemaNames := defaultPrivilegesSchemaNames(d)
+	oldSchemas, _ := d.GetChange(defaultPrivilegesSchemasAttr)
+	for _, schemaName := range setToStringList(oldSchemas.(*schema.Set)) {
+		if !slices.Contains(schemaNames, schemaName) {
+			if _, err := tx.Exec(createAlterDefaultsRevokeQuery(d, schemaName, connectedUser)); err != nil {
+				return err
+			}
+		}
 	}
 
-	if len(privileges) > 0 {
-		alterDefaultQuery := createAlterDefaultsGrantQuery(d, privileges)
-		if _, err := tx.Exec(alterDefaultQuery); err != nil {
+	for _, schemaName := range schemaNames {
+		if _, err := tx.Exec(createAlterDefaultsRevokeQuery(d, schemaName, connectedUser)); err != nil {
 			return err
 		}
+		if len(privileges) > 0 {
+			if _, err := tx.Exec(createAlterDefaultsGrantQuery(d, schemaName, privileges, connectedUser)); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -156,200 +216,383 @@ func resourceRedshiftDefaultPrivilegesCreate(db *DBConnection, d *schema.Resourc
 	return resourceRedshiftDefaultPrivilegesReadImpl(db, d)
 }
 
+// resourceRedshiftDefaultPrivilegesImport sets the attributes encoded in the ID, the privileges are read afterwards.
+func resourceRedshiftDefaultPrivilegesImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	id, err := parseDefaultPrivilegesID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if id.withGrantOption {
+		return nil, fmt.Errorf("importing default privileges granted with grant option is not supported: %q", d.Id())
+	}
+	if !slices.Contains(defaultPrivilegesAllowedObjectTypes, id.objectType) {
+		return nil, fmt.Errorf("unsupported object type %q in default privileges ID %q", id.objectType, d.Id())
+	}
+
+	entityAttr := map[string]string{
+		"gn": defaultPrivilegesGroupAttr,
+		"un": defaultPrivilegesUserAttr,
+		"rn": defaultPrivilegesRoleAttr,
+	}
+	entityType, entityName, _ := strings.Cut(id.entity, ":")
+	d.Set(entityAttr[entityType], entityName)
+	d.Set(defaultPrivilegesOwnerAttr, id.owner)
+	d.Set(defaultPrivilegesObjectTypeAttr, id.objectType)
+	if id.schema != "" {
+		d.Set(defaultPrivilegesSchemaAttr, id.schema)
+	}
+	if len(id.schemas) > 0 {
+		d.Set(defaultPrivilegesSchemasAttr, id.schemas)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceRedshiftDefaultPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
 	return resourceRedshiftDefaultPrivilegesReadImpl(db, d)
 }
 
 func resourceRedshiftDefaultPrivilegesReadImpl(db *DBConnection, d *schema.ResourceData) error {
-	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
-
-	tx, err := startTransaction(db.client)
-	if err != nil {
-		return err
+	objectType := d.Get(defaultPrivilegesObjectTypeAttr).(string)
+	if _, ok := defaultPrivilegesObjectTypePrivileges[objectType]; !ok {
+		return nil
 	}
-	defer deferredRollback(tx)
 
-	log.Printf("[DEBUG] getting ID for owner %s\n", ownerName)
-	ownerID, err := getUserIDFromName(tx, ownerName)
-	if err != nil {
-		return fmt.Errorf("failed to get user ID: %w", err)
+	log.Printf("[DEBUG] reading %s default privileges\n", objectType)
+	if err := readDefaultPrivileges(db, d, objectType); err != nil {
+		return fmt.Errorf("failed to read %s privileges: %w", objectType, err)
 	}
 
-	switch strings.ToUpper(d.Get(defaultPrivilegesObjectTypeAttr).(string)) {
-	case "TABLE":
-		log.Println("[DEBUG] reading default privileges")
-		if err := readGroupTableDefaultPrivileges(tx, d, ownerID); err != nil {
-			return fmt.Errorf("failed to read table privileges: %w", err)
+	return nil
+}
+
+func readDefaultPrivileges(db *DBConnection, d *schema.ResourceData, objectType string) error {
+	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
+	entityType, entityName := getDefaultPrivilegesGrantee(d)
+
+	// With several schemas, only the privileges granted in all of them are read, so the ones missing in any
+	// schema are granted again on the next apply.
+	var privileges []string
+	var otherOwners []string
+	for i, schemaName := range defaultPrivilegesSchemaNames(d) {
+		privilegesByObjectType, err := queryDefaultPrivileges(db, d, schemaName)
+		if err != nil {
+			return err
+		}
+		schemaPrivileges, schemaOtherOwners := splitDefaultPrivilegesByOwner(ownerName, defaultPrivilegesObjectTypePrivileges[objectType], privilegesByObjectType[objectType])
+		if i == 0 {
+			privileges = schemaPrivileges
+		} else {
+			if !slices.Equal(privileges, schemaPrivileges) {
+				log.Printf("[DEBUG] Default privileges %v for entity %s %s in schema %q differ from the other schemas", schemaPrivileges, entityType, entityName, schemaName)
+			}
+			privileges = slices.DeleteFunc(privileges, func(privilege string) bool {
+				return !slices.Contains(schemaPrivileges, privilege)
+			})
 		}
+		otherOwners = append(otherOwners, schemaOtherOwners...)
 	}
+	slices.Sort(otherOwners)
+	otherOwners = slices.Compact(otherOwners)
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
-	}
+	log.Printf("[DEBUG] Collected privileges for entity %s %s: %v\n", entityType, entityName, privileges)
+	warnDefaultPrivilegesOwnerMismatch(db, ownerName, entityType, entityName, privileges, otherOwners)
+
+	d.Set(defaultPrivilegesPrivilegesAttr, privileges)
+	d.Set(defaultPrivilegesOtherOwnersAttr, otherOwners)
 
 	return nil
 }
 
-func readGroupTableDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, ownerID int) error {
-	var tableSelect, tableUpdate, tableInsert, tableDelete, tableDrop, tableReferences, tableTruncate, tableAlter bool
+// queryDefaultPrivileges reads the default privileges of the grantee in the schema, or the global ones if schemaName
+// is empty, by object type and owner. The default privileges of all owners are read, so default privileges defined by
+// another user than the configured owner are reported instead of silently reading none.
+func queryDefaultPrivileges(db *DBConnection, d *schema.ResourceData, schemaName string) (map[string]map[string][]string, error) {
+	entityType, entityName := getDefaultPrivilegesGrantee(d)
 
-	var entityName string
-	var entityType string
-	var query string
-
-	schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr)
-
-	if groupName, groupNameSet := d.GetOk(defaultPrivilegesGroupAttr); groupNameSet {
-		entityName = groupName.(string)
-		entityType = "group"
-	} else if userName, userNameSet := d.GetOk(defaultPrivilegesUserAttr); userNameSet {
-		entityName = userName.(string)
-		entityType = "user"
-	} else if roleName, roleNameSet := d.GetOk(defaultPrivilegesRoleAttr); roleNameSet {
-		entityName = roleName.(string)
-		entityType = "role"
-	}
-
-	queryArgs := []interface{}{entityName, entityType, ownerID}
+	queryArgs := []interface{}{entityName, entityType}
 	var schemaFilter string
-	if schemaNameSet {
-		schemaFilter = "AND schema_name = $4"
+	if schemaName != "" {
+		schemaFilter = "AND dp.schema_name = $3"
 		queryArgs = append(queryArgs, schemaName)
 	} else {
-		schemaFilter = "AND schema_name IS NULL"
-	}
-
-	query = fmt.Sprintf(`
-		SELECT
-			COALESCE(MAX(CASE WHEN privilege_type = 'SELECT' THEN 1 ELSE 0 END), 0) AS SELECT,
-			COALESCE(MAX(CASE WHEN privilege_type = 'UPDATE' THEN 1 ELSE 0 END), 0) AS UPDATE,
-			COALESCE(MAX(CASE WHEN privilege_type = 'INSERT' THEN 1 ELSE 0 END), 0) AS INSERT,
-			COALESCE(MAX(CASE WHEN privilege_type = 'DELETE' THEN 1 ELSE 0 END), 0) AS DELETE,
-			COALESCE(MAX(CASE WHEN privilege_type = 'DROP' THEN 1 ELSE 0 END), 0) AS DROP,
-			COALESCE(MAX(CASE WHEN privilege_type = 'REFERENCES' THEN 1 ELSE 0 END), 0) AS REFERENCES,
-			COALESCE(MAX(CASE WHEN privilege_type = 'TRUNCATE' THEN 1 ELSE 0 END), 0) AS TRUNCATE,
-			COALESCE(MAX(CASE WHEN privilege_type = 'ALTER' THEN 1 ELSE 0 END), 0) AS ALTER
-		FROM svv_default_privileges
-		WHERE object_type = 'RELATION'
-			AND grantee_name = $1
-			AND grantee_type = $2
-			AND owner_id = $3
+		schemaFilter = "AND dp.schema_name IS NULL"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT dp.object_type, u.usename, dp.privilege_type
+		FROM svv_default_privileges dp
+		JOIN pg_user u ON u.usesysid = dp.owner_id
+		WHERE dp.grantee_name = $1
+			AND LOWER(dp.grantee_type) = $2
 			%s
 		`, schemaFilter)
 
-	if err := tx.QueryRow(query, queryArgs...).Scan(
-		&tableSelect,
-		&tableUpdate,
-		&tableInsert,
-		&tableDelete,
-		&tableDrop,
-		&tableReferences,
-		&tableTruncate,
-		&tableAlter); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return fmt.Errorf("failed to collect privileges: %w", err)
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect privileges: %w", err)
 	}
+	defer rows.Close()
 
-	var privileges []string
-	appendIfTrue(tableSelect, "select", &privileges)
-	appendIfTrue(tableUpdate, "update", &privileges)
-	appendIfTrue(tableInsert, "insert", &privileges)
-	appendIfTrue(tableDelete, "delete", &privileges)
-	appendIfTrue(tableDrop, "drop", &privileges)
-	appendIfTrue(tableReferences, "references", &privileges)
-	appendIfTrue(tableTruncate, "truncate", &privileges)
-	appendIfTrue(tableAlter, "alter", &privileges)
+	privilegesByObjectType := map[string]map[string][]string{}
+	for rows.Next() {
+		var catalogObjectType, owner, privilege string
+		if err := rows.Scan(&catalogObjectType, &owner, &privilege); err != nil {
+			return nil, fmt.Errorf("failed to collect privileges: %w", err)
+		}
+		objectType, ok := defaultPrivilegesCatalogObjectTypes[catalogObjectType]
+		if !ok {
+			continue
+		}
+		if privilegesByObjectType[objectType] == nil {
+			privilegesByObjectType[objectType] = map[string][]string{}
+		}
+		privilegesByObjectType[objectType][owner] = append(privilegesByObjectType[objectType][owner], strings.ToLower(privilege))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to collect privileges: %w", err)
+	}
 
-	log.Printf("[DEBUG] Collected privileges for entity %s %s: %v\n", entityType, entityName, privileges)
+	return privilegesByObjectType, nil
+}
 
-	d.Set(defaultPrivilegesPrivilegesAttr, privileges)
+// defaultPrivilegesSchemaNames returns the sorted names of the schemas the default privileges are defined in,
+// or a single empty name for the default privileges of the entire database.
+func defaultPrivilegesSchemaNames(d *schema.ResourceData) []string {
+	if schemas, ok := d.GetOk(defaultPrivilegesSchemasAttr); ok {
+		schemaNames := setToStringList(schemas.(*schema.Set))
+		slices.Sort(schemaNames)
+		return schemaNames
+	}
+	return []string{d.Get(defaultPrivilegesSchemaAttr).(string)}
+}
 
-	return nil
+// getDefaultPrivilegesGrantee returns the grantee type, as listed in the grantee_type column of
+// svv_default_privileges (see defaultPrivilegesGranteeEntities), and the name of the grantee.
+func getDefaultPrivilegesGrantee(d *schema.ResourceData) (string, string) {
+	if groupName, groupNameSet := d.GetOk(defaultPrivilegesGroupAttr); groupNameSet {
+		return "group", groupName.(string)
+	}
+	if userName, userNameSet := d.GetOk(defaultPrivilegesUserAttr); userNameSet {
+		return "user", userName.(string)
+	}
+	if roleName, roleNameSet := d.GetOk(defaultPrivilegesRoleAttr); roleNameSet {
+		return "role", roleName.(string)
+	}
+	return "", ""
+}
+
+// warnDefaultPrivilegesOwnerMismatch reports a warning with the plan when no default privileges of the owner were
+// read, but of other owners. The privileges of other owners are only stored in the computed other_owners, which
+// doesn't show up in the plan, so a misconfigured owner would otherwise go unnoticed.
+func warnDefaultPrivilegesOwnerMismatch(db *DBConnection, ownerName, entityType, entityName string, privileges, otherOwners []string) {
+	if len(otherOwners) == 0 {
+		return
+	}
+	if len(privileges) > 0 {
+		log.Printf("[DEBUG] Default privileges for %s %s are also defined by %v", entityType, entityName, otherOwners)
+		return
+	}
+	db.warn(
+		"Default privileges defined by another owner",
+		fmt.Sprintf("No default privileges of owner %q were found for %s %q, but of %s. Check whether owner is configured correctly, the privileges of other owners are not managed by this resource.",
+			ownerName, entityType, entityName, strings.Join(otherOwners, ", ")),
+	)
+}
+
+// splitDefaultPrivilegesByOwner returns the privileges of the given owner, restricted to the
+// supported privileges of the object type, and the sorted names of the other owners defining any.
+func splitDefaultPrivilegesByOwner(ownerName string, supportedPrivileges []string, privilegesByOwner map[string][]string) ([]string, []string) {
+	privileges := []string{}
+	for _, privilege := range supportedPrivileges {
+		if slices.Contains(privilegesByOwner[ownerName], privilege) {
+			privileges = append(privileges, privilege)
+		}
+	}
+
+	otherOwners := []string{}
+	for owner := range privilegesByOwner {
+		if owner != ownerName {
+			otherOwners = append(otherOwners, owner)
+		}
+	}
+	slices.Sort(otherOwners)
+
+	return privileges, otherOwners
 }
 
 func generateDefaultPrivilegesID(d *schema.ResourceData) string {
-	var entityName, schemaName string
+	id := defaultPrivilegesID{
+		owner:      d.Get(defaultPrivilegesOwnerAttr).(string),
+		objectType: d.Get(defaultPrivilegesObjectTypeAttr).(string),
+	}
 
 	if groupName, isGroup := d.GetOk(defaultPrivilegesGroupAttr); isGroup {
-		entityName = fmt.Sprintf("gn:%s", groupName.(string))
+		id.entity = fmt.Sprintf("gn:%s", groupName.(string))
 	} else if userName, isUser := d.GetOk(defaultPrivilegesUserAttr); isUser {
-		entityName = fmt.Sprintf("un:%s", userName.(string))
+		id.entity = fmt.Sprintf("un:%s", userName.(string))
 	} else if roleName, isRole := d.GetOk(defaultPrivilegesRoleAttr); isRole {
-		entityName = fmt.Sprintf("rn:%s", roleName.(string))
+		id.entity = fmt.Sprintf("rn:%s", roleName.(string))
 	}
 
-	if schemaNameRaw, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr); schemaNameSet {
-		schemaName = fmt.Sprintf("sn:%s", schemaNameRaw.(string))
-	} else {
-		schemaName = "noschema"
+	if schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr); schemaNameSet {
+		id.schema = schemaName.(string)
+	} else if _, schemasSet := d.GetOk(defaultPrivilegesSchemasAttr); schemasSet {
+		id.schemas = defaultPrivilegesSchemaNames(d)
 	}
 
-	ownerName := fmt.Sprintf("on:%s", d.Get(defaultPrivilegesOwnerAttr).(string))
-	objectType := fmt.Sprintf("ot:%s", d.Get(defaultPrivilegesObjectTypeAttr).(string))
+	return id.String()
+}
 
-	return strings.Join([]string{
-		entityName, schemaName, ownerName, objectType,
-	}, "_")
+// defaultPrivilegesSchemasSeparator joins the sorted schemas in the schemas part of the ID.
+const defaultPrivilegesSchemasSeparator = ","
+
+// defaultPrivilegesGrantOptionIDSuffix is appended to the ID of default privileges granted WITH GRANT OPTION.
+// IDs without grant option keep the format they always had.
+const defaultPrivilegesGrantOptionIDSuffix = "wgo"
+
+// defaultPrivilegesID is the parsed form of a default privileges ID:
+// <entity>_<sn:schema|ss:schema,...|noschema>_on:<owner>_ot:<object type>[_wgo]
+// where entity is one of gn:<group>, un:<user> or rn:<role>.
+type defaultPrivilegesID struct {
+	entity          string
+	schema          string
+	schemas         []string
+	owner           string
+	objectType      string
+	withGrantOption bool
 }
 
-func createAlterDefaultsGrantQuery(d *schema.ResourceData, privileges []string) string {
-	schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr)
-	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
-	objectType := strings.ToUpper(d.Get(defaultPrivilegesObjectTypeAttr).(string))
+func (id defaultPrivilegesID) String() string {
+	schemaName := "noschema"
+	if id.schema != "" {
+		schemaName = fmt.Sprintf("sn:%s", id.schema)
+	} else if len(id.schemas) > 0 {
+		schemaName = fmt.Sprintf("ss:%s", strings.Join(id.schemas, defaultPrivilegesSchemasSeparator))
+	}
 
-	var entityName, toWhomIndicator string
-	if groupName, isGroup := d.GetOk(defaultPrivilegesGroupAttr); isGroup {
-		entityName = groupName.(string)
-		toWhomIndicator = "GROUP"
-	} else if userName, isUser := d.GetOk(defaultPrivilegesUserAttr); isUser {
-		entityName = userName.(string)
-	} else if roleName, isRole := d.GetOk(defaultPrivilegesRoleAttr); isRole {
-		entityName = roleName.(string)
-		toWhomIndicator = "ROLE"
+	parts := []string{
+		id.entity,
+		schemaName,
+		fmt.Sprintf("on:%s", id.owner),
+		fmt.Sprintf("ot:%s", id.objectType),
 	}
+	if id.withGrantOption {
+		parts = append(parts, defaultPrivilegesGrantOptionIDSuffix)
+	}
+
+	return strings.Join(parts, "_")
+}
 
-	alterQuery := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s", pq.QuoteIdentifier(ownerName))
+func parseDefaultPrivilegesID(raw string) (defaultPrivilegesID, error) {
+	var id defaultPrivilegesID
+	invalid := fmt.Errorf("invalid default privileges ID %q, expected <gn|un|rn>:<name>_<sn:<schema>|ss:<schema>,...|noschema>_on:<owner>_ot:<object type>[_%s]", raw, defaultPrivilegesGrantOptionIDSuffix)
 
-	if schemaNameSet {
-		alterQuery = fmt.Sprintf("%s IN SCHEMA %s", alterQuery, pq.QuoteIdentifier(schemaName.(string)))
+	rest := raw
+	if strings.HasSuffix(rest, "_"+defaultPrivilegesGrantOptionIDSuffix) {
+		id.withGrantOption = true
+		rest = strings.TrimSuffix(rest, "_"+defaultPrivilegesGrantOptionIDSuffix)
 	}
 
-	return fmt.Sprintf(
-		"%s GRANT %s ON %sS TO %s %s",
-		alterQuery,
-		strings.Join(privileges, ","),
-		objectType,
-		toWhomIndicator,
-		pq.QuoteIdentifier(entityName),
-	)
-}
+	objectTypeIndex := strings.LastIndex(rest, "_ot:")
+	if objectTypeIndex < 0 {
+		return id, invalid
+	}
+	id.objectType = rest[objectTypeIndex+len("_ot:"):]
+	rest = rest[:objectTypeIndex]
 
-func createAlterDefaultsRevokeQuery(d *schema.ResourceData) string {
-	schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr)
-	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
-	objectType := strings.ToUpper(d.Get(defaultPrivilegesObjectTypeAttr).(string))
+	ownerIndex := strings.LastIndex(rest, "_on:")
+	if ownerIndex < 0 {
+		return id, invalid
+	}
+	id.owner = rest[ownerIndex+len("_on:"):]
+	rest = rest[:ownerIndex]
+
+	if strings.HasSuffix(rest, "_noschema") {
+		id.entity = strings.TrimSuffix(rest, "_noschema")
+	} else if schemaIndex := strings.LastIndex(rest, "_sn:"); schemaIndex >= 0 {
+		id.entity = rest[:schemaIndex]
+		id.schema = rest[schemaIndex+len("_sn:"):]
+	} else if schemasIndex := strings.LastIndex(rest, "_ss:"); schemasIndex >= 0 {
+		id.entity = rest[:schemasIndex]
+		id.schemas = strings.Split(rest[schemasIndex+len("_ss:"):], defaultPrivilegesSchemasSeparator)
+		if slices.Contains(id.schemas, "") {
+			return id, invalid
+		}
+	} else {
+		return id, invalid
+	}
 
-	var entityName, fromWhomIndicator string
-	if groupName, isGroup := d.GetOk(defaultPrivilegesGroupAttr); isGroup {
-		entityName = groupName.(string)
-		fromWhomIndicator = "GROUP"
-	} else if userName, isUser := d.GetOk(defaultPrivilegesUserAttr); isUser {
-		entityName = userName.(string)
-	} else if roleName, isRole := d.GetOk(defaultPrivilegesRoleAttr); isRole {
-		entityName = roleName.(string)
-		fromWhomIndicator = "ROLE"
+	switch {
+	case strings.HasPrefix(id.entity, "gn:"), strings.HasPrefix(id.entity, "un:"), strings.HasPrefix(id.entity, "rn:"):
+	default:
+		return id, invalid
+	}
+	if id.objectType == "" || id.owner == "" || (id.schema == "" && len(id.schemas) == 0 && !strings.HasSuffix(rest, "_noschema")) {
+		return id, invalid
 	}
 
-	alterQuery := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s", pq.QuoteIdentifier(ownerName))
+	return id, nil
+}
 
-	if schemaNameSet {
-		alterQuery = fmt.Sprintf("%s IN SCHEMA %s", alterQuery, pq.QuoteIdentifier(schemaName.(string)))
+// alterDefaultPrivilegesQuery returns the start of the ALTER DEFAULT PRIVILEGES statement for the owner. FOR USER is
+// left out for the connected user's own default privileges, as only superusers may name other users there.
+func alterDefaultPrivilegesQuery(ownerName, connectedUser string) string {
+	if strings.EqualFold(ownerName, connectedUser) {
+		return "ALTER DEFAULT PRIVILEGES"
 	}
+	return fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s", pq.QuoteIdentifier(ownerName))
+}
+
+// defaultPrivilegesGranteeSQL returns the grantee of the default privileges for the TO and FROM clauses,
+// users are given without a keyword.
+func defaultPrivilegesGranteeSQL(d *schema.ResourceData) string {
+	granteeType, granteeName := getDefaultPrivilegesGrantee(d)
+	switch granteeType {
+	case "group", "role":
+		return fmt.Sprintf("%s %s", strings.ToUpper(granteeType), pq.QuoteIdentifier(granteeName))
+	default:
+		return pq.QuoteIdentifier(granteeName)
+	}
+}
+
+// alterDefaultPrivilegesInSchemaQuery returns the start of the ALTER DEFAULT PRIVILEGES statement, restricted to
+// the schema unless schemaName is empty.
+func alterDefaultPrivilegesInSchemaQuery(d *schema.ResourceData, schemaName string, connectedUser string) string {
+	alterQuery := alterDefaultPrivilegesQuery(d.Get(defaultPrivilegesOwnerAttr).(string), connectedUser)
+	if schemaName != "" {
+		alterQuery = fmt.Sprintf("%s IN SCHEMA %s", alterQuery, pq.QuoteIdentifier(schemaName))
+	}
+	return alterQuery
+}
+
+func createAlterDefaultsGrantQuery(d *schema.ResourceData, schemaName string, privileges []string, connectedUser string) string {
+	return alterDefaultsGrantQuery(d, schemaName, d.Get(defaultPrivilegesObjectTypeAttr).(string), privileges, connectedUser)
+}
+
+func createAlterDefaultsRevokeQuery(d *schema.ResourceData, schemaName string, connectedUser string) string {
+	return alterDefaultsRevokeQuery(d, schemaName, d.Get(defaultPrivilegesObjectTypeAttr).(string), connectedUser)
+}
+
+// alterDefaultsGrantQuery returns the statement granting privileges on objects of objectType created in the future in
+// the schema to the grantee of d.
+func alterDefaultsGrantQuery(d *schema.ResourceData, schemaName string, objectType string, privileges []string, connectedUser string) string {
+	return fmt.Sprintf(
+		"%s GRANT %s ON %sS TO %s",
+		alterDefaultPrivilegesInSchemaQuery(d, schemaName, connectedUser),
+		strings.Join(privileges, ","),
+		strings.ToUpper(objectType),
+		defaultPrivilegesGranteeSQL(d),
+	)
+}
 
+// alterDefaultsRevokeQuery returns the statement revoking all default privileges on objects of objectType in the
+// schema from the grantee of d.
+func alterDefaultsRevokeQuery(d *schema.ResourceData, schemaName string, objectType string, connectedUser string) string {
 	return fmt.Sprintf(
-		"%s REVOKE ALL PRIVILEGES ON %sS FROM %s %s",
-		alterQuery,
-		objectType,
-		fromWhomIndicator,
-		pq.QuoteIdentifier(entityName),
+		"%s REVOKE ALL PRIVILEGES ON %sS FROM %s",
+		alterDefaultPrivilegesInSchemaQuery(d, schemaName, connectedUser),
+		strings.ToUpper(objectType),
+		defaultPrivilegesGranteeSQL(d),
 	)
 }
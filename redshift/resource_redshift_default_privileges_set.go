@@ -0,0 +1,334 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	defaultPrivilegesSetObjectTypesAttr = "object_types"
+
+	// defaultPrivilegesSetObjectTypeSeparator joins the object types in the object type part of the ID.
+	defaultPrivilegesSetObjectTypeSeparator = ","
+)
+
+func redshiftDefaultPrivilegesSet() *schema.Resource {
+	return &schema.Resource{
+		Description: `Defines the default privileges of a grantee for several object types at once, e.g. tables and functions, which are created in the future by the specified user. All object types are granted and revoked in one transaction and read back with one query. Use ` + "`redshift_default_privileges`" + ` to manage a single object type. Revokes never cascade: Redshift only supports ` + "`RESTRICT`" + ` with ` + "`ALTER DEFAULT PRIVILEGES`" + `, so privileges the grantees passed on to others are not revoked along with theirs.`,
+		ReadContext: IdempotentResourceFunc(resourceRedshiftDefaultPrivilegesSetRead),
+		CreateContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesSetCreate),
+		),
+		DeleteContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesSetDelete),
+		),
+		// Since we revoke all when creating, we can use create as update
+		UpdateContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesSetCreate),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedshiftDefaultPrivilegesSetImport,
+		},
+		Timeouts:      operationTimeouts(),
+		CustomizeDiff: validateDefaultPrivilegesSetDiff,
+
+		Schema: map[string]*schema.Schema{
+			defaultPrivilegesSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "If set, the default privileges are applied to new objects created in the specified schema. By default, default privileges are applied globally to the entire database.",
+			},
+			defaultPrivilegesGroupAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr, defaultPrivilegesRoleAttr},
+				Description:  "The name of the group to which the default privileges are applied.",
+			},
+			defaultPrivilegesUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr, defaultPrivilegesRoleAttr},
+				Description:  "The name of the user to which the default privileges are applied.",
+			},
+			defaultPrivilegesRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr, defaultPrivilegesRoleAttr},
+				Description:  "The name of the role to which the default privileges are applied.",
+			},
+			defaultPrivilegesOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The name of the user for which default privileges are defined, defaults to the user the provider is connected as. Only a superuser can specify default privileges for other users.",
+			},
+			defaultPrivilegesSetObjectTypesAttr: {
+				Type:     schema.TypeSet,
+				Required: true,
+				Set:      hashDefaultPrivilegesSetObjectType,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						defaultPrivilegesObjectTypeAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(defaultPrivilegesAllowedObjectTypes, false),
+							Description:  "The Redshift object type to set the default privileges on (one of: " + strings.Join(defaultPrivilegesAllowedObjectTypes, ", ") + ").",
+						},
+						defaultPrivilegesPrivilegesAttr: {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem: &schema.Schema{
+								Type:      schema.TypeString,
+								StateFunc: normalizePrivilege,
+							},
+							Set:         hashPrivilege,
+							Description: "The list of privileges to apply as default privileges on the object type.",
+						},
+					},
+				},
+				Description: "The object types with their default privileges. Each object type may only be given once.",
+			},
+			defaultPrivilegesOtherOwnersAttr: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The other users which define default privileges for the same grantee and schema on any of the object types. They are not managed by this resource. When only other users define default privileges, a warning is shown, as the owner is likely misconfigured.",
+			},
+		},
+	}
+}
+
+// hashDefaultPrivilegesSetObjectType hashes an object type block by its object type and normalized privileges, so
+// privileges configured in another case don't show up as a diff.
+func hashDefaultPrivilegesSetObjectType(v interface{}) int {
+	m := v.(map[string]interface{})
+	var rawPrivileges []interface{}
+	switch p := m[defaultPrivilegesPrivilegesAttr].(type) {
+	case *schema.Set:
+		rawPrivileges = p.List()
+	case []interface{}:
+		rawPrivileges = p
+	}
+	var privileges []string
+	for _, privilege := range rawPrivileges {
+		privileges = append(privileges, normalizePrivilege(privilege))
+	}
+	slices.Sort(privileges)
+	return schema.HashString(fmt.Sprintf("%s:%s", m[defaultPrivilegesObjectTypeAttr], strings.Join(privileges, ",")))
+}
+
+// defaultPrivilegesSetObjectTypes returns the privileges of the object type blocks by object type.
+func defaultPrivilegesSetObjectTypes(set *schema.Set) map[string][]string {
+	objectTypes := map[string][]string{}
+	for _, raw := range set.List() {
+		m := raw.(map[string]interface{})
+		var privileges []string
+		for _, privilege := range m[defaultPrivilegesPrivilegesAttr].(*schema.Set).List() {
+			privileges = append(privileges, strings.ToUpper(privilege.(string)))
+		}
+		slices.Sort(privileges)
+		objectTypes[m[defaultPrivilegesObjectTypeAttr].(string)] = privileges
+	}
+	return objectTypes
+}
+
+func validateDefaultPrivilegesSetDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown(defaultPrivilegesSetObjectTypesAttr) {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, raw := range d.Get(defaultPrivilegesSetObjectTypesAttr).(*schema.Set).List() {
+		m := raw.(map[string]interface{})
+		objectType := m[defaultPrivilegesObjectTypeAttr].(string)
+		if seen[objectType] {
+			return fmt.Errorf("object type %q is given more than once in %s", objectType, defaultPrivilegesSetObjectTypesAttr)
+		}
+		seen[objectType] = true
+
+		if privileges := setToStringList(m[defaultPrivilegesPrivilegesAttr].(*schema.Set)); !validatePrivileges(privileges, objectType) {
+			return fmt.Errorf("invalid privileges %v for object type %q", privileges, objectType)
+		}
+	}
+	return nil
+}
+
+func resourceRedshiftDefaultPrivilegesSetCreate(db *DBConnection, d *schema.ResourceData) error {
+	oldObjectTypes, newObjectTypes := d.GetChange(defaultPrivilegesSetObjectTypesAttr)
+	privilegesByObjectType := defaultPrivilegesSetObjectTypes(newObjectTypes.(*schema.Set))
+	for objectType, privileges := range privilegesByObjectType {
+		if !validatePrivileges(privileges, objectType) {
+			return fmt.Errorf(`invalid privileges list %+v for object type %q`, privileges, objectType)
+		}
+	}
+
+	connectedUser, err := getConnectedUsername(db)
+	if err != nil {
+		return err
+	}
+	if _, ok := d.GetOk(defaultPrivilegesOwnerAttr); !ok {
+		d.Set(defaultPrivilegesOwnerAttr, connectedUser)
+	}
+
+	// Object types which were removed from the configuration are revoked as well.
+	objectTypes := make([]string, 0, len(privilegesByObjectType))
+	for objectType := range defaultPrivilegesSetObjectTypes(oldObjectTypes.(*schema.Set)) {
+		objectTypes = append(objectTypes, objectType)
+	}
+	for objectType := range privilegesByObjectType {
+		objectTypes = append(objectTypes, objectType)
+	}
+	slices.Sort(objectTypes)
+	objectTypes = slices.Compact(objectTypes)
+
+	schemaName := d.Get(defaultPrivilegesSchemaAttr).(string)
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	for _, objectType := range objectTypes {
+		if _, err := tx.Exec(alterDefaultsRevokeQuery(d, schemaName, objectType, connectedUser)); err != nil {
+			return fmt.Errorf("could not revoke default privileges on %s: %w", objectType, err)
+		}
+		if privileges := privilegesByObjectType[objectType]; len(privileges) > 0 {
+			if _, err := tx.Exec(alterDefaultsGrantQuery(d, schemaName, objectType, privileges, connectedUser)); err != nil {
+				return fmt.Errorf("could not grant default privileges on %s: %w", objectType, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	d.SetId(generateDefaultPrivilegesSetID(d))
+
+	return resourceRedshiftDefaultPrivilegesSetRead(db, d)
+}
+
+func resourceRedshiftDefaultPrivilegesSetDelete(db *DBConnection, d *schema.ResourceData) error {
+	connectedUser, err := getConnectedUsername(db)
+	if err != nil {
+		return err
+	}
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	schemaName := d.Get(defaultPrivilegesSchemaAttr).(string)
+	for objectType := range defaultPrivilegesSetObjectTypes(d.Get(defaultPrivilegesSetObjectTypesAttr).(*schema.Set)) {
+		if _, err := tx.Exec(alterDefaultsRevokeQuery(d, schemaName, objectType, connectedUser)); err != nil {
+			return fmt.Errorf("could not revoke default privileges on %s: %w", objectType, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftDefaultPrivilegesSetRead(db *DBConnection, d *schema.ResourceData) error {
+	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
+	entityType, entityName := getDefaultPrivilegesGrantee(d)
+
+	privilegesByObjectType, err := queryDefaultPrivileges(db, d, d.Get(defaultPrivilegesSchemaAttr).(string))
+	if err != nil {
+		return err
+	}
+
+	var objectTypes []interface{}
+	var allPrivileges, otherOwners []string
+	for objectType := range defaultPrivilegesSetObjectTypes(d.Get(defaultPrivilegesSetObjectTypesAttr).(*schema.Set)) {
+		privileges, owners := splitDefaultPrivilegesByOwner(ownerName, defaultPrivilegesObjectTypePrivileges[objectType], privilegesByObjectType[objectType])
+		log.Printf("[DEBUG] Collected %s privileges for entity %s %s: %v\n", objectType, entityType, entityName, privileges)
+
+		objectTypes = append(objectTypes, map[string]interface{}{
+			defaultPrivilegesObjectTypeAttr: objectType,
+			defaultPrivilegesPrivilegesAttr: privileges,
+		})
+		allPrivileges = append(allPrivileges, privileges...)
+		otherOwners = append(otherOwners, owners...)
+	}
+	slices.Sort(otherOwners)
+	otherOwners = slices.Compact(otherOwners)
+	warnDefaultPrivilegesOwnerMismatch(db, ownerName, entityType, entityName, allPrivileges, otherOwners)
+
+	d.Set(defaultPrivilegesSetObjectTypesAttr, objectTypes)
+	d.Set(defaultPrivilegesOtherOwnersAttr, otherOwners)
+
+	return nil
+}
+
+// generateDefaultPrivilegesSetID returns the ID of default privileges, with the sorted object types joined in the
+// object type part.
+func generateDefaultPrivilegesSetID(d *schema.ResourceData) string {
+	var objectTypes []string
+	for objectType := range defaultPrivilegesSetObjectTypes(d.Get(defaultPrivilegesSetObjectTypesAttr).(*schema.Set)) {
+		objectTypes = append(objectTypes, objectType)
+	}
+	slices.Sort(objectTypes)
+
+	entityType, entityName := getDefaultPrivilegesGrantee(d)
+	id := defaultPrivilegesID{
+		entity:     fmt.Sprintf("%s:%s", defaultPrivilegesGranteeEntities[entityType], entityName),
+		owner:      d.Get(defaultPrivilegesOwnerAttr).(string),
+		objectType: strings.Join(objectTypes, defaultPrivilegesSetObjectTypeSeparator),
+	}
+	if schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr); schemaNameSet {
+		id.schema = schemaName.(string)
+	}
+
+	return id.String()
+}
+
+// resourceRedshiftDefaultPrivilegesSetImport sets the attributes encoded in the ID, the privileges are read afterwards.
+func resourceRedshiftDefaultPrivilegesSetImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	id, err := parseDefaultPrivilegesID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if id.withGrantOption {
+		return nil, fmt.Errorf("importing default privileges granted with grant option is not supported: %q", d.Id())
+	}
+
+	var objectTypes []interface{}
+	for _, objectType := range strings.Split(id.objectType, defaultPrivilegesSetObjectTypeSeparator) {
+		if !slices.Contains(defaultPrivilegesAllowedObjectTypes, objectType) {
+			return nil, fmt.Errorf("unsupported object type %q in default privileges ID %q", objectType, d.Id())
+		}
+		objectTypes = append(objectTypes, map[string]interface{}{
+			defaultPrivilegesObjectTypeAttr: objectType,
+			defaultPrivilegesPrivilegesAttr: []string{},
+		})
+	}
+
+	entityAttr := map[string]string{
+		"gn": defaultPrivilegesGroupAttr,
+		"un": defaultPrivilegesUserAttr,
+		"rn": defaultPrivilegesRoleAttr,
+	}
+	entityType, entityName, _ := strings.Cut(id.entity, ":")
+	d.Set(entityAttr[entityType], entityName)
+	d.Set(defaultPrivilegesOwnerAttr, id.owner)
+	d.Set(defaultPrivilegesSetObjectTypesAttr, objectTypes)
+	if id.schema != "" {
+		d.Set(defaultPrivilegesSchemaAttr, id.schema)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
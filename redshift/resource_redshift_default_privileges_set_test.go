@@ -0,0 +1,185 @@
+package redshift
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestHashDefaultPrivilegesSetObjectType(t *testing.T) {
+	objectType := func(objectType string, privileges ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			defaultPrivilegesObjectTypeAttr: objectType,
+			defaultPrivilegesPrivilegesAttr: schema.NewSet(hashPrivilege, privileges),
+		}
+	}
+
+	if hashDefaultPrivilegesSetObjectType(objectType("table", "select", "insert")) != hashDefaultPrivilegesSetObjectType(objectType("table", "INSERT", "Select")) {
+		t.Error("expected privileges in another case and order to hash the same")
+	}
+	if hashDefaultPrivilegesSetObjectType(objectType("function", "execute")) == hashDefaultPrivilegesSetObjectType(objectType("procedure", "execute")) {
+		t.Error("expected different object types to hash differently")
+	}
+}
+
+func TestGenerateDefaultPrivilegesSetID(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftDefaultPrivilegesSet().Schema, map[string]interface{}{
+		defaultPrivilegesRoleAttr:   "analysts",
+		defaultPrivilegesOwnerAttr:  "etl",
+		defaultPrivilegesSchemaAttr: "my_schema",
+		defaultPrivilegesSetObjectTypesAttr: []interface{}{
+			map[string]interface{}{
+				defaultPrivilegesObjectTypeAttr: "table",
+				defaultPrivilegesPrivilegesAttr: []interface{}{"select"},
+			},
+			map[string]interface{}{
+				defaultPrivilegesObjectTypeAttr: "function",
+				defaultPrivilegesPrivilegesAttr: []interface{}{"execute"},
+			},
+		},
+	})
+
+	expected := "rn:analysts_sn:my_schema_on:etl_ot:function,table"
+	if id := generateDefaultPrivilegesSetID(d); id != expected {
+		t.Errorf("generateDefaultPrivilegesSetID() = %q, want %q", id, expected)
+	}
+
+	parsed, err := parseDefaultPrivilegesID(expected)
+	if err != nil {
+		t.Fatalf("unexpected error parsing %q: %v", expected, err)
+	}
+	if parsed.objectType != "function,table" || parsed.schema != "my_schema" || parsed.owner != "etl" {
+		t.Errorf("unexpected parsed ID %+v", parsed)
+	}
+}
+
+func TestAccRedshiftDefaultPrivilegesSet_InvalidConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "redshift_default_privileges_set" "invalid" {
+  role  = "analysts"
+  owner = "etl"
+
+  object_types {
+    object_type = "function"
+    privileges  = ["select"]
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`invalid privileges \[select\] for object type "function"`),
+			},
+			{
+				Config: `
+resource "redshift_default_privileges_set" "duplicate" {
+  role  = "analysts"
+  owner = "etl"
+
+  object_types {
+    object_type = "table"
+    privileges  = ["select"]
+  }
+
+  object_types {
+    object_type = "table"
+    privileges  = ["insert"]
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`object type "table" is given more than once`),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDefaultPrivilegesSet_Basic(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	rootUsername := getRootUsername()
+	config := func(objectTypes string) string {
+		return fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_default_privileges_set" "set" {
+  role   = redshift_role.role.name
+  schema = redshift_schema.schema.name
+  owner  = %[3]q
+%[4]s
+}
+`, roleName, schemaName, rootUsername, objectTypes)
+	}
+	tableAndFunction := `
+  object_types {
+    object_type = "table"
+    privileges  = ["select", "insert"]
+  }
+
+  object_types {
+    object_type = "function"
+    privileges  = ["execute"]
+  }
+`
+	tableOnly := `
+  object_types {
+    object_type = "table"
+    privileges  = ["select"]
+  }
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(tableAndFunction),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges_set.set", "id", fmt.Sprintf("rn:%s_sn:%s_on:%s_ot:function,table", roleName, schemaName, rootUsername)),
+					resource.TestCheckResourceAttr("redshift_default_privileges_set.set", "object_types.#", "2"),
+					testAccCheckRoleDefaultPrivilege(roleName, "RELATION", "SELECT"),
+					testAccCheckRoleDefaultPrivilege(roleName, "RELATION", "INSERT"),
+					testAccCheckRoleDefaultPrivilege(roleName, "FUNCTION", "EXECUTE"),
+				),
+			},
+			{
+				Config:   config(tableAndFunction),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "redshift_default_privileges_set.set",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Removing an object type revokes its default privileges.
+				Config: config(tableOnly),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges_set.set", "object_types.#", "1"),
+					testAccCheckRoleDefaultPrivilege(roleName, "RELATION", "SELECT"),
+					func(s *terraform.State) error {
+						if err := testAccCheckRoleDefaultPrivilege(roleName, "FUNCTION", "EXECUTE")(s); err == nil {
+							return fmt.Errorf("expected the function default privileges of %q to be revoked", roleName)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
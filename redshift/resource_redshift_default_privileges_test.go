@@ -1,17 +1,22 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccRedshiftDefaultPrivileges_Basic(t *testing.T) {
@@ -44,6 +49,7 @@ func TestAccRedshiftDefaultPrivileges_Basic(t *testing.T) {
 
 		resource "redshift_role" "role" {
 		  name = %[4]q
+		  quoted = true
 		}
 		
 		resource "redshift_default_privileges" "group" {
@@ -194,6 +200,7 @@ func TestAccRedshiftDefaultPrivileges_UpdateToRevoke(t *testing.T) {
 
 		resource "redshift_role" "role" {
 		  name = %[4]q
+		  quoted = true
 		}
 
 		resource "redshift_default_privileges" "group" {
@@ -230,6 +237,7 @@ func TestAccRedshiftDefaultPrivileges_UpdateToRevoke(t *testing.T) {
 
 		resource "redshift_role" "role" {
 		  name = %[4]q
+		  quoted = true
 		}
 
 		resource "redshift_default_privileges" "group" {
@@ -327,6 +335,71 @@ func TestAccRedshiftDefaultPrivileges_UpdateToRevoke(t *testing.T) {
 	}
 }
 
+// TestAccRedshiftDefaultPrivileges_OtherOwners checks that default privileges another
+// owner defines for the same grantee are reported, without being mixed into the
+// privileges of the configured owner.
+func TestAccRedshiftDefaultPrivileges_OtherOwners(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	otherOwner := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_owner"), "-", "_")
+	rootUsername := getRootUsername()
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_user" "other_owner" {
+  name = %[2]q
+}
+
+resource "redshift_default_privileges" "group" {
+  group       = redshift_group.group.name
+  owner       = %[3]q
+  object_type = "table"
+  privileges  = ["select"]
+}
+`, groupName, otherOwner, rootUsername)
+	alterOtherOwnerDefaults := func(action string) func() {
+		return func() {
+			withAccGrantConn(t, func(db *DBConnection) error {
+				_, err := db.Exec(fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s %s", pq.QuoteIdentifier(otherOwner), action))
+				return err
+			})
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckDefaultPrivilegesDestory(defaultPrivilegesAllSchemasID, 100, "r", groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "other_owners.#", "0"),
+				),
+			},
+			{
+				PreConfig: alterOtherOwnerDefaults(fmt.Sprintf("GRANT INSERT ON TABLES TO GROUP %s", pq.QuoteIdentifier(groupName))),
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "select"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "other_owners.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "other_owners.*", otherOwner),
+				),
+			},
+			{
+				PreConfig: alterOtherOwnerDefaults(fmt.Sprintf("REVOKE ALL PRIVILEGES ON TABLES FROM GROUP %s", pq.QuoteIdentifier(groupName))),
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "other_owners.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftDefaultPrivileges_BothUserGroupError(t *testing.T) {
 	rootUsername := getRootUsername()
 	config := fmt.Sprintf(`
@@ -427,3 +500,706 @@ func getRootUsername() string {
 	}
 	return envRootUsername
 }
+
+func TestDefaultPrivilegesID(t *testing.T) {
+	tests := map[string]struct {
+		id       defaultPrivilegesID
+		expected string
+	}{
+		"group in schema": {
+			id:       defaultPrivilegesID{entity: "gn:analysts", schema: "my_schema", owner: "etl", objectType: "table"},
+			expected: "gn:analysts_sn:my_schema_on:etl_ot:table",
+		},
+		"user without schema": {
+			id:       defaultPrivilegesID{entity: "un:john", owner: "etl_user", objectType: "table"},
+			expected: "un:john_noschema_on:etl_user_ot:table",
+		},
+		"role with grant option": {
+			id:       defaultPrivilegesID{entity: "rn:reader", schema: "my_schema", owner: "etl", objectType: "table", withGrantOption: true},
+			expected: "rn:reader_sn:my_schema_on:etl_ot:table_wgo",
+		},
+		"user without schema with grant option": {
+			id:       defaultPrivilegesID{entity: "un:john", owner: "etl", objectType: "table", withGrantOption: true},
+			expected: "un:john_noschema_on:etl_ot:table_wgo",
+		},
+		"group in several schemas": {
+			id:       defaultPrivilegesID{entity: "gn:analysts", schemas: []string{"sales", "team_a"}, owner: "etl", objectType: "table"},
+			expected: "gn:analysts_ss:sales,team_a_on:etl_ot:table",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if result := tt.id.String(); result != tt.expected {
+				t.Fatalf("Expected ID to be %q but got %q", tt.expected, result)
+			}
+			parsed, err := parseDefaultPrivilegesID(tt.expected)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, tt.id) {
+				t.Errorf("Expected parsed ID to be %+v but got %+v", tt.id, parsed)
+			}
+		})
+	}
+}
+
+func TestDefaultPrivilegesID_GrantOptionDistinct(t *testing.T) {
+	withoutGrantOption := defaultPrivilegesID{entity: "un:john", schema: "my_schema", owner: "etl", objectType: "table"}
+	withGrantOption := withoutGrantOption
+	withGrantOption.withGrantOption = true
+
+	if withoutGrantOption.String() == withGrantOption.String() {
+		t.Fatalf("Expected IDs with and without grant option to differ, both are %q", withGrantOption.String())
+	}
+}
+
+func TestGenerateDefaultPrivilegesID_Unchanged(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{
+		defaultPrivilegesGroupAttr:      "analysts",
+		defaultPrivilegesSchemaAttr:     "my_schema",
+		defaultPrivilegesOwnerAttr:      "etl",
+		defaultPrivilegesObjectTypeAttr: "table",
+		defaultPrivilegesPrivilegesAttr: []interface{}{"select"},
+	})
+
+	expected := "gn:analysts_sn:my_schema_on:etl_ot:table"
+	if result := generateDefaultPrivilegesID(d); result != expected {
+		t.Errorf("Expected ID to be %q but got %q", expected, result)
+	}
+}
+
+func TestGenerateDefaultPrivilegesID_Schemas(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{
+		defaultPrivilegesGroupAttr:      "analysts",
+		defaultPrivilegesSchemasAttr:    []interface{}{"team_b", "team_a"},
+		defaultPrivilegesOwnerAttr:      "etl",
+		defaultPrivilegesObjectTypeAttr: "table",
+		defaultPrivilegesPrivilegesAttr: []interface{}{"select"},
+	})
+
+	expected := "gn:analysts_ss:team_a,team_b_on:etl_ot:table"
+	if result := generateDefaultPrivilegesID(d); result != expected {
+		t.Errorf("Expected ID to be %q but got %q", expected, result)
+	}
+	if schemaNames := defaultPrivilegesSchemaNames(d); !reflect.DeepEqual(schemaNames, []string{"team_a", "team_b"}) {
+		t.Errorf("Expected schemas [team_a team_b] but got %v", schemaNames)
+	}
+}
+
+func TestParseDefaultPrivilegesID_Invalid(t *testing.T) {
+	for _, id := range []string{
+		"",
+		"analysts_sn:my_schema_on:etl_ot:table",
+		"gn:analysts_sn:my_schema_ot:table",
+		"gn:analysts_on:etl_ot:table",
+		"gn:analysts_noschema_on:etl",
+		"gn:analysts_sn:_on:etl_ot:table",
+		"gn:analysts_ss:sales,,team_a_on:etl_ot:table",
+	} {
+		if _, err := parseDefaultPrivilegesID(id); err == nil {
+			t.Errorf("Expected an error for ID %q", id)
+		}
+	}
+}
+
+func TestWarnDefaultPrivilegesOwnerMismatch(t *testing.T) {
+	tests := map[string]struct {
+		privileges      []string
+		otherOwners     []string
+		expectedWarning bool
+	}{
+		"only the owner":               {privileges: []string{"select"}},
+		"also other owners":            {privileges: []string{"select"}, otherOwners: []string{"etl"}},
+		"only other owners":            {otherOwners: []string{"admin", "etl"}, expectedWarning: true},
+		"no default privileges at all": {},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := &DBConnection{}
+			warnDefaultPrivilegesOwnerMismatch(db, "root", "group", "analysts", tt.privileges, tt.otherOwners)
+
+			if tt.expectedWarning != (len(db.warnings) == 1) {
+				t.Fatalf("Expected warning %t but got %v", tt.expectedWarning, db.warnings)
+			}
+			if tt.expectedWarning && db.warnings[0].Severity != diag.Warning {
+				t.Errorf("Expected a warning but got %v", db.warnings[0])
+			}
+			if tt.expectedWarning && !strings.Contains(db.warnings[0].Detail, `"root"`) {
+				t.Errorf("Expected the warning to name the owner, got %q", db.warnings[0].Detail)
+			}
+		})
+	}
+}
+
+func TestSplitDefaultPrivilegesByOwner(t *testing.T) {
+	privilegesByOwner := map[string][]string{
+		"root":  {"insert", "select", "usage"},
+		"etl":   {"select"},
+		"admin": {"delete"},
+	}
+
+	privileges, otherOwners := splitDefaultPrivilegesByOwner("root", defaultPrivilegesObjectTypePrivileges["table"], privilegesByOwner)
+	if expected := []string{"select", "insert"}; !reflect.DeepEqual(privileges, expected) {
+		t.Errorf("Expected privileges %v but got %v", expected, privileges)
+	}
+	if expected := []string{"admin", "etl"}; !reflect.DeepEqual(otherOwners, expected) {
+		t.Errorf("Expected other owners %v but got %v", expected, otherOwners)
+	}
+
+	// Default privileges defined by other owners only don't show up as privileges of the owner.
+	privileges, otherOwners = splitDefaultPrivilegesByOwner("nobody", defaultPrivilegesObjectTypePrivileges["table"], privilegesByOwner)
+	if len(privileges) != 0 {
+		t.Errorf("Expected no privileges but got %v", privileges)
+	}
+	if expected := []string{"admin", "etl", "root"}; !reflect.DeepEqual(otherOwners, expected) {
+		t.Errorf("Expected other owners %v but got %v", expected, otherOwners)
+	}
+}
+
+// The owner's own default privileges are defined by a non-superuser, connected with a password.
+func TestAccRedshiftDefaultPrivileges_NonSuperuserOwner(t *testing.T) {
+	testAccPreCheck(t)
+	if os.Getenv("REDSHIFT_HOST") == "" {
+		t.Skip("REDSHIFT_HOST must be set to connect as another user")
+	}
+	ownerName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_owner"), "-", "_")
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	const ownerPassword = "Tf_acc_Passw0rd"
+
+	withAccGrantConn(t, func(db *DBConnection) error {
+		if _, err := db.Exec(fmt.Sprintf("CREATE USER %s PASSWORD '%s'", pq.QuoteIdentifier(ownerName), ownerPassword)); err != nil {
+			return err
+		}
+		_, err := db.Exec(fmt.Sprintf("CREATE GROUP %s", pq.QuoteIdentifier(groupName)))
+		return err
+	})
+	t.Cleanup(func() {
+		withAccGrantConn(t, func(db *DBConnection) error {
+			if _, err := db.Exec(fmt.Sprintf("DROP USER %s", pq.QuoteIdentifier(ownerName))); err != nil {
+				return err
+			}
+			_, err := db.Exec(fmt.Sprintf("DROP GROUP %s", pq.QuoteIdentifier(groupName)))
+			return err
+		})
+	})
+
+	cfg, err := getConfigFromResourceData(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"username": ownerName,
+		"password": ownerPassword,
+	}), nil)
+	if err != nil {
+		t.Fatalf("could not configure connection as %s: %v", ownerName, err)
+	}
+	db, err := cfg.NewClient().Connect()
+	if err != nil {
+		t.Fatalf("could not connect as %s: %v", ownerName, err)
+	}
+
+	d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{
+		defaultPrivilegesGroupAttr:      groupName,
+		defaultPrivilegesObjectTypeAttr: "table",
+		defaultPrivilegesPrivilegesAttr: []interface{}{"select"},
+	})
+	if err := resourceRedshiftDefaultPrivilegesCreate(db, d); err != nil {
+		t.Fatalf("could not create default privileges as non-superuser owner: %v", err)
+	}
+	if owner := d.Get(defaultPrivilegesOwnerAttr).(string); owner != ownerName {
+		t.Errorf("expected owner to default to the connected user %q, got %q", ownerName, owner)
+	}
+	if privileges := d.Get(defaultPrivilegesPrivilegesAttr).(*schema.Set); privileges.Len() != 1 || !privileges.Contains("select") {
+		t.Errorf("expected privileges [select], got %v", privileges.List())
+	}
+
+	if err := resourceRedshiftDefaultPrivilegesDelete(db, d); err != nil {
+		t.Fatalf("could not delete default privileges as non-superuser owner: %v", err)
+	}
+}
+
+func TestCreateAlterDefaultsQueries_ConnectedOwner(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{
+		defaultPrivilegesGroupAttr:      "analysts",
+		defaultPrivilegesSchemaAttr:     "my_schema",
+		defaultPrivilegesOwnerAttr:      "etl",
+		defaultPrivilegesObjectTypeAttr: "table",
+		defaultPrivilegesPrivilegesAttr: []interface{}{"select"},
+	})
+
+	tests := map[string]struct {
+		connectedUser  string
+		expectedGrant  string
+		expectedRevoke string
+	}{
+		"other owner": {
+			connectedUser:  "root",
+			expectedGrant:  `ALTER DEFAULT PRIVILEGES FOR USER "etl" IN SCHEMA "my_schema" GRANT SELECT ON TABLES TO GROUP "analysts"`,
+			expectedRevoke: `ALTER DEFAULT PRIVILEGES FOR USER "etl" IN SCHEMA "my_schema" REVOKE ALL PRIVILEGES ON TABLES FROM GROUP "analysts"`,
+		},
+		"connected owner": {
+			connectedUser:  "ETL",
+			expectedGrant:  `ALTER DEFAULT PRIVILEGES IN SCHEMA "my_schema" GRANT SELECT ON TABLES TO GROUP "analysts"`,
+			expectedRevoke: `ALTER DEFAULT PRIVILEGES IN SCHEMA "my_schema" REVOKE ALL PRIVILEGES ON TABLES FROM GROUP "analysts"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := createAlterDefaultsGrantQuery(d, "my_schema", []string{"SELECT"}, tt.connectedUser); got != tt.expectedGrant {
+				t.Errorf("createAlterDefaultsGrantQuery() = %q, want %q", got, tt.expectedGrant)
+			}
+			if got := createAlterDefaultsRevokeQuery(d, "my_schema", tt.connectedUser); got != tt.expectedRevoke {
+				t.Errorf("createAlterDefaultsRevokeQuery() = %q, want %q", got, tt.expectedRevoke)
+			}
+		})
+	}
+}
+
+func TestCreateAlterDefaultsQueries_Grantees(t *testing.T) {
+	tests := map[string]struct {
+		attr           string
+		objectType     string
+		privileges     []string
+		expectedGrant  string
+		expectedRevoke string
+	}{
+		"user table": {
+			attr:           defaultPrivilegesUserAttr,
+			objectType:     "table",
+			privileges:     []string{"SELECT"},
+			expectedGrant:  `ALTER DEFAULT PRIVILEGES FOR USER "etl" GRANT SELECT ON TABLES TO "grantee"`,
+			expectedRevoke: `ALTER DEFAULT PRIVILEGES FOR USER "etl" REVOKE ALL PRIVILEGES ON TABLES FROM "grantee"`,
+		},
+		"role function": {
+			attr:           defaultPrivilegesRoleAttr,
+			objectType:     "function",
+			privileges:     []string{"EXECUTE"},
+			expectedGrant:  `ALTER DEFAULT PRIVILEGES FOR USER "etl" GRANT EXECUTE ON FUNCTIONS TO ROLE "grantee"`,
+			expectedRevoke: `ALTER DEFAULT PRIVILEGES FOR USER "etl" REVOKE ALL PRIVILEGES ON FUNCTIONS FROM ROLE "grantee"`,
+		},
+		"role procedure": {
+			attr:           defaultPrivilegesRoleAttr,
+			objectType:     "procedure",
+			privileges:     []string{"EXECUTE"},
+			expectedGrant:  `ALTER DEFAULT PRIVILEGES FOR USER "etl" GRANT EXECUTE ON PROCEDURES TO ROLE "grantee"`,
+			expectedRevoke: `ALTER DEFAULT PRIVILEGES FOR USER "etl" REVOKE ALL PRIVILEGES ON PROCEDURES FROM ROLE "grantee"`,
+		},
+		"group function": {
+			attr:           defaultPrivilegesGroupAttr,
+			objectType:     "function",
+			privileges:     []string{"EXECUTE"},
+			expectedGrant:  `ALTER DEFAULT PRIVILEGES FOR USER "etl" GRANT EXECUTE ON FUNCTIONS TO GROUP "grantee"`,
+			expectedRevoke: `ALTER DEFAULT PRIVILEGES FOR USER "etl" REVOKE ALL PRIVILEGES ON FUNCTIONS FROM GROUP "grantee"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{
+				tt.attr:                         "grantee",
+				defaultPrivilegesOwnerAttr:      "etl",
+				defaultPrivilegesObjectTypeAttr: tt.objectType,
+				defaultPrivilegesPrivilegesAttr: []interface{}{strings.ToLower(tt.privileges[0])},
+			})
+
+			if got := createAlterDefaultsGrantQuery(d, "", tt.privileges, "root"); got != tt.expectedGrant {
+				t.Errorf("createAlterDefaultsGrantQuery() = %q, want %q", got, tt.expectedGrant)
+			}
+			if got := createAlterDefaultsRevokeQuery(d, "", "root"); got != tt.expectedRevoke {
+				t.Errorf("createAlterDefaultsRevokeQuery() = %q, want %q", got, tt.expectedRevoke)
+			}
+		})
+	}
+}
+
+// TestAccRedshiftDefaultPrivileges_Schemas applies the same default privileges to several schemas. Revoking them in
+// one schema must show up as drift, and removing a schema must revoke them there.
+func TestAccRedshiftDefaultPrivileges_Schemas(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_")
+	schemaNames := []string{
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_a"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_b"), "-", "_"),
+	}
+	rootUsername := getRootUsername()
+	config := func(schemas string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "a" {
+  name = %[2]q
+}
+
+resource "redshift_schema" "b" {
+  name = %[3]q
+}
+
+resource "redshift_default_privileges" "schemas" {
+  user        = redshift_user.user.name
+  schemas     = %[5]s
+  owner       = %[4]q
+  object_type = "table"
+  privileges  = ["select", "insert"]
+}
+`, userName, schemaNames[0], schemaNames[1], rootUsername, schemas)
+	}
+	bothSchemas := config("[redshift_schema.a.name, redshift_schema.b.name]")
+	countDefaultPrivileges := func(schemaName string, expected int) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			client := testAccProvider.Meta().(*Client)
+			db, err := client.Connect()
+			if err != nil {
+				return err
+			}
+			var count int
+			if err := db.QueryRow("SELECT COUNT(*) FROM svv_default_privileges WHERE grantee_name = $1 AND schema_name = $2", userName, schemaName).Scan(&count); err != nil {
+				return fmt.Errorf("could not read default privileges of %q: %w", userName, err)
+			}
+			if count != expected {
+				return fmt.Errorf("expected %d default privileges of %q in schema %q, found %d", expected, userName, schemaName, count)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: bothSchemas,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.schemas", "schemas.#", "2"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.schemas", "privileges.#", "2"),
+					countDefaultPrivileges(schemaNames[0], 2),
+					countDefaultPrivileges(schemaNames[1], 2),
+				),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s IN SCHEMA %s REVOKE INSERT ON TABLES FROM %s",
+							pq.QuoteIdentifier(rootUsername), pq.QuoteIdentifier(schemaNames[1]), pq.QuoteIdentifier(userName)))
+						return err
+					})
+				},
+				Config:             bothSchemas,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: bothSchemas,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.schemas", "privileges.#", "2"),
+					countDefaultPrivileges(schemaNames[1], 2),
+				),
+			},
+			{
+				Config: config("[redshift_schema.a.name]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.schemas", "schemas.#", "1"),
+					countDefaultPrivileges(schemaNames[0], 2),
+					countDefaultPrivileges(schemaNames[1], 0),
+				),
+			},
+			{
+				ResourceName:      "redshift_default_privileges.schemas",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccRedshiftDefaultPrivileges_RoleFunctions grants EXECUTE on future functions and procedures of
+// an owner to a role, which must be read back from svv_default_privileges without a diff.
+func TestAccRedshiftDefaultPrivileges_RoleFunctions(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	rootUsername := getRootUsername()
+	config := func(privileges string) string {
+		return fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_default_privileges" "functions" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  owner       = %[3]q
+  object_type = "function"
+  privileges  = %[4]s
+}
+
+resource "redshift_default_privileges" "procedures" {
+  role        = redshift_role.role.name
+  owner       = %[3]q
+  object_type = "procedure"
+  privileges  = %[4]s
+}
+`, roleName, schemaName, rootUsername, privileges)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`["execute"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.functions", "id", fmt.Sprintf("rn:%s_sn:%s_on:%s_ot:function", roleName, schemaName, rootUsername)),
+					resource.TestCheckResourceAttr("redshift_default_privileges.functions", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.functions", "privileges.*", "execute"),
+					testAccCheckRoleDefaultPrivilege(roleName, "FUNCTION", "EXECUTE"),
+
+					resource.TestCheckResourceAttr("redshift_default_privileges.procedures", "id", fmt.Sprintf("rn:%s_noschema_on:%s_ot:procedure", roleName, rootUsername)),
+					resource.TestCheckResourceAttr("redshift_default_privileges.procedures", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.procedures", "privileges.*", "execute"),
+					testAccCheckRoleDefaultPrivilege(roleName, "PROCEDURE", "EXECUTE"),
+				),
+			},
+			{
+				Config:   config(`["execute"]`),
+				PlanOnly: true,
+			},
+			{
+				// An empty list revokes all default privileges, like for tables.
+				Config: config("[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.functions", "privileges.#", "0"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.procedures", "privileges.#", "0"),
+					func(s *terraform.State) error {
+						for _, objectType := range []string{"FUNCTION", "PROCEDURE"} {
+							if err := testAccCheckRoleDefaultPrivilege(roleName, objectType, "EXECUTE")(s); err == nil {
+								return fmt.Errorf("expected the %s default privileges of %q to be revoked", strings.ToLower(objectType), roleName)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   config("[]"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckRoleDefaultPrivilege(roleName, catalogObjectType, privilege string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var found int
+		err = db.QueryRow(`
+			SELECT 1 FROM svv_default_privileges
+			WHERE grantee_name = $1 AND LOWER(grantee_type) = 'role' AND object_type = $2 AND privilege_type = $3`,
+			roleName, catalogObjectType, privilege).Scan(&found)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("default privilege %s on %s for role %q not found", privilege, catalogObjectType, roleName)
+		case err != nil:
+			return fmt.Errorf("error reading default privileges of role %q: %w", roleName, err)
+		}
+		return nil
+	}
+}
+
+// TestAccRedshiftDefaultPrivileges_Schema covers default privileges in a schema for a group and a role,
+// which must read back without a diff.
+func TestAccRedshiftDefaultPrivileges_Schema(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	rootUsername := getRootUsername()
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_role" "role" {
+  name = %[2]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[3]q
+}
+
+resource "redshift_default_privileges" "group" {
+  group       = redshift_group.group.name
+  schema      = redshift_schema.schema.name
+  owner       = %[4]q
+  object_type = "table"
+  privileges  = ["select", "insert"]
+}
+
+resource "redshift_default_privileges" "role" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  owner       = %[4]q
+  object_type = "table"
+  privileges  = ["select", "insert"]
+}
+`, groupName, roleName, schemaName, rootUsername)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "id", fmt.Sprintf("gn:%s_sn:%s_on:%s_ot:table", groupName, schemaName, rootUsername)),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "select"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "insert"),
+
+					resource.TestCheckResourceAttr("redshift_default_privileges.role", "id", fmt.Sprintf("rn:%s_sn:%s_on:%s_ot:table", roleName, schemaName, rootUsername)),
+					resource.TestCheckResourceAttr("redshift_default_privileges.role", "role", roleName),
+					resource.TestCheckResourceAttr("redshift_default_privileges.role", "schema", schemaName),
+					resource.TestCheckResourceAttr("redshift_default_privileges.role", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.role", "privileges.*", "select"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.role", "privileges.*", "insert"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestGetDefaultPrivilegesGrantee(t *testing.T) {
+	tests := map[string]struct {
+		attr         string
+		expectedType string
+	}{
+		"group": {attr: defaultPrivilegesGroupAttr, expectedType: "group"},
+		"user":  {attr: defaultPrivilegesUserAttr, expectedType: "user"},
+		"role":  {attr: defaultPrivilegesRoleAttr, expectedType: "role"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftDefaultPrivileges().Schema, map[string]interface{}{
+				tt.attr:                         "grantee",
+				defaultPrivilegesSchemaAttr:     "my_schema",
+				defaultPrivilegesOwnerAttr:      "etl",
+				defaultPrivilegesObjectTypeAttr: "table",
+				defaultPrivilegesPrivilegesAttr: []interface{}{"select"},
+			})
+
+			granteeType, granteeName := getDefaultPrivilegesGrantee(d)
+			if granteeType != tt.expectedType || granteeName != "grantee" {
+				t.Errorf("getDefaultPrivilegesGrantee() = (%q, %q), want (%q, %q)", granteeType, granteeName, tt.expectedType, "grantee")
+			}
+			// The grantee types are the values of svv_default_privileges.grantee_type, which the data source maps to ID prefixes.
+			if _, ok := defaultPrivilegesGranteeEntities[granteeType]; !ok {
+				t.Errorf("grantee type %q is not a grantee type of svv_default_privileges", granteeType)
+			}
+		})
+	}
+}
+
+func TestRedshiftDefaultPrivileges_PrivilegesCaseDiff(t *testing.T) {
+	r := redshiftDefaultPrivileges()
+	d := r.Data(&terraform.InstanceState{ID: "gn:analysts_noschema_on:etl_ot:table"})
+	d.Set(defaultPrivilegesGroupAttr, "analysts")
+	d.Set(defaultPrivilegesOwnerAttr, "etl")
+	d.Set(defaultPrivilegesObjectTypeAttr, "table")
+	d.Set(defaultPrivilegesPrivilegesAttr, []string{"select", "insert"})
+	d.Set(defaultPrivilegesOtherOwnersAttr, []string{})
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		defaultPrivilegesGroupAttr:      "analysts",
+		defaultPrivilegesOwnerAttr:      "etl",
+		defaultPrivilegesObjectTypeAttr: "table",
+		defaultPrivilegesPrivilegesAttr: []interface{}{"SELECT", "Insert"},
+	}), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no diff for privileges in another case, got: %v", diff.Attributes)
+	}
+}
+
+// TestAccRedshiftDefaultPrivileges_UppercasePrivileges makes sure privileges configured in upper case
+// don't show up as a diff once they are read back in lower case.
+func TestAccRedshiftDefaultPrivileges_UppercasePrivileges(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	rootUsername := getRootUsername()
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_default_privileges" "group" {
+  group       = redshift_group.group.name
+  owner       = %[2]q
+  object_type = "table"
+  privileges  = ["SELECT", "Insert", "UPDATE"]
+}
+`, groupName, rootUsername)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckDefaultPrivilegesDestory(defaultPrivilegesAllSchemasID, 100, "r", groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "3"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "select"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "insert"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "update"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDefaultPrivileges_ValidateOwnerAtPlan(t *testing.T) {
+	t.Setenv("REDSHIFT_VALIDATE_AT_PLAN", "true")
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	ownerName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_no_such_owner"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_default_privileges" "group" {
+  group       = redshift_group.group.name
+  owner       = %[2]q
+  object_type = "table"
+  privileges  = ["select"]
+}
+`, groupName, ownerName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`owner %q is not a user of the database`, ownerName)),
+			},
+		},
+	})
+}
@@ -1,12 +1,17 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
@@ -16,11 +21,18 @@ const (
 	grantUserAttr       = "user"
 	grantGroupAttr      = "group"
 	grantRoleAttr       = "role"
+	grantGranteesAttr   = "grantees"
 	grantDatabaseAttr   = "database"
 	grantSchemaAttr     = "schema"
 	grantObjectTypeAttr = "object_type"
 	grantObjectsAttr    = "objects"
 	grantPrivilegesAttr = "privileges"
+	grantAllSchemasAttr = "all_schemas"
+
+	grantValidateObjectsExistAttr = "validate_objects_exist"
+
+	grantGranteeTypeAttr = "type"
+	grantGranteeNameAttr = "name"
 
 	grantToPublicName = "public"
 )
@@ -31,48 +43,64 @@ var grantAllowedObjectTypes = []string{
 	"database",
 	"function",
 	"procedure",
+	"external_function",
 	"language",
 }
 
+// grantGranteeTypes lists the types of grantees of the grantees attribute.
+var grantGranteeTypes = []string{"user", "group", "role"}
+
 var grantObjectTypesCodes = map[string][]string{
 	"table":     {"r", "m", "v"},
 	"procedure": {"p"},
 	"function":  {"f"},
+	// External functions are functions of the exfunc language, see externalFunctionFilter.
+	"external_function": {"f"},
 }
 
+// externalFunctionFilter restricts the callables read from pg_proc_info to external (Lambda) functions.
+const externalFunctionFilter = `		AND pr.prolang = (SELECT oid FROM pg_language WHERE lanname = 'exfunc')
+`
+
 func redshiftGrant() *schema.Resource {
 	return &schema.Resource{
 		Description: `
 Defines access privileges for users and  groups. Privileges include access options such as being able to read data in tables and views, write data, create tables, and drop tables. Use this command to give specific privileges for a table, database, schema, function, procedure, language, or column.
 `,
-		ReadContext: ResourceFunc(resourceRedshiftGrantRead),
-		CreateContext: ResourceFunc(
+		ReadContext: IdempotentResourceFunc(resourceRedshiftGrantRead),
+		// GRANT and REVOKE statements can safely be run again.
+		CreateContext: IdempotentResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftGrantCreate),
 		),
-		DeleteContext: ResourceFunc(
+		DeleteContext: IdempotentResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftGrantDelete),
 		),
 
-		// Since we revoke all when creating, we can use create as update
-		UpdateContext: ResourceFunc(
-			ResourceRetryOnPQErrors(resourceRedshiftGrantCreate),
+		UpdateContext: IdempotentResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftGrantUpdate),
+		),
+		Timeouts: operationTimeouts(),
+		CustomizeDiff: customdiff.All(
+			validatePrivilegesDiff(grantPrivilegesAttr, grantObjectTypeAttr),
+			validateLanguageGrantDiff,
 		),
 
 		Schema: map[string]*schema.Schema{
 			grantUserAttr: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ForceNew:     true,
-				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr},
-				Description:  "The name of the user to grant privileges on. Exactly one of `user`, `group`, or `role` must be set.",
-				ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "User name cannot be 'public'. To use GRANT ... TO PUBLIC set the group name to 'public' instead."),
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{grantUserAttr, grantGroupAttr, grantRoleAttr, grantGranteesAttr},
+				Description:      "The name of the user to grant privileges on. Exactly one of `user`, `group`, `role` or `grantees` must be set.",
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
+				ValidateFunc:     validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "User name cannot be 'public'. To use GRANT ... TO PUBLIC set the group name to 'public' instead."),
 			},
 			grantGroupAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ForceNew:     true,
-				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr},
-				Description:  "The name of the group to grant privileges on. Exactly one of `user`, `group`, or `role` must be set. Settings the group name to `public` or `PUBLIC` (it is case insensitive in this case) will result in a `GRANT ... TO PUBLIC` statement.",
+				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr, grantGranteesAttr},
+				Description:  "The name of the group to grant privileges on. Exactly one of `user`, `group`, `role` or `grantees` must be set. Settings the group name to `public` or `PUBLIC` (it is case insensitive in this case) will result in a `GRANT ... TO PUBLIC` statement.",
 				StateFunc: func(val interface{}) string {
 					name := val.(string)
 					if strings.ToLower(name) == grantToPublicName {
@@ -82,20 +110,55 @@ Defines access privileges for users and  groups. Privileges include access optio
 				},
 			},
 			grantRoleAttr: {
-				Type:         schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{grantUserAttr, grantGroupAttr, grantRoleAttr, grantGranteesAttr},
+				Description:      "The name of the role to grant privileges on. Exactly one of `user`, `group`, `role` or `grantees` must be set. Keep in mind: When granting to a role, the privileges are not read back from the system tables. The GRANT is executed successfully, so we trust the state.", // todo: change when role grants are read back from the system tables
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
+			},
+			grantGranteesAttr: {
+				Type:         schema.TypeSet,
 				Optional:     true,
 				ForceNew:     true,
-				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr},
-				Description:  "The name of the role to grant privileges on. Exactly one of `user`, `group`, or `role` must be set. Keep in mind: When granting to a role, the privileges are not read back from the system tables. The GRANT is executed successfully, so we trust the state.", // todo: change when role grants are read back from the system tables
-				StateFunc: func(val interface{}) string {
-					return strings.ToLower(val.(string))
+				MinItems:     1,
+				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr, grantGranteesAttr},
+				Description:  "The users, groups and roles to grant privileges to, for granting the same privileges to several grantees at once. A privilege is only read back as granted if every grantee holds it. Exactly one of `user`, `group`, `role` or `grantees` must be set. To grant to `PUBLIC`, set `group` to `public` instead.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						grantGranteeTypeAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							Description:  "The type of the grantee (one of: " + strings.Join(grantGranteeTypes, ", ") + ").",
+							ValidateFunc: validation.StringInSlice(grantGranteeTypes, false),
+						},
+						grantGranteeNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							Description:  "The name of the grantee. User and role names are handled like the `user` and `role` attributes, see `preserve_case`.",
+							ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "Grantee name cannot be 'public'. To use GRANT ... TO PUBLIC set the group name to 'public' instead."),
+						},
+					},
 				},
 			},
 			grantSchemaAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The database schema to grant privileges on.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ConflictsWith:    []string{grantAllSchemasAttr},
+				Description:      "The database schema to grant privileges on.",
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
+			},
+			preserveCaseAttr: preserveCaseSchema(),
+			grantAllSchemasAttr: {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       false,
+				ConflictsWith: []string{grantSchemaAttr},
+				Description:   "Grant the privileges on every schema which is not owned by the system, including `public`. Only used when `object_type` is `schema`. The schemas are listed on every read, so schemas created later show up as drift. When granting to `PUBLIC`, the `public` schema is left untouched since `PUBLIC` holds usage on it by default.",
 			},
 			grantDatabaseAttr: {
 				Type:        schema.TypeString,
@@ -114,29 +177,27 @@ Defines access privileges for users and  groups. Privileges include access optio
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
-					StateFunc: func(val interface{}) string {
-						return strings.ToLower(val.(string))
-					},
+					Type:         schema.TypeString,
+					ValidateFunc: validateGrantObjectName,
 				},
 				Set:         schema.HashString,
-				Description: "The objects upon which to grant the privileges. An empty list (the default) means to grant permissions on all objects of the specified type; see the resource notes on grants on all objects in a schema for what to expect. Ignored when `object_type` is one of (`database`, `schema`).",
+				Description: "The objects upon which to grant the privileges. An empty list (the default) means to grant permissions on all objects of the specified type; see the resource notes on grants on all objects in a schema for what to expect. Objects are given by their bare names, the schema they are in is set in `schema`. Functions, procedures and external functions are given with their argument types, e.g. `myproc(int, varchar)`, to tell overloads apart. Object names are folded to lower case unless `preserve_case` is set, names of callables also keep their case when enclosed in double quotes, e.g. `\"MyProc\"(int)`. Required when `object_type` is `external_function`. Ignored when `object_type` is one of (`database`, `schema`).",
 			},
 			grantPrivilegesAttr: {
 				Type:     schema.TypeSet,
 				Required: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
-					StateFunc: func(val interface{}) string {
-						strVal := strings.ToLower(val.(string))
-						if strVal == "temporary" {
-							strVal = "temp"
-						}
-						return strVal
-					},
+					Type:      schema.TypeString,
+					StateFunc: normalizePrivilege,
 				},
-				Set:         schema.HashString,
-				Description: "The list of privileges to apply as default privileges. See [GRANT command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html) to see what privileges are available to which object type. An empty list could be provided to revoke all privileges for this user or group. Required when `object_type` is set to `language`.",
+				Set:         hashPrivilege,
+				Description: "The list of privileges to apply as default privileges. See [GRANT command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html) to see what privileges are available to which object type. On databases, `temporary` and its alias `temp` are both accepted and stored as `temp`. An empty list revokes all privileges of the grantee on the objects, for every object type.",
+			},
+			grantValidateObjectsExistAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Check that the database, schema and objects to grant on exist before the grant is created, failing with an error naming the missing object and the grantee. Disable it for objects which can't be found in the catalog of the connected database.",
 			},
 		},
 	}
@@ -144,7 +205,7 @@ Defines access privileges for users and  groups. Privileges include access optio
 
 func resourceRedshiftGrantCreate(db *DBConnection, d *schema.ResourceData) error {
 	objectType := d.Get(grantObjectTypeAttr).(string)
-	schemaName := d.Get(grantSchemaAttr).(string)
+	schemaName := getIdentifier(d, grantSchemaAttr)
 	objects := d.Get(grantObjectsAttr).(*schema.Set).List()
 
 	var privileges []string
@@ -153,58 +214,175 @@ func resourceRedshiftGrantCreate(db *DBConnection, d *schema.ResourceData) error
 	}
 
 	// validate parameters
-	if (objectType == "table" || objectType == "function" || objectType == "procedure") && schemaName == "" {
-		return fmt.Errorf("parameter `%s` is required for objects of type table, function and procedure", grantSchemaAttr)
+	if (objectType == "table" || objectType == "function" || objectType == "procedure" || objectType == "external_function") && schemaName == "" {
+		return fmt.Errorf("parameter `%s` is required for objects of type table, function, procedure and external_function", grantSchemaAttr)
 	}
 
 	if (objectType == "database" || objectType == "schema") && len(objects) > 0 {
 		return fmt.Errorf("cannot specify `%s` when `%s` is `database` or `schema`", grantObjectsAttr, grantObjectTypeAttr)
 	}
 
+	if d.Get(grantAllSchemasAttr).(bool) && objectType != "schema" {
+		return fmt.Errorf("parameter `%s` can only be used when `%s` is `schema`", grantAllSchemasAttr, grantObjectTypeAttr)
+	}
+
 	if objectType == "language" && len(objects) == 0 {
 		return fmt.Errorf("parameter `%s` is required for objects of type language", grantObjectsAttr)
 	}
 
+	// Redshift has no ALL EXTERNAL FUNCTIONS IN SCHEMA, granting on all functions would include the regular ones.
+	if objectType == "external_function" && len(objects) == 0 {
+		return fmt.Errorf("parameter `%s` is required for objects of type external_function", grantObjectsAttr)
+	}
+
+	if objectType == "language" && schemaName != "" {
+		return fmt.Errorf("cannot specify `%s` when `%s` is `language`, languages are not schema-qualified", grantSchemaAttr, grantObjectTypeAttr)
+	}
+
 	if !validatePrivileges(privileges, objectType) {
 		return fmt.Errorf(`invalid privileges list %+v for object of type %q`, privileges, objectType)
 	}
 
-	databaseName := getDatabaseName(db, d)
+	if d.Get(grantValidateObjectsExistAttr).(bool) {
+		if err := verifyGrantObjectsExist(db, d); err != nil {
+			return err
+		}
+	}
 
-	tx, err := startTransaction(db.client)
-	if err != nil {
+	if err := applyGrantChange(db, d, getGrantChange(d)); err != nil {
 		return err
 	}
-	defer deferredRollback(tx)
 
-	if err := revokeGrants(tx, databaseName, d); err != nil {
-		return err
+	d.SetId(generateGrantID(d))
+
+	return resourceRedshiftGrantReadImpl(db, d)
+}
+
+// validateLanguageGrantDiff rejects language grants without languages or with a schema during plan, the same
+// checks are repeated on apply for values which are only known then.
+func validateLanguageGrantDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Get(grantObjectTypeAttr).(string) != "language" {
+		return nil
+	}
+	if d.NewValueKnown(grantObjectsAttr) && d.Get(grantObjectsAttr).(*schema.Set).Len() == 0 {
+		return fmt.Errorf("parameter `%s` is required for objects of type language", grantObjectsAttr)
+	}
+	if d.NewValueKnown(grantSchemaAttr) && d.Get(grantSchemaAttr).(string) != "" {
+		return fmt.Errorf("cannot specify `%s` when `%s` is `language`, languages are not schema-qualified", grantSchemaAttr, grantObjectTypeAttr)
 	}
+	return nil
+}
 
-	if err := createGrants(tx, databaseName, d); err != nil {
-		return err
+// validateGrantObjectName rejects schema-qualified object names, the schema of the objects is given in the schema
+// attribute and object names are quoted as a whole, so "sales.orders" would be a table of that name.
+func validateGrantObjectName(v interface{}, k string) (ws []string, errs []error) {
+	name := parseCallableSignature(v.(string), true).name
+	switch strings.Count(name, ".") {
+	case 0:
+	case 1:
+		schemaName, objectName, _ := strings.Cut(v.(string), ".")
+		errs = append(errs, fmt.Errorf("%s: object %q must not be schema-qualified, set %s = %q and use %q instead", k, v, grantSchemaAttr, schemaName, objectName))
+	default:
+		errs = append(errs, fmt.Errorf("%s: object %q must be a bare name, set its schema in %s", k, v, grantSchemaAttr))
 	}
+	return
+}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
+func resourceRedshiftGrantUpdate(db *DBConnection, d *schema.ResourceData) error {
+	var privileges []string
+	for _, p := range d.Get(grantPrivilegesAttr).(*schema.Set).List() {
+		privileges = append(privileges, p.(string))
 	}
 
-	d.SetId(generateGrantID(d))
+	if !validatePrivileges(privileges, d.Get(grantObjectTypeAttr).(string)) {
+		return fmt.Errorf(`invalid privileges list %+v for object of type %q`, privileges, d.Get(grantObjectTypeAttr).(string))
+	}
+
+	if err := applyGrantChange(db, d, getGrantChange(d)); err != nil {
+		return err
+	}
 
 	return resourceRedshiftGrantReadImpl(db, d)
 }
 
 func resourceRedshiftGrantDelete(db *DBConnection, d *schema.ResourceData) error {
+	// Only the privileges managed by this resource are revoked, so other grants
+	// on the same object and grantee stay in place.
+	change := grantChange{
+		revoke: setToStringList(d.Get(grantPrivilegesAttr).(*schema.Set)),
+	}
+
+	return applyGrantChange(db, d, change)
+}
+
+// grantChange describes the privileges to revoke and grant when applying a grant resource.
+type grantChange struct {
+	// revokeAll revokes every privilege of the grantee on the objects, it is used when the resource manages an empty privilege list.
+	revokeAll bool
+	revoke    []string
+	grant     []string
+}
+
+// getGrantChange computes the change from the privileges in state to the configured ones.
+// Only privileges which were managed before and are no longer configured are revoked, and
+// only privileges which weren't managed before are granted. When the objects change, the
+// new objects hold none of the privileges yet, so all configured privileges are granted.
+func getGrantChange(d *schema.ResourceData) grantChange {
+	oldPrivileges, newPrivileges := d.GetChange(grantPrivilegesAttr)
+	oldPrivilegesSet := oldPrivileges.(*schema.Set)
+	newPrivilegesSet := newPrivileges.(*schema.Set)
+
+	if newPrivilegesSet.Len() == 0 {
+		return grantChange{revokeAll: true}
+	}
+
+	grantedPrivilegesSet := newPrivilegesSet.Difference(oldPrivilegesSet)
+	if d.HasChange(grantObjectsAttr) {
+		grantedPrivilegesSet = newPrivilegesSet
+	}
+
+	return grantChange{
+		revoke: setToStringList(oldPrivilegesSet.Difference(newPrivilegesSet)),
+		grant:  setToStringList(grantedPrivilegesSet),
+	}
+}
+
+func applyGrantChange(db *DBConnection, d *schema.ResourceData, change grantChange) error {
+	databaseName := getDatabaseName(db, d)
+
+	var schemaNamesQuery string
+	if isAllSchemasGrant(d) {
+		schemaNamesQuery = db.catalogQuery(listSchemasQuery)
+	}
+
+	if err := verifyDatabaseUsage(db, d, databaseName, change.grant); err != nil {
+		return err
+	}
+
 	tx, err := startTransaction(db.client)
 	if err != nil {
 		return err
 	}
 	defer deferredRollback(tx)
 
-	databaseName := getDatabaseName(db, d)
+	if isAllSchemasGrant(d) {
+		if err := setAllSchemasGrants(tx, schemaNamesQuery, d, change); err != nil {
+			return err
+		}
+	} else {
+		if len(change.grant) > 0 {
+			if err := verifyRelationKinds(tx, d); err != nil {
+				return err
+			}
+		}
 
-	if err := revokeGrants(tx, databaseName, d); err != nil {
-		return err
+		if err := revokeGrants(tx, databaseName, d, change); err != nil {
+			return err
+		}
+
+		if err := createGrants(tx, databaseName, d, change.grant); err != nil {
+			return err
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -218,26 +396,61 @@ func resourceRedshiftGrantRead(db *DBConnection, d *schema.ResourceData) error {
 	return resourceRedshiftGrantReadImpl(db, d)
 }
 
+// resourceRedshiftGrantReadImpl reads the privileges of every grantee. Each read narrows the privileges
+// in state down to the ones the grantee holds, so only privileges held by all grantees are kept.
+// An empty privilege list revokes all privileges instead, so every privilege held by any grantee is kept.
 func resourceRedshiftGrantReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+	setDefaultIfUnset(d, grantAllSchemasAttr, isAllSchemasGrantID(d))
+	setDefaultIfUnset(d, grantValidateObjectsExistAttr, true)
+
+	revokesAll := d.Get(grantPrivilegesAttr).(*schema.Set).Len() == 0
+	heldPrivileges := schema.NewSet(hashPrivilege, nil)
+
+	for _, grantee := range getGrantGrantees(d) {
+		if revokesAll {
+			d.Set(grantPrivilegesAttr, schema.NewSet(hashPrivilege, nil))
+		}
+		if err := readGranteeGrants(db, d, grantee); err != nil {
+			return err
+		}
+		if revokesAll {
+			heldPrivileges = heldPrivileges.Union(d.Get(grantPrivilegesAttr).(*schema.Set))
+		}
+	}
+
+	if revokesAll {
+		d.Set(grantPrivilegesAttr, heldPrivileges)
+	}
+	return nil
+}
+
+// readGranteeGrants reads the privileges granted to the grantee directly. Privileges it only holds through
+// a role or group are not reported, otherwise the resource would take them for ones it granted itself: the
+// svv_*_privileges views only list explicit grants, and the ACLs are matched against the whole grantee name.
+func readGranteeGrants(db *DBConnection, d *schema.ResourceData, grantee grantGrantee) error {
 	objectType := d.Get(grantObjectTypeAttr).(string)
 
 	switch objectType {
 	case "database":
-		return readDatabaseGrants(db, d)
+		return readDatabaseGrants(db, d, grantee)
 	case "schema":
-		return readSchemaGrants(db, d)
+		if isAllSchemasGrant(d) {
+			return readAllSchemasGrants(db, d, grantee)
+		}
+		return readSchemaGrants(db, d, grantee)
 	case "table":
-		return readTableGrants(db, d)
-	case "function", "procedure":
-		return readCallableGrants(db, d)
+		return readTableGrants(db, d, grantee)
+	case "function", "procedure", "external_function":
+		return readCallableGrants(db, d, grantee)
 	case "language":
-		return readLanguageGrants(db, d)
+		return readLanguageGrants(db, d, grantee)
 	default:
 		return fmt.Errorf("unsupported %s: %q", grantObjectTypeAttr, objectType)
 	}
 }
 
-func readDatabaseGrants(db *DBConnection, d *schema.ResourceData) error {
+func readDatabaseGrants(db *DBConnection, d *schema.ResourceData, grantee grantGrantee) error {
 	databaseName := getDatabaseName(db, d)
 
 	query := `
@@ -247,53 +460,121 @@ WHERE sdp.database_name = $1
 AND sdp.identity_type = $2
 AND sdp.identity_name = $3;`
 
-	return readIdentityPrivileges(db, d, "database", databaseName, query)
+	return readIdentityPrivileges(db, d, grantee, "database", databaseName, query)
+}
+
+func readSchemaGrants(db *DBConnection, d *schema.ResourceData, grantee grantGrantee) error {
+	schemaName := getIdentifier(d, grantSchemaAttr)
+	identityType, identityName := grantee.identity()
+
+	privileges, err := readGranteePrivileges(db, identityType, identityName, schemaName, getDatabaseName(db, d))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Collected schema %q privileges for %s %q: %v", schemaName, identityType, identityName, privileges.schema.List())
+
+	setManagedGrantPrivileges(d, privileges.schema)
+
+	return nil
 }
 
-func readSchemaGrants(db *DBConnection, d *schema.ResourceData) error {
-	schemaName := d.Get(grantSchemaAttr).(string)
+func readAllSchemasGrants(db *DBConnection, d *schema.ResourceData, grantee grantGrantee) error {
+	schemaNames, err := listSchemas(db, db.catalogQuery(listSchemasQuery))
+	if err != nil {
+		return err
+	}
+	schemaNames = filterAllSchemasGrantSchemas(d, schemaNames)
 
+	identityType, identityName := grantee.identity()
 	query := `
-SELECT 
+SELECT
+    ssp.namespace_name,
     ssp.privilege_type
 FROM svv_schema_privileges ssp
-WHERE ssp.namespace_name = $1
-AND identity_type = $2
-AND identity_name = $3`
+WHERE identity_type = $1
+AND identity_name = $2`
+
+	rows, err := db.Query(query, identityType, identityName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 
-	return readIdentityPrivileges(db, d, "schema", schemaName, query)
+	schemaPrivileges := make(map[string]*schema.Set, len(schemaNames))
+	for _, schemaName := range schemaNames {
+		schemaPrivileges[schemaName] = schema.NewSet(schema.HashString, nil)
+	}
+	for rows.Next() {
+		var schemaName, privilege string
+		if err := rows.Scan(&schemaName, &privilege); err != nil {
+			return err
+		}
+		if privileges, ok := schemaPrivileges[schemaName]; ok {
+			privileges.Add(strings.ToLower(privilege))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Same as for grants on all tables in a schema: a privilege is only reported
+	// if every schema grants it, so a schema created later shows up as drift.
+	var privilegesSet *schema.Set
+	for schemaName, privileges := range schemaPrivileges {
+		log.Printf("[DEBUG] Collected schema %q privileges for %s %q: %v", schemaName, identityType, identityName, privileges.List())
+		if privilegesSet == nil {
+			privilegesSet = privileges
+		} else {
+			privilegesSet = privilegesSet.Intersection(privileges)
+		}
+	}
+
+	if privilegesSet == nil {
+		return nil
+	}
+
+	setManagedGrantPrivileges(d, privilegesSet)
+
+	return nil
 }
 
-func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
+// readTableGrants reads the privileges on the tables from the catalog entries the names currently resolve to, no OIDs
+// are kept between reads. A table which is dropped and recreated outside of Terraform is thus read without the
+// privileges of the dropped one, and they are granted again on the next apply.
+func readTableGrants(db *DBConnection, d *schema.ResourceData, grantee grantGrantee) error {
 	log.Printf("[DEBUG] Reading table grants")
 
 	var entityName, query string
 	var queryArgs []interface{}
-	_, isUser := d.GetOk(grantUserAttr)
-	_, isGroup := d.GetOk(grantGroupAttr)
-	_, isRole := d.GetOk(grantRoleAttr)
+	isUser := grantee.granteeType == "user"
+	isGroup := grantee.granteeType == "group"
+	isRole := grantee.granteeType == "role"
 	databaseName := getDatabaseName(db, d)
-	schemaName := d.Get(grantSchemaAttr).(string)
-	objects := d.Get(grantObjectsAttr).(*schema.Set)
+	schemaName := getIdentifier(d, grantSchemaAttr)
+	objects := getGrantObjects(d)
 
 	// The pg_class-based queries below exclude the internal storage tables that
 	// back materialized views (named "mv_tbl__<view>__<n>"). GRANT ... ON ALL
 	// TABLES does not touch those tables, so including them would leave the
 	// intersection permanently missing the granted privilege. The role query
 	// reads from svv_all_tables, which does not surface them.
+	// The ACL items of users are matched from the item separator on, so items of
+	// groups and roles, or of users whose name ends with the name of the grantee,
+	// don't count.
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = grantee.name
 		query = `
   SELECT
     relname,
-    decode(charindex('r',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS SELECT,
-    decode(charindex('w',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS UPDATE,
-    decode(charindex('a',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS INSERT,
-    decode(charindex('d',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS DELETE,
-    decode(charindex('D',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS DROP,
-    decode(charindex('x',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS REFERENCES,
-    decode(charindex('P',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS TRUNCATE,
-    decode(charindex('A',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS ALTER
+    decode(charindex('r',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS SELECT,
+    decode(charindex('w',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS UPDATE,
+    decode(charindex('a',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS INSERT,
+    decode(charindex('d',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS DELETE,
+    decode(charindex('D',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS DROP,
+    decode(charindex('x',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS REFERENCES,
+    decode(charindex('P',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS TRUNCATE,
+    decode(charindex('A',split_part(split_part('|'||replace(array_to_string(relacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)),NULL,0,0,0,1) AS ALTER
   FROM pg_user u, pg_class cl
   JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
   WHERE
@@ -306,7 +587,7 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 			pq.Array(grantObjectTypesCodes["table"]), entityName, schemaName,
 		}
 	} else if isGroup {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = grantee.name
 		query = `
   SELECT
     relname,
@@ -330,34 +611,10 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 			pq.Array(grantObjectTypesCodes["table"]), entityName, schemaName,
 		}
 	} else if isRole {
-		entityName = d.Get(grantRoleAttr).(string)
-		query = `
-  SELECT
-    t.table_name,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'SELECT' THEN 1 ELSE 0 END), 0) AS SELECT,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'UPDATE' THEN 1 ELSE 0 END), 0) AS UPDATE,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'INSERT' THEN 1 ELSE 0 END), 0) AS INSERT,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'DELETE' THEN 1 ELSE 0 END), 0) AS DELETE,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'DROP' THEN 1 ELSE 0 END), 0) AS DROP,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'REFERENCES' THEN 1 ELSE 0 END), 0) AS REFERENCES,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'TRUNCATE' THEN 1 ELSE 0 END), 0) AS TRUNCATE,
-    COALESCE(MAX(CASE WHEN p.privilege_type = 'ALTER' THEN 1 ELSE 0 END), 0) AS ALTER
-  FROM SVV_ALL_TABLES t
-  LEFT JOIN svv_relation_privileges p
-    ON p.relation_name = t.table_name
-    AND p.namespace_name = t.schema_name
-    AND p.identity_name = $1
-    AND p.identity_type = 'role'
-  WHERE t.schema_name = $2
-    and t.database_name = $3
-  GROUP BY t.table_name
-`
-		queryArgs = []interface{}{
-			entityName, schemaName, databaseName,
-		}
+		entityName = grantee.name
 	}
 
-	if isGrantToPublic(d) {
+	if grantee.isPublic() {
 		query = `
 		SELECT
 		  relname,
@@ -381,28 +638,87 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 		}
 	}
 
-	rows, err := db.Query(query, queryArgs...)
-	if err != nil {
-		return err
+	var allTablesPrivileges map[string]*schema.Set
+	if isRole {
+		privileges, err := readGranteePrivileges(db, "role", entityName, schemaName, databaseName)
+		if err != nil {
+			return err
+		}
+		allTablesPrivileges = privileges.tables
+	} else {
+		identityType, identityName := grantee.identity()
+		privileges, err := readCachedGranteePrivileges(db, []string{"table", identityType, identityName, databaseName, schemaName}, func() (*granteePrivileges, error) {
+			tables, err := queryTablesPrivileges(db, query, queryArgs)
+			if err != nil {
+				return nil, err
+			}
+			return &granteePrivileges{tables: tables}, nil
+		})
+		if err != nil {
+			return err
+		}
+		allTablesPrivileges = privileges.tables
 	}
-	defer rows.Close()
 
-	// The query returns one row per in-scope table (all tables in the schema
-	// when objects is empty, otherwise every relation matching relkind). We
+	// There is one entry per in-scope table (all tables in the schema when
+	// objects is empty, otherwise every relation matching relkind). We
 	// aggregate by intersection: a privilege is reported present only if EVERY
 	// relevant table grants it to the grantee. This reflects the invariant an
 	// "ALL TABLES IN SCHEMA" grant maintains and is independent of row order.
 	var privilegesSet *schema.Set
+	tablesPrivileges := map[string]*schema.Set{}
+	for objName, tablePrivileges := range allTablesPrivileges {
+		if objects.Len() > 0 && !objects.Contains(objName) {
+			continue
+		}
+
+		if privilegesSet == nil {
+			privilegesSet = tablePrivileges
+		} else {
+			privilegesSet = privilegesSet.Intersection(tablePrivileges)
+		}
+		tablesPrivileges[objName] = tablePrivileges
+
+		log.Printf("[DEBUG] Collected table grants; table: '%v'; privileges: %v; for: %s", objName, tablePrivileges.List(), entityName)
+	}
+
+	// An ALL TABLES IN SCHEMA grant only covers the tables existing when it was
+	// applied, so tables lacking the privileges have most likely been created since.
+	if objects.Len() == 0 {
+		if uncovered := uncoveredObjects(tablesPrivileges, d.Get(grantPrivilegesAttr).(*schema.Set)); len(uncovered) > 0 {
+			log.Printf("[WARN] Tables %v in schema %q lack privileges granted on all tables to %s, they will be granted on the next apply. Use redshift_default_privileges to cover tables created in the future.", uncovered, schemaName, entityName)
+		}
+	}
+
+	// No in-scope tables were found (empty schema, or none of the named objects
+	// exist). There is nothing to read back, so leave the configured privileges
+	// in state. Reporting an empty set here would be permanent drift that no
+	// apply could resolve, since there are no tables to grant on.
+	if privilegesSet == nil {
+		return nil
+	}
+
+	setManagedGrantPrivileges(d, privilegesSet)
+
+	return nil
+}
+
+// queryTablesPrivileges runs a query returning the name of each table with one flag per table privilege
+// and returns the privileges by table name.
+func queryTablesPrivileges(db *DBConnection, query string, queryArgs []interface{}) (map[string]*schema.Set, error) {
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tablesPrivileges := map[string]*schema.Set{}
 	for rows.Next() {
 		var objName string
 		var tableSelect, tableUpdate, tableInsert, tableDelete, tableDrop, tableReferences, tableTruncate, tableAlter bool
 
 		if err := rows.Scan(&objName, &tableSelect, &tableUpdate, &tableInsert, &tableDelete, &tableDrop, &tableReferences, &tableTruncate, &tableAlter); err != nil {
-			return err
-		}
-
-		if objects.Len() > 0 && !objects.Contains(objName) {
-			continue
+			return nil, err
 		}
 
 		tablePrivileges := schema.NewSet(schema.HashString, nil)
@@ -430,51 +746,79 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 		if tableAlter {
 			tablePrivileges.Add("alter")
 		}
+		tablesPrivileges[objName] = tablePrivileges
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		if privilegesSet == nil {
-			privilegesSet = tablePrivileges
-		} else {
-			privilegesSet = privilegesSet.Intersection(tablePrivileges)
-		}
+	return tablesPrivileges, nil
+}
 
-		log.Printf("[DEBUG] Collected table grants; table: '%v'; privileges: %v; for: %s", objName, tablePrivileges.List(), entityName)
+// queryCallablesPrivileges runs a query returning the name and argument types of each function or procedure
+// with a flag for the EXECUTE privilege and returns the privileges by callable.
+func queryCallablesPrivileges(db *DBConnection, query string, queryArgs []interface{}) (*granteePrivileges, error) {
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	// No in-scope tables were found (empty schema, or none of the named objects
-	// exist). There is nothing to read back, so leave the configured privileges
-	// in state. Reporting an empty set here would be permanent drift that no
-	// apply could resolve, since there are no tables to grant on.
-	if privilegesSet == nil {
-		return nil
-	}
+	privileges := newGranteePrivileges()
+	for rows.Next() {
+		var objName, objArgs string
+		var callableExecute bool
 
-	if !privilegesSet.Equal(d.Get(grantPrivilegesAttr).(*schema.Set)) {
-		d.Set(grantPrivilegesAttr, privilegesSet)
+		if err := rows.Scan(&objName, &objArgs, &callableExecute); err != nil {
+			return nil, err
+		}
+
+		executePrivileges := schema.NewSet(schema.HashString, nil)
+		if callableExecute {
+			executePrivileges.Add("execute")
+		}
+		privileges.callables = append(privileges.callables, callablePrivileges{name: objName, args: objArgs, privileges: executePrivileges})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return privileges, nil
 }
 
-func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
+// uncoveredObjects returns the sorted names of the tables or callables which lack at least one of the managed privileges.
+func uncoveredObjects(objectsPrivileges map[string]*schema.Set, managedPrivileges *schema.Set) []string {
+	var uncovered []string
+	for objectName, objectPrivileges := range objectsPrivileges {
+		if managedPrivileges.Difference(objectPrivileges).Len() > 0 {
+			uncovered = append(uncovered, objectName)
+		}
+	}
+	sort.Strings(uncovered)
+	return uncovered
+}
+
+func readCallableGrants(db *DBConnection, d *schema.ResourceData, grantee grantGrantee) error {
 	log.Printf("[DEBUG] Reading callable grants")
 
 	var entityName, query string
 	var queryArgs []interface{}
 
-	_, isUser := d.GetOk(grantUserAttr)
-	_, isGroup := d.GetOk(grantGroupAttr)
-	_, isRole := d.GetOk(grantRoleAttr)
-	schemaName := d.Get(grantSchemaAttr).(string)
+	isUser := grantee.granteeType == "user"
+	isGroup := grantee.granteeType == "group"
+	isRole := grantee.granteeType == "role"
+	schemaName := getIdentifier(d, grantSchemaAttr)
 	objectType := d.Get(grantObjectTypeAttr).(string)
 
 	databaseName := getDatabaseName(db, d)
 
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = grantee.name
 		query = `
 	SELECT
 		proname,
-		decode(nvl(charindex('X',split_part(split_part(regexp_replace(replace(array_to_string(pr.proacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0), 0,0,1) AS EXECUTE
+		oidvectortypes(pr.proargtypes),
+		decode(nvl(charindex('X',split_part(split_part('|'||replace(array_to_string(pr.proacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)), 0), 0,0,1) AS EXECUTE
 	FROM pg_proc_info pr
 		JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace,
 	pg_user u
@@ -487,10 +831,11 @@ func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
 			schemaName, entityName, pq.Array(grantObjectTypesCodes[objectType]),
 		}
 	} else if isGroup {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = grantee.name
 		query = `
 	SELECT
 		proname,
+		oidvectortypes(pr.proargtypes),
 		decode(nvl(charindex('X',split_part(split_part(replace(array_to_string(pr.proacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0), 0,0,1) AS EXECUTE
 	FROM pg_proc_info pr
 		JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace,
@@ -504,30 +849,19 @@ func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
 			schemaName, entityName, pq.Array(grantObjectTypesCodes[objectType]),
 		}
 	} else if isRole {
-		entityName = d.Get(grantRoleAttr).(string)
-		query = `
-	SELECT
-		p.function_name,
-		COALESCE(MAX(CASE WHEN p.privilege_type = 'EXECUTE' THEN 1 ELSE 0 END), 0) AS EXECUTE
-	FROM svv_function_privileges p
-	JOIN svv_redshift_functions pr ON pr.function_name = p.function_name AND pr.schema_name = p.namespace_name
-	WHERE p.namespace_name = $1
-		AND p.identity_name = $2
-		AND p.identity_type = 'role'
-        AND pr.database_name = $3
-	GROUP BY p.function_name
-`
-		queryArgs = []interface{}{
-			schemaName, entityName, databaseName,
-		}
+		entityName = grantee.name
 	}
 
-	callables := stripArgumentsFromCallablesDefinitions(d.Get(grantObjectsAttr).(*schema.Set))
+	callables, err := resolveCallableSignatures(db, schemaName, getGrantObjects(d))
+	if err != nil {
+		return err
+	}
 
-	if isGrantToPublic(d) {
+	if grantee.isPublic() {
 		query = `
 	SELECT
 		proname,
+		oidvectortypes(pr.proargtypes),
 		decode(nvl(charindex('X',split_part(split_part(regexp_replace(replace(array_to_string(pr.proacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0), 0,0,1) AS EXECUTE
 	FROM pg_proc_info pr
 		JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace
@@ -540,67 +874,120 @@ func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
 		}
 	}
 
-	rows, err := db.Query(query, queryArgs...)
+	if objectType == "external_function" {
+		query += externalFunctionFilter
+	}
+
+	var privileges *granteePrivileges
+	if isRole {
+		privileges, err = readGranteePrivileges(db, "role", entityName, schemaName, databaseName)
+	} else {
+		identityType, identityName := grantee.identity()
+		privileges, err = readCachedGranteePrivileges(db, []string{objectType, identityType, identityName, databaseName, schemaName}, func() (*granteePrivileges, error) {
+			return queryCallablesPrivileges(db, query, queryArgs)
+		})
+	}
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
 
-	contains := func(callables []string, objName string) bool {
-		for _, callable := range callables {
-			if callable == objName {
-				return true
-			}
+	// The privileges by callable, keyed by the signature name(args) to tell overloads apart.
+	callablesPrivileges := map[string]*schema.Set{}
+	for _, callable := range privileges.callables {
+		if len(callables) > 0 && !matchesCallableSignatures(callables, callable.name, callable.args) {
+			continue
+		}
+		callablePrivileges := schema.NewSet(schema.HashString, nil)
+		if callable.privileges.Contains("execute") {
+			callablePrivileges.Add("execute")
 		}
-		return false
+		callablesPrivileges[fmt.Sprintf("%s(%s)", callable.name, callable.args)] = callablePrivileges
 	}
 
-	privilegesSet := schema.NewSet(schema.HashString, nil)
-	for rows.Next() {
-		var objName string
-		var callableExecute bool
-
-		if err := rows.Scan(&objName, &callableExecute); err != nil {
-			return err
-		}
-		if len(callables) > 0 && !contains(callables, objName) {
-			continue
+	// Like for tables, a privilege is only reported present if every in-scope callable grants it,
+	// so a callable lacking it shows up as drift.
+	var privilegesSet *schema.Set
+	for _, callablePrivileges := range callablesPrivileges {
+		if privilegesSet == nil {
+			privilegesSet = callablePrivileges
+		} else {
+			privilegesSet = privilegesSet.Intersection(callablePrivileges)
 		}
+	}
 
-		if callableExecute {
-			privilegesSet.Add("execute")
+	// An ALL FUNCTIONS or ALL PROCEDURES IN SCHEMA grant only covers the callables existing when it was applied.
+	if d.Get(grantObjectsAttr).(*schema.Set).Len() == 0 {
+		if uncovered := uncoveredObjects(callablesPrivileges, d.Get(grantPrivilegesAttr).(*schema.Set)); len(uncovered) > 0 {
+			log.Printf("[WARN] %ss %v in schema %q lack privileges granted on all %ss to %s, they will be granted on the next apply.", objectType, uncovered, schemaName, objectType, entityName)
 		}
 	}
 
-	if !privilegesSet.Equal(d.Get(grantPrivilegesAttr).(*schema.Set)) {
-		d.Set(grantPrivilegesAttr, privilegesSet)
+	// No in-scope callables were found, so there is nothing to read back and the configured privileges are left in state.
+	if privilegesSet == nil {
+		log.Printf("[DEBUG] Reading callable grants - Done")
+		return nil
 	}
+
+	setManagedGrantPrivileges(d, privilegesSet)
 	log.Printf("[DEBUG] Reading callable grants - Done")
 
 	return nil
 }
 
-func readLanguageGrants(db *DBConnection, d *schema.ResourceData) error {
+// resolveCallableSignatures parses the function or procedure signatures of a grant. Argument types
+// are resolved to the form pg_proc uses (e.g. int to integer), so overloads can be told apart.
+func resolveCallableSignatures(db *DBConnection, schemaName string, defs *schema.Set) ([]callableSignature, error) {
+	callables := make([]callableSignature, 0, defs.Len())
+	for _, def := range defs.List() {
+		callable := parseCallableSignature(def.(string), false)
+		if callable.hasArgs {
+			query := fmt.Sprintf("SELECT oidvectortypes(proargtypes) FROM pg_proc WHERE oid = '%s'::regprocedure", pqQuoteLiteral(callable.quoted(schemaName)))
+			err := db.QueryRow(query).Scan(&callable.args)
+			switch {
+			case err == nil:
+			case isPqErrorWithCode(err, pqErrorCodeUndefinedFunction), errors.Is(err, sql.ErrNoRows):
+				log.Printf("[DEBUG] %q does not exist in schema %q", def, schemaName)
+			case err != nil:
+				return nil, fmt.Errorf("could not resolve the argument types of %q: %w", def, err)
+			}
+		}
+		callables = append(callables, callable)
+	}
+	return callables, nil
+}
+
+// matchesCallableSignatures returns whether the function or procedure name(args) is one of callables.
+// Callables given without argument types match all overloads.
+func matchesCallableSignatures(callables []callableSignature, name, args string) bool {
+	for _, callable := range callables {
+		if callable.name == name && (!callable.hasArgs || callable.args == args) {
+			return true
+		}
+	}
+	return false
+}
+
+func readLanguageGrants(db *DBConnection, d *schema.ResourceData, grantee grantGrantee) error {
 	log.Printf("[DEBUG] Reading language grants")
 
 	var entityName, query string
 
-	_, isUser := d.GetOk(grantUserAttr)
-	_, isGroup := d.GetOk(grantGroupAttr)
-	_, isRole := d.GetOk(grantRoleAttr)
+	isUser := grantee.granteeType == "user"
+	isGroup := grantee.granteeType == "group"
+	isRole := grantee.granteeType == "role"
 
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = grantee.name
 		query = `
   SELECT
 		lanname,
-    decode(nvl(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0), 0,0,1) AS USAGE
+    decode(nvl(charindex('U',split_part(split_part('|'||replace(array_to_string(lg.lanacl, '|'), '"', ''), '|'||u.usename||'=', 2) ,'/',1)), 0), 0,0,1) AS USAGE
   FROM pg_language lg, pg_user u
   WHERE
     u.usename=$1
 `
 	} else if isGroup {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = grantee.name
 		query = `
   SELECT
 		lanname,
@@ -610,7 +997,7 @@ func readLanguageGrants(db *DBConnection, d *schema.ResourceData) error {
     gr.groname=$1
 `
 	} else if isRole {
-		entityName = d.Get(grantRoleAttr).(string)
+		entityName = grantee.name
 		query = `
 SELECT
 	p.language_name,
@@ -625,12 +1012,14 @@ GROUP BY p.language_name
 
 	queryArgs := []interface{}{entityName}
 
-	// Handle GRANT TO PUBLIC
-	if isGrantToPublic(d) {
+	// Handle GRANT TO PUBLIC: the ACL item of PUBLIC has an empty grantee, e.g. =U/rdsdb. Trusted languages
+	// without ACL have the default privileges, which grant USAGE to PUBLIC.
+	if grantee.isPublic() {
 		query = `
 		SELECT
 			  lanname,
-		  decode(nvl(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0), 0,0,1) AS USAGE
+		  CASE WHEN lg.lanacl IS NULL THEN decode(lg.lanpltrusted, true, 1, 0)
+		  ELSE decode(nvl(charindex('U',split_part(split_part('|'||replace(array_to_string(lg.lanacl, '|'), '"', ''), '|=', 2) ,'/',1)), 0), 0,0,1) END AS USAGE
 		FROM pg_language lg
 	  `
 		queryArgs = []interface{}{}
@@ -642,7 +1031,7 @@ GROUP BY p.language_name
 	}
 	defer rows.Close()
 
-	objects := d.Get(grantObjectsAttr).(*schema.Set)
+	objects := getGrantObjects(d)
 
 	// Intersection across all in-scope languages, matching readTableGrants:
 	// report a privilege only if every relevant language grants it, then set
@@ -679,16 +1068,14 @@ GROUP BY p.language_name
 		return nil
 	}
 
-	if !privilegesSet.Equal(d.Get(grantPrivilegesAttr).(*schema.Set)) {
-		d.Set(grantPrivilegesAttr, privilegesSet)
-	}
+	setManagedGrantPrivileges(d, privilegesSet)
 	log.Printf("[DEBUG] Reading language grants - Done")
 
 	return nil
 }
 
-func readIdentityPrivileges(db *DBConnection, d *schema.ResourceData, objectType, objectName, query string) error {
-	identityType, identityName := getGrantIdentity(d)
+func readIdentityPrivileges(db *DBConnection, d *schema.ResourceData, grantee grantGrantee, objectType, objectName, query string) error {
+	identityType, identityName := grantee.identity()
 
 	rows, err := db.Query(query, objectName, identityType, identityName)
 	if err != nil {
@@ -698,136 +1085,455 @@ func readIdentityPrivileges(db *DBConnection, d *schema.ResourceData, objectType
 		_ = rows.Close()
 	}()
 
-	var privileges []string
+	// The catalog may report TEMP as TEMPORARY, so privileges are normalized like the configured ones
+	// to keep them distinguishable from USAGE and CREATE without a diff for either spelling.
+	privileges := schema.NewSet(hashPrivilege, nil)
 	for rows.Next() {
 		var privilege string
 		if err := rows.Scan(&privilege); err != nil {
 			return err
 		}
-		privileges = append(privileges, strings.ToLower(privilege))
+		privileges.Add(normalizePrivilege(privilege))
 	}
 	if err := rows.Err(); err != nil {
 		return err
 	}
 
-	log.Printf("[DEBUG] Collected %s %q privileges for %s %q: %v", objectType, objectName, identityType, identityName, privileges)
+	log.Printf("[DEBUG] Collected %s %q privileges for %s %q: %v", objectType, objectName, identityType, identityName, privileges.List())
 
-	d.Set(grantPrivilegesAttr, privileges)
+	setManagedGrantPrivileges(d, privileges)
 
 	return nil
 }
 
-func getGrantIdentity(d *schema.ResourceData) (string, string) {
-	if isGrantToPublic(d) {
+// setManagedGrantPrivileges stores the privileges read from the database, limited to the ones managed
+// by this resource. Privileges held through other grant resources on the same object and grantee are
+// ignored, so several resources can manage disjoint privileges without fighting over them. A resource
+// with an empty privilege list revokes all privileges, so all of them are stored to show them as a change.
+func setManagedGrantPrivileges(d *schema.ResourceData, privilegesSet *schema.Set) {
+	managedPrivileges := d.Get(grantPrivilegesAttr).(*schema.Set)
+	if managedPrivileges.Len() == 0 {
+		d.Set(grantPrivilegesAttr, privilegesSet)
+		return
+	}
+	privilegesSet = privilegesSet.Intersection(managedPrivileges)
+
+	if !privilegesSet.Equal(managedPrivileges) {
+		d.Set(grantPrivilegesAttr, privilegesSet)
+	}
+}
+
+// grantGrantee is a user, group or role privileges are granted to. The group public stands for PUBLIC.
+type grantGrantee struct {
+	granteeType string
+	name        string
+}
+
+func (g grantGrantee) isPublic() bool {
+	return g.granteeType == "group" && strings.ToLower(g.name) == grantToPublicName
+}
+
+// identity returns the identity type and name of the grantee as used in the svv_*_privileges views.
+func (g grantGrantee) identity() (string, string) {
+	if g.isPublic() {
 		return "public", "public"
 	}
+	return g.granteeType, g.name
+}
+
+// clause returns the grantee as given in GRANT and REVOKE statements.
+func (g grantGrantee) clause() string {
+	switch {
+	case g.isPublic():
+		return "PUBLIC"
+	case g.granteeType == "group":
+		return "GROUP " + pq.QuoteIdentifier(g.name)
+	case g.granteeType == "role":
+		return "ROLE " + pq.QuoteIdentifier(g.name)
+	default:
+		return pq.QuoteIdentifier(g.name)
+	}
+}
 
-	if userName, isUser := d.GetOk(grantUserAttr); isUser {
-		return "user", userName.(string)
+// getGrantGrantees returns the grantees of the grant, either the single user, group or role or
+// the grantees ordered by type and name.
+func getGrantGrantees(d *schema.ResourceData) []grantGrantee {
+	if grantees, ok := d.GetOk(grantGranteesAttr); ok {
+		var result []grantGrantee
+		for _, raw := range grantees.(*schema.Set).List() {
+			grantee := raw.(map[string]interface{})
+			granteeType := grantee[grantGranteeTypeAttr].(string)
+			name := grantee[grantGranteeNameAttr].(string)
+			if granteeType != "group" {
+				name = identifierOf(d, name)
+			}
+			result = append(result, grantGrantee{granteeType: granteeType, name: name})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			if result[i].granteeType != result[j].granteeType {
+				return result[i].granteeType < result[j].granteeType
+			}
+			return result[i].name < result[j].name
+		})
+		return result
 	}
 
 	if groupName, isGroup := d.GetOk(grantGroupAttr); isGroup {
-		return "group", groupName.(string)
+		return []grantGrantee{{granteeType: "group", name: groupName.(string)}}
 	}
 
-	if roleName, isRole := d.GetOk(grantRoleAttr); isRole {
-		return "role", roleName.(string)
+	if _, isUser := d.GetOk(grantUserAttr); isUser {
+		return []grantGrantee{{granteeType: "user", name: getIdentifier(d, grantUserAttr)}}
 	}
 
-	return "", ""
+	if _, isRole := d.GetOk(grantRoleAttr); isRole {
+		return []grantGrantee{{granteeType: "role", name: getIdentifier(d, grantRoleAttr)}}
+	}
+
+	return nil
 }
 
-func revokeGrants(tx *sql.Tx, databaseName string, d *schema.ResourceData) error {
-	query := createGrantsRevokeQuery(d, databaseName)
+// describeGrantees returns the grantees for messages, e.g. user "john", role "reader".
+func describeGrantees(grantees []grantGrantee) string {
+	descriptions := make([]string, len(grantees))
+	for i, grantee := range grantees {
+		identityType, identityName := grantee.identity()
+		descriptions[i] = fmt.Sprintf("%s %q", identityType, identityName)
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// verifyRelationKinds makes sure the objects of a table grant are relations which can be granted on as tables.
+// Objects which cannot be found in pg_class, e.g. external tables, are left for Redshift to reject.
+func verifyRelationKinds(tx *transaction, d *schema.ResourceData) error {
+	objectType := d.Get(grantObjectTypeAttr).(string)
+	if objectType != "table" {
+		return nil
+	}
+
+	schemaName := getIdentifier(d, grantSchemaAttr)
+	for _, object := range getGrantObjects(d).List() {
+		relKind, err := getRelationKind(tx, schemaName, object.(string))
+		if err != nil {
+			return fmt.Errorf("could not read kind of relation %q: %w", object.(string), err)
+		}
+		if relKind == "" {
+			continue
+		}
+		if err := checkRelationKind(objectType, object.(string), relKind); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyGrantObjectsExist returns an error naming the missing object and the grantee if the database, schema
+// or one of the objects to grant on doesn't exist, instead of the terse error of the GRANT.
+func verifyGrantObjectsExist(db *DBConnection, d *schema.ResourceData) error {
+	objectType := d.Get(grantObjectTypeAttr).(string)
+	schemaName := getIdentifier(d, grantSchemaAttr)
+
+	missing := func(kind, name string) error {
+		return fmt.Errorf("could not grant %s privileges to %s: %s %q does not exist, make sure it is created before the grant, e.g. by referencing its resource",
+			objectType, describeGrantees(getGrantGrantees(d)), kind, name)
+	}
+
+	if databaseName := d.Get(grantDatabaseAttr).(string); objectType == "database" && databaseName != "" {
+		exists, err := grantObjectExists(db, "SELECT 1 FROM pg_database WHERE datname = $1", databaseName)
+		if err != nil {
+			return fmt.Errorf("could not check whether database %q exists: %w", databaseName, err)
+		}
+		if !exists {
+			return missing("database", databaseName)
+		}
+	}
+
+	if schemaName != "" && !isAllSchemasGrant(d) {
+		exists, err := grantObjectExists(db, "SELECT 1 FROM pg_namespace WHERE nspname = $1", schemaName)
+		if err != nil {
+			return fmt.Errorf("could not check whether schema %q exists: %w", schemaName, err)
+		}
+		if !exists {
+			return missing("schema", schemaName)
+		}
+	}
+
+	for _, object := range getGrantObjects(d).List() {
+		name := object.(string)
+		var exists bool
+		var err error
+		switch objectType {
+		case "table":
+			exists, err = grantObjectExists(db, db.catalogQuery(grantTableExistsQuery), schemaName, name)
+		case "function", "procedure", "external_function":
+			exists, err = callableExists(db, schemaName, parseCallableSignature(name, false))
+		case "language":
+			exists, err = grantObjectExists(db, "SELECT 1 FROM pg_language WHERE lanname = $1", name)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not check whether %s %q exists: %w", objectType, name, err)
+		}
+		if !exists {
+			return missing(strings.ReplaceAll(objectType, "_", " "), qualifiedGrantObjectName(schemaName, name))
+		}
+	}
+
+	return nil
+}
+
+// grantTableExistsQuery finds tables, views and materialized views. The svv variant also finds external tables, which aren't in pg_class.
+var grantTableExistsQuery = catalogQuery{
+	pg: `
+SELECT 1
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2`,
+	svv: `
+SELECT 1
+FROM svv_all_tables
+WHERE database_name = current_database() AND schema_name = $1 AND table_name = $2`,
+}
+
+func grantObjectExists(db *DBConnection, query string, args ...interface{}) (bool, error) {
+	var exists int
+	err := db.QueryRow(fmt.Sprintf("SELECT 1 FROM (%s) LIMIT 1", query), args...).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// callableExists returns whether the function or procedure exists, any overload matches callables given without argument types.
+func callableExists(db *DBConnection, schemaName string, callable callableSignature) (bool, error) {
+	if !callable.hasArgs {
+		return grantObjectExists(db, "SELECT 1 FROM pg_proc_info pr JOIN pg_namespace n ON n.oid = pr.pronamespace WHERE n.nspname = $1 AND pr.proname = $2", schemaName, callable.name)
+	}
+
+	var oid int
+	query := fmt.Sprintf("SELECT oid FROM pg_proc WHERE oid = '%s'::regprocedure", pqQuoteLiteral(callable.quoted(schemaName)))
+	err := db.QueryRow(query).Scan(&oid)
+	switch {
+	case isPqErrorWithCode(err, pqErrorCodeUndefinedFunction), errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func qualifiedGrantObjectName(schemaName, name string) string {
+	if schemaName == "" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", schemaName, name)
+}
+
+// verifyDatabaseUsage makes sure USAGE is only granted on databases created from datashares, the only
+// databases supporting it. Databases which can't be found are left for Redshift to reject.
+func verifyDatabaseUsage(db *DBConnection, d *schema.ResourceData, databaseName string, privileges []string) error {
+	if d.Get(grantObjectTypeAttr).(string) != "database" || !slices.ContainsFunc(privileges, func(privilege string) bool {
+		return strings.EqualFold(privilege, "usage")
+	}) {
+		return nil
+	}
+
+	var databaseType string
+	err := db.QueryRow("SELECT database_type FROM svv_redshift_databases WHERE database_name = $1", databaseName).Scan(&databaseType)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read type of database %q: %w", databaseName, err)
+	}
+	if databaseType != "shared" {
+		return fmt.Errorf("usage can only be granted on databases created from datashares, but %q is a %s database", databaseName, databaseType)
+	}
+	return nil
+}
+
+func revokeGrants(tx *transaction, databaseName string, d *schema.ResourceData, change grantChange) error {
+	if !change.revokeAll && len(change.revoke) == 0 {
+		return nil
+	}
+
+	query := createGrantsRevokeQuery(d, databaseName, change.revoke)
 	_, err := tx.Exec(query)
 	return err
 }
 
-func createGrants(tx *sql.Tx, databaseName string, d *schema.ResourceData) error {
-	if d.Get(grantPrivilegesAttr).(*schema.Set).Len() == 0 {
+func createGrants(tx *transaction, databaseName string, d *schema.ResourceData, privileges []string) error {
+	if len(privileges) == 0 {
 		log.Printf("[DEBUG] no privileges to grant for %s", d.Get(grantGroupAttr).(string))
 		return nil
 	}
 
-	query := createGrantsQuery(d, databaseName)
+	query := createGrantsQuery(d, databaseName, privileges)
 	_, err := tx.Exec(query)
 	return err
 }
 
-func createGrantsRevokeQuery(d *schema.ResourceData, databaseName string) string {
-	var query, toWhomIndicator, entityName string
+// setAllSchemasGrants applies the grant change to every schema.
+func setAllSchemasGrants(tx *transaction, schemaNamesQuery string, d *schema.ResourceData, change grantChange) error {
+	schemaNames, err := listSchemas(tx, schemaNamesQuery)
+	if err != nil {
+		return err
+	}
 
-	if groupName, isGroup := d.GetOk(grantGroupAttr); isGroup {
-		toWhomIndicator = "GROUP"
-		entityName = groupName.(string)
-	} else if userName, isUser := d.GetOk(grantUserAttr); isUser {
-		entityName = userName.(string)
-	} else if roleName, isRole := d.GetOk(grantRoleAttr); isRole {
-		toWhomIndicator = "ROLE"
-		entityName = roleName.(string)
+	for _, query := range createAllSchemasGrantsQueries(d, filterAllSchemasGrantSchemas(d, schemaNames), change) {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
 	}
 
-	fromEntityName := pq.QuoteIdentifier(entityName)
+	return nil
+}
+
+func createAllSchemasGrantsQueries(d *schema.ResourceData, schemaNames []string, change grantChange) []string {
+	toWhomIndicator, entityName := getGrantee(d)
+
+	var queries []string
+	for _, schemaName := range schemaNames {
+		if change.revokeAll || len(change.revoke) > 0 {
+			queries = append(queries, fmt.Sprintf(
+				"REVOKE %s ON SCHEMA %s FROM %s %s",
+				revokePrivilegesClause(change.revoke),
+				pq.QuoteIdentifier(schemaName),
+				toWhomIndicator,
+				entityName,
+			))
+		}
+		if len(change.grant) > 0 {
+			queries = append(queries, fmt.Sprintf(
+				"GRANT %s ON SCHEMA %s TO %s %s",
+				strings.Join(change.grant, ","),
+				pq.QuoteIdentifier(schemaName),
+				toWhomIndicator,
+				entityName,
+			))
+		}
+	}
+	return queries
+}
+
+// revokePrivilegesClause returns the privileges to revoke, or ALL PRIVILEGES if none are given.
+func revokePrivilegesClause(privileges []string) string {
+	if len(privileges) == 0 {
+		return "ALL PRIVILEGES"
+	}
+	return strings.Join(privileges, ",")
+}
+
+// filterAllSchemasGrantSchemas leaves out the public schema when granting to PUBLIC.
+// PUBLIC holds usage on it by default and revoking it would lock out every user.
+func filterAllSchemasGrantSchemas(d *schema.ResourceData, schemaNames []string) []string {
+	if !isGrantToPublic(d) {
+		return schemaNames
+	}
+
+	filtered := make([]string, 0, len(schemaNames))
+	for _, schemaName := range schemaNames {
+		if schemaName != "public" {
+			filtered = append(filtered, schemaName)
+		}
+	}
+	return filtered
+}
+
+// getGrantee returns the grantee keyword (GROUP, ROLE or empty for users and PUBLIC) and the quoted grantee name.
+// Grants to several grantees return an empty keyword and the comma separated list of grantees.
+func getGrantee(d *schema.ResourceData) (string, string) {
 	if isGrantToPublic(d) {
-		toWhomIndicator = ""
-		fromEntityName = "PUBLIC"
+		return "", "PUBLIC"
 	}
 
+	grantees := getGrantGrantees(d)
+	if len(grantees) != 1 {
+		clauses := make([]string, len(grantees))
+		for i, grantee := range grantees {
+			clauses[i] = grantee.clause()
+		}
+		return "", strings.Join(clauses, ", ")
+	}
+
+	switch grantees[0].granteeType {
+	case "group":
+		return "GROUP", pq.QuoteIdentifier(grantees[0].name)
+	case "role":
+		return "ROLE", pq.QuoteIdentifier(grantees[0].name)
+	default:
+		return "", pq.QuoteIdentifier(grantees[0].name)
+	}
+}
+
+func createGrantsRevokeQuery(d *schema.ResourceData, databaseName string, privileges []string) string {
+	var query string
+	revokedPrivileges := revokePrivilegesClause(privileges)
+	toWhomIndicator, fromEntityName := getGrantee(d)
+
 	switch strings.ToUpper(d.Get(grantObjectTypeAttr).(string)) {
 	case "DATABASE":
 		query = fmt.Sprintf(
-			"REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s %s",
+			"REVOKE %s ON DATABASE %s FROM %s %s",
+			revokedPrivileges,
 			pq.QuoteIdentifier(databaseName),
 			toWhomIndicator,
 			fromEntityName,
 		)
 	case "SCHEMA":
 		query = fmt.Sprintf(
-			"REVOKE ALL PRIVILEGES ON SCHEMA %s FROM %s %s",
-			pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
+			"REVOKE %s ON SCHEMA %s FROM %s %s",
+			revokedPrivileges,
+			pq.QuoteIdentifier(getIdentifier(d, grantSchemaAttr)),
 			toWhomIndicator,
 			fromEntityName,
 		)
 	case "TABLE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
+		objects := getGrantObjects(d)
 		if objects.Len() > 0 {
 			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON %s %s FROM %s %s",
+				"REVOKE %s ON %s %s FROM %s %s",
+				revokedPrivileges,
 				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentList(objects, d.Get(grantSchemaAttr).(string)),
+				setToPgIdentList(objects, getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				fromEntityName,
 			)
 		} else {
 			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON ALL %sS IN SCHEMA %s FROM %s %s",
+				"REVOKE %s ON ALL %sS IN SCHEMA %s FROM %s %s",
+				revokedPrivileges,
 				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
+				pq.QuoteIdentifier(getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				fromEntityName,
 			)
 		}
-	case "FUNCTION", "PROCEDURE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
+	case "FUNCTION", "PROCEDURE", "EXTERNAL_FUNCTION":
+		objects := getGrantObjects(d)
 		if objects.Len() > 0 {
 			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON %s %s FROM %s %s",
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentListNotQuoted(objects, d.Get(grantSchemaAttr).(string)),
+				"REVOKE %s ON %s %s FROM %s %s",
+				revokedPrivileges,
+				callableObjectKeyword(d.Get(grantObjectTypeAttr).(string)),
+				setToPgCallableList(objects, getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				fromEntityName,
 			)
 		} else {
 			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON ALL %sS IN SCHEMA %s FROM %s %s",
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
+				"REVOKE %s ON ALL %sS IN SCHEMA %s FROM %s %s",
+				revokedPrivileges,
+				callableObjectKeyword(d.Get(grantObjectTypeAttr).(string)),
+				pq.QuoteIdentifier(getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				fromEntityName,
 			)
 		}
 	case "LANGUAGE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
+		objects := getGrantObjects(d)
 		query = fmt.Sprintf(
 			"REVOKE USAGE ON LANGUAGE %s FROM %s %s",
 			setToPgIdentList(objects, ""),
@@ -835,32 +1541,13 @@ func createGrantsRevokeQuery(d *schema.ResourceData, databaseName string) string
 			fromEntityName,
 		)
 	}
-	log.Printf("[DEBUG] Created REVOKE query: %s", query)
 	return query
 }
 
-func createGrantsQuery(d *schema.ResourceData, databaseName string) string {
-	var query, toWhomIndicator, entityName string
-	var privileges []string
-	for _, p := range d.Get(grantPrivilegesAttr).(*schema.Set).List() {
-		privileges = append(privileges, p.(string))
-	}
-
-	if groupName, isGroup := d.GetOk(grantGroupAttr); isGroup {
-		toWhomIndicator = "GROUP"
-		entityName = groupName.(string)
-	} else if userName, isUser := d.GetOk(grantUserAttr); isUser {
-		entityName = userName.(string)
-	} else if roleName, isRole := d.GetOk(grantRoleAttr); isRole {
-		toWhomIndicator = "ROLE"
-		entityName = roleName.(string)
-	}
+func createGrantsQuery(d *schema.ResourceData, databaseName string, privileges []string) string {
+	var query string
 
-	toEntityName := pq.QuoteIdentifier(entityName)
-	if isGrantToPublic(d) {
-		toWhomIndicator = ""
-		toEntityName = "PUBLIC"
-	}
+	toWhomIndicator, toEntityName := getGrantee(d)
 
 	switch strings.ToUpper(d.Get(grantObjectTypeAttr).(string)) {
 	case "DATABASE":
@@ -875,18 +1562,27 @@ func createGrantsQuery(d *schema.ResourceData, databaseName string) string {
 		query = fmt.Sprintf(
 			"GRANT %s ON SCHEMA %s TO %s %s",
 			strings.Join(privileges, ","),
-			pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
+			pq.QuoteIdentifier(getIdentifier(d, grantSchemaAttr)),
+			toWhomIndicator,
+			toEntityName,
+		)
+	case "LANGUAGE":
+		// Languages are database-wide, so their names are never schema-qualified.
+		query = fmt.Sprintf(
+			"GRANT %s ON LANGUAGE %s TO %s %s",
+			strings.Join(privileges, ","),
+			setToPgIdentList(getGrantObjects(d), ""),
 			toWhomIndicator,
 			toEntityName,
 		)
-	case "TABLE", "LANGUAGE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
+	case "TABLE":
+		objects := getGrantObjects(d)
 		if objects.Len() > 0 {
 			query = fmt.Sprintf(
 				"GRANT %s ON %s %s TO %s %s",
 				strings.Join(privileges, ","),
 				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentList(objects, d.Get(grantSchemaAttr).(string)),
+				setToPgIdentList(objects, getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				toEntityName,
 			)
@@ -895,19 +1591,19 @@ func createGrantsQuery(d *schema.ResourceData, databaseName string) string {
 				"GRANT %s ON ALL %sS IN SCHEMA %s TO %s %s",
 				strings.Join(privileges, ","),
 				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
+				pq.QuoteIdentifier(getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				toEntityName,
 			)
 		}
-	case "FUNCTION", "PROCEDURE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
+	case "FUNCTION", "PROCEDURE", "EXTERNAL_FUNCTION":
+		objects := getGrantObjects(d)
 		if objects.Len() > 0 {
 			query = fmt.Sprintf(
 				"GRANT %s ON %s %s TO %s %s",
 				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentListNotQuoted(objects, d.Get(grantSchemaAttr).(string)),
+				callableObjectKeyword(d.Get(grantObjectTypeAttr).(string)),
+				setToPgCallableList(objects, getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				toEntityName,
 			)
@@ -915,15 +1611,14 @@ func createGrantsQuery(d *schema.ResourceData, databaseName string) string {
 			query = fmt.Sprintf(
 				"GRANT %s ON ALL %sS IN SCHEMA %s TO %s %s",
 				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
+				callableObjectKeyword(d.Get(grantObjectTypeAttr).(string)),
+				pq.QuoteIdentifier(getIdentifier(d, grantSchemaAttr)),
 				toWhomIndicator,
 				toEntityName,
 			)
 		}
 	}
 
-	log.Printf("[DEBUG] Created GRANT query: %s", query)
 	return query
 }
 
@@ -935,6 +1630,34 @@ func getDatabaseName(db *DBConnection, d *schema.ResourceData) string {
 	return databaseName
 }
 
+// getGrantObjects returns the objects of the grant as Redshift stores them, honoring preserve_case. The objects
+// are kept in state as configured, callables are returned with their names quoted, e.g. "MyProc"(int), so
+// parsing them again keeps their case.
+func getGrantObjects(d *schema.ResourceData) *schema.Set {
+	objectType := d.Get(grantObjectTypeAttr).(string)
+	preserveCase := d.Get(preserveCaseAttr).(bool)
+	objects := schema.NewSet(schema.HashString, nil)
+	for _, object := range d.Get(grantObjectsAttr).(*schema.Set).List() {
+		switch objectType {
+		case "function", "procedure", "external_function":
+			objects.Add(parseCallableSignature(object.(string), preserveCase).quoted(""))
+		default:
+			objects.Add(identifierCase(object.(string), preserveCase))
+		}
+	}
+	return objects
+}
+
+func isAllSchemasGrant(d *schema.ResourceData) bool {
+	return d.Get(grantObjectTypeAttr).(string) == "schema" && d.Get(grantAllSchemasAttr).(bool)
+}
+
+// isAllSchemasGrantID tells from the ID of a grant without schema whether it is on all schemas, which
+// is needed for state without a value for all_schemas.
+func isAllSchemasGrantID(d *schema.ResourceData) bool {
+	return d.Get(grantSchemaAttr).(string) == "" && strings.HasSuffix(d.Id(), "_ot:schema_"+grantAllSchemasAttr)
+}
+
 func isGrantToPublic(d *schema.ResourceData) bool {
 	if _, isGroup := d.GetOk(grantGroupAttr); isGroup {
 		entityName := d.Get(grantGroupAttr).(string)
@@ -945,6 +1668,15 @@ func isGrantToPublic(d *schema.ResourceData) bool {
 	return false
 }
 
+// callableObjectKeyword returns the object type as used in GRANT and REVOKE statements,
+// external functions are granted on like any other function.
+func callableObjectKeyword(objectType string) string {
+	if objectType == "external_function" {
+		return "FUNCTION"
+	}
+	return strings.ToUpper(objectType)
+}
+
 func generateGrantID(d *schema.ResourceData) string {
 	var parts []string
 
@@ -958,21 +1690,31 @@ func generateGrantID(d *schema.ResourceData) string {
 	}
 
 	if _, isUser := d.GetOk(grantUserAttr); isUser {
-		parts = append(parts, fmt.Sprintf("un:%s", d.Get(grantUserAttr).(string)))
+		parts = append(parts, fmt.Sprintf("un:%s", getIdentifier(d, grantUserAttr)))
 	}
 
 	if _, isRole := d.GetOk(grantRoleAttr); isRole {
-		parts = append(parts, fmt.Sprintf("rn:%s", d.Get(grantRoleAttr).(string)))
+		parts = append(parts, fmt.Sprintf("rn:%s", getIdentifier(d, grantRoleAttr)))
+	}
+
+	if _, hasGrantees := d.GetOk(grantGranteesAttr); hasGrantees {
+		grantees := make([]string, 0)
+		for _, grantee := range getGrantGrantees(d) {
+			grantees = append(grantees, fmt.Sprintf("%s:%s", grantee.granteeType, grantee.name))
+		}
+		parts = append(parts, fmt.Sprintf("gs:%d", schema.HashString(strings.Join(grantees, ","))))
 	}
 
 	objectType := fmt.Sprintf("ot:%s", d.Get(grantObjectTypeAttr).(string))
 	parts = append(parts, objectType)
 
-	if objectType != "ot:database" && objectType != "ot:language" {
-		parts = append(parts, d.Get(grantSchemaAttr).(string))
+	if isAllSchemasGrant(d) {
+		parts = append(parts, grantAllSchemasAttr)
+	} else if objectType != "ot:database" && objectType != "ot:language" {
+		parts = append(parts, getIdentifier(d, grantSchemaAttr))
 	}
 
-	for _, object := range d.Get(grantObjectsAttr).(*schema.Set).List() {
+	for _, object := range getGrantObjects(d).List() {
 		parts = append(parts, object.(string))
 	}
 
@@ -1,7 +1,11 @@
 package redshift
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
@@ -124,6 +128,55 @@ resource "redshift_grant" "public" {
 	})
 }
 
+// TestAccRedshiftGrant_PlpythonuToPublic reads the ACL item of PUBLIC, which has an empty grantee, back from
+// pg_language. A grant to a user must not be mistaken for it once PUBLIC's grant is revoked.
+func TestAccRedshiftGrant_PlpythonuToPublic(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_plpythonu"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + `
+resource "redshift_grant" "public" {
+  group       = "public"
+  object_type = "language"
+  objects     = ["plpythonu"]
+  privileges  = ["usage"]
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.public", "id", "gn:public_ot:language_plpythonu"),
+					resource.TestCheckResourceAttr("redshift_grant.public", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.public", "privileges.*", "usage"),
+				),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						if _, err := db.Exec(fmt.Sprintf("GRANT USAGE ON LANGUAGE plpythonu TO %s", pq.QuoteIdentifier(userName))); err != nil {
+							return err
+						}
+						_, err := db.Exec("REVOKE USAGE ON LANGUAGE plpythonu FROM PUBLIC")
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.public", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.public", "privileges.*", "usage"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGrant_TableToPublic(t *testing.T) {
 	config := `
 resource "redshift_grant" "public" {
@@ -212,6 +265,59 @@ resource "redshift_grant" "grant" {
 	})
 }
 
+func TestAccRedshiftGrant_Database_CreateAndTemporary(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_grant_db_priv")
+	config := func(privileges string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %[1]q
+}
+
+resource "redshift_grant" "grant" {
+  user        = redshift_user.user.name
+  object_type = "database"
+  privileges  = %[2]s
+}
+`, userName, privileges)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(`["create"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "create"),
+				),
+			},
+			{
+				Config: config(`["TEMP"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "temp"),
+				),
+			},
+			{
+				Config:   config(`["temporary"]`),
+				PlanOnly: true,
+			},
+			{
+				Config:   config(`["temp"]`),
+				PlanOnly: true,
+			},
+			{
+				Config: config(`["create", "temporary"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckTypeSetElems("redshift_grant.grant", "privileges", "create", "temp"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGrant_BasicDatabase(t *testing.T) {
 	groupNames := []string{
 		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_"),
@@ -241,6 +347,7 @@ func TestAccRedshiftGrant_BasicDatabase(t *testing.T) {
 
 		resource "redshift_role" "role" {
 		  name = %[3]q
+		  quoted = true
 		}
 		
 		resource "redshift_grant" "grant" {
@@ -322,6 +429,7 @@ func TestAccRedshiftGrant_BasicSchema(t *testing.T) {
 
 		resource "redshift_role" "role" {
 		  name = %[4]q
+		  quoted = true
 		}
 
 		resource "redshift_schema" "schema" {
@@ -409,6 +517,7 @@ func TestAccRedshiftGrant_BasicTable(t *testing.T) {
 
 		resource "redshift_role" "role" {
 		  name = %[3]q
+		  quoted = true
 		}
 		
 		resource "redshift_user" "user" {
@@ -1084,13 +1193,12 @@ func TestAccRedshiftGrant_AllTables_UncoveredTableConverges(t *testing.T) {
 	})
 }
 
-// TestAccRedshiftGrant_AllTables_EmptySchema guards against reporting drift when
-// the schema contains no tables. There is nothing to read back, so the
-// configured privileges must be left in state and the follow-up plan must be
-// empty (an empty set would be permanent, unresolvable drift).
-func TestAccRedshiftGrant_AllTables_EmptySchema(t *testing.T) {
-	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_emptyschema"), "-", "_")
-	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_emptyschema"), "-", "_")
+// TestAccRedshiftGrant_AllTables_NewTableDrift checks that a table created
+// after an ALL TABLES IN SCHEMA grant is reported as drift, and that applying
+// grants the privileges on it.
+func TestAccRedshiftGrant_AllTables_NewTableDrift(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_newtable"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_newtable"), "-", "_")
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -1103,121 +1211,44 @@ func TestAccRedshiftGrant_AllTables_EmptySchema(t *testing.T) {
 			{
 				PreConfig: func() {
 					withAccGrantConn(t, func(db *DBConnection) error {
-						return testAccRedshiftGrantCreateSchemaTables(db, schemaName)
+						return testAccRedshiftGrantCreateSchemaTables(db, schemaName, "table_a")
 					})
 				},
 				Config: testAccRedshiftGrantAllTablesConfig(userName, schemaName, "select"),
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("redshift_grant.all_tables", "privileges.#", "1"),
-					resource.TestCheckTypeSetElemAttr("redshift_grant.all_tables", "privileges.*", "select"),
-				),
+				Check:  testAccCheckUserTablePrivilege(schemaName, "table_a", userName, "select", true),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("CREATE TABLE %s.table_b (id int)", pq.QuoteIdentifier(schemaName)))
+						return err
+					})
+				},
+				Config:             testAccRedshiftGrantAllTablesConfig(userName, schemaName, "select"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccRedshiftGrantAllTablesConfig(userName, schemaName, "select"),
+				Check:  testAccCheckUserTablePrivilege(schemaName, "table_b", userName, "select", true),
 			},
 		},
 	})
 }
 
-func testAccRedshiftGrantUserConfig(user string) string {
-	return fmt.Sprintf(`
-resource "redshift_user" "grantee" {
-  name = %[1]q
-}
-`, user)
-}
-
-func testAccRedshiftGrantAllTablesConfig(user, schemaName string, privileges ...string) string {
-	quoted := make([]string, len(privileges))
-	for i, privilege := range privileges {
-		quoted[i] = fmt.Sprintf("%q", privilege)
-	}
-	return testAccRedshiftGrantUserConfig(user) + fmt.Sprintf(`
-resource "redshift_grant" "all_tables" {
+// TestAccRedshiftGrant_Schema_RevokedDrift revokes a privilege of a schema grant outside of Terraform,
+// which must show up as drift and be granted again by the next apply.
+func TestAccRedshiftGrant_Schema_RevokedDrift(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_schema_drift"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_drift"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + fmt.Sprintf(`
+resource "redshift_grant" "schema" {
   user        = redshift_user.grantee.name
   schema      = %[1]q
-  object_type = "table"
-  objects     = []
-  privileges  = [%[2]s]
-}
-`, schemaName, strings.Join(quoted, ", "))
-}
-
-// withAccGrantConn opens a Redshift connection using the configured test
-// provider and runs fn against it, failing the test on any error.
-func withAccGrantConn(t *testing.T, fn func(db *DBConnection) error) {
-	dbClient := testAccProvider.Meta().(*Client)
-	conn, err := dbClient.Connect()
-	defer dbClient.Close()
-	if err != nil {
-		t.Fatalf("couldn't start redshift connection: %s", err)
-	}
-	if err := fn(conn); err != nil {
-		t.Fatalf("redshift setup/teardown failed: %s", err)
-	}
-}
-
-// testAccCheckUserTablePrivilege asserts whether a user holds a specific
-// privilege on a specific table, read directly from the catalog.
-func testAccCheckUserTablePrivilege(schemaName, table, user, privilege string, want bool) resource.TestCheckFunc {
-	return func(*terraform.State) error {
-		dbClient := testAccProvider.Meta().(*Client)
-		conn, err := dbClient.Connect()
-		if err != nil {
-			return fmt.Errorf("couldn't connect to redshift: %w", err)
-		}
-		defer dbClient.Close()
-
-		const query = `
-SELECT COALESCE(MAX(CASE WHEN privilege_type = $1 THEN 1 ELSE 0 END), 0)
-FROM svv_relation_privileges
-WHERE namespace_name = $2 AND relation_name = $3 AND identity_name = $4 AND identity_type = 'user'`
-		var granted int
-		if err := conn.QueryRow(query, strings.ToUpper(privilege), schemaName, table, user).Scan(&granted); err != nil {
-			return fmt.Errorf("couldn't read privileges for %s.%s: %w", schemaName, table, err)
-		}
-		if (granted == 1) != want {
-			return fmt.Errorf("table %s.%s: privilege %q for user %s granted=%v, want %v", schemaName, table, privilege, user, granted == 1, want)
-		}
-		return nil
-	}
-}
-
-// testAccRedshiftGrantDropSchema returns a CheckDestroy that removes the test
-// schema (and its tables) once the Terraform-managed resources are destroyed.
-func testAccRedshiftGrantDropSchema(schemaName string) func(*terraform.State) error {
-	return func(*terraform.State) error {
-		dbClient := testAccProvider.Meta().(*Client)
-		conn, err := dbClient.Connect()
-		if err != nil {
-			return fmt.Errorf("couldn't connect to redshift: %w", err)
-		}
-		defer dbClient.Close()
-		if _, err := conn.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", pq.QuoteIdentifier(schemaName))); err != nil {
-			return fmt.Errorf("couldn't drop test schema: %w", err)
-		}
-		return nil
-	}
-}
-
-func testAccRedshiftGrantCreateSchemaTables(db *DBConnection, schema string, tables ...string) error {
-	if _, err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s", pq.QuoteIdentifier(schema))); err != nil {
-		return fmt.Errorf("couldn't create schema: %w", err)
-	}
-	for _, table := range tables {
-		stmt := fmt.Sprintf("CREATE TABLE %s.%s (id int)", pq.QuoteIdentifier(schema), pq.QuoteIdentifier(table))
-		if _, err := db.Exec(stmt); err != nil {
-			return fmt.Errorf("couldn't create table %s: %w", table, err)
-		}
-	}
-	return nil
+  object_type = "schema"
+  privileges  = ["usage", "create"]
 }
-
-// TestAccRedshiftGrant_AllTables_MaterializedView guards convergence when the
-// schema contains a materialized view. The view's internal storage table
-// (mv_tbl__*) is never covered by GRANT ... ON ALL TABLES, so it must be
-// excluded from the read; otherwise the intersection would permanently drop the
-// granted privilege.
-func TestAccRedshiftGrant_AllTables_MaterializedView(t *testing.T) {
-	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_matview"), "-", "_")
-	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_matview"), "-", "_")
+`, schemaName)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -1225,94 +1256,222 @@ func TestAccRedshiftGrant_AllTables_MaterializedView(t *testing.T) {
 		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccRedshiftGrantUserConfig(userName),
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantCreateSchemaTables(db, schemaName)
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.schema", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.schema", "privileges.*", "usage"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.schema", "privileges.*", "create"),
+				),
 			},
 			{
 				PreConfig: func() {
 					withAccGrantConn(t, func(db *DBConnection) error {
-						return testAccRedshiftGrantCreateSchemaWithMatview(db, schemaName)
+						_, err := db.Exec(fmt.Sprintf("REVOKE CREATE ON SCHEMA %s FROM %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName)))
+						return err
 					})
 				},
-				Config: testAccRedshiftGrantAllTablesConfig(userName, schemaName, "select"),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("redshift_grant.all_tables", "privileges.#", "1"),
-					resource.TestCheckTypeSetElemAttr("redshift_grant.all_tables", "privileges.*", "select"),
+					resource.TestCheckResourceAttr("redshift_grant.schema", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.schema", "privileges.*", "create"),
 				),
 			},
 		},
 	})
 }
 
-// TestAccRedshiftGrant_AllTables_Group exercises the group query branch: the
-// grant converges (including with a materialized view present) and an extra
-// privilege on one table is ignored by the intersection.
-func TestAccRedshiftGrant_AllTables_Group(t *testing.T) {
-	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_alltables"), "-", "_")
-	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_group"), "-", "_")
-	grantID := fmt.Sprintf("gn:%s_ot:table_%s", groupName, schemaName)
+// TestAccRedshiftGrant_TableRecreated drops and recreates a granted table outside of Terraform, which drops its
+// privileges with it. The next plan must grant them again on the new table.
+func TestAccRedshiftGrant_TableRecreated(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_recreated"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_recreated"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + fmt.Sprintf(`
+resource "redshift_grant" "table" {
+  user        = redshift_user.grantee.name
+  schema      = %[1]q
+  object_type = "table"
+  objects     = ["rebuilt"]
+  privileges  = ["select", "insert"]
+}
+`, schemaName)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: testAccProviders,
 		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
 		Steps: []resource.TestStep{
-			{
-				Config: testAccRedshiftGrantGroupConfig(groupName),
-			},
 			{
 				PreConfig: func() {
 					withAccGrantConn(t, func(db *DBConnection) error {
-						return testAccRedshiftGrantCreateSchemaWithMatview(db, schemaName)
+						return testAccRedshiftGrantCreateSchemaTables(db, schemaName, "rebuilt")
 					})
 				},
-				Config: testAccRedshiftGrantAllTablesGroupConfig(groupName, schemaName, "select"),
+				Config: config,
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("redshift_grant.all_tables", "id", grantID),
-					resource.TestCheckResourceAttr("redshift_grant.all_tables", "privileges.#", "1"),
-					resource.TestCheckTypeSetElemAttr("redshift_grant.all_tables", "privileges.*", "select"),
+					resource.TestCheckResourceAttr("redshift_grant.table", "privileges.#", "2"),
+					testAccCheckUserTablePrivilege(schemaName, "rebuilt", userName, "select", true),
 				),
 			},
 			{
-				// Extra privilege on one table, out of band: still ignored.
 				PreConfig: func() {
 					withAccGrantConn(t, func(db *DBConnection) error {
-						_, err := db.Exec(fmt.Sprintf("GRANT UPDATE ON %s.table_b TO GROUP %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName)))
+						table := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier("rebuilt"))
+						if _, err := db.Exec(fmt.Sprintf("DROP TABLE %s", table)); err != nil {
+							return err
+						}
+						_, err := db.Exec(fmt.Sprintf("CREATE TABLE %s (id int)", table))
 						return err
 					})
 				},
-				Config:   testAccRedshiftGrantAllTablesGroupConfig(groupName, schemaName, "select"),
-				PlanOnly: true,
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.table", "privileges.#", "2"),
+					testAccCheckUserTablePrivilege(schemaName, "rebuilt", userName, "select", true),
+					testAccCheckUserTablePrivilege(schemaName, "rebuilt", userName, "insert", true),
+				),
 			},
 		},
 	})
 }
 
-// TestAccRedshiftGrant_AllTables_Public exercises the GRANT TO PUBLIC query
-// branch, including convergence with a materialized view present.
-func TestAccRedshiftGrant_AllTables_Public(t *testing.T) {
-	anchorGroup := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_anchor"), "-", "_")
-	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_public"), "-", "_")
-	grantID := fmt.Sprintf("gn:public_ot:table_%s", schemaName)
+func TestAccRedshiftGrant_InheritedThroughRole(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_inherited"), "-", "_")
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_inherited"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_inherited"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + fmt.Sprintf(`
+resource "redshift_role" "reader" {
+  name = %[1]q
+}
 
-	resource.Test(t, resource.TestCase{
+resource "redshift_user_role" "grantee" {
+  user  = redshift_user.grantee.name
+  roles = [redshift_role.reader.name]
+}
+
+resource "redshift_grant" "role" {
+  role        = redshift_role.reader.name
+  schema      = %[2]q
+  object_type = "table"
+  objects     = ["test_table"]
+  privileges  = ["select"]
+}
+
+resource "redshift_grant" "user" {
+  user        = redshift_user.grantee.name
+  schema      = %[2]q
+  object_type = "table"
+  objects     = ["test_table"]
+  privileges  = ["insert"]
+
+  depends_on = [redshift_grant.role, redshift_user_role.grantee]
+}
+`, roleName, schemaName)
+
+	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: testAccProviders,
 		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
 		Steps: []resource.TestStep{
 			{
-				// The anchor group only configures the provider so the following
-				// PreConfig can open a connection; the grant itself targets PUBLIC.
-				Config: testAccRedshiftGrantGroupConfig(anchorGroup),
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantCreateSchemaTables(db, schemaName, "test_table")
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.user", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.user", "privileges.*", "insert"),
+					resource.TestCheckResourceAttr("redshift_grant.role", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.role", "privileges.*", "select"),
+					testAccCheckUserTablePrivilege(schemaName, "test_table", userName, "select", false),
+				),
+			},
+			// SELECT inherited through the role must not show up as drift of the direct grant.
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestUncoveredTables(t *testing.T) {
+	privileges := func(privileges ...interface{}) *tfschema.Set {
+		return tfschema.NewSet(tfschema.HashString, privileges)
+	}
+	tablesPrivileges := map[string]*tfschema.Set{
+		"table_c": privileges(),
+		"table_a": privileges("select", "insert"),
+		"table_b": privileges("select"),
+	}
+
+	tests := map[string]struct {
+		managed  *tfschema.Set
+		expected []string
+	}{
+		"all tables covered": {
+			managed:  privileges(),
+			expected: nil,
+		},
+		"new table without privileges": {
+			managed:  privileges("select"),
+			expected: []string{"table_c"},
+		},
+		"partially covered tables": {
+			managed:  privileges("select", "insert"),
+			expected: []string{"table_b", "table_c"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if result := uncoveredObjects(tablesPrivileges, tt.managed); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected uncovered tables %v but got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestAccRedshiftGrant_AllTables_EmptySchema guards against reporting drift when
+// the schema contains no tables. There is nothing to read back, so the
+// configured privileges must be left in state and the follow-up plan must be
+// empty (an empty set would be permanent, unresolvable drift).
+func TestAccRedshiftGrant_AllTables_EmptySchema(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_emptyschema"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_emptyschema"), "-", "_")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedshiftGrantUserConfig(userName),
 			},
 			{
 				PreConfig: func() {
 					withAccGrantConn(t, func(db *DBConnection) error {
-						return testAccRedshiftGrantCreateSchemaWithMatview(db, schemaName)
+						return testAccRedshiftGrantCreateSchemaTables(db, schemaName)
 					})
 				},
-				Config: testAccRedshiftGrantAllTablesPublicConfig(anchorGroup, schemaName, "select"),
+				Config: testAccRedshiftGrantAllTablesConfig(userName, schemaName, "select"),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("redshift_grant.all_tables", "id", grantID),
 					resource.TestCheckResourceAttr("redshift_grant.all_tables", "privileges.#", "1"),
 					resource.TestCheckTypeSetElemAttr("redshift_grant.all_tables", "privileges.*", "select"),
 				),
@@ -1321,53 +1480,1376 @@ func TestAccRedshiftGrant_AllTables_Public(t *testing.T) {
 	})
 }
 
-func testAccRedshiftGrantGroupConfig(group string) string {
+func testAccRedshiftGrantUserConfig(user string) string {
 	return fmt.Sprintf(`
-resource "redshift_group" "grantee" {
+resource "redshift_user" "grantee" {
   name = %[1]q
 }
-`, group)
+`, user)
 }
 
-func testAccRedshiftGrantAllTablesGroupConfig(group, schemaName string, privileges ...string) string {
-	return testAccRedshiftGrantGroupConfig(group) + fmt.Sprintf(`
+func testAccRedshiftGrantAllTablesConfig(user, schemaName string, privileges ...string) string {
+	quoted := make([]string, len(privileges))
+	for i, privilege := range privileges {
+		quoted[i] = fmt.Sprintf("%q", privilege)
+	}
+	return testAccRedshiftGrantUserConfig(user) + fmt.Sprintf(`
 resource "redshift_grant" "all_tables" {
-  group       = redshift_group.grantee.name
+  user        = redshift_user.grantee.name
   schema      = %[1]q
   object_type = "table"
   objects     = []
   privileges  = [%[2]s]
 }
-`, schemaName, quotePrivileges(privileges))
+`, schemaName, strings.Join(quoted, ", "))
 }
 
-func testAccRedshiftGrantAllTablesPublicConfig(anchorGroup, schemaName string, privileges ...string) string {
-	return testAccRedshiftGrantGroupConfig(anchorGroup) + fmt.Sprintf(`
-resource "redshift_grant" "all_tables" {
-  group       = "public"
+// withAccGrantConn opens a Redshift connection using the configured test
+// provider and runs fn against it, failing the test on any error.
+// TestAccRedshiftGrant_RoleSchemaAndTables covers grants to a role on a schema and
+// its tables, which are read from the same snapshot of the role's privileges. A
+// privilege revoked out of band must still show up as drift.
+func TestAccRedshiftGrant_RoleSchemaAndTables(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_objects"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_role_objects"), "-", "_")
+
+	roleConfig := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %q
+}
+`, roleName)
+	config := roleConfig + fmt.Sprintf(`
+resource "redshift_grant" "schema" {
+  role        = redshift_role.role.name
+  schema      = %[1]q
+  object_type = "schema"
+  privileges  = ["usage"]
+}
+
+resource "redshift_grant" "tables" {
+  role        = redshift_role.role.name
   schema      = %[1]q
   object_type = "table"
-  objects     = []
-  privileges  = [%[2]s]
+  privileges  = ["select"]
 }
-`, schemaName, quotePrivileges(privileges))
+`, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: roleConfig,
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantCreateSchemaTables(db, schemaName, "table_a", "table_b")
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.schema", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.schema", "privileges.*", "usage"),
+					resource.TestCheckResourceAttr("redshift_grant.tables", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.tables", "privileges.*", "select"),
+				),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("REVOKE SELECT ON %s.%s FROM ROLE %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier("table_a"), pq.QuoteIdentifier(roleName)))
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
 }
 
-func quotePrivileges(privileges []string) string {
-	quoted := make([]string, len(privileges))
-	for i, privilege := range privileges {
-		quoted[i] = fmt.Sprintf("%q", privilege)
+func withAccGrantConn(t *testing.T, fn func(db *DBConnection) error) {
+	dbClient := testAccProvider.Meta().(*Client)
+	conn, err := dbClient.Connect()
+	defer dbClient.Close()
+	if err != nil {
+		t.Fatalf("couldn't start redshift connection: %s", err)
+	}
+	if err := fn(conn); err != nil {
+		t.Fatalf("redshift setup/teardown failed: %s", err)
 	}
-	return strings.Join(quoted, ", ")
 }
 
-func testAccRedshiftGrantCreateSchemaWithMatview(db *DBConnection, schema string) error {
-	if err := testAccRedshiftGrantCreateSchemaTables(db, schema, "table_a", "table_b"); err != nil {
-		return err
+// testAccCheckUserTablePrivilege asserts whether a user holds a specific
+// privilege on a specific table, read directly from the catalog.
+func testAccCheckUserTablePrivilege(schemaName, table, user, privilege string, want bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		dbClient := testAccProvider.Meta().(*Client)
+		conn, err := dbClient.Connect()
+		if err != nil {
+			return fmt.Errorf("couldn't connect to redshift: %w", err)
+		}
+		defer dbClient.Close()
+
+		const query = `
+SELECT COALESCE(MAX(CASE WHEN privilege_type = $1 THEN 1 ELSE 0 END), 0)
+FROM svv_relation_privileges
+WHERE namespace_name = $2 AND relation_name = $3 AND identity_name = $4 AND identity_type = 'user'`
+		var granted int
+		if err := conn.QueryRow(query, strings.ToUpper(privilege), schemaName, table, user).Scan(&granted); err != nil {
+			return fmt.Errorf("couldn't read privileges for %s.%s: %w", schemaName, table, err)
+		}
+		if (granted == 1) != want {
+			return fmt.Errorf("table %s.%s: privilege %q for user %s granted=%v, want %v", schemaName, table, privilege, user, granted == 1, want)
+		}
+		return nil
 	}
-	stmt := fmt.Sprintf("CREATE MATERIALIZED VIEW %s.mv_a AS SELECT id FROM %s.table_a", pq.QuoteIdentifier(schema), pq.QuoteIdentifier(schema))
-	if _, err := db.Exec(stmt); err != nil {
-		return fmt.Errorf("couldn't create materialized view: %w", err)
+}
+
+// testAccRedshiftGrantDropSchema returns a CheckDestroy that removes the test
+// schema (and its tables) once the Terraform-managed resources are destroyed.
+func testAccRedshiftGrantDropSchema(schemaName string) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		dbClient := testAccProvider.Meta().(*Client)
+		conn, err := dbClient.Connect()
+		if err != nil {
+			return fmt.Errorf("couldn't connect to redshift: %w", err)
+		}
+		defer dbClient.Close()
+		if _, err := conn.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", pq.QuoteIdentifier(schemaName))); err != nil {
+			return fmt.Errorf("couldn't drop test schema: %w", err)
+		}
+		return nil
+	}
+}
+
+func testAccRedshiftGrantCreateSchemaTables(db *DBConnection, schema string, tables ...string) error {
+	if _, err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s", pq.QuoteIdentifier(schema))); err != nil {
+		return fmt.Errorf("couldn't create schema: %w", err)
+	}
+	for _, table := range tables {
+		stmt := fmt.Sprintf("CREATE TABLE %s.%s (id int)", pq.QuoteIdentifier(schema), pq.QuoteIdentifier(table))
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("couldn't create table %s: %w", table, err)
+		}
 	}
 	return nil
 }
+
+// TestAccRedshiftGrant_AllTables_MaterializedView guards convergence when the
+// schema contains a materialized view. The view's internal storage table
+// (mv_tbl__*) is never covered by GRANT ... ON ALL TABLES, so it must be
+// excluded from the read; otherwise the intersection would permanently drop the
+// granted privilege.
+func TestAccRedshiftGrant_AllTables_MaterializedView(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_matview"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_matview"), "-", "_")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedshiftGrantUserConfig(userName),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantCreateSchemaWithMatview(db, schemaName)
+					})
+				},
+				Config: testAccRedshiftGrantAllTablesConfig(userName, schemaName, "select"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.all_tables", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.all_tables", "privileges.*", "select"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccRedshiftGrant_AllTables_Group exercises the group query branch: the
+// grant converges (including with a materialized view present) and an extra
+// privilege on one table is ignored by the intersection.
+func TestAccRedshiftGrant_AllTables_Group(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_alltables"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_group"), "-", "_")
+	grantID := fmt.Sprintf("gn:%s_ot:table_%s", groupName, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedshiftGrantGroupConfig(groupName),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantCreateSchemaWithMatview(db, schemaName)
+					})
+				},
+				Config: testAccRedshiftGrantAllTablesGroupConfig(groupName, schemaName, "select"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.all_tables", "id", grantID),
+					resource.TestCheckResourceAttr("redshift_grant.all_tables", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.all_tables", "privileges.*", "select"),
+				),
+			},
+			{
+				// Extra privilege on one table, out of band: still ignored.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("GRANT UPDATE ON %s.table_b TO GROUP %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName)))
+						return err
+					})
+				},
+				Config:   testAccRedshiftGrantAllTablesGroupConfig(groupName, schemaName, "select"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestAccRedshiftGrant_AllTables_Public exercises the GRANT TO PUBLIC query
+// branch, including convergence with a materialized view present.
+func TestAccRedshiftGrant_AllTables_Public(t *testing.T) {
+	anchorGroup := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_anchor"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_public"), "-", "_")
+	grantID := fmt.Sprintf("gn:public_ot:table_%s", schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				// The anchor group only configures the provider so the following
+				// PreConfig can open a connection; the grant itself targets PUBLIC.
+				Config: testAccRedshiftGrantGroupConfig(anchorGroup),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantCreateSchemaWithMatview(db, schemaName)
+					})
+				},
+				Config: testAccRedshiftGrantAllTablesPublicConfig(anchorGroup, schemaName, "select"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.all_tables", "id", grantID),
+					resource.TestCheckResourceAttr("redshift_grant.all_tables", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.all_tables", "privileges.*", "select"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRedshiftGrantGroupConfig(group string) string {
+	return fmt.Sprintf(`
+resource "redshift_group" "grantee" {
+  name = %[1]q
+}
+`, group)
+}
+
+func testAccRedshiftGrantAllTablesGroupConfig(group, schemaName string, privileges ...string) string {
+	return testAccRedshiftGrantGroupConfig(group) + fmt.Sprintf(`
+resource "redshift_grant" "all_tables" {
+  group       = redshift_group.grantee.name
+  schema      = %[1]q
+  object_type = "table"
+  objects     = []
+  privileges  = [%[2]s]
+}
+`, schemaName, quotePrivileges(privileges))
+}
+
+func testAccRedshiftGrantAllTablesPublicConfig(anchorGroup, schemaName string, privileges ...string) string {
+	return testAccRedshiftGrantGroupConfig(anchorGroup) + fmt.Sprintf(`
+resource "redshift_grant" "all_tables" {
+  group       = "public"
+  schema      = %[1]q
+  object_type = "table"
+  objects     = []
+  privileges  = [%[2]s]
+}
+`, schemaName, quotePrivileges(privileges))
+}
+
+func quotePrivileges(privileges []string) string {
+	quoted := make([]string, len(privileges))
+	for i, privilege := range privileges {
+		quoted[i] = fmt.Sprintf("%q", privilege)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func testAccRedshiftGrantCreateSchemaWithMatview(db *DBConnection, schema string) error {
+	if err := testAccRedshiftGrantCreateSchemaTables(db, schema, "table_a", "table_b"); err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf("CREATE MATERIALIZED VIEW %s.mv_a AS SELECT id FROM %s.table_a", pq.QuoteIdentifier(schema), pq.QuoteIdentifier(schema))
+	if _, err := db.Exec(stmt); err != nil {
+		return fmt.Errorf("couldn't create materialized view: %w", err)
+	}
+	return nil
+}
+
+func TestAccRedshiftGrant_AllSchemas(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_allschemas"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_allschemas"), "-", "_")
+	grantID := fmt.Sprintf("gn:%s_ot:schema_all_schemas", groupName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedshiftGrantAllSchemasConfig(groupName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.all_schemas", "id", grantID),
+					resource.TestCheckResourceAttr("redshift_grant.all_schemas", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.all_schemas", "privileges.*", "usage"),
+				),
+			},
+			{
+				// A schema created out of band lacks the grant and shows up as drift.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s", pq.QuoteIdentifier(schemaName)))
+						return err
+					})
+				},
+				Config:             testAccRedshiftGrantAllSchemasConfig(groupName),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccRedshiftGrantAllSchemasConfig(groupName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.all_schemas", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.all_schemas", "privileges.*", "usage"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRedshiftGrantAllSchemasConfig(group string) string {
+	return testAccRedshiftGrantGroupConfig(group) + `
+resource "redshift_grant" "all_schemas" {
+  group       = redshift_group.grantee.name
+  object_type = "schema"
+  all_schemas = true
+  privileges  = ["usage"]
+}
+`
+}
+
+func TestCreateAllSchemasGrantsQueries(t *testing.T) {
+	schemaNames := []string{"public", "analytics"}
+
+	tests := map[string]struct {
+		raw      map[string]interface{}
+		change   grantChange
+		expected []string
+	}{
+		"grant to group": {
+			raw: map[string]interface{}{
+				grantGroupAttr:      "analysts",
+				grantObjectTypeAttr: "schema",
+				grantAllSchemasAttr: true,
+				grantPrivilegesAttr: []interface{}{"usage"},
+			},
+			change: grantChange{grant: []string{"usage"}},
+			expected: []string{
+				`GRANT usage ON SCHEMA "public" TO GROUP "analysts"`,
+				`GRANT usage ON SCHEMA "analytics" TO GROUP "analysts"`,
+			},
+		},
+		"revoke from role": {
+			raw: map[string]interface{}{
+				grantRoleAttr:       "reader",
+				grantObjectTypeAttr: "schema",
+				grantAllSchemasAttr: true,
+				grantPrivilegesAttr: []interface{}{"usage"},
+			},
+			change: grantChange{revoke: []string{"usage"}},
+			expected: []string{
+				`REVOKE usage ON SCHEMA "public" FROM ROLE "reader"`,
+				`REVOKE usage ON SCHEMA "analytics" FROM ROLE "reader"`,
+			},
+		},
+		"revoke all from user": {
+			raw: map[string]interface{}{
+				grantUserAttr:       "john",
+				grantObjectTypeAttr: "schema",
+				grantAllSchemasAttr: true,
+				grantPrivilegesAttr: []interface{}{},
+			},
+			change: grantChange{revokeAll: true},
+			expected: []string{
+				`REVOKE ALL PRIVILEGES ON SCHEMA "public" FROM  "john"`,
+				`REVOKE ALL PRIVILEGES ON SCHEMA "analytics" FROM  "john"`,
+			},
+		},
+		"grant to public skips public schema": {
+			raw: map[string]interface{}{
+				grantGroupAttr:      "public",
+				grantObjectTypeAttr: "schema",
+				grantAllSchemasAttr: true,
+				grantPrivilegesAttr: []interface{}{"usage"},
+			},
+			change: grantChange{grant: []string{"usage"}},
+			expected: []string{
+				`GRANT usage ON SCHEMA "analytics" TO  PUBLIC`,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, tt.raw)
+			queries := createAllSchemasGrantsQueries(d, filterAllSchemasGrantSchemas(d, schemaNames), tt.change)
+			if len(queries) != len(tt.expected) {
+				t.Fatalf("Expected %d queries but got %d: %v", len(tt.expected), len(queries), queries)
+			}
+			for i := range queries {
+				if queries[i] != tt.expected[i] {
+					t.Errorf("Expected query %q but got %q", tt.expected[i], queries[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsAllSchemasGrantID(t *testing.T) {
+	tests := map[string]struct {
+		raw      map[string]interface{}
+		expected bool
+	}{
+		"all schemas": {
+			raw: map[string]interface{}{
+				grantGroupAttr:      "analysts",
+				grantObjectTypeAttr: "schema",
+				grantAllSchemasAttr: true,
+				grantPrivilegesAttr: []interface{}{"usage"},
+			},
+			expected: true,
+		},
+		"single schema": {
+			raw: map[string]interface{}{
+				grantGroupAttr:      "analysts",
+				grantObjectTypeAttr: "schema",
+				grantSchemaAttr:     "analytics",
+				grantPrivilegesAttr: []interface{}{"usage"},
+			},
+		},
+		"schema named like the attribute": {
+			raw: map[string]interface{}{
+				grantGroupAttr:      "analysts",
+				grantObjectTypeAttr: "schema",
+				grantSchemaAttr:     grantAllSchemasAttr,
+				grantPrivilegesAttr: []interface{}{"usage"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, tt.raw)
+			d.SetId(generateGrantID(d))
+			d.Set(grantAllSchemasAttr, false)
+
+			if actual := isAllSchemasGrantID(d); actual != tt.expected {
+				t.Errorf("Expected %t for ID %q but got %t", tt.expected, d.Id(), actual)
+			}
+		})
+	}
+}
+
+func TestCreateGrantsRevokeQuery(t *testing.T) {
+	d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantUserAttr:       "john",
+		grantSchemaAttr:     "my_schema",
+		grantObjectTypeAttr: "table",
+		grantObjectsAttr:    []interface{}{"my_table"},
+		grantPrivilegesAttr: []interface{}{"select"},
+	})
+
+	tests := map[string]struct {
+		privileges []string
+		expected   string
+	}{
+		"managed privileges only": {
+			privileges: []string{"select", "update"},
+			expected:   `REVOKE select,update ON TABLE "my_schema"."my_table" FROM  "john"`,
+		},
+		"all privileges": {
+			expected: `REVOKE ALL PRIVILEGES ON TABLE "my_schema"."my_table" FROM  "john"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if query := createGrantsRevokeQuery(d, "db", tt.privileges); query != tt.expected {
+				t.Errorf("Expected query %q but got %q", tt.expected, query)
+			}
+		})
+	}
+}
+
+func TestGetGrantChange_OnlyDelta(t *testing.T) {
+	base := func(privileges []interface{}, objects []interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			grantUserAttr:       "john",
+			grantSchemaAttr:     "my_schema",
+			grantObjectTypeAttr: "table",
+			grantObjectsAttr:    objects,
+			grantPrivilegesAttr: privileges,
+		}
+	}
+
+	tests := map[string]struct {
+		old      map[string]interface{}
+		new      map[string]interface{}
+		expected []string
+	}{
+		"added privilege": {
+			old: base([]interface{}{"select"}, []interface{}{"my_table"}),
+			new: base([]interface{}{"select", "insert"}, []interface{}{"my_table"}),
+			expected: []string{
+				`GRANT insert ON TABLE "my_schema"."my_table" TO  "john"`,
+			},
+		},
+		"removed privilege": {
+			old: base([]interface{}{"select", "insert"}, []interface{}{"my_table"}),
+			new: base([]interface{}{"select"}, []interface{}{"my_table"}),
+			expected: []string{
+				`REVOKE insert ON TABLE "my_schema"."my_table" FROM  "john"`,
+			},
+		},
+		"replaced privilege": {
+			old: base([]interface{}{"select", "insert"}, []interface{}{"my_table"}),
+			new: base([]interface{}{"select", "update"}, []interface{}{"my_table"}),
+			expected: []string{
+				`REVOKE insert ON TABLE "my_schema"."my_table" FROM  "john"`,
+				`GRANT update ON TABLE "my_schema"."my_table" TO  "john"`,
+			},
+		},
+		"changed objects grant all privileges": {
+			old: base([]interface{}{"select"}, []interface{}{"my_table"}),
+			new: base([]interface{}{"select", "insert"}, []interface{}{"my_table", "other_table"}),
+			expected: []string{
+				`GRANT insert,select ON TABLE "my_schema"."my_table","my_schema"."other_table" TO  "john"`,
+			},
+		},
+	}
+
+	r := redshiftGrant()
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := tfschema.TestResourceDataRaw(t, r.Schema, tt.old)
+			old.SetId("un:john_my_schema_table")
+			state := old.State()
+			diff, err := r.Diff(t.Context(), state, terraform.NewResourceConfigRaw(tt.new), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			d, err := tfschema.InternalMap(r.Schema).Data(state, diff)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			change := getGrantChange(d)
+			slices.Sort(change.grant)
+			var queries []string
+			if change.revokeAll || len(change.revoke) > 0 {
+				queries = append(queries, createGrantsRevokeQuery(d, "", change.revoke))
+			}
+			if len(change.grant) > 0 {
+				queries = append(queries, createGrantsQuery(d, "", change.grant))
+			}
+			if !reflect.DeepEqual(queries, tt.expected) {
+				t.Errorf("Expected queries %q but got %q", tt.expected, queries)
+			}
+		})
+	}
+}
+
+func TestAccRedshiftGrant_DisjointPrivilegesSameObject(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_disjoint")
+	schemaName := generateRandomObjectName("tf_acc_schema_disjoint")
+
+	config := func(resources string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %[1]q
+}
+%[2]s
+`, userName, resources)
+	}
+	selectGrant := fmt.Sprintf(`
+resource "redshift_grant" "select" {
+  user        = redshift_user.user.name
+  schema      = %[1]q
+  object_type = "table"
+  objects     = ["table_a"]
+  privileges  = ["select"]
+}
+`, schemaName)
+	insertGrant := fmt.Sprintf(`
+resource "redshift_grant" "insert" {
+  user        = redshift_user.user.name
+  schema      = %[1]q
+  object_type = "table"
+  objects     = ["table_a"]
+  privileges  = ["insert"]
+}
+`, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantCreateSchemaTables(db, schemaName, "table_a")
+					})
+				},
+				Config: config(selectGrant + insertGrant),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.select", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("redshift_grant.insert", "privileges.#", "1"),
+					testAccCheckUserTablePrivilege(schemaName, "table_a", userName, "select", true),
+					testAccCheckUserTablePrivilege(schemaName, "table_a", userName, "insert", true),
+				),
+			},
+			{
+				// Both resources converge instead of revoking each other's privileges.
+				Config:   config(selectGrant + insertGrant),
+				PlanOnly: true,
+			},
+			{
+				// Removing one resource leaves the privilege of the other one in place.
+				Config: config(selectGrant),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserTablePrivilege(schemaName, "table_a", userName, "select", true),
+					testAccCheckUserTablePrivilege(schemaName, "table_a", userName, "insert", false),
+				),
+			},
+		},
+	})
+}
+
+func TestCreateGrantsQuery_Language(t *testing.T) {
+	d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantGroupAttr:      "analysts",
+		grantObjectTypeAttr: "language",
+		grantObjectsAttr:    []interface{}{"plpythonu"},
+		grantPrivilegesAttr: []interface{}{"usage"},
+	})
+
+	expected := `GRANT usage ON LANGUAGE "plpythonu" TO GROUP "analysts"`
+	if query := createGrantsQuery(d, "db", []string{"usage"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+
+	expected = `REVOKE USAGE ON LANGUAGE "plpythonu" FROM GROUP "analysts"`
+	if query := createGrantsRevokeQuery(d, "db", []string{"usage"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+}
+
+func TestValidateLanguageGrantDiff(t *testing.T) {
+	tests := map[string]struct {
+		config      map[string]interface{}
+		expectedErr *regexp.Regexp
+	}{
+		"public": {
+			config: map[string]interface{}{"group": "public", "object_type": "language", "objects": []interface{}{"plpythonu"}, "privileges": []interface{}{"usage"}},
+		},
+		"no languages": {
+			config:      map[string]interface{}{"group": "public", "object_type": "language", "privileges": []interface{}{"usage"}},
+			expectedErr: regexp.MustCompile("parameter `objects` is required for objects of type language"),
+		},
+		"schema": {
+			config:      map[string]interface{}{"user": "alice", "schema": "sales", "object_type": "language", "objects": []interface{}{"plpythonu"}, "privileges": []interface{}{"usage"}},
+			expectedErr: regexp.MustCompile("cannot specify `schema` when `object_type` is `language`"),
+		},
+		"schema without objects": {
+			config: map[string]interface{}{"user": "alice", "schema": "sales", "object_type": "schema", "privileges": []interface{}{"usage"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := redshiftGrant().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			switch {
+			case tt.expectedErr == nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.expectedErr != nil && err == nil:
+				t.Errorf("expected an error matching %q", tt.expectedErr)
+			case tt.expectedErr != nil && !tt.expectedErr.MatchString(err.Error()):
+				t.Errorf("expected an error matching %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateGrantObjectName(t *testing.T) {
+	tests := map[string]struct {
+		object      string
+		expectedErr string
+	}{
+		"table":                {object: "orders"},
+		"callable":             {object: "sales_tax(numeric(10,2), float)"},
+		"qualified table":      {object: "sales.orders", expectedErr: `set schema = "sales" and use "orders" instead`},
+		"qualified callable":   {object: "sales.tax(float)", expectedErr: `set schema = "sales" and use "tax(float)" instead`},
+		"table and column":     {object: "sales.orders.id", expectedErr: "must be a bare name, set its schema in schema"},
+		"database qualified":   {object: "dev.sales.tax(float)", expectedErr: "must be a bare name"},
+		"dot in argument type": {object: "tax(sales.money)"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, errs := validateGrantObjectName(tt.object, "objects")
+			switch {
+			case tt.expectedErr == "" && len(errs) > 0:
+				t.Errorf("unexpected errors: %v", errs)
+			case tt.expectedErr != "" && len(errs) == 0:
+				t.Errorf("expected an error containing %q", tt.expectedErr)
+			case tt.expectedErr != "" && !strings.Contains(errs[0].Error(), tt.expectedErr):
+				t.Errorf("expected an error containing %q, got %v", tt.expectedErr, errs[0])
+			}
+		})
+	}
+}
+
+func TestCreateGrantsQuery_ProcedureSignature(t *testing.T) {
+	d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantUserAttr:       "john",
+		grantSchemaAttr:     "my_schema",
+		grantObjectTypeAttr: "procedure",
+		grantObjectsAttr:    []interface{}{"MyProc(int, varchar)"},
+		grantPrivilegesAttr: []interface{}{"execute"},
+	})
+
+	expected := `GRANT execute ON PROCEDURE "my_schema"."myproc"(int, varchar) TO  "john"`
+	if query := createGrantsQuery(d, "db", []string{"execute"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+
+	expected = `REVOKE execute ON PROCEDURE "my_schema"."myproc"(int, varchar) FROM  "john"`
+	if query := createGrantsRevokeQuery(d, "db", []string{"execute"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+}
+
+// TestRedshiftGrant_QuotedCallableName takes a quoted mixed case function name from the configuration through
+// the GRANT, the state and the matching of the functions read from the catalog.
+func TestRedshiftGrant_QuotedCallableName(t *testing.T) {
+	config := map[string]interface{}{
+		grantUserAttr:       "john",
+		grantSchemaAttr:     "my_schema",
+		grantObjectTypeAttr: "function",
+		grantObjectsAttr:    []interface{}{`"MyProc"(int)`},
+		grantPrivilegesAttr: []interface{}{"execute"},
+	}
+	d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, config)
+
+	expected := `GRANT execute ON FUNCTION "my_schema"."MyProc"(int) TO  "john"`
+	if query := createGrantsQuery(d, "db", []string{"execute"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+
+	d.SetId(generateGrantID(d))
+	state := d.State()
+	if objects := d.Get(grantObjectsAttr).(*tfschema.Set); !objects.Contains(`"MyProc"(int)`) {
+		t.Errorf("Expected the object to be kept in state as configured, got %v", objects.List())
+	}
+
+	diff, err := redshiftGrant().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Errorf("Expected no diff, got %v", diff.Attributes)
+	}
+
+	var callables []callableSignature
+	for _, object := range getGrantObjects(d).List() {
+		callables = append(callables, parseCallableSignature(object.(string), false))
+	}
+	if !matchesCallableSignatures(callables, "MyProc", "int") {
+		t.Error("Expected the function read from the catalog to match")
+	}
+	if matchesCallableSignatures(callables, "myproc", "int") {
+		t.Error("Expected the lower case function read from the catalog not to match")
+	}
+}
+
+func TestGetGrantObjects_PreserveCase(t *testing.T) {
+	tests := map[string]struct {
+		objectType   string
+		objects      []interface{}
+		preserveCase bool
+		expected     []string
+	}{
+		"tables":                   {objectType: "table", objects: []interface{}{"MyTable"}, expected: []string{"mytable"}},
+		"tables, preserve case":    {objectType: "table", objects: []interface{}{"MyTable"}, preserveCase: true, expected: []string{"MyTable"}},
+		"callables":                {objectType: "function", objects: []interface{}{"MyProc(int)", `"OtherProc"`}, expected: []string{`"OtherProc"`, `"myproc"(int)`}},
+		"callables, preserve case": {objectType: "function", objects: []interface{}{"MyProc(int)", `"OtherProc"`}, preserveCase: true, expected: []string{`"MyProc"(int)`, `"OtherProc"`}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+				grantUserAttr:       "john",
+				grantSchemaAttr:     "my_schema",
+				grantObjectTypeAttr: tt.objectType,
+				grantObjectsAttr:    tt.objects,
+				grantPrivilegesAttr: []interface{}{"select"},
+				preserveCaseAttr:    tt.preserveCase,
+			})
+
+			objects := setToStringList(getGrantObjects(d))
+			slices.Sort(objects)
+			if !reflect.DeepEqual(objects, tt.expected) {
+				t.Errorf("Expected objects %v but got %v", tt.expected, objects)
+			}
+		})
+	}
+}
+
+func TestCreateGrantsQuery_ExternalFunction(t *testing.T) {
+	d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantRoleAttr:       "analyst",
+		grantSchemaAttr:     "my_schema",
+		grantObjectTypeAttr: "external_function",
+		grantObjectsAttr:    []interface{}{"my_lambda_udf(varchar)"},
+		grantPrivilegesAttr: []interface{}{"execute"},
+	})
+
+	expected := `GRANT execute ON FUNCTION "my_schema"."my_lambda_udf"(varchar) TO ROLE "analyst"`
+	if query := createGrantsQuery(d, "db", []string{"execute"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+
+	expected = `REVOKE execute ON FUNCTION "my_schema"."my_lambda_udf"(varchar) FROM ROLE "analyst"`
+	if query := createGrantsRevokeQuery(d, "db", []string{"execute"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+}
+
+func TestCreateGrantsQuery_FunctionReservedWord(t *testing.T) {
+	d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantGroupAttr:      "analysts",
+		grantSchemaAttr:     "my_schema",
+		grantObjectTypeAttr: "function",
+		grantObjectsAttr:    []interface{}{"select(int)"},
+		grantPrivilegesAttr: []interface{}{"execute"},
+	})
+
+	expected := `GRANT execute ON FUNCTION "my_schema"."select"(int) TO GROUP "analysts"`
+	if query := createGrantsQuery(d, "db", []string{"execute"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+}
+
+// TestAccRedshiftGrant_OverloadedFunctions checks that grants on overloads of the same
+// function are read back per signature.
+func TestCreateGrantsQuery_Grantees(t *testing.T) {
+	d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantGranteesAttr: []interface{}{
+			map[string]interface{}{grantGranteeTypeAttr: "role", grantGranteeNameAttr: "Reader"},
+			map[string]interface{}{grantGranteeTypeAttr: "user", grantGranteeNameAttr: "john"},
+			map[string]interface{}{grantGranteeTypeAttr: "group", grantGranteeNameAttr: "Analysts"},
+		},
+		grantSchemaAttr:     "my_schema",
+		grantObjectTypeAttr: "table",
+		grantPrivilegesAttr: []interface{}{"select"},
+	})
+
+	expected := `GRANT select ON ALL TABLES IN SCHEMA "my_schema" TO  GROUP "Analysts", ROLE "reader", "john"`
+	if query := createGrantsQuery(d, "db", []string{"select"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+
+	expected = `REVOKE select ON ALL TABLES IN SCHEMA "my_schema" FROM  GROUP "Analysts", ROLE "reader", "john"`
+	if query := createGrantsRevokeQuery(d, "db", []string{"select"}); query != expected {
+		t.Errorf("Expected query %q but got %q", expected, query)
+	}
+
+	expectedGrantees := []grantGrantee{
+		{granteeType: "group", name: "Analysts"},
+		{granteeType: "role", name: "reader"},
+		{granteeType: "user", name: "john"},
+	}
+	if grantees := getGrantGrantees(d); !reflect.DeepEqual(grantees, expectedGrantees) {
+		t.Errorf("Expected grantees %v but got %v", expectedGrantees, grantees)
+	}
+
+	id := generateGrantID(d)
+	if !regexp.MustCompile(`^gs:\d+_ot:table_my_schema$`).MatchString(id) {
+		t.Errorf("Unexpected ID %q", id)
+	}
+
+	reordered := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantGranteesAttr: []interface{}{
+			map[string]interface{}{grantGranteeTypeAttr: "group", grantGranteeNameAttr: "Analysts"},
+			map[string]interface{}{grantGranteeTypeAttr: "user", grantGranteeNameAttr: "john"},
+			map[string]interface{}{grantGranteeTypeAttr: "role", grantGranteeNameAttr: "reader"},
+		},
+		grantSchemaAttr:     "my_schema",
+		grantObjectTypeAttr: "table",
+		grantPrivilegesAttr: []interface{}{"select"},
+	})
+	if reorderedID := generateGrantID(reordered); reorderedID != id {
+		t.Errorf("Expected the ID %q not to depend on the order of the grantees, got %q", id, reorderedID)
+	}
+}
+
+func TestAccRedshiftGrant_Grantees(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_grantees"), "-", "_")
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_grantees"), "-", "_")
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_grantees"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_grantees"), "-", "_")
+
+	config := fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %[1]q
+}
+
+resource "redshift_group" "group" {
+  name = %[2]q
+}
+
+resource "redshift_role" "role" {
+  name = %[3]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[4]q
+}
+
+resource "redshift_grant" "grantees" {
+  schema      = redshift_schema.schema.name
+  object_type = "schema"
+  privileges  = ["usage", "create"]
+
+  grantees {
+    type = "user"
+    name = redshift_user.user.name
+  }
+
+  grantees {
+    type = "group"
+    name = redshift_group.group.name
+  }
+
+  grantees {
+    type = "role"
+    name = redshift_role.role.name
+  }
+}
+`, userName, groupName, roleName, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("redshift_grant.grantees", "id", regexp.MustCompile(fmt.Sprintf(`^gs:\d+_ot:schema_%s$`, schemaName))),
+					resource.TestCheckResourceAttr("redshift_grant.grantees", "grantees.#", "3"),
+					testCheckTypeSetElems("redshift_grant.grantees", "privileges", "usage", "create"),
+				),
+			},
+			{
+				// A privilege revoked from one of the grantees shows up as drift.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("REVOKE CREATE ON SCHEMA %s FROM ROLE %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(roleName)))
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check:  testCheckTypeSetElems("redshift_grant.grantees", "privileges", "usage", "create"),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_OverloadedFunctions(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_overload"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_overload"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + fmt.Sprintf(`
+resource "redshift_grant" "int_overload" {
+  user        = redshift_user.grantee.name
+  schema      = %[1]q
+  object_type = "function"
+  objects     = ["test_call(int, int)"]
+  privileges  = ["execute"]
+}
+
+resource "redshift_grant" "float_overload" {
+  user        = redshift_user.grantee.name
+  schema      = %[1]q
+  object_type = "function"
+  objects     = ["test_call(float, float)"]
+  privileges  = ["execute"]
+}
+`, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedshiftGrantUserConfig(userName),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						return testAccRedshiftGrantBasicCallablesCreateSchemaAndCallables(t, db, schemaName)
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.int_overload", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("redshift_grant.float_overload", "privileges.#", "1"),
+				),
+			},
+			{
+				// Revoking one overload must be reported as drift, although the other overload is still granted.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("REVOKE EXECUTE ON FUNCTION %s.test_call(int, int) FROM %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName)))
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.int_overload", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("redshift_grant.float_overload", "privileges.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccRedshiftGrant_QuotedCallableNames checks grants on functions named with a reserved
+// word or in mixed case, which Redshift stores folded to lower case.
+func TestAccRedshiftGrant_QuotedCallableNames(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_quoted"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_quoted"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + fmt.Sprintf(`
+resource "redshift_grant" "reserved_word" {
+  user        = redshift_user.grantee.name
+  schema      = %[1]q
+  object_type = "function"
+  objects     = ["order(int)"]
+  privileges  = ["execute"]
+}
+
+resource "redshift_grant" "mixed_case" {
+  user        = redshift_user.grantee.name
+  schema      = %[1]q
+  object_type = "function"
+  objects     = ["SalesTax(float)"]
+  privileges  = ["execute"]
+}
+
+resource "redshift_grant" "quoted_mixed_case" {
+  user        = redshift_user.grantee.name
+  schema      = %[1]q
+  object_type = "function"
+  objects     = ["\"NetPrice\"(float)"]
+  privileges  = ["execute"]
+}
+`, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedshiftGrantUserConfig(userName),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						statements := []string{
+							fmt.Sprintf("CREATE SCHEMA %s", pq.QuoteIdentifier(schemaName)),
+							fmt.Sprintf(`CREATE FUNCTION %s."order" (a int) RETURNS int STABLE AS $$ SELECT $1 $$ LANGUAGE sql`, pq.QuoteIdentifier(schemaName)),
+							fmt.Sprintf(`CREATE FUNCTION %s.SalesTax (a float) RETURNS float STABLE AS $$ SELECT $1 * 0.19 $$ LANGUAGE sql`, pq.QuoteIdentifier(schemaName)),
+							fmt.Sprintf(`CREATE FUNCTION %s."NetPrice" (a float) RETURNS float STABLE AS $$ SELECT $1 / 1.19 $$ LANGUAGE sql`, pq.QuoteIdentifier(schemaName)),
+						}
+						for _, statement := range statements {
+							if _, err := db.Exec(statement); err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.reserved_word", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.reserved_word", "privileges.*", "execute"),
+					resource.TestCheckResourceAttr("redshift_grant.mixed_case", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.mixed_case", "privileges.*", "execute"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.quoted_mixed_case", "objects.*", `"NetPrice"(float)`),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.quoted_mixed_case", "privileges.*", "execute"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_MissingObjects(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_missing"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_missing"), "-", "_")
+	config := func(objectType, object, privilege string) string {
+		return testAccRedshiftGrantUserConfig(userName) + fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+  name = %[1]q
+}
+
+resource "redshift_grant" "missing" {
+  user        = redshift_user.grantee.name
+  schema      = redshift_schema.schema.name
+  object_type = %[2]q
+  objects     = [%[3]q]
+  privileges  = [%[4]q]
+}
+`, schemaName, objectType, object, privilege)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("table", "missing_table", "select"),
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`user "%s": table "%s.missing_table" does not exist`, userName, schemaName)),
+			},
+			{
+				Config:      config("function", "missing_function(int)", "execute"),
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`user "%s": function "%s.missing_function\(int\)" does not exist`, userName, schemaName)),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_MissingSchema(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_missing"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + `
+resource "redshift_grant" "missing" {
+  user        = redshift_user.grantee.name
+  schema      = "tf_acc_missing_schema"
+  object_type = "schema"
+  privileges  = ["usage"]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(fmt.Sprintf(`could not grant schema privileges to user "%s": schema "tf_acc_missing_schema" does not exist`, userName)),
+			},
+		},
+	})
+}
+
+// TestAccRedshiftGrant_AllFunctions_NewFunctionDrift covers GRANT EXECUTE ON ALL FUNCTIONS IN SCHEMA: a function
+// created after the grant lacks the privilege, which shows up as drift until the grant is applied again.
+func TestAccRedshiftGrant_AllFunctions_NewFunctionDrift(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_allfunctions"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_allfunctions"), "-", "_")
+	config := testAccRedshiftGrantUserConfig(userName) + fmt.Sprintf(`
+resource "redshift_grant" "all_functions" {
+  user        = redshift_user.grantee.name
+  schema      = %[1]q
+  object_type = "function"
+  objects     = []
+  privileges  = ["execute"]
+}
+`, schemaName)
+	createFunction := func(name string) func() {
+		return func() {
+			withAccGrantConn(t, func(db *DBConnection) error {
+				_, err := db.Exec(fmt.Sprintf("CREATE FUNCTION %s.%s (a int) RETURNS int STABLE AS $$ SELECT $1 $$ LANGUAGE sql", pq.QuoteIdentifier(schemaName), name))
+				return err
+			})
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccRedshiftGrantDropSchema(schemaName),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s", pq.QuoteIdentifier(schemaName)))
+						return err
+					})
+					createFunction("func_a")()
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.all_functions", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.all_functions", "privileges.*", "execute"),
+				),
+			},
+			{
+				PreConfig:          createFunction("func_b"),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestAccRedshiftGrant_DatabaseUsageFromDatashare grants usage on a database created from a datashare,
+// REDSHIFT_DATASHARE_CONSUMER_DATABASE names such a database of the cluster.
+func TestAccRedshiftGrant_DatabaseUsageFromDatashare(t *testing.T) {
+	databaseName := getEnvOrSkip("REDSHIFT_DATASHARE_CONSUMER_DATABASE", t)
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_datashare"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_grant" "usage" {
+  role        = redshift_role.role.name
+  database    = %[2]q
+  object_type = "database"
+  privileges  = ["usage"]
+}
+`, roleName, databaseName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.usage", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.usage", "privileges.*", "usage"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_DatabaseUsageOnLocalDatabase(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_usage"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_grant" "usage" {
+  role        = redshift_role.role.name
+  object_type = "database"
+  privileges  = ["usage"]
+}
+`, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`usage can only be granted on databases created from datashares`),
+			},
+		},
+	})
+}
+
+func TestSetManagedGrantPrivileges(t *testing.T) {
+	tests := map[string]struct {
+		managed  []interface{}
+		read     []interface{}
+		expected []interface{}
+	}{
+		"only managed privileges are kept": {
+			managed:  []interface{}{"select", "insert"},
+			read:     []interface{}{"select", "update"},
+			expected: []interface{}{"select"},
+		},
+		"empty privileges keep everything held": {
+			managed:  []interface{}{},
+			read:     []interface{}{"select", "update"},
+			expected: []interface{}{"select", "update"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := tfschema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+				grantUserAttr:       "john",
+				grantObjectTypeAttr: "database",
+				grantPrivilegesAttr: tt.managed,
+			})
+
+			setManagedGrantPrivileges(d, tfschema.NewSet(hashPrivilege, tt.read))
+
+			expected := tfschema.NewSet(hashPrivilege, tt.expected)
+			if privileges := d.Get(grantPrivilegesAttr).(*tfschema.Set); !privileges.Equal(expected) {
+				t.Errorf("Expected privileges %v but got %v", expected.List(), privileges.List())
+			}
+		})
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 	"regexp"
-	"strings"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -13,8 +14,9 @@ import (
 )
 
 const (
-	groupNameAttr  = "name"
-	groupUsersAttr = "users"
+	groupNameAttr          = "name"
+	groupUsersAttr         = "users"
+	groupAdoptExistingAttr = "adopt_existing"
 )
 
 func redshiftGroup() *schema.Resource {
@@ -23,7 +25,7 @@ func redshiftGroup() *schema.Resource {
 Groups are collections of users who are all granted whatever privileges are associated with the group. You can use groups to assign privileges by role. For example, you can create different groups for sales, administration, and support and give the users in each group the appropriate access to the data they require for their work. You can grant or revoke privileges at the group level, and those changes will apply to all members of the group, except for superusers.
 `,
 		CreateContext: ResourceFunc(resourceRedshiftGroupCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftGroupRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftGroupRead),
 		UpdateContext: ResourceFunc(resourceRedshiftGroupUpdate),
 		DeleteContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftGroupDelete),
@@ -31,16 +33,15 @@ Groups are collections of users who are all granted whatever privileges are asso
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: operationTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			groupNameAttr: {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "Name of the user group. Group names beginning with two underscores are reserved for Amazon Redshift internal use.",
-				ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile("^__.*"), "Group names beginning with two underscores are reserved for Amazon Redshift internal use"),
-				StateFunc: func(val interface{}) string {
-					return strings.ToLower(val.(string))
-				},
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "Name of the user group. Group names beginning with two underscores are reserved for Amazon Redshift internal use.",
+				ValidateFunc:     validation.StringDoesNotMatch(regexp.MustCompile("^__.*"), "Group names beginning with two underscores are reserved for Amazon Redshift internal use"),
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
 			},
 			groupUsersAttr: {
 				Type:     schema.TypeSet,
@@ -50,6 +51,13 @@ Groups are collections of users who are all granted whatever privileges are asso
 				},
 				Description: "List of the user names to add to the group",
 			},
+			groupAdoptExistingAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Take over a group with the same name which already exists instead of failing to create it, e.g. when bringing an existing cluster under management of Terraform. The members of the adopted group are set to `users`.",
+			},
+			preserveCaseAttr: preserveCaseSchema(),
 		},
 	}
 }
@@ -59,6 +67,9 @@ func resourceRedshiftGroupRead(db *DBConnection, d *schema.ResourceData) error {
 }
 
 func resourceRedshiftGroupReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	setDefaultIfUnset(d, groupAdoptExistingAttr, false)
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+
 	var (
 		groupName  string
 		groupUsers []string
@@ -91,8 +102,14 @@ func resourceRedshiftGroupReadImpl(db *DBConnection, d *schema.ResourceData) err
 	if len(groupUsers) == 0 {
 		// no users found so the group name could not be fetched, we have to query for the name
 		query = `SELECT groname FROM pg_group WHERE grosysid = $1;`
-		if err := db.QueryRow(query, d.Id()).Scan(&groupName); err != nil {
-			return err
+		err := db.QueryRow(query, d.Id()).Scan(&groupName)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Printf("[WARN] Redshift group (%s) not found", d.Id())
+			d.SetId("")
+			return nil
+		case err != nil:
+			return fmt.Errorf("could not read group with id %q: %w", d.Id(), err)
 		}
 	}
 
@@ -103,7 +120,7 @@ func resourceRedshiftGroupReadImpl(db *DBConnection, d *schema.ResourceData) err
 }
 
 func resourceRedshiftGroupCreate(db *DBConnection, d *schema.ResourceData) error {
-	groupName := d.Get(groupNameAttr).(string)
+	groupName := getIdentifier(d, groupNameAttr)
 
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -111,28 +128,35 @@ func resourceRedshiftGroupCreate(db *DBConnection, d *schema.ResourceData) error
 	}
 	defer deferredRollback(tx)
 
-	query := fmt.Sprintf("CREATE GROUP %s", pq.QuoteIdentifier(groupName))
-	if v, ok := d.GetOk(groupUsersAttr); ok && len(v.(*schema.Set).List()) > 0 {
-		usernames := v.(*schema.Set).List()
-
-		var usernamesSafe []string
-		for _, name := range usernames {
-			usernamesSafe = append(usernamesSafe, pq.QuoteIdentifier(name.(string)))
+	if d.Get(groupAdoptExistingAttr).(bool) {
+		adopted, err := adoptExistingGroup(tx, d)
+		if err != nil {
+			return err
+		}
+		if adopted {
+			if err = tx.Commit(); err != nil {
+				return fmt.Errorf("could not commit transaction: %w", err)
+			}
+			return resourceRedshiftGroupReadImpl(db, d)
 		}
+	}
 
-		query = fmt.Sprintf("%s WITH USER %s", query, strings.Join(usernamesSafe, ", "))
+	query := fmt.Sprintf("CREATE GROUP %s", pq.QuoteIdentifier(groupName))
+	if v, ok := d.GetOk(groupUsersAttr); ok && len(v.(*schema.Set).List()) > 0 {
+		userNames := setToStringList(v.(*schema.Set))
+		query = fmt.Sprintf("%s WITH USER %s", query, buildUserStringArray(userNames, false, d.Get(preserveCaseAttr).(bool)))
 	}
 
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("could not create redshift group: %w", err)
 	}
 
-	var groSysID string
-	if err := tx.QueryRow("SELECT grosysid FROM pg_group WHERE groname = $1", strings.ToLower(groupName)).Scan(&groSysID); err != nil {
+	groSysID, err := getGroupIDFromName(tx, groupName)
+	if err != nil {
 		return fmt.Errorf("could not get redshift group id for %q: %w", groupName, err)
 	}
 
-	d.SetId(groSysID)
+	d.SetId(strconv.Itoa(groSysID))
 
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
@@ -141,43 +165,80 @@ func resourceRedshiftGroupCreate(db *DBConnection, d *schema.ResourceData) error
 	return resourceRedshiftGroupReadImpl(db, d)
 }
 
-func resourceRedshiftGroupDelete(db *DBConnection, d *schema.ResourceData) error {
-	groupName := d.Get(groupNameAttr).(string)
+// adoptExistingGroup takes over the group named like the resource if it exists and sets its members
+// to the configured users. It returns false if there is no such group.
+func adoptExistingGroup(tx *transaction, d *schema.ResourceData) (bool, error) {
+	groupName := getIdentifier(d, groupNameAttr)
+	preserveCase := d.Get(preserveCaseAttr).(bool)
 
-	tx, err := startTransaction(db.client)
-	if err != nil {
-		return err
+	groSysID, err := getGroupIDFromName(tx, groupName)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if group %q exists: %w", groupName, err)
 	}
-	defer deferredRollback(tx)
+	log.Printf("[INFO] adopting existing group %q with id %d\n", groupName, groSysID)
 
-	rows, err := tx.Query("SELECT nspname FROM pg_namespace WHERE nspowner != 1 OR nspname = 'public'")
+	rows, err := tx.Query("SELECT u.usename FROM pg_user_info u, pg_group g WHERE g.grosysid = $1 AND u.usesysid = ANY(g.grolist)", groSysID)
 	if err != nil {
-		return err
+		return false, fmt.Errorf("could not read members of group %q: %w", groupName, err)
 	}
+	defer rows.Close()
 
-	var schemaNames []string
-
+	var currentUserNames []string
 	for rows.Next() {
-		var schemaName string
-		if err = rows.Scan(&schemaName); err != nil {
-			_ = rows.Close()
-			return err
+		var userName string
+		if err := rows.Scan(&userName); err != nil {
+			return false, fmt.Errorf("could not read members of group %q: %w", groupName, err)
 		}
-		schemaNames = append(schemaNames, schemaName)
+		currentUserNames = append(currentUserNames, userName)
 	}
-	if err = rows.Err(); err != nil {
-		_ = rows.Close()
-		return err
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("could not read members of group %q: %w", groupName, err)
 	}
-	if err = rows.Close(); err != nil {
+
+	removedUserNames, addedUserNames := calculateUserNamesDiff(currentUserNames, setToStringList(d.Get(groupUsersAttr).(*schema.Set)))
+	if err := dropUsersFromGroup(tx, groupName, removedUserNames, preserveCase); err != nil {
+		return false, err
+	}
+	if err := addUsersToGroup(tx, groupName, addedUserNames, preserveCase); err != nil {
+		return false, err
+	}
+
+	d.SetId(strconv.Itoa(groSysID))
+
+	return true, nil
+}
+
+func resourceRedshiftGroupDelete(db *DBConnection, d *schema.ResourceData) error {
+	groupName := getIdentifier(d, groupNameAttr)
+
+	schemaNamesQuery := db.catalogQuery(listSchemasQuery)
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
 		return err
 	}
+	defer deferredRollback(tx)
 
-	for _, schemaName := range schemaNames {
-		if _, err := tx.Exec(fmt.Sprintf("REVOKE ALL ON ALL TABLES IN SCHEMA %s FROM GROUP %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName))); err != nil {
-			return err
+	// Redshift doesn't support DROP GROUP IF EXISTS and the revokes below fail for a missing group as well.
+	var exists int
+	if err := tx.QueryRow("SELECT 1 FROM pg_group WHERE grosysid = $1", d.Id()).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] group %q with id %s does not exist, already dropped\n", groupName, d.Id())
+			return nil
 		}
-		if _, err := tx.Exec(fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s REVOKE ALL ON TABLES FROM GROUP %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName))); err != nil {
+		return fmt.Errorf("could not check if group %q exists: %w", groupName, err)
+	}
+
+	schemaNames, err := listSchemas(tx, schemaNamesQuery)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range createGroupRevokeQueries(groupName, schemaNames) {
+		if _, err := tx.Exec(query); err != nil {
 			return err
 		}
 	}
@@ -185,10 +246,30 @@ func resourceRedshiftGroupDelete(db *DBConnection, d *schema.ResourceData) error
 	if _, err := tx.Exec(fmt.Sprintf("DROP GROUP %s", pq.QuoteIdentifier(groupName))); err != nil {
 		return err
 	}
+	tx.invalidateID(idCacheKindGroup, groupName)
 
 	return tx.Commit()
 }
 
+// createGroupRevokeQueries returns the statements revoking the privileges of the group in the given schemas,
+// which would otherwise make dropping the group fail.
+func createGroupRevokeQueries(groupName string, schemaNames []string) []string {
+	var queries []string
+	for _, schemaName := range schemaNames {
+		quotedSchemaName, quotedGroupName := pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName)
+		queries = append(queries,
+			fmt.Sprintf("REVOKE ALL ON SCHEMA %s FROM GROUP %s", quotedSchemaName, quotedGroupName),
+			fmt.Sprintf("REVOKE ALL ON ALL TABLES IN SCHEMA %s FROM GROUP %s", quotedSchemaName, quotedGroupName),
+			fmt.Sprintf("REVOKE ALL ON ALL FUNCTIONS IN SCHEMA %s FROM GROUP %s", quotedSchemaName, quotedGroupName),
+			fmt.Sprintf("REVOKE ALL ON ALL PROCEDURES IN SCHEMA %s FROM GROUP %s", quotedSchemaName, quotedGroupName),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s REVOKE ALL ON TABLES FROM GROUP %s", quotedSchemaName, quotedGroupName),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s REVOKE ALL ON FUNCTIONS FROM GROUP %s", quotedSchemaName, quotedGroupName),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s REVOKE ALL ON PROCEDURES FROM GROUP %s", quotedSchemaName, quotedGroupName),
+		)
+	}
+	return queries
+}
+
 func resourceRedshiftGroupUpdate(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -211,7 +292,7 @@ func resourceRedshiftGroupUpdate(db *DBConnection, d *schema.ResourceData) error
 	return resourceRedshiftGroupReadImpl(db, d)
 }
 
-func setGroupName(tx *sql.Tx, d *schema.ResourceData) error {
+func setGroupName(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(groupNameAttr) {
 		return nil
 	}
@@ -228,11 +309,12 @@ func setGroupName(tx *sql.Tx, d *schema.ResourceData) error {
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating Group NAME: %w", err)
 	}
+	tx.invalidateID(idCacheKindGroup, oldValue)
 
 	return nil
 }
 
-func checkIfUserExists(tx *sql.Tx, name string) (bool, error) {
+func checkIfUserExists(tx *transaction, name string) (bool, error) {
 
 	var result int
 	err := tx.QueryRow("SELECT 1 FROM pg_user_info WHERE usename=$1", name).Scan(&result)
@@ -247,50 +329,31 @@ func checkIfUserExists(tx *sql.Tx, name string) (bool, error) {
 	return true, nil
 }
 
-func setUsersNames(tx *sql.Tx, _ *DBConnection, d *schema.ResourceData) error {
+func setUsersNames(tx *transaction, _ *DBConnection, d *schema.ResourceData) error {
 	if !d.HasChange(groupUsersAttr) {
 		return nil
 	}
 
-	groupName := d.Get(groupNameAttr).(string)
+	groupName := getIdentifier(d, groupNameAttr)
+	preserveCase := d.Get(preserveCaseAttr).(bool)
 	oldUsersSet, newUsersSet := d.GetChange(groupUsersAttr)
 	removedUsers := oldUsersSet.(*schema.Set).Difference(newUsersSet.(*schema.Set))
 	addedUsers := newUsersSet.(*schema.Set).Difference(oldUsersSet.(*schema.Set))
 
-	if removedUsers.Len() > 0 {
-		var removedUsersNamesSafe []string
-		for _, name := range removedUsers.List() {
-			userExists, err := checkIfUserExists(tx, name.(string))
-			if err != nil {
-				return err
-			}
-
-			if userExists {
-				removedUsersNamesSafe = append(removedUsersNamesSafe, pq.QuoteIdentifier(name.(string)))
-			}
+	var removedUserNames []string
+	for _, name := range setToStringList(removedUsers) {
+		userExists, err := checkIfUserExists(tx, identifierCase(name, preserveCase))
+		if err != nil {
+			return err
 		}
 
-		if len(removedUsersNamesSafe) > 0 {
-			query := fmt.Sprintf("ALTER GROUP %s DROP USER %s", pq.QuoteIdentifier(groupName), strings.Join(removedUsersNamesSafe, ", "))
-
-			if _, err := tx.Exec(query); err != nil {
-				return err
-			}
+		if userExists {
+			removedUserNames = append(removedUserNames, name)
 		}
 	}
 
-	if addedUsers.Len() > 0 {
-		var addedUsersNamesSafe []string
-		for _, name := range addedUsers.List() {
-			addedUsersNamesSafe = append(addedUsersNamesSafe, pq.QuoteIdentifier(name.(string)))
-		}
-
-		query := fmt.Sprintf("ALTER GROUP %s ADD USER %s", pq.QuoteIdentifier(groupName), strings.Join(addedUsersNamesSafe, ", "))
-
-		if _, err := tx.Exec(query); err != nil {
-			return err
-		}
+	if err := dropUsersFromGroup(tx, groupName, removedUserNames, preserveCase); err != nil {
+		return err
 	}
-
-	return nil
+	return addUsersToGroup(tx, groupName, setToStringList(addedUsers), preserveCase)
 }
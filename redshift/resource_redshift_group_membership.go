@@ -1,6 +1,7 @@
 package redshift
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -9,17 +10,19 @@ import (
 	"github.com/lib/pq"
 )
 
+const groupMembershipExclusiveAttr = "exclusive"
+
 func redshiftGroupMembership() *schema.Resource {
 	return &schema.Resource{
 		Description: fmt.Sprintf(`
 Manages Redshift group memberships. Allows either to exclusively manage group memberships or to add members to an existing group. Note: this resource conflicts with the %s attribute of the %s resource
 `, "`users`", "`redshift_group`"),
 		CreateContext: ResourceFunc(resourceRedshiftGroupMembershipCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftGroupMembershipRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftGroupMembershipRead),
 		UpdateContext: ResourceFunc(resourceRedshiftGroupMembershipUpdate),
 		DeleteContext: ResourceFunc(resourceRedshiftGroupMembershipDelete),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceRedshiftGroupMembershipImport,
 		},
 		Schema: map[string]*schema.Schema{
 			groupNameAttr: {
@@ -32,35 +35,61 @@ Manages Redshift group memberships. Allows either to exclusively manage group me
 				Type:        schema.TypeSet,
 				Required:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
-				Description: "List of the user names to add to the group. Note: this resource does not check whether the specified users exist.",
+				Description: "List of the user names to add to the group. Users removed from the group outside of Terraform are added again. Note: this resource does not check whether the specified users exist.",
+			},
+			groupMembershipExclusiveAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the users are the only members of the group. If set, members of the group which are not in `users` are removed, otherwise they are left untouched.",
 			},
+			preserveCaseAttr: preserveCaseSchema(),
 		},
 	}
 }
 
 func resourceRedshiftGroupMembershipCreate(db *DBConnection, d *schema.ResourceData) error {
-	groupName := d.Get(groupNameAttr).(string)
+	groupName := getIdentifier(d, groupNameAttr)
 	userNames := parseUserNames(d.Get(groupUsersAttr))
 
 	if len(userNames) == 0 {
 		return fmt.Errorf("at least one user must be specified in %q", groupUsersAttr)
 	}
 
-	if err := addUsersToGroup(db, groupName, userNames); err != nil {
+	extraUserNames, err := readExtraGroupMembers(db, d, groupName, userNames)
+	if err != nil {
 		return err
 	}
 
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	preserveCase := d.Get(preserveCaseAttr).(bool)
+	if err := dropUsersFromGroup(tx, groupName, extraUserNames, preserveCase); err != nil {
+		return err
+	}
+	if err := addUsersToGroup(tx, groupName, userNames, preserveCase); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
 	return resourceRedshiftGroupMembershipRead(db, d)
 }
 
-func addUsersToGroup(db *DBConnection, group string, userNames []string) error {
+func addUsersToGroup(tx *transaction, group string, userNames []string, preserveCase bool) error {
 	if len(userNames) == 0 {
 		return nil
 	}
-	userNamesParam := buildUserStringArray(userNames, false)
+	userNamesParam := buildUserStringArray(userNames, false, preserveCase)
 	query := fmt.Sprintf("ALTER GROUP %s ADD USER %s;", pq.QuoteIdentifier(group), userNamesParam)
 
-	if _, err := db.Exec(query); err != nil {
+	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("could not add users %s to group %q: %w", userNamesParam, group, err)
 	}
 	return nil
@@ -68,30 +97,96 @@ func addUsersToGroup(db *DBConnection, group string, userNames []string) error {
 }
 
 func resourceRedshiftGroupMembershipRead(db *DBConnection, d *schema.ResourceData) error {
-	groupName := d.Get(groupNameAttr).(string)
+	setDefaultIfUnset(d, groupMembershipExclusiveAttr, false)
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+
+	groupName := getIdentifier(d, groupNameAttr)
 	userNames := parseUserNames(d.Get(groupUsersAttr))
 
-	userNamesParam := buildUserStringArray(userNames, true)
+	members, err := readGroupMembers(db, groupName)
+	if err != nil {
+		return err
+	}
+
+	memberUserNames := groupMembershipUsers(userNames, members, d.Get(groupMembershipExclusiveAttr).(bool), d.Get(preserveCaseAttr).(bool))
+	if len(memberUserNames) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(groupUsersAttr, memberUserNames)
+	d.SetId(generateGroupMembershipId(groupName, userNames))
+	return nil
+}
 
-	query := fmt.Sprintf(
-		`SELECT 1 FROM pg_group pgg JOIN pg_user pgu ON pgu.usesysid = ANY(pgg.grolist) WHERE pgg.groname = %s AND pgu.usename IN (%s);`,
-		pq.QuoteLiteral(groupName), userNamesParam,
-	)
+// readExtraGroupMembers returns the members of the group which are not in userNames when the
+// membership is exclusive. They have to be read before the transaction which removes them is
+// started, as it may hold the only connection.
+func readExtraGroupMembers(db *DBConnection, d *schema.ResourceData, groupName string, userNames []string) ([]string, error) {
+	if !d.Get(groupMembershipExclusiveAttr).(bool) {
+		return nil, nil
+	}
+	members, err := readGroupMembers(db, groupName)
+	if err != nil {
+		return nil, err
+	}
+	storedUserNames := make([]string, len(userNames))
+	for i, userName := range userNames {
+		storedUserNames[i] = identifierCase(userName, d.Get(preserveCaseAttr).(bool))
+	}
+	extraUserNames, _ := calculateUserNamesDiff(members, storedUserNames)
+	return extraUserNames, nil
+}
 
-	rows, err := db.Query(query)
+// readGroupMembers returns the names of all users in the group.
+func readGroupMembers(db *DBConnection, groupName string) ([]string, error) {
+	query := `SELECT pgu.usename FROM pg_group pgg JOIN pg_user pgu ON pgu.usesysid = ANY(pgg.grolist) WHERE pgg.groname = $1`
+
+	rows, err := db.Query(query, groupName)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("could not read group membership for group %q: %w", groupName, err)
 	}
 	defer rows.Close()
-	if rows.Next() {
-		if err = rows.Err(); err != nil {
-			return fmt.Errorf("could not read group membership for group %q: %w", groupName, err)
+
+	var members []string
+	for rows.Next() {
+		var member string
+		if err := rows.Scan(&member); err != nil {
+			return nil, fmt.Errorf("could not read group membership for group %q: %w", groupName, err)
 		}
-		d.SetId(generateGroupMembershipId(groupName, userNames))
-	} else {
-		d.SetId("")
+		members = append(members, member)
 	}
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not read group membership for group %q: %w", groupName, err)
+	}
+	return members, nil
+}
+
+// groupMembershipUsers returns the configured users which are members of the group, so users removed
+// from the group outside of Terraform show up as drift and are added again. In exclusive mode the
+// other members are returned as well, so they are removed from the group on the next apply.
+// User names are compared as folded to lower case unless preserveCase is set.
+func groupMembershipUsers(userNames, members []string, exclusive, preserveCase bool) []string {
+	isMember := map[string]bool{}
+	for _, member := range members {
+		isMember[member] = true
+	}
+
+	var memberUserNames []string
+	for _, userName := range userNames {
+		if storedUserName := identifierCase(userName, preserveCase); isMember[storedUserName] {
+			memberUserNames = append(memberUserNames, userName)
+			delete(isMember, storedUserName)
+		}
+	}
+	if exclusive {
+		for _, member := range members {
+			if isMember[member] {
+				memberUserNames = append(memberUserNames, member)
+			}
+		}
+	}
+	return memberUserNames
 }
 
 func resourceRedshiftGroupMembershipUpdate(db *DBConnection, d *schema.ResourceData) error {
@@ -101,25 +196,80 @@ func resourceRedshiftGroupMembershipUpdate(db *DBConnection, d *schema.ResourceD
 	if len(newUserNames) == 0 {
 		return fmt.Errorf("at least one user must be specified in %q", groupUsersAttr)
 	}
+
+	groupName := getIdentifier(d, groupNameAttr)
+	extraUserNames, err := readExtraGroupMembers(db, d, groupName, newUserNames)
+	if err != nil {
+		return err
+	}
+
+	// All membership changes are applied in one transaction, so a failure
+	// half-way does not leave the group with a partial membership. Partial mode
+	// keeps the prior state in that case, as nothing has been changed.
+	d.Partial(true)
+	preserveCase := d.Get(preserveCaseAttr).(bool)
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
 	if d.HasChange(groupNameAttr) {
-		if err := resourceRedshiftGroupMembershipDelete(db, d); err != nil {
+		oldGroupName, newGroupName := d.GetChange(groupNameAttr)
+		if err := dropUsersFromGroup(tx, identifierOf(d, oldGroupName.(string)), oldUserNames, preserveCase); err != nil {
 			return fmt.Errorf("error deleting group membership while updating the resource: %w", err)
 		}
-		if err := resourceRedshiftGroupMembershipCreate(db, d); err != nil {
+		if err := addUsersToGroup(tx, identifierOf(d, newGroupName.(string)), newUserNames, preserveCase); err != nil {
 			return fmt.Errorf("error creating group membership while updating the resource: %w", err)
 		}
-		return nil
-	}
-	deletedUserNames, addedUserNames := calculateUserNamesDiff(oldUserNames, newUserNames)
-	if err := dropUsersFromGroup(db, d.Get(groupNameAttr).(string), deletedUserNames); err != nil {
-		return fmt.Errorf("error removing users from group while updating the resource: %w", err)
+		if err := dropUsersFromGroup(tx, identifierOf(d, newGroupName.(string)), extraUserNames, preserveCase); err != nil {
+			return fmt.Errorf("error removing users from group while updating the resource: %w", err)
+		}
+	} else {
+		deletedUserNames, addedUserNames := calculateUserNamesDiff(oldUserNames, newUserNames)
+		if d.Get(groupMembershipExclusiveAttr).(bool) {
+			// The extra members include the users which are no longer configured.
+			deletedUserNames = extraUserNames
+		}
+		if err := dropUsersFromGroup(tx, getIdentifier(d, groupNameAttr), deletedUserNames, preserveCase); err != nil {
+			return fmt.Errorf("error removing users from group while updating the resource: %w", err)
+		}
+		if err := addUsersToGroup(tx, getIdentifier(d, groupNameAttr), addedUserNames, preserveCase); err != nil {
+			return fmt.Errorf("error adding users to group while updating the resource: %w", err)
+		}
 	}
-	if err := addUsersToGroup(db, d.Get(groupNameAttr).(string), addedUserNames); err != nil {
-		return fmt.Errorf("error adding users to group while updating the resource: %w", err)
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
 	}
+	d.Partial(false)
+
 	return resourceRedshiftGroupMembershipRead(db, d)
 }
 
+// resourceRedshiftGroupMembershipImport imports the membership of users in a group given as <group>/<user1>,<user2>.
+// The generated ID can't be used, since group and user names may contain underscores.
+func resourceRedshiftGroupMembershipImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	groupName, rawUserNames, found := strings.Cut(d.Id(), "/")
+	if !found || groupName == "" || rawUserNames == "" {
+		return nil, fmt.Errorf("invalid group membership ID %q, expected <group>/<user1>,<user2>", d.Id())
+	}
+
+	var userNames []interface{}
+	for _, userName := range strings.Split(rawUserNames, ",") {
+		if userName = strings.TrimSpace(userName); userName == "" {
+			return nil, fmt.Errorf("invalid group membership ID %q, user names must not be empty", d.Id())
+		}
+		userNames = append(userNames, userName)
+	}
+
+	d.Set(groupNameAttr, groupName)
+	d.Set(groupUsersAttr, schema.NewSet(schema.HashString, userNames))
+	d.Set(preserveCaseAttr, false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func calculateUserNamesDiff(oldUserNames, newUserNames []string) (deletedUserNames, addedUserNames []string) {
 	deletedUserNames = make([]string, 0)
 	addedUserNames = make([]string, 0)
@@ -151,20 +301,34 @@ func calculateUserNamesDiff(oldUserNames, newUserNames []string) (deletedUserNam
 }
 
 func resourceRedshiftGroupMembershipDelete(db *DBConnection, d *schema.ResourceData) error {
-	groupName := d.Get(groupNameAttr).(string)
+	groupName := getIdentifier(d, groupNameAttr)
 	userNames := parseUserNames(d.Get(groupUsersAttr))
 
-	return dropUsersFromGroup(db, groupName, userNames)
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := dropUsersFromGroup(tx, groupName, userNames, d.Get(preserveCaseAttr).(bool)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
 }
 
-func dropUsersFromGroup(db *DBConnection, groupName string, userNames []string) error {
+func dropUsersFromGroup(tx *transaction, groupName string, userNames []string, preserveCase bool) error {
 	if len(userNames) == 0 {
 		return nil
 	}
-	userNamesParam := buildUserStringArray(userNames, false)
+	userNamesParam := buildUserStringArray(userNames, false, preserveCase)
 	query := fmt.Sprintf("ALTER GROUP %s DROP USER %s;", pq.QuoteIdentifier(groupName), userNamesParam)
 
-	if _, err := db.Exec(query); err != nil {
+	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("could not remove users %s from group %q: %w", userNamesParam, groupName, err)
 	}
 	return nil
@@ -179,10 +343,12 @@ func parseUserNames(rawUserNames interface{}) []string {
 	return userNames
 }
 
-func buildUserStringArray(userNames []string, encodeAsLiteral bool) string {
+// buildUserStringArray returns the quoted user names separated by commas, folded to lower case
+// unless preserveCase is set.
+func buildUserStringArray(userNames []string, encodeAsLiteral, preserveCase bool) string {
 	var userNamesSafe []string
 	for _, userName := range userNames {
-		encodedUserName := strings.ToLower(userName)
+		encodedUserName := identifierCase(userName, preserveCase)
 		if encodeAsLiteral {
 			encodedUserName = pq.QuoteLiteral(encodedUserName)
 		} else {
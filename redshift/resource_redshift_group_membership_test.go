@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccRedshiftGroupMembership_Basic(t *testing.T) {
@@ -63,6 +66,12 @@ resource "redshift_user" "simple" {
 					testAccCheckRedshiftGroupMembershipPresence(groupName, userName, true),
 				),
 			},
+			{
+				ResourceName:      "redshift_group_membership.simple",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s", groupName, userName),
+				ImportStateVerify: true,
+			},
 			{
 				Config: updateConfig,
 				Check: resource.ComposeTestCheckFunc(
@@ -151,6 +160,213 @@ resource "redshift_group_membership" "simple" {
 	})
 }
 
+func TestAccRedshiftGroupMembership_UserRemovedOutOfBand(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_membership")
+	userNames := []string{
+		generateRandomObjectName("tf_acc_group_membership_user"),
+		generateRandomObjectName("tf_acc_group_membership_user"),
+	}
+	config := fmt.Sprintf(`
+resource "redshift_group" "simple" {
+  name = %[1]q
+
+  lifecycle {
+    ignore_changes = [
+      users
+    ]
+  }
+}
+
+resource "redshift_user" "first" {
+  name = %[2]q
+}
+
+resource "redshift_user" "second" {
+  name = %[3]q
+}
+
+resource "redshift_group_membership" "simple" {
+  name  = redshift_group.simple.name
+  users = [redshift_user.first.name, redshift_user.second.name]
+}
+`, groupName, userNames[0], userNames[1])
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftGroupMembershipDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.simple", "users.#", "2"),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[0], true),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[1], true),
+				),
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("ALTER GROUP %s DROP USER %s", pq.QuoteIdentifier(groupName), pq.QuoteIdentifier(userNames[1])))
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.simple", "users.#", "2"),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[1], true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGroupMembership_Exclusive(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_membership")
+	userNames := []string{
+		generateRandomObjectName("tf_acc_group_membership_user"),
+		generateRandomObjectName("tf_acc_group_membership_user"),
+	}
+	baseConfig := fmt.Sprintf(`
+resource "redshift_group" "simple" {
+  name = %[1]q
+
+  lifecycle {
+    ignore_changes = [
+      users
+    ]
+  }
+}
+
+resource "redshift_user" "member" {
+  name = %[2]q
+}
+
+resource "redshift_user" "extra" {
+  name = %[3]q
+}
+`, groupName, userNames[0], userNames[1])
+	config := func(exclusive bool) string {
+		return baseConfig + fmt.Sprintf(`
+resource "redshift_group_membership" "simple" {
+  name      = redshift_group.simple.name
+  users     = [redshift_user.member.name]
+  exclusive = %t
+}
+`, exclusive)
+	}
+	addExtraMember := func() {
+		withAccGrantConn(t, func(db *DBConnection) error {
+			_, err := db.Exec(fmt.Sprintf("ALTER GROUP %s ADD USER %s", pq.QuoteIdentifier(groupName), pq.QuoteIdentifier(userNames[1])))
+			return err
+		})
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftGroupMembershipDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+			},
+			{
+				// Members which are not configured are left untouched by default.
+				PreConfig: addExtraMember,
+				Config:    config(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.simple", "users.#", "1"),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[0], true),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[1], true),
+				),
+			},
+			{
+				// Making the membership exclusive removes the extra member.
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.simple", "exclusive", "true"),
+					resource.TestCheckResourceAttr("redshift_group_membership.simple", "users.#", "1"),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[0], true),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[1], false),
+				),
+			},
+			{
+				PreConfig:          addExtraMember,
+				Config:             config(true),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.simple", "users.#", "1"),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[1], false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGroupMembership_ExclusiveCreate(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_membership")
+	userNames := []string{
+		generateRandomObjectName("tf_acc_group_membership_user"),
+		generateRandomObjectName("tf_acc_group_membership_user"),
+	}
+	baseConfig := fmt.Sprintf(`
+resource "redshift_group" "simple" {
+  name = %[1]q
+
+  lifecycle {
+    ignore_changes = [
+      users
+    ]
+  }
+}
+
+resource "redshift_user" "member" {
+  name = %[2]q
+}
+
+resource "redshift_user" "extra" {
+  name = %[3]q
+}
+`, groupName, userNames[0], userNames[1])
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftGroupMembershipDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+			},
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("ALTER GROUP %s ADD USER %s", pq.QuoteIdentifier(groupName), pq.QuoteIdentifier(userNames[1])))
+						return err
+					})
+				},
+				Config: baseConfig + `
+resource "redshift_group_membership" "simple" {
+  name      = redshift_group.simple.name
+  users     = [redshift_user.member.name]
+  exclusive = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.simple", "users.#", "1"),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[0], true),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userNames[1], false),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGroupMembership_Update(t *testing.T) {
 	groupName := generateRandomObjectName("tf_acc_group_membership")
 	newGroupName := generateRandomObjectName("tf_acc_group_membership")
@@ -268,6 +484,75 @@ resource "redshift_group_membership" "simple" {
 	})
 }
 
+func TestAccRedshiftGroupMembership_UpdateRollback(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_membership")
+	userName1 := generateRandomObjectName("tf_acc_group_membership_user")
+	userName2 := generateRandomObjectName("tf_acc_group_membership_user")
+	missingUserName := generateRandomObjectName("tf_acc_group_membership_missing")
+	config := func(users string) string {
+		return fmt.Sprintf(`
+resource "redshift_group" "simple" {
+  name = %[1]q
+
+  lifecycle {
+    ignore_changes = [
+      users
+    ]
+  }
+}
+
+resource "redshift_user" "simple" {
+  name = %[2]q
+}
+
+resource "redshift_user" "also_simple" {
+  name = %[3]q
+}
+
+resource "redshift_group_membership" "simple" {
+  name = redshift_group.simple.name
+  users = %[4]s
+}
+`, groupName, userName1, userName2, users)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftGroupMembershipDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("[redshift_user.simple.name, redshift_user.also_simple.name]"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userName1, true),
+					testAccCheckRedshiftGroupMembershipPresence(groupName, userName2, true),
+				),
+			},
+			{
+				// Dropping userName1 succeeds, adding the missing user fails afterwards.
+				Config:      config(fmt.Sprintf("[redshift_user.also_simple.name, %q]", missingUserName)),
+				ExpectError: regexp.MustCompile("error adding users to group"),
+			},
+			{
+				// The failed update must have been rolled back completely: userName1 is still a member
+				// and refreshing the membership doesn't show any change to the original configuration.
+				PreConfig: func() {
+					for _, check := range []resource.TestCheckFunc{
+						testAccCheckRedshiftGroupMembershipPresence(groupName, userName1, true),
+						testAccCheckRedshiftGroupMembershipPresence(groupName, userName2, true),
+						testAccCheckRedshiftGroupMembershipPresence(groupName, missingUserName, false),
+					} {
+						if err := check(nil); err != nil {
+							t.Fatal(err)
+						}
+					}
+				},
+				Config:   config("[redshift_user.simple.name, redshift_user.also_simple.name]"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGroupMembership_Invalid_EmptyGroupName(t *testing.T) {
 	groupName := generateRandomObjectName("tf_acc_group_membership")
 	userName := generateRandomObjectName("tf_acc_group_membership_user")
@@ -368,7 +653,7 @@ func checkGroupMembershipExists(client *Client, groupName string, userNames ...s
 	if len(userNames) == 0 {
 		return false, nil
 	}
-	userNamesParam := buildUserStringArray(userNames, true)
+	userNamesParam := buildUserStringArray(userNames, true, false)
 	query := fmt.Sprintf(`SELECT 1 FROM pg_group pgg JOIN pg_user pgu ON pgu.usesysid = ANY(pgg.grolist) WHERE pgu.usename IN (%s)  AND pgg.groname = $1`, userNamesParam)
 	err = db.QueryRow(query, groupName).Scan(&_rez)
 
@@ -490,3 +775,114 @@ func Test_calculateUserNamesDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceRedshiftGroupMembershipImport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftGroupMembership().Schema, map[string]interface{}{})
+	d.SetId("my_group/user_1, user_2")
+
+	if _, err := resourceRedshiftGroupMembershipImport(t.Context(), d, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := d.Get(groupNameAttr).(string); got != "my_group" {
+		t.Errorf("expected group name %q, got %q", "my_group", got)
+	}
+	got := parseUserNames(d.Get(groupUsersAttr))
+	sort.Strings(got)
+	if expected := []string{"user_1", "user_2"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected users %v, got %v", expected, got)
+	}
+
+	for _, id := range []string{"my_group_user_1_user_2", "my_group/", "/user_1", "my_group/user_1,,user_2"} {
+		d.SetId(id)
+		if _, err := resourceRedshiftGroupMembershipImport(t.Context(), d, nil); err == nil {
+			t.Errorf("expected an error importing %q", id)
+		}
+	}
+}
+
+func TestGroupMembershipUsers(t *testing.T) {
+	tests := map[string]struct {
+		userNames    []string
+		members      []string
+		exclusive    bool
+		preserveCase bool
+		expected     []string
+	}{
+		"all members": {
+			userNames: []string{"alice", "Bob"},
+			members:   []string{"alice", "bob"},
+			expected:  []string{"Bob", "alice"},
+		},
+		"removed member": {
+			userNames: []string{"alice", "bob"},
+			members:   []string{"alice"},
+			expected:  []string{"alice"},
+		},
+		"extra member": {
+			userNames: []string{"alice"},
+			members:   []string{"alice", "carol"},
+			expected:  []string{"alice"},
+		},
+		"extra member when exclusive": {
+			userNames: []string{"alice"},
+			members:   []string{"alice", "carol"},
+			exclusive: true,
+			expected:  []string{"alice", "carol"},
+		},
+		"no members": {
+			userNames: []string{"alice"},
+			members:   nil,
+			exclusive: true,
+			expected:  nil,
+		},
+		"mixed case member": {
+			userNames: []string{"Alice"},
+			members:   []string{"alice"},
+			expected:  []string{"Alice"},
+		},
+		"mixed case member, preserve case": {
+			userNames:    []string{"Alice"},
+			members:      []string{"Alice"},
+			preserveCase: true,
+			expected:     []string{"Alice"},
+		},
+		"lower case member, preserve case": {
+			userNames:    []string{"Alice"},
+			members:      []string{"alice"},
+			preserveCase: true,
+			expected:     nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := groupMembershipUsers(tt.userNames, tt.members, tt.exclusive, tt.preserveCase)
+			sort.Strings(result)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildUserStringArray(t *testing.T) {
+	tests := map[string]struct {
+		encodeAsLiteral bool
+		preserveCase    bool
+		expected        string
+	}{
+		"identifiers":                {expected: `"alice", "mixed_case"`},
+		"identifiers, preserve case": {preserveCase: true, expected: `"alice", "Mixed_Case"`},
+		"literals":                   {encodeAsLiteral: true, expected: `'alice', 'mixed_case'`},
+		"literals, preserve case":    {encodeAsLiteral: true, preserveCase: true, expected: `'alice', 'Mixed_Case'`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := buildUserStringArray([]string{"alice", "Mixed_Case"}, tt.encodeAsLiteral, tt.preserveCase)
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
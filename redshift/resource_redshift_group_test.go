@@ -4,12 +4,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccRedshiftGroup_Basic(t *testing.T) {
@@ -41,6 +44,113 @@ func TestAccRedshiftGroup_Basic(t *testing.T) {
 	})
 }
 
+func TestAccRedshiftGroup_DeleteDroppedOutOfBand(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_dropped")
+	config := fmt.Sprintf(`
+resource "redshift_group" "dropped" {
+  name = %[1]q
+}
+`, groupName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeleteAfterDroppedOutOfBand("redshift_group.dropped", "redshift_group", fmt.Sprintf("DROP GROUP %s", pq.QuoteIdentifier(groupName))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGroup_DroppedOutOfBand(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_recreated")
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+`, groupName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckRedshiftGroupExists(groupName),
+			},
+			{
+				// Drop the group outside of Terraform: the plan recreates it instead of failing to read it.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("DROP GROUP %s", pq.QuoteIdentifier(groupName)))
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftGroupExists(groupName),
+					resource.TestCheckResourceAttr("redshift_group.group", "name", groupName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGroup_AdoptExisting(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_adopted")
+	userName := generateRandomObjectName("tf_acc_user_adopted")
+	config := func(adoptExisting bool) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %[2]q
+}
+
+resource "redshift_group" "group" {
+  name           = %[1]q
+  users          = [redshift_user.user.name]
+  adopt_existing = %[3]t
+}
+`, groupName, userName, adoptExisting)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("CREATE GROUP %s", pq.QuoteIdentifier(groupName)))
+						return err
+					})
+				},
+				Config:      config(false),
+				ExpectError: regexp.MustCompile("already exists"),
+			},
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftGroupExists(groupName),
+					resource.TestCheckResourceAttr("redshift_group.group", "name", groupName),
+					resource.TestCheckResourceAttr("redshift_group.group", "users.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_group.group", "users.*", userName),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGroup_Update(t *testing.T) {
 	groupNames := []string{
 		strings.ReplaceAll(acctest.RandomWithPrefix("TF_acc_group"), "-", "_"),
@@ -87,6 +197,9 @@ func TestAccRedshiftGroup_Update(t *testing.T) {
 		  ]
 		}
 		`, userName1, userName2, userName3, groupNameUpdated)
+
+		// Renames must update the group in place, the group is tracked by its grosysid.
+		var groupID string
 		resource.Test(t, resource.TestCase{
 			PreCheck:          func() { testAccPreCheck(t) },
 			ProviderFactories: testAccProviders,
@@ -98,6 +211,7 @@ func TestAccRedshiftGroup_Update(t *testing.T) {
 						testAccCheckRedshiftGroupExists(groupName),
 						resource.TestCheckResourceAttr("redshift_group.update_group", "name", strings.ToLower(groupName)),
 						resource.TestCheckResourceAttr("redshift_group.update_group", "users.#", "0"),
+						testAccCheckResourceIDUnchanged("redshift_group.update_group", &groupID),
 					),
 				},
 				{
@@ -109,8 +223,14 @@ func TestAccRedshiftGroup_Update(t *testing.T) {
 						resource.TestCheckTypeSetElemAttr("redshift_group.update_group", "users.*", userName1),
 						resource.TestCheckTypeSetElemAttr("redshift_group.update_group", "users.*", userName2),
 						resource.TestCheckTypeSetElemAttr("redshift_group.update_group", "users.*", userName3),
+						testAccCheckResourceIDUnchanged("redshift_group.update_group", &groupID),
 					),
 				},
+				{
+					ResourceName:      "redshift_group.update_group",
+					ImportState:       true,
+					ImportStateVerify: true,
+				},
 				// apply the first one again to check if all parameters roll back properly
 				{
 					Config: configCreate,
@@ -118,6 +238,7 @@ func TestAccRedshiftGroup_Update(t *testing.T) {
 						testAccCheckRedshiftGroupExists(groupName),
 						resource.TestCheckResourceAttr("redshift_group.update_group", "name", strings.ToLower(groupName)),
 						resource.TestCheckResourceAttr("redshift_group.update_group", "users.#", "0"),
+						testAccCheckResourceIDUnchanged("redshift_group.update_group", &groupID),
 					),
 				},
 			},
@@ -192,6 +313,73 @@ resource "redshift_user" "user2" {
 	})
 }
 
+func TestAccRedshiftGroup_DeleteWithSchemaPrivileges(t *testing.T) {
+	groupName := generateRandomObjectName("tf_acc_group_privileges")
+	schemaName := generateRandomObjectName("tf_acc_schema_group")
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %q
+}
+`, groupName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if err := testAccRedshiftGrantDropSchema(schemaName)(s); err != nil {
+				return err
+			}
+			return testAccCheckRedshiftGroupDestroy(s)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckRedshiftGroupExists(groupName),
+			},
+			{
+				// Privileges granted outside of Terraform must not prevent dropping the group.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						if err := testAccRedshiftGrantCreateSchemaTables(db, schemaName, "tbl"); err != nil {
+							return err
+						}
+						for _, statement := range []string{
+							"GRANT USAGE ON SCHEMA %[1]s TO GROUP %[2]s",
+							"GRANT SELECT ON ALL TABLES IN SCHEMA %[1]s TO GROUP %[2]s",
+							"ALTER DEFAULT PRIVILEGES IN SCHEMA %[1]s GRANT EXECUTE ON FUNCTIONS TO GROUP %[2]s",
+						} {
+							if _, err := db.Exec(fmt.Sprintf(statement, pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName))); err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+				},
+				Config: `data "redshift_namespace" "ns" {}`,
+			},
+		},
+	})
+}
+
+func TestCreateGroupRevokeQueries(t *testing.T) {
+	expected := []string{
+		`REVOKE ALL ON SCHEMA "my_schema" FROM GROUP "my_group"`,
+		`REVOKE ALL ON ALL TABLES IN SCHEMA "my_schema" FROM GROUP "my_group"`,
+		`REVOKE ALL ON ALL FUNCTIONS IN SCHEMA "my_schema" FROM GROUP "my_group"`,
+		`REVOKE ALL ON ALL PROCEDURES IN SCHEMA "my_schema" FROM GROUP "my_group"`,
+		`ALTER DEFAULT PRIVILEGES IN SCHEMA "my_schema" REVOKE ALL ON TABLES FROM GROUP "my_group"`,
+		`ALTER DEFAULT PRIVILEGES IN SCHEMA "my_schema" REVOKE ALL ON FUNCTIONS FROM GROUP "my_group"`,
+		`ALTER DEFAULT PRIVILEGES IN SCHEMA "my_schema" REVOKE ALL ON PROCEDURES FROM GROUP "my_group"`,
+	}
+
+	if queries := createGroupRevokeQueries("my_group", []string{"my_schema"}); !reflect.DeepEqual(queries, expected) {
+		t.Errorf("Expected queries %v but got %v", expected, queries)
+	}
+	if queries := createGroupRevokeQueries("my_group", nil); len(queries) != 0 {
+		t.Errorf("Expected no queries without schemas but got %v", queries)
+	}
+}
+
 func testAccCheckRedshiftGroupDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -0,0 +1,283 @@
+package redshift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	maskingAttachmentColumnsAttr      = "columns"
+	maskingAttachmentInputColumnsAttr = "input_columns"
+	maskingAttachmentPriorityAttr     = "priority"
+)
+
+func redshiftMaskingAttachment() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Attaches a dynamic data masking policy to columns of a table for a user, a role or PUBLIC.
+
+For more information, see [ATTACH MASKING POLICY documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_ATTACH_MASKING_POLICY.html).
+`,
+		CreateContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftMaskingAttachmentCreate)),
+		ReadContext:   IdempotentResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftMaskingAttachmentRead)),
+		UpdateContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftMaskingAttachmentUpdate)),
+		DeleteContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftMaskingAttachmentDelete)),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedshiftMaskingAttachmentImport,
+		},
+
+		Schema: policyAttachmentSchema("masking", map[string]*schema.Schema{
+			maskingAttachmentColumnsAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The columns of the table which are masked, one for each output expression of the policy.",
+			},
+			maskingAttachmentInputColumnsAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The columns of the table which are bound to the input columns of the policy. Defaults to `columns`.",
+			},
+			maskingAttachmentPriorityAttr: {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "The priority of the attachment. When several policies are attached to a column for a user, the one with the highest priority is applied.",
+			},
+		}),
+	}
+}
+
+// maskingAttachment identifies the attachment of a masking policy to columns of a table for a grantee.
+type maskingAttachment struct {
+	policyAttachment
+
+	columns []string
+}
+
+func getMaskingAttachment(d *schema.ResourceData) (maskingAttachment, error) {
+	attachment, err := getPolicyAttachment(d)
+	if err != nil {
+		return maskingAttachment{}, err
+	}
+	return maskingAttachment{
+		policyAttachment: attachment,
+		columns:          listToStringList(d.Get(maskingAttachmentColumnsAttr).([]interface{})),
+	}, nil
+}
+
+func resourceRedshiftMaskingAttachmentCreate(db *DBConnection, d *schema.ResourceData) error {
+	attachment, err := getMaskingAttachment(d)
+	if err != nil {
+		return err
+	}
+
+	query := createMaskingAttachQuery(attachment, listToStringList(d.Get(maskingAttachmentInputColumnsAttr).([]interface{})), d.Get(maskingAttachmentPriorityAttr).(int))
+
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("could not attach masking policy %q: %w", attachment.policyName, err)
+	}
+
+	d.SetId(attachment.id())
+
+	return resourceRedshiftMaskingAttachmentRead(db, d)
+}
+
+func resourceRedshiftMaskingAttachmentRead(db *DBConnection, d *schema.ResourceData) error {
+	attachment, err := getMaskingAttachment(d)
+	if err != nil {
+		return err
+	}
+
+	query := `
+SELECT output_columns, priority
+FROM svv_attached_masking_policy
+WHERE policy_name = $1
+	AND schema_name = $2
+	AND table_name = $3
+	AND LOWER(grantee_type) = LOWER($4)
+	AND ($4 = 'PUBLIC' OR grantee = $5)`
+
+	rows, err := db.Query(query, attachment.policyName, attachment.schemaName, attachment.tableName, attachment.granteeType, attachment.granteeName)
+	if err != nil {
+		return fmt.Errorf("error reading masking policy attachment %s: %w", d.Id(), err)
+	}
+	defer rows.Close()
+
+	found := false
+	var priority int
+	for rows.Next() {
+		var outputColumns string
+		var outputPriority int
+		if err := rows.Scan(&outputColumns, &outputPriority); err != nil {
+			return fmt.Errorf("error reading masking policy attachment %s: %w", d.Id(), err)
+		}
+		if matchesMaskedColumns(outputColumns, attachment.columns) {
+			found = true
+			priority = outputPriority
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading masking policy attachment %s: %w", d.Id(), err)
+	}
+
+	if !found {
+		log.Printf("[WARN] Masking policy attachment %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(maskingAttachmentPriorityAttr, priority)
+
+	return nil
+}
+
+func resourceRedshiftMaskingAttachmentUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChange(maskingAttachmentPriorityAttr) {
+		attachment, err := getMaskingAttachment(d)
+		if err != nil {
+			return err
+		}
+
+		tx, err := startTransaction(db.client)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(tx)
+
+		// The priority of an attachment can't be altered, so the policy is attached again.
+		for _, query := range []string{
+			createMaskingDetachQuery(attachment),
+			createMaskingAttachQuery(attachment, listToStringList(d.Get(maskingAttachmentInputColumnsAttr).([]interface{})), d.Get(maskingAttachmentPriorityAttr).(int)),
+		} {
+			if _, err := tx.Exec(query); err != nil {
+				return fmt.Errorf("could not update priority of masking policy %q: %w", attachment.policyName, err)
+			}
+		}
+
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("could not commit transaction: %w", err)
+		}
+	}
+
+	return resourceRedshiftMaskingAttachmentRead(db, d)
+}
+
+func resourceRedshiftMaskingAttachmentDelete(db *DBConnection, d *schema.ResourceData) error {
+	attachment, err := getMaskingAttachment(d)
+	if err != nil {
+		return err
+	}
+
+	query := createMaskingDetachQuery(attachment)
+
+	if _, err := db.Exec(query); err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			log.Printf("[WARN] Masking policy, table or grantee does not exist, policy already detached: %v", err)
+			return nil
+		}
+		return fmt.Errorf("could not detach masking policy %q: %w", attachment.policyName, err)
+	}
+
+	return nil
+}
+
+func resourceRedshiftMaskingAttachmentImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	attachment, err := parseMaskingAttachmentID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set(policyAttachmentPolicyNameAttr, attachment.policyName)
+	d.Set(policyAttachmentSchemaAttr, attachment.schemaName)
+	d.Set(policyAttachmentTableAttr, attachment.tableName)
+	d.Set(policyAttachmentGranteeTypeAttr, attachment.granteeType)
+	d.Set(policyAttachmentGranteeNameAttr, attachment.granteeName)
+	d.Set(maskingAttachmentColumnsAttr, attachment.columns)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func (a maskingAttachment) quotedColumns() string {
+	return quotedColumnList(a.columns)
+}
+
+func quotedColumnList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func createMaskingAttachQuery(attachment maskingAttachment, inputColumns []string, priority int) string {
+	query := fmt.Sprintf("ATTACH MASKING POLICY %s ON %s(%s)",
+		pq.QuoteIdentifier(attachment.policyName),
+		attachment.quotedTable(),
+		attachment.quotedColumns(),
+	)
+	if len(inputColumns) > 0 {
+		query = fmt.Sprintf("%s USING (%s)", query, quotedColumnList(inputColumns))
+	}
+	query = fmt.Sprintf("%s TO %s", query, attachment.quotedGrantee())
+	if priority != 0 {
+		query = fmt.Sprintf("%s PRIORITY %d", query, priority)
+	}
+	return query
+}
+
+func createMaskingDetachQuery(attachment maskingAttachment) string {
+	return fmt.Sprintf("DETACH MASKING POLICY %s ON %s(%s) FROM %s",
+		pq.QuoteIdentifier(attachment.policyName),
+		attachment.quotedTable(),
+		attachment.quotedColumns(),
+		attachment.quotedGrantee(),
+	)
+}
+
+// matchesMaskedColumns reports whether the output_columns of svv_attached_masking_policy,
+// e.g. ["credit_card"], are the given columns.
+func matchesMaskedColumns(outputColumns string, columns []string) bool {
+	var attached []string
+	if err := json.Unmarshal([]byte(outputColumns), &attached); err != nil {
+		log.Printf("[WARN] Could not parse masked columns %q: %v", outputColumns, err)
+		return false
+	}
+	return slices.EqualFunc(attached, columns, strings.EqualFold)
+}
+
+// id returns the ID of the attachment in the format <policy>;<schema>;<table>;<columns>;<grantee type>[;<grantee name>].
+func (a maskingAttachment) id() string {
+	parts := []string{strings.ToLower(a.policyName), a.schemaName, a.tableName, strings.Join(a.columns, ","), strings.ToLower(a.granteeType)}
+	if a.granteeType != "PUBLIC" {
+		parts = append(parts, a.granteeName)
+	}
+	return strings.Join(parts, ";")
+}
+
+func parseMaskingAttachmentID(id string) (maskingAttachment, error) {
+	parts := strings.Split(id, ";")
+	if len(parts) < 5 || len(parts) > 6 || parts[3] == "" {
+		return maskingAttachment{}, fmt.Errorf("invalid masking policy attachment ID %q, expected <policy>;<schema>;<table>;<columns>;<user|role|public>[;<grantee>]", id)
+	}
+
+	attachment, err := parsePolicyAttachmentID(strings.Join(slices.Delete(slices.Clone(parts), 3, 4), ";"))
+	if err != nil {
+		return maskingAttachment{}, fmt.Errorf("invalid masking policy attachment ID %q: %w", id, err)
+	}
+	return maskingAttachment{
+		policyAttachment: attachment,
+		columns:          strings.Split(parts[3], ","),
+	}, nil
+}
@@ -0,0 +1,171 @@
+package redshift
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestAccRedshiftMaskingAttachment_Priority(t *testing.T) {
+	name := generateRandomObjectName("acc_test_masking_attachment")
+
+	baseConfig := fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	name              = %[1]q
+	cascade_on_delete = true
+}
+
+resource "redshift_role" "role" {
+	name = %[1]q
+}
+
+resource "redshift_masking_policy" "policy" {
+	name = %[1]q
+
+	with {
+		name = "credit_card"
+		type = "VARCHAR(256)"
+	}
+
+	using = "'XXXX-XXXX-XXXX-XXXX'::VARCHAR(256)"
+}
+`, name)
+
+	configTemplate := baseConfig + `
+resource "redshift_masking_attachment" "attachment" {
+	policy_name  = redshift_masking_policy.policy.name
+	schema       = redshift_schema.schema.name
+	table        = "customers"
+	columns      = ["credit_card"]
+	grantee_type = "ROLE"
+	grantee_name = redshift_role.role.name
+	priority     = %d
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+			},
+			{
+				PreConfig: func() {
+					db, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't connect to redshift: %v", err)
+					}
+					if _, err := db.Exec(fmt.Sprintf("CREATE TABLE %s.customers (credit_card VARCHAR(256))", pq.QuoteIdentifier(name))); err != nil {
+						t.Fatalf("couldn't create table: %v", err)
+					}
+				},
+				Config: fmt.Sprintf(configTemplate, 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_masking_attachment.attachment", "id", fmt.Sprintf("%[1]s;%[1]s;customers;credit_card;role;%[1]s", name)),
+					resource.TestCheckResourceAttr("redshift_masking_attachment.attachment", "priority", "10"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_masking_attachment.attachment", "priority", "20"),
+				),
+			},
+			{
+				ResourceName:      "redshift_masking_attachment.attachment",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCreateMaskingAttachQuery(t *testing.T) {
+	attachment := maskingAttachment{
+		policyAttachment: policyAttachment{policyName: "mask_card", schemaName: "sales", tableName: "customers", granteeType: "ROLE", granteeName: "analysts"},
+		columns:          []string{"credit_card"},
+	}
+
+	tests := map[string]struct {
+		inputColumns []string
+		priority     int
+		expected     string
+	}{
+		"default priority": {
+			expected: `ATTACH MASKING POLICY "mask_card" ON "sales"."customers"("credit_card") TO ROLE "analysts"`,
+		},
+		"input columns and priority": {
+			inputColumns: []string{"card_number"},
+			priority:     30,
+			expected:     `ATTACH MASKING POLICY "mask_card" ON "sales"."customers"("credit_card") USING ("card_number") TO ROLE "analysts" PRIORITY 30`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := createMaskingAttachQuery(attachment, tt.inputColumns, tt.priority); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	expectedDetach := `DETACH MASKING POLICY "mask_card" ON "sales"."customers"("credit_card") FROM ROLE "analysts"`
+	if got := createMaskingDetachQuery(attachment); got != expectedDetach {
+		t.Errorf("expected %q, got %q", expectedDetach, got)
+	}
+}
+
+func TestParseMaskingAttachmentID(t *testing.T) {
+	for _, attachment := range []maskingAttachment{
+		{policyAttachment{policyName: "mask_card", schemaName: "sales", tableName: "customers", granteeType: "USER", granteeName: "alice"}, []string{"credit_card"}},
+		{policyAttachment{policyName: "mask_card", schemaName: "sales", tableName: "customers", granteeType: "PUBLIC"}, []string{"credit_card", "iban"}},
+	} {
+		t.Run(attachment.id(), func(t *testing.T) {
+			parsed, err := parseMaskingAttachmentID(attachment.id())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, attachment) {
+				t.Errorf("expected %+v, got %+v", attachment, parsed)
+			}
+		})
+	}
+
+	for _, id := range []string{
+		"mask_card;sales;customers;role;analysts",
+		"mask_card;sales;customers;;role;analysts",
+		"mask_card;sales;customers;credit_card;public;alice",
+	} {
+		if _, err := parseMaskingAttachmentID(id); err == nil {
+			t.Errorf("expected an error parsing %q", id)
+		}
+	}
+}
+
+func TestMatchesMaskedColumns(t *testing.T) {
+	tests := map[string]struct {
+		outputColumns string
+		columns       []string
+		expected      bool
+	}{
+		"same column":       {`["credit_card"]`, []string{"credit_card"}, true},
+		"different case":    {`["credit_card"]`, []string{"CREDIT_CARD"}, true},
+		"different column":  {`["iban"]`, []string{"credit_card"}, false},
+		"subset of columns": {`["credit_card","iban"]`, []string{"credit_card"}, false},
+		"not json":          {`credit_card`, []string{"credit_card"}, false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := matchesMaskedColumns(tt.outputColumns, tt.columns); got != tt.expected {
+				t.Errorf("matchesMaskedColumns(%q, %v) = %v, want %v", tt.outputColumns, tt.columns, got, tt.expected)
+			}
+		})
+	}
+}
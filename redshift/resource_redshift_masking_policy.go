@@ -0,0 +1,158 @@
+package redshift
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	maskingPolicyNameAttr       = "name"
+	maskingPolicyUsingAttr      = "using"
+	maskingPolicyModifiedByAttr = "modified_by"
+)
+
+func redshiftMaskingPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Manages a dynamic data masking policy. The policy masks the values of the columns it is attached to,
+see ` + "`redshift_masking_attachment`" + `.
+
+For more information, see [CREATE MASKING POLICY documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_MASKING_POLICY.html).
+`,
+		CreateContext: ResourceFunc(resourceRedshiftMaskingPolicyCreate),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftMaskingPolicyRead),
+		UpdateContext: ResourceFunc(resourceRedshiftMaskingPolicyUpdate),
+		DeleteContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftMaskingPolicyDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			maskingPolicyNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the policy.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			policyWithAttr: policyWithSchema("The input columns of the policy, they are bound to the columns of the table when the policy is attached."),
+			maskingPolicyUsingAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The masking expression, e.g. `SUBSTRING(credit_card, 1, 4) || '****'`. It has to return the type of the columns the policy is attached to. Multiple output columns are separated by commas.",
+				DiffSuppressFunc: suppressEquivalentPolicyExpressions,
+			},
+			maskingPolicyModifiedByAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The user who last modified the policy.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftMaskingPolicyCreate(db *DBConnection, d *schema.ResourceData) error {
+	policyName := d.Get(maskingPolicyNameAttr).(string)
+
+	query := fmt.Sprintf("CREATE MASKING POLICY %s%s USING (%s)",
+		pq.QuoteIdentifier(policyName),
+		createPolicyWithClause(getPolicyColumns(d), ""),
+		d.Get(maskingPolicyUsingAttr).(string),
+	)
+
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("could not create masking policy %q: %w", policyName, err)
+	}
+
+	d.SetId(strings.ToLower(policyName))
+
+	return resourceRedshiftMaskingPolicyRead(db, d)
+}
+
+func resourceRedshiftMaskingPolicyRead(db *DBConnection, d *schema.ResourceData) error {
+	var policyName, columns, expression, modifiedBy string
+
+	query := "SELECT policy_name, COALESCE(input_columns, ''), policy_expression, COALESCE(policy_modified_by, '') FROM svv_masking_policy WHERE policy_name = $1"
+
+	err := db.QueryRow(query, d.Id()).Scan(&policyName, &columns, &expression, &modifiedBy)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Printf("[WARN] Redshift masking policy (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("error reading masking policy %q: %w", d.Id(), err)
+	}
+
+	// See resourceRedshiftRLSPolicyRead for why the columns are only read on import.
+	if _, ok := d.GetOk(policyWithAttr); !ok {
+		policyColumns, err := parsePolicyColumns(columns)
+		if err != nil {
+			return fmt.Errorf("error reading input columns of masking policy %q: %w", policyName, err)
+		}
+		d.Set(policyWithAttr, flattenPolicyColumns(policyColumns))
+	}
+
+	d.Set(maskingPolicyNameAttr, policyName)
+	d.Set(maskingPolicyUsingAttr, parseMaskingPolicyExpression(expression))
+	d.Set(maskingPolicyModifiedByAttr, modifiedBy)
+
+	return nil
+}
+
+func resourceRedshiftMaskingPolicyUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChange(maskingPolicyUsingAttr) {
+		policyName := d.Get(maskingPolicyNameAttr).(string)
+		query := fmt.Sprintf("ALTER MASKING POLICY %s USING (%s)", pq.QuoteIdentifier(policyName), d.Get(maskingPolicyUsingAttr).(string))
+
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("could not update masking policy %q: %w", policyName, err)
+		}
+	}
+
+	return resourceRedshiftMaskingPolicyRead(db, d)
+}
+
+func resourceRedshiftMaskingPolicyDelete(db *DBConnection, d *schema.ResourceData) error {
+	policyName := d.Get(maskingPolicyNameAttr).(string)
+
+	query := fmt.Sprintf("DROP MASKING POLICY %s", pq.QuoteIdentifier(policyName))
+
+	if _, err := db.Exec(query); err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			log.Printf("[WARN] Masking policy %q does not exist, already dropped: %v", policyName, err)
+			return nil
+		}
+		return fmt.Errorf("could not drop masking policy %q: %w", policyName, err)
+	}
+
+	return nil
+}
+
+// parseMaskingPolicyExpression returns the masking expression from the policy_expression column of
+// svv_masking_policy, which holds the output expressions with their types,
+// e.g. [{"expr":"SUBSTRING(credit_card, 1, 4)","type":"character varying(256)"}].
+func parseMaskingPolicyExpression(raw string) string {
+	var outputs []struct {
+		Expression string `json:"expr"`
+	}
+	if err := json.Unmarshal([]byte(raw), &outputs); err != nil || len(outputs) == 0 {
+		return raw
+	}
+	expressions := make([]string, len(outputs))
+	for i, output := range outputs {
+		expressions[i] = output.Expression
+	}
+	return strings.Join(expressions, ", ")
+}
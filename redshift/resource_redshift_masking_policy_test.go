@@ -0,0 +1,138 @@
+package redshift
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftMaskingPolicy_Basic(t *testing.T) {
+	policyName := generateRandomObjectName("acc_test_masking")
+
+	configTemplate := `
+resource "redshift_masking_policy" "policy" {
+	name = %[1]q
+
+	with {
+		name = "credit_card"
+		type = "VARCHAR(256)"
+	}
+
+	using = %[2]q
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftMaskingPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, policyName, "'XXXX-XXXX-XXXX-XXXX'::VARCHAR(256)"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftMaskingPolicyExists(policyName),
+					resource.TestCheckResourceAttr("redshift_masking_policy.policy", "name", policyName),
+					resource.TestCheckResourceAttr("redshift_masking_policy.policy", "with.0.name", "credit_card"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, policyName, "SUBSTRING(credit_card, 1, 4) || '-XXXX-XXXX-XXXX'"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftMaskingPolicyExists(policyName),
+				),
+			},
+			{
+				ResourceName:            "redshift_masking_policy.policy",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"with.0.type"},
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftMaskingPolicyDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_masking_policy" {
+			continue
+		}
+
+		exists, err := checkMaskingPolicyExists(client, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error checking masking policy: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("masking policy still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckRedshiftMaskingPolicyExists(policyName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+
+		exists, err := checkMaskingPolicyExists(client, policyName)
+		if err != nil {
+			return fmt.Errorf("error checking masking policy: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("masking policy not found")
+		}
+
+		return nil
+	}
+}
+
+func checkMaskingPolicyExists(client *Client, policyName string) (bool, error) {
+	db, err := client.Connect()
+	if err != nil {
+		return false, err
+	}
+	var resp int
+	err = db.QueryRow("SELECT 1 FROM svv_masking_policy WHERE policy_name = $1", policyName).Scan(&resp)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("error reading info about masking policy: %w", err)
+	}
+
+	return true, nil
+}
+
+func TestParseMaskingPolicyExpression(t *testing.T) {
+	tests := map[string]struct {
+		raw      string
+		expected string
+	}{
+		"single output": {
+			raw:      `[{"expr":"SUBSTRING(credit_card, 1, 4)","type":"character varying(256)"}]`,
+			expected: "SUBSTRING(credit_card, 1, 4)",
+		},
+		"multiple outputs": {
+			raw:      `[{"expr":"'XXX'","type":"character varying(3)"},{"expr":"0","type":"integer"}]`,
+			expected: "'XXX', 0",
+		},
+		"plain expression": {
+			raw:      "SUBSTRING(credit_card, 1, 4)",
+			expected: "SUBSTRING(credit_card, 1, 4)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := parseMaskingPolicyExpression(tt.raw); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
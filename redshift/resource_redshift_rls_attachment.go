@@ -0,0 +1,321 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	policyAttachmentPolicyNameAttr    = "policy_name"
+	policyAttachmentSchemaAttr        = "schema"
+	policyAttachmentTableAttr         = "table"
+	policyAttachmentGranteeTypeAttr   = "grantee_type"
+	policyAttachmentGranteeNameAttr   = "grantee_name"
+	rlsAttachmentRowLevelSecurityAttr = "row_level_security"
+)
+
+var policyGranteeTypes = []string{"USER", "ROLE", "PUBLIC"}
+
+func redshiftRLSAttachment() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Attaches a row-level security policy to a table for a user, a role or PUBLIC.
+
+For more information, see [ATTACH RLS POLICY documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_ATTACH_RLS_POLICY.html).
+`,
+		CreateContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftRLSAttachmentCreate)),
+		ReadContext:   IdempotentResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftRLSAttachmentRead)),
+		UpdateContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftRLSAttachmentUpdate)),
+		DeleteContext: ResourceFunc(ResourceRetryOnPQErrors(resourceRedshiftRLSAttachmentDelete)),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedshiftRLSAttachmentImport,
+		},
+
+		Schema: policyAttachmentSchema("RLS", map[string]*schema.Schema{
+			rlsAttachmentRowLevelSecurityAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to turn on row-level security for the table, without it attached policies are not applied. It is not turned off when the attachment is deleted, as other policies might still be attached to the table.",
+			},
+		}),
+	}
+}
+
+// policyAttachmentSchema returns the schema of a policy attachment resource, the arguments
+// which identify the table and the grantee merged with the policy specific ones.
+func policyAttachmentSchema(policyKind string, policySchema map[string]*schema.Schema) map[string]*schema.Schema {
+	attachmentSchema := map[string]*schema.Schema{
+		policyAttachmentPolicyNameAttr: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: fmt.Sprintf("The name of the %s policy to attach.", policyKind),
+			StateFunc: func(val interface{}) string {
+				return strings.ToLower(val.(string))
+			},
+		},
+		policyAttachmentSchemaAttr: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     "public",
+			Description: "The schema of the table.",
+		},
+		policyAttachmentTableAttr: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The table to attach the policy to.",
+		},
+		policyAttachmentGranteeTypeAttr: {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			Description:  "The type of principal the policy applies to. Valid values are: 'USER', 'ROLE', 'PUBLIC'.",
+			ValidateFunc: validation.StringInSlice(policyGranteeTypes, false),
+		},
+		policyAttachmentGranteeNameAttr: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "The name of the user or role the policy applies to. Must not be set for 'PUBLIC'.",
+		},
+	}
+	for name, attr := range policySchema {
+		attachmentSchema[name] = attr
+	}
+	return attachmentSchema
+}
+
+func resourceRedshiftRLSAttachmentCreate(db *DBConnection, d *schema.ResourceData) error {
+	attachment, err := getPolicyAttachment(d)
+	if err != nil {
+		return err
+	}
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("ATTACH RLS POLICY %s ON %s TO %s",
+		pq.QuoteIdentifier(attachment.policyName),
+		attachment.quotedTable(),
+		attachment.quotedGrantee(),
+	)
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not attach RLS policy %q: %w", attachment.policyName, err)
+	}
+
+	if d.Get(rlsAttachmentRowLevelSecurityAttr).(bool) {
+		if err := enableTableRowLevelSecurity(tx, attachment); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(attachment.id())
+
+	return resourceRedshiftRLSAttachmentRead(db, d)
+}
+
+func resourceRedshiftRLSAttachmentRead(db *DBConnection, d *schema.ResourceData) error {
+	attachment, err := getPolicyAttachment(d)
+	if err != nil {
+		return err
+	}
+
+	var rowLevelSecurity bool
+	query := `
+SELECT is_rls_on
+FROM svv_rls_attached_policy
+WHERE polname = $1
+	AND schemaname = $2
+	AND relname = $3
+	AND LOWER(granteekind) = LOWER($4)
+	AND ($4 = 'PUBLIC' OR grantee = $5)`
+
+	err = db.QueryRow(query, attachment.policyName, attachment.schemaName, attachment.tableName, attachment.granteeType, attachment.granteeName).Scan(&rowLevelSecurity)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Printf("[WARN] RLS policy attachment %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("error reading RLS policy attachment %s: %w", d.Id(), err)
+	}
+
+	// Turning row-level security off is never done by this resource, so only a table it was
+	// turned off for outside of Terraform is a drift.
+	if d.Get(rlsAttachmentRowLevelSecurityAttr).(bool) {
+		d.Set(rlsAttachmentRowLevelSecurityAttr, rowLevelSecurity)
+	}
+
+	return nil
+}
+
+func resourceRedshiftRLSAttachmentUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChange(rlsAttachmentRowLevelSecurityAttr) && d.Get(rlsAttachmentRowLevelSecurityAttr).(bool) {
+		attachment, err := getPolicyAttachment(d)
+		if err != nil {
+			return err
+		}
+
+		tx, err := startTransaction(db.client)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(tx)
+
+		if err := enableTableRowLevelSecurity(tx, attachment); err != nil {
+			return err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("could not commit transaction: %w", err)
+		}
+	}
+
+	return resourceRedshiftRLSAttachmentRead(db, d)
+}
+
+func resourceRedshiftRLSAttachmentDelete(db *DBConnection, d *schema.ResourceData) error {
+	attachment, err := getPolicyAttachment(d)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DETACH RLS POLICY %s ON %s FROM %s",
+		pq.QuoteIdentifier(attachment.policyName),
+		attachment.quotedTable(),
+		attachment.quotedGrantee(),
+	)
+
+	if _, err := db.Exec(query); err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			log.Printf("[WARN] RLS policy, table or grantee does not exist, policy already detached: %v", err)
+			return nil
+		}
+		return fmt.Errorf("could not detach RLS policy %q: %w", attachment.policyName, err)
+	}
+
+	return nil
+}
+
+func resourceRedshiftRLSAttachmentImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	attachment, err := parsePolicyAttachmentID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set(policyAttachmentPolicyNameAttr, attachment.policyName)
+	d.Set(policyAttachmentSchemaAttr, attachment.schemaName)
+	d.Set(policyAttachmentTableAttr, attachment.tableName)
+	d.Set(policyAttachmentGranteeTypeAttr, attachment.granteeType)
+	d.Set(policyAttachmentGranteeNameAttr, attachment.granteeName)
+	d.Set(rlsAttachmentRowLevelSecurityAttr, true)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func enableTableRowLevelSecurity(tx *transaction, attachment policyAttachment) error {
+	query := fmt.Sprintf("ALTER TABLE %s ROW LEVEL SECURITY ON", attachment.quotedTable())
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not turn on row-level security for table %s: %w", attachment.quotedTable(), err)
+	}
+	return nil
+}
+
+// policyAttachment identifies the attachment of an RLS or masking policy to a table for a grantee.
+type policyAttachment struct {
+	policyName  string
+	schemaName  string
+	tableName   string
+	granteeType string
+	granteeName string
+}
+
+func getPolicyAttachment(d *schema.ResourceData) (policyAttachment, error) {
+	attachment := policyAttachment{
+		policyName:  d.Get(policyAttachmentPolicyNameAttr).(string),
+		schemaName:  d.Get(policyAttachmentSchemaAttr).(string),
+		tableName:   d.Get(policyAttachmentTableAttr).(string),
+		granteeType: d.Get(policyAttachmentGranteeTypeAttr).(string),
+		granteeName: d.Get(policyAttachmentGranteeNameAttr).(string),
+	}
+	return attachment, attachment.validate()
+}
+
+func (a policyAttachment) validate() error {
+	switch {
+	case a.granteeType == "PUBLIC" && a.granteeName != "":
+		return fmt.Errorf("%s must not be set for grantee type PUBLIC", policyAttachmentGranteeNameAttr)
+	case a.granteeType != "PUBLIC" && a.granteeName == "":
+		return fmt.Errorf("%s is required for grantee type %s", policyAttachmentGranteeNameAttr, a.granteeType)
+	}
+	return nil
+}
+
+func (a policyAttachment) quotedTable() string {
+	return fmt.Sprintf("%s.%s", pq.QuoteIdentifier(a.schemaName), pq.QuoteIdentifier(a.tableName))
+}
+
+func (a policyAttachment) quotedGrantee() string {
+	switch a.granteeType {
+	case "PUBLIC":
+		return "PUBLIC"
+	case "ROLE":
+		return "ROLE " + pq.QuoteIdentifier(a.granteeName)
+	default:
+		return pq.QuoteIdentifier(a.granteeName)
+	}
+}
+
+// id returns the ID of the attachment in the format <policy>;<schema>;<table>;<grantee type>[;<grantee name>].
+func (a policyAttachment) id() string {
+	parts := []string{strings.ToLower(a.policyName), a.schemaName, a.tableName, strings.ToLower(a.granteeType)}
+	if a.granteeType != "PUBLIC" {
+		parts = append(parts, a.granteeName)
+	}
+	return strings.Join(parts, ";")
+}
+
+func parsePolicyAttachmentID(id string) (policyAttachment, error) {
+	parts := strings.Split(id, ";")
+	if len(parts) < 4 || len(parts) > 5 {
+		return policyAttachment{}, fmt.Errorf("invalid policy attachment ID %q, expected <policy>;<schema>;<table>;<user|role|public>[;<grantee>]", id)
+	}
+
+	attachment := policyAttachment{
+		policyName:  parts[0],
+		schemaName:  parts[1],
+		tableName:   parts[2],
+		granteeType: strings.ToUpper(parts[3]),
+	}
+	if len(parts) == 5 {
+		attachment.granteeName = parts[4]
+	}
+	if !slices.Contains(policyGranteeTypes, attachment.granteeType) {
+		return policyAttachment{}, fmt.Errorf("unsupported grantee type %q in policy attachment ID %q", parts[3], id)
+	}
+	if err := attachment.validate(); err != nil {
+		return policyAttachment{}, fmt.Errorf("invalid policy attachment ID %q: %w", id, err)
+	}
+	return attachment, nil
+}
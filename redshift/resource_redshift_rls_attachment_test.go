@@ -0,0 +1,126 @@
+package redshift
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestAccRedshiftRLSAttachment_Basic(t *testing.T) {
+	name := generateRandomObjectName("acc_test_rls_attachment")
+
+	baseConfig := fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	name              = %[1]q
+	cascade_on_delete = true
+}
+
+resource "redshift_role" "role" {
+	name = %[1]q
+}
+
+resource "redshift_rls_policy" "policy" {
+	name = %[1]q
+
+	with {
+		name = "department"
+		type = "VARCHAR(256)"
+	}
+
+	using = "department = current_user"
+}
+`, name)
+
+	config := baseConfig + `
+resource "redshift_rls_attachment" "attachment" {
+	policy_name  = redshift_rls_policy.policy.name
+	schema       = redshift_schema.schema.name
+	table        = "employees"
+	grantee_type = "ROLE"
+	grantee_name = redshift_role.role.name
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+			},
+			{
+				PreConfig: func() {
+					db, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't connect to redshift: %v", err)
+					}
+					if _, err := db.Exec(fmt.Sprintf("CREATE TABLE %s.employees (department VARCHAR(256))", pq.QuoteIdentifier(name))); err != nil {
+						t.Fatalf("couldn't create table: %v", err)
+					}
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_rls_attachment.attachment", "id", fmt.Sprintf("%[1]s;%[1]s;employees;role;%[1]s", name)),
+					resource.TestCheckResourceAttr("redshift_rls_attachment.attachment", "row_level_security", "true"),
+				),
+			},
+			{
+				ResourceName:      "redshift_rls_attachment.attachment",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParsePolicyAttachmentID(t *testing.T) {
+	for _, attachment := range []policyAttachment{
+		{policyName: "policy", schemaName: "public", tableName: "employees", granteeType: "USER", granteeName: "alice"},
+		{policyName: "policy", schemaName: "hr", tableName: "employees", granteeType: "ROLE", granteeName: "analysts"},
+		{policyName: "policy", schemaName: "hr", tableName: "employees", granteeType: "PUBLIC"},
+	} {
+		t.Run(attachment.id(), func(t *testing.T) {
+			parsed, err := parsePolicyAttachmentID(attachment.id())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parsed != attachment {
+				t.Errorf("expected %+v, got %+v", attachment, parsed)
+			}
+		})
+	}
+
+	for _, id := range []string{
+		"policy;public;employees",
+		"policy;public;employees;group;analysts",
+		"policy;public;employees;public;alice",
+		"policy;public;employees;user",
+	} {
+		if _, err := parsePolicyAttachmentID(id); err == nil {
+			t.Errorf("expected an error parsing %q", id)
+		}
+	}
+}
+
+func TestPolicyAttachmentQuotedGrantee(t *testing.T) {
+	tests := map[string]struct {
+		attachment policyAttachment
+		expected   string
+	}{
+		"user":   {policyAttachment{granteeType: "USER", granteeName: "alice"}, `"alice"`},
+		"role":   {policyAttachment{granteeType: "ROLE", granteeName: "analysts"}, `ROLE "analysts"`},
+		"public": {policyAttachment{granteeType: "PUBLIC"}, "PUBLIC"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.attachment.quotedGrantee(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package redshift
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	rlsPolicyNameAttr       = "name"
+	policyWithAttr          = "with"
+	policyWithNameAttr      = "name"
+	policyWithTypeAttr      = "type"
+	rlsPolicyAliasAttr      = "alias"
+	rlsPolicyUsingAttr      = "using"
+	rlsPolicyCascadeAttr    = "cascade_on_delete"
+	rlsPolicyModifiedByAttr = "modified_by"
+)
+
+func redshiftRLSPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Manages a row-level security (RLS) policy. The policy filters the rows of the tables it is attached to,
+see ` + "`redshift_rls_attachment`" + `.
+
+For more information, see [CREATE RLS POLICY documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_RLS_POLICY.html).
+`,
+		CreateContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftRLSPolicyCreate),
+		),
+		ReadContext: IdempotentResourceFunc(resourceRedshiftRLSPolicyRead),
+		UpdateContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftRLSPolicyUpdate),
+		),
+		DeleteContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftRLSPolicyDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			rlsPolicyNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the policy.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			policyWithAttr: policyWithSchema("The columns the `using` expression refers to. They are matched by name with the columns of the tables the policy is attached to."),
+			rlsPolicyAliasAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "An alias for the table the policy is attached to, which can be used in the `using` expression.",
+			},
+			rlsPolicyUsingAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The filter expression. Only the rows for which it evaluates to true are visible, e.g. `department = current_user`.",
+				DiffSuppressFunc: suppressEquivalentPolicyExpressions,
+			},
+			rlsPolicyCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to detach the policy from all tables when it is dropped. Without it the policy can only be dropped after all its attachments are gone.",
+			},
+			rlsPolicyModifiedByAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The user who last modified the policy.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftRLSPolicyCreate(db *DBConnection, d *schema.ResourceData) error {
+	policyName := d.Get(rlsPolicyNameAttr).(string)
+
+	query := createRLSPolicyQuery(policyName, getPolicyColumns(d), d.Get(rlsPolicyAliasAttr).(string), d.Get(rlsPolicyUsingAttr).(string))
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not create RLS policy %q: %w", policyName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(strings.ToLower(policyName))
+
+	return resourceRedshiftRLSPolicyRead(db, d)
+}
+
+func resourceRedshiftRLSPolicyRead(db *DBConnection, d *schema.ResourceData) error {
+	setDefaultIfUnset(d, rlsPolicyCascadeAttr, false)
+
+	var policyName, alias, columns, using, modifiedBy string
+
+	query := "SELECT polname, COALESCE(polalias, ''), COALESCE(polatts, ''), polqual, COALESCE(polmodifiedby, '') FROM svv_rls_policy WHERE polname = $1"
+
+	err := db.QueryRow(query, d.Id()).Scan(&policyName, &alias, &columns, &using, &modifiedBy)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Printf("[WARN] Redshift RLS policy (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("error reading RLS policy %q: %w", d.Id(), err)
+	}
+
+	// Redshift normalizes the data types of the columns, so they are only read when nothing is known
+	// about them yet (on import), otherwise every spelling but the normalized one would replace the policy.
+	if _, ok := d.GetOk(policyWithAttr); !ok {
+		policyColumns, err := parsePolicyColumns(columns)
+		if err != nil {
+			return fmt.Errorf("error reading columns of RLS policy %q: %w", policyName, err)
+		}
+		d.Set(policyWithAttr, flattenPolicyColumns(policyColumns))
+		d.Set(rlsPolicyAliasAttr, alias)
+	}
+
+	d.Set(rlsPolicyNameAttr, policyName)
+	d.Set(rlsPolicyUsingAttr, using)
+	d.Set(rlsPolicyModifiedByAttr, modifiedBy)
+
+	return nil
+}
+
+func resourceRedshiftRLSPolicyUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if d.HasChange(rlsPolicyUsingAttr) {
+		policyName := d.Get(rlsPolicyNameAttr).(string)
+		query := fmt.Sprintf("ALTER RLS POLICY %s USING (%s)", pq.QuoteIdentifier(policyName), d.Get(rlsPolicyUsingAttr).(string))
+
+		tx, err := startTransaction(db.client)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(tx)
+
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("could not update RLS policy %q: %w", policyName, err)
+		}
+
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("could not commit transaction: %w", err)
+		}
+	}
+
+	return resourceRedshiftRLSPolicyRead(db, d)
+}
+
+func resourceRedshiftRLSPolicyDelete(db *DBConnection, d *schema.ResourceData) error {
+	policyName := d.Get(rlsPolicyNameAttr).(string)
+
+	query := fmt.Sprintf("DROP RLS POLICY IF EXISTS %s", pq.QuoteIdentifier(policyName))
+	if d.Get(rlsPolicyCascadeAttr).(bool) {
+		query += " CASCADE"
+	}
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not drop RLS policy %q: %w", policyName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// policyWithSchema returns the schema of the columns declared in the WITH clause of a policy.
+func policyWithSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		ForceNew:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				policyWithNameAttr: {
+					Type:        schema.TypeString,
+					Required:    true,
+					ForceNew:    true,
+					Description: "The name of the column.",
+				},
+				policyWithTypeAttr: {
+					Type:        schema.TypeString,
+					Required:    true,
+					ForceNew:    true,
+					Description: "The data type of the column, e.g. `VARCHAR(256)`.",
+				},
+			},
+		},
+	}
+}
+
+// policyColumn is a column of the WITH clause of a policy.
+type policyColumn struct {
+	name     string
+	dataType string
+}
+
+func getPolicyColumns(d *schema.ResourceData) []policyColumn {
+	var columns []policyColumn
+	for _, raw := range d.Get(policyWithAttr).([]interface{}) {
+		column := raw.(map[string]interface{})
+		columns = append(columns, policyColumn{
+			name:     column[policyWithNameAttr].(string),
+			dataType: column[policyWithTypeAttr].(string),
+		})
+	}
+	return columns
+}
+
+// parsePolicyColumns parses the polatts column of svv_rls_policy and svv_masking_policy,
+// e.g. [{"colname":"department","type":"character varying(256)"}].
+func parsePolicyColumns(raw string) ([]policyColumn, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var attributes []struct {
+		Name string `json:"colname"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(raw), &attributes); err != nil {
+		return nil, err
+	}
+	columns := make([]policyColumn, len(attributes))
+	for i, attribute := range attributes {
+		columns[i] = policyColumn{name: attribute.Name, dataType: attribute.Type}
+	}
+	return columns, nil
+}
+
+func flattenPolicyColumns(columns []policyColumn) []interface{} {
+	flattened := make([]interface{}, len(columns))
+	for i, column := range columns {
+		flattened[i] = map[string]interface{}{
+			policyWithNameAttr: column.name,
+			policyWithTypeAttr: column.dataType,
+		}
+	}
+	return flattened
+}
+
+// createPolicyWithClause returns the WITH clause declaring the columns and the table alias a policy refers to.
+func createPolicyWithClause(columns []policyColumn, alias string) string {
+	var clause string
+	if len(columns) > 0 {
+		columnDefinitions := make([]string, len(columns))
+		for i, column := range columns {
+			columnDefinitions[i] = fmt.Sprintf("%s %s", pq.QuoteIdentifier(column.name), column.dataType)
+		}
+		clause = fmt.Sprintf(" WITH (%s)", strings.Join(columnDefinitions, ", "))
+	}
+	if alias != "" {
+		clause += " AS " + pq.QuoteIdentifier(alias)
+	}
+	return clause
+}
+
+func createRLSPolicyQuery(policyName string, columns []policyColumn, alias, using string) string {
+	return fmt.Sprintf("CREATE RLS POLICY %s%s USING (%s)",
+		pq.QuoteIdentifier(policyName),
+		createPolicyWithClause(columns, alias),
+		using,
+	)
+}
+
+// suppressEquivalentPolicyExpressions ignores the differences between a policy expression as
+// configured and as it is stored by Redshift: case, whitespace, quoting and enclosing parentheses.
+func suppressEquivalentPolicyExpressions(_, old, new string, _ *schema.ResourceData) bool {
+	return normalizePolicyExpression(old) == normalizePolicyExpression(new)
+}
+
+func normalizePolicyExpression(expression string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(expression), ""))
+	normalized = strings.ReplaceAll(normalized, `"`, "")
+	for strings.HasPrefix(normalized, "(") && strings.HasSuffix(normalized, ")") && enclosedInParentheses(normalized) {
+		normalized = normalized[1 : len(normalized)-1]
+	}
+	return normalized
+}
+
+// enclosedInParentheses reports whether the opening parenthesis at the start of expression is closed at its end.
+func enclosedInParentheses(expression string) bool {
+	depth := 0
+	for i, c := range expression {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i == len(expression)-1
+			}
+		}
+	}
+	return false
+}
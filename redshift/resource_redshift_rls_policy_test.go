@@ -0,0 +1,178 @@
+package redshift
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftRLSPolicy_Basic(t *testing.T) {
+	policyName := generateRandomObjectName("acc_test_rls")
+
+	configTemplate := `
+resource "redshift_rls_policy" "policy" {
+	name = %[1]q
+
+	with {
+		name = "department"
+		type = "VARCHAR(256)"
+	}
+
+	using = %[2]q
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRLSPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, policyName, "department = 'engineering'"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRLSPolicyExists(policyName),
+					resource.TestCheckResourceAttr("redshift_rls_policy.policy", "name", policyName),
+					resource.TestCheckResourceAttr("redshift_rls_policy.policy", "with.#", "1"),
+					resource.TestCheckResourceAttr("redshift_rls_policy.policy", "with.0.name", "department"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, policyName, "department = current_user"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRLSPolicyExists(policyName),
+				),
+			},
+			{
+				ResourceName:            "redshift_rls_policy.policy",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"with.0.type"},
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftRLSPolicyDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_rls_policy" {
+			continue
+		}
+
+		exists, err := checkRLSPolicyExists(client, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error checking RLS policy: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("RLS policy still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckRedshiftRLSPolicyExists(policyName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+
+		exists, err := checkRLSPolicyExists(client, policyName)
+		if err != nil {
+			return fmt.Errorf("error checking RLS policy: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("RLS policy not found")
+		}
+
+		return nil
+	}
+}
+
+func checkRLSPolicyExists(client *Client, policyName string) (bool, error) {
+	db, err := client.Connect()
+	if err != nil {
+		return false, err
+	}
+	var resp int
+	err = db.QueryRow("SELECT 1 FROM svv_rls_policy WHERE polname = $1", policyName).Scan(&resp)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("error reading info about RLS policy: %w", err)
+	}
+
+	return true, nil
+}
+
+func TestCreateRLSPolicyQuery(t *testing.T) {
+	tests := map[string]struct {
+		columns  []policyColumn
+		alias    string
+		expected string
+	}{
+		"without columns": {
+			expected: `CREATE RLS POLICY "policy" USING (true)`,
+		},
+		"with columns": {
+			columns:  []policyColumn{{"department", "VARCHAR(256)"}, {"region", "INT"}},
+			expected: `CREATE RLS POLICY "policy" WITH ("department" VARCHAR(256), "region" INT) USING (true)`,
+		},
+		"with columns and alias": {
+			columns:  []policyColumn{{"department", "VARCHAR(256)"}},
+			alias:    "t",
+			expected: `CREATE RLS POLICY "policy" WITH ("department" VARCHAR(256)) AS "t" USING (true)`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := createRLSPolicyQuery("policy", tt.columns, tt.alias, "true"); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParsePolicyColumns(t *testing.T) {
+	columns, err := parsePolicyColumns(`[{"colname":"department","type":"character varying(256)"},{"colname":"region","type":"integer"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []policyColumn{{"department", "character varying(256)"}, {"region", "integer"}}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("expected %v, got %v", expected, columns)
+	}
+
+	if columns, err := parsePolicyColumns(""); err != nil || len(columns) != 0 {
+		t.Errorf("expected no columns and no error, got %v, %v", columns, err)
+	}
+}
+
+func TestSuppressEquivalentPolicyExpressions(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		expected bool
+	}{
+		"identical":              {"department = current_user", "department = current_user", true},
+		"whitespace and case":    {"department=CURRENT_USER", "department = current_user", true},
+		"enclosing parentheses":  {"((\"department\" = current_user))", "department = current_user", true},
+		"separate parentheses":   {"(a = 1) OR (b = 2)", "a = 1) OR (b = 2", false},
+		"different expressions":  {"department = current_user", "region = current_user", false},
+		"different literal case": {"department = 'HR'", "department = 'engineering'", false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := suppressEquivalentPolicyExpressions("", tt.old, tt.new, nil); got != tt.expected {
+				t.Errorf("suppressEquivalentPolicyExpressions(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,20 +1,69 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
 )
 
 const (
-	roleNameAttr = "name"
+	roleNameAttr                    = "name"
+	roleSystemPermissionsAttr       = "system_permissions"
+	roleOwnerAttr                   = "owner"
+	roleExternalManagedAttr         = "external_managed"
+	roleCreatedOutsideTerraformAttr = "created_outside_terraform"
+	roleQuotedAttr                  = "quoted"
+	roleExternalIDAttr              = "external_id"
 )
 
+// roleSystemPermissions lists the system permissions which can be granted to a role,
+// see https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html
+var roleSystemPermissions = []string{
+	"ACCESS CATALOG",
+	"ACCESS SYSTEM TABLE",
+	"ALTER DATASHARE",
+	"ALTER DEFAULT PRIVILEGES",
+	"ALTER TABLE",
+	"ALTER USER",
+	"ANALYZE",
+	"CANCEL",
+	"CREATE DATASHARE",
+	"CREATE LIBRARY",
+	"CREATE MODEL",
+	"CREATE OR REPLACE EXTERNAL FUNCTION",
+	"CREATE OR REPLACE FUNCTION",
+	"CREATE OR REPLACE PROCEDURE",
+	"CREATE OR REPLACE VIEW",
+	"CREATE ROLE",
+	"CREATE SCHEMA",
+	"CREATE TABLE",
+	"CREATE USER",
+	"DROP DATASHARE",
+	"DROP FUNCTION",
+	"DROP LIBRARY",
+	"DROP MODEL",
+	"DROP PROCEDURE",
+	"DROP ROLE",
+	"DROP SCHEMA",
+	"DROP TABLE",
+	"DROP USER",
+	"DROP VIEW",
+	"EXPLAIN MASKING",
+	"EXPLAIN RLS",
+	"IGNORE RLS",
+	"TRUNCATE TABLE",
+	"VACUUM",
+}
+
 func redshiftRole() *schema.Resource {
 	return &schema.Resource{
 		Description: `
@@ -24,30 +73,73 @@ Roles allow you to create a hierarchy of permissions, where a role can inherit p
 For more information, see [Redshift Roles Documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_roles-managing.html).
 `,
 		CreateContext: ResourceFunc(resourceRedshiftRoleCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftRoleRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftRoleRead),
 		UpdateContext: ResourceFunc(resourceRedshiftRoleUpdate),
 		DeleteContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftRoleDelete),
 		),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceRedshiftRoleImport,
 		},
+		CustomizeDiff: customdiff.All(
+			forceNewIfRoleCreatedOutsideTerraform,
+			validateRoleNameQuoting,
+		),
 
 		Schema: map[string]*schema.Schema{
 			roleNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the role. Role names are case-insensitive and must be unique within the database.",
-				StateFunc: func(val interface{}) string {
-					return strings.ToLower(val.(string))
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The name of the role. Role names are case-insensitive unless `preserve_case` is set and must be unique within the database. Names are limited to 127 bytes, names beginning with `__` or `sys:` are reserved. Names which need to be quoted in SQL require `quoted` to be set.",
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
+				ValidateFunc:     roleNameValidate,
+			},
+			roleQuotedAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow a role name which needs to be quoted in SQL, e.g. because it contains `@`, `:` or spaces or is a reserved word. Such names are rejected during plan otherwise, as they are often a mistake.",
+			},
+			preserveCaseAttr: preserveCaseSchema(),
+			roleSystemPermissionsAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(roleSystemPermissions, false),
 				},
+				Set:         schema.HashString,
+				Description: "System permissions granted to the role, e.g. `CREATE USER` or `ACCESS SYSTEM TABLE`. Permissions must be given in upper case. When set, permissions granted outside of Terraform are revoked, so it must not be combined with the `redshift_system_privilege_grant` resource for the same role. When left out or empty, the system permissions of the role are only read, e.g. to grant them with `redshift_system_privilege_grant`.",
+			},
+			roleOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Owner of the role, usually the user who created it.",
+			},
+			roleExternalManagedAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, roles which were created outside of Terraform are replaced on the next apply, so the role is always the one Terraform created. This covers imported roles and roles which were dropped and created again under the same name outside of Terraform. If false, imported roles are kept and a role which was dropped outside of Terraform is removed from the state.",
+			},
+			roleCreatedOutsideTerraformAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the role found in the database was created outside of Terraform, i.e. it was imported or dropped and created again outside of Terraform. The latter is only detected when `external_managed` is true.",
+			},
+			roleExternalIDAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An identifier to correlate the role across environments, e.g. from a CMDB. It is stored as comment of the role. Removing it removes the comment in Redshift. When the catalog of roles is not visible to the provider user, the comment cannot be read and changes made outside of Terraform are not detected.",
 			},
 		},
 	}
 }
 
 func resourceRedshiftRoleCreate(db *DBConnection, d *schema.ResourceData) error {
-	roleName := d.Get(roleNameAttr).(string)
+	roleName := getIdentifier(d, roleNameAttr)
 
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -56,7 +148,6 @@ func resourceRedshiftRoleCreate(db *DBConnection, d *schema.ResourceData) error
 	defer deferredRollback(tx)
 
 	query := fmt.Sprintf("CREATE ROLE %s", pq.QuoteIdentifier(roleName))
-	log.Printf("[DEBUG] %s\n", query)
 
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("could not create redshift role: %w", err)
@@ -66,13 +157,29 @@ func resourceRedshiftRoleCreate(db *DBConnection, d *schema.ResourceData) error
 	// SVV_ROLES should have: role_name, role_owner, role_id
 	var roleId string
 	query = "SELECT role_id FROM SVV_ROLES WHERE role_name = $1"
-	log.Printf("[DEBUG] %s, $1=%s\n", query, roleName)
 	if err := tx.QueryRow(query, roleName).Scan(&roleId); err != nil {
 		return fmt.Errorf("could not verify role creation for %q: %w", roleName, err)
 	}
 
 	// Use role id as ID (similar to groups using grosysid)
 	d.SetId(roleId)
+	d.Set(roleCreatedOutsideTerraformAttr, false)
+
+	if owner, ownerIsSet := d.GetOk(roleOwnerAttr); ownerIsSet {
+		if err := setRoleOwner(tx, roleName, owner.(string)); err != nil {
+			return err
+		}
+	}
+
+	if err := setRoleSystemPermissions(tx, d); err != nil {
+		return fmt.Errorf("could not grant system permissions to role %q: %w", roleName, err)
+	}
+
+	if externalID := d.Get(roleExternalIDAttr).(string); externalID != "" {
+		if _, err := tx.Exec(createRoleCommentQuery(roleName, externalID)); err != nil {
+			return fmt.Errorf("could not set external id of role %q: %w", roleName, err)
+		}
+	}
 
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
@@ -82,13 +189,26 @@ func resourceRedshiftRoleCreate(db *DBConnection, d *schema.ResourceData) error
 }
 
 func resourceRedshiftRoleRead(db *DBConnection, d *schema.ResourceData) error {
-	var roleName string
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+	setDefaultIfUnset(d, roleQuotedAttr, false)
+	setDefaultIfUnset(d, roleExternalManagedAttr, false)
+	setDefaultIfUnset(d, roleCreatedOutsideTerraformAttr, false)
+
+	var roleName, roleOwner string
 
 	// Query SVV_ROLES (similar to SVV_DATASHARES pattern)
-	query := "SELECT role_name FROM SVV_ROLES WHERE role_id = $1"
-	log.Printf("[DEBUG] %s, $1=%s\n", query, d.Id())
+	query := "SELECT role_name, role_owner FROM SVV_ROLES WHERE role_id = $1"
 
-	err := db.QueryRow(query, d.Id()).Scan(&roleName)
+	err := db.QueryRow(query, d.Id()).Scan(&roleName, &roleOwner)
+	if errors.Is(err, sql.ErrNoRows) && d.Get(roleExternalManagedAttr).(bool) {
+		var recreated bool
+		if recreated, err = adoptRecreatedRole(db, d); err != nil {
+			return err
+		}
+		if recreated {
+			err = db.QueryRow(query, d.Id()).Scan(&roleName, &roleOwner)
+		}
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("[WARN] Redshift Role (%s) not found", d.Id())
@@ -98,40 +218,225 @@ func resourceRedshiftRoleRead(db *DBConnection, d *schema.ResourceData) error {
 		return fmt.Errorf("error reading role: %w", err)
 	}
 
+	permissionsQuery := "SELECT system_privilege FROM svv_system_privileges WHERE identity_type = 'role' AND identity_name = $1"
+	rows, err := db.Query(permissionsQuery, roleName)
+	if err != nil {
+		return fmt.Errorf("error reading system permissions of role %q: %w", roleName, err)
+	}
+	defer rows.Close()
+
+	var systemPermissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return fmt.Errorf("error reading system permissions of role %q: %w", roleName, err)
+		}
+		systemPermissions = append(systemPermissions, strings.ToUpper(permission))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading system permissions of role %q: %w", roleName, err)
+	}
+
+	externalID, found, err := readRoleExternalID(db, d.Id())
+	if err != nil {
+		return fmt.Errorf("could not read external id of role %q: %w", roleName, err)
+	}
+	if found {
+		d.Set(roleExternalIDAttr, externalID)
+	}
+
 	d.Set(roleNameAttr, roleName)
+	d.Set(roleOwnerAttr, roleOwner)
+	d.Set(roleSystemPermissionsAttr, systemPermissions)
+
+	return nil
+}
+
+// readRoleExternalID returns the comment of the role with the given ID, or an empty string if it has none.
+// OIDs are only unique within a catalog, so the comment is looked up by the OID of the catalog of roles. If
+// that isn't visible to the user, e.g. on Serverless, found is false and the external id can't be read.
+func readRoleExternalID(db *DBConnection, roleID string) (externalID string, found bool, err error) {
+	var classID int
+	err = db.QueryRow("SELECT oid FROM pg_class WHERE relname = 'pg_role'").Scan(&classID)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Printf("[WARN] the catalog of roles is not visible, the external id of role %s is kept as is", roleID)
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	err = db.QueryRow("SELECT COALESCE(description, '') FROM pg_description WHERE objoid = $1 AND classoid = $2 AND objsubid = 0", roleID, classID).Scan(&externalID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", false, err
+	}
+	return externalID, true, nil
+}
+
+// resourceRedshiftRoleImport flags imported roles as created outside of Terraform, so they are
+// replaced if external_managed is set.
+func resourceRedshiftRoleImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	d.Set(roleCreatedOutsideTerraformAttr, true)
+	return []*schema.ResourceData{d}, nil
+}
+
+// adoptRecreatedRole looks up a role which is no longer found by its ID by name. If a role with
+// that name exists, it was created outside of Terraform: the ID is switched to the new role and
+// the role is flagged, so the next plan replaces it.
+func adoptRecreatedRole(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	roleName := getIdentifier(d, roleNameAttr)
+
+	var roleId string
+	query := "SELECT role_id FROM SVV_ROLES WHERE role_name = $1"
+	err := db.QueryRow(query, roleName).Scan(&roleId)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("error reading role %q: %w", roleName, err)
+	}
+
+	log.Printf("[WARN] Redshift Role %q (%s) was created outside of Terraform with ID %s", roleName, d.Id(), roleId)
+	d.SetId(roleId)
+	d.Set(roleCreatedOutsideTerraformAttr, true)
+	return true, nil
+}
+
+func forceNewIfRoleCreatedOutsideTerraform(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.Get(roleExternalManagedAttr).(bool) || !d.Get(roleCreatedOutsideTerraformAttr).(bool) {
+		return nil
+	}
+
+	if err := d.SetNew(roleCreatedOutsideTerraformAttr, false); err != nil {
+		return err
+	}
+	return d.ForceNew(roleCreatedOutsideTerraformAttr)
+}
 
+// validateRoleNameQuoting rejects role names which need to be quoted in SQL unless quoted is set.
+func validateRoleNameQuoting(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown(roleNameAttr) || d.Get(roleQuotedAttr).(bool) {
+		return nil
+	}
+
+	if name := d.Get(roleNameAttr).(string); identifierNeedsQuoting(name) {
+		return fmt.Errorf("role name %q needs to be quoted in SQL, set `%s = true` to use it anyway", name, roleQuotedAttr)
+	}
+	return nil
+}
+
+func setRoleOwner(tx *transaction, roleName, owner string) error {
+	query := fmt.Sprintf("ALTER ROLE %s OWNER TO %s", pq.QuoteIdentifier(roleName), pq.QuoteIdentifier(owner))
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error changing owner of role %q: %w", roleName, err)
+	}
 	return nil
 }
 
 func resourceRedshiftRoleUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
 	if d.HasChange(roleNameAttr) {
 		oldNameRaw, newNameRaw := d.GetChange(roleNameAttr)
-		oldName := oldNameRaw.(string)
-		newName := newNameRaw.(string)
-
-		tx, err := startTransaction(db.client)
-		if err != nil {
-			return err
-		}
-		defer deferredRollback(tx)
+		oldName := identifierOf(d, oldNameRaw.(string))
+		newName := identifierOf(d, newNameRaw.(string))
 
 		query := fmt.Sprintf("ALTER ROLE %s RENAME TO %s",
 			pq.QuoteIdentifier(oldName),
 			pq.QuoteIdentifier(newName))
-		log.Printf("[DEBUG] %s\n", query)
 
 		if _, err := tx.Exec(query); err != nil {
 			return fmt.Errorf("error renaming role: %w", err)
 		}
+	}
 
-		if err = tx.Commit(); err != nil {
-			return fmt.Errorf("could not commit transaction: %w", err)
+	if d.HasChange(roleOwnerAttr) {
+		if owner, ownerIsSet := d.GetOk(roleOwnerAttr); ownerIsSet {
+			if err := setRoleOwner(tx, getIdentifier(d, roleNameAttr), owner.(string)); err != nil {
+				return err
+			}
 		}
 	}
 
+	if err := setRoleSystemPermissions(tx, d); err != nil {
+		return fmt.Errorf("error updating system permissions of role: %w", err)
+	}
+
+	if err := setRoleExternalID(tx, d); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
 	return resourceRedshiftRoleRead(db, d)
 }
 
+func setRoleExternalID(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChange(roleExternalIDAttr) {
+		return nil
+	}
+
+	query := createRoleCommentQuery(getIdentifier(d, roleNameAttr), d.Get(roleExternalIDAttr).(string))
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error updating external id of role: %w", err)
+	}
+	return nil
+}
+
+// createRoleCommentQuery returns the statement setting the comment of a role, an empty comment removes it.
+func createRoleCommentQuery(roleName, comment string) string {
+	if comment == "" {
+		return fmt.Sprintf("COMMENT ON ROLE %s IS NULL", pq.QuoteIdentifier(roleName))
+	}
+	return fmt.Sprintf("COMMENT ON ROLE %s IS '%s'", pq.QuoteIdentifier(roleName), pqQuoteLiteral(comment))
+}
+
+func setRoleSystemPermissions(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChange(roleSystemPermissionsAttr) {
+		return nil
+	}
+
+	roleName := getIdentifier(d, roleNameAttr)
+	oldPermissionsSet, newPermissionsSet := d.GetChange(roleSystemPermissionsAttr)
+	revokedPermissions := oldPermissionsSet.(*schema.Set).Difference(newPermissionsSet.(*schema.Set))
+	grantedPermissions := newPermissionsSet.(*schema.Set).Difference(oldPermissionsSet.(*schema.Set))
+
+	for _, query := range createRoleSystemPermissionsQueries(roleName, revokedPermissions, grantedPermissions) {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createRoleSystemPermissionsQueries(roleName string, revokedPermissions, grantedPermissions *schema.Set) []string {
+	var queries []string
+	if revokedPermissions.Len() > 0 {
+		queries = append(queries, fmt.Sprintf("REVOKE %s FROM ROLE %s", joinSystemPermissions(revokedPermissions), pq.QuoteIdentifier(roleName)))
+	}
+	if grantedPermissions.Len() > 0 {
+		queries = append(queries, fmt.Sprintf("GRANT %s TO ROLE %s", joinSystemPermissions(grantedPermissions), pq.QuoteIdentifier(roleName)))
+	}
+	return queries
+}
+
+func joinSystemPermissions(permissions *schema.Set) string {
+	var list []string
+	for _, permission := range permissions.List() {
+		list = append(list, permission.(string))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
 func resourceRedshiftRoleDelete(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -142,19 +447,16 @@ func resourceRedshiftRoleDelete(db *DBConnection, d *schema.ResourceData) error
 	// Check if role exists in SVV_ROLES and get role name
 	var roleName string
 	query := "SELECT role_name FROM SVV_ROLES WHERE role_id = $1"
-	log.Printf("[DEBUG] %s, $1=%s\n", query, d.Id())
 	if err := tx.QueryRow(query, d.Id()).Scan(&roleName); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] role with id %s does not exist, already dropped\n", d.Id())
+			return nil
+		}
 		return err
 	}
 
-	if roleName == "" {
-		log.Printf("[WARN] Role with name %s does not exist.\n", d.Id())
-		return nil
-	}
-
 	// Drop the role
 	query = fmt.Sprintf("DROP ROLE %s", pq.QuoteIdentifier(roleName))
-	log.Printf("[DEBUG] %s\n", query)
 
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error dropping role: %w", err)
@@ -1,10 +1,12 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,6 +17,7 @@ const (
 	roleGrantRoleNameAttr    = "role_name"
 	roleGrantGrantToTypeAttr = "grant_to_type"
 	roleGrantGrantToNameAttr = "grant_to_name"
+	roleGrantAdminOptionAttr = "with_admin_option"
 )
 
 func redshiftRoleGrant() *schema.Resource {
@@ -24,27 +27,29 @@ Grants a role to a user or another role. This allows hierarchical role-based acc
 
 When a role is granted to another role, the recipient role inherits all privileges of the granted role. 
 This enables role inheritance chains where permissions can be organized hierarchically.
+To grant several roles to a user, ` + "`redshift_user_role`" + ` can be used instead, but the same grant must not be managed by both resources.
 
 For more information, see [GRANT documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html).
 `,
 		CreateContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftRoleGrantCreate),
 		),
-		ReadContext: ResourceFunc(resourceRedshiftRoleGrantRead),
+		ReadContext: IdempotentResourceFunc(resourceRedshiftRoleGrantRead),
 		DeleteContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftRoleGrantDelete),
 		),
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceRedshiftRoleGrantImport,
 		},
 
 		Schema: map[string]*schema.Schema{
 			roleGrantRoleNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The name of the role to grant.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The name of the role to grant.",
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
 			},
 			roleGrantGrantToTypeAttr: {
 				Type:        schema.TypeString,
@@ -60,19 +65,33 @@ For more information, see [GRANT documentation](https://docs.aws.amazon.com/reds
 				},
 			},
 			roleGrantGrantToNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The name of the user, or role to grant this role to.",
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
+			},
+			preserveCaseAttr: preserveCaseSchema(),
+			roleGrantAdminOptionAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
 				ForceNew:    true,
-				Description: "The name of the user, or role to grant this role to.",
+				Default:     false,
+				Description: "Whether the user may grant the role to others (`WITH ADMIN OPTION`). Only supported when `grant_to_type` is `USER`. Changing it re-issues the grant.",
 			},
 		},
 	}
 }
 
 func resourceRedshiftRoleGrantCreate(db *DBConnection, d *schema.ResourceData) error {
-	roleName := d.Get(roleGrantRoleNameAttr).(string)
+	roleName := getIdentifier(d, roleGrantRoleNameAttr)
 	grantToType := d.Get(roleGrantGrantToTypeAttr).(string)
-	grantToName := d.Get(roleGrantGrantToNameAttr).(string)
+	grantToName := getIdentifier(d, roleGrantGrantToNameAttr)
+	withAdminOption := d.Get(roleGrantAdminOptionAttr).(bool)
+
+	if withAdminOption && grantToType != "USER" {
+		return fmt.Errorf("%q can only be used when %q is 'USER'", roleGrantAdminOptionAttr, roleGrantGrantToTypeAttr)
+	}
 
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -81,7 +100,7 @@ func resourceRedshiftRoleGrantCreate(db *DBConnection, d *schema.ResourceData) e
 	defer deferredRollback(tx)
 
 	// GRANT ROLE syntax in Redshift:
-	// - For USER: GRANT ROLE role TO username (no USER keyword)
+	// - For USER: GRANT ROLE role TO username [WITH ADMIN OPTION] (no USER keyword)
 	// - For ROLE: GRANT ROLE role TO ROLE rolename (ROLE keyword required)
 	var query string
 	switch grantToType {
@@ -89,6 +108,9 @@ func resourceRedshiftRoleGrantCreate(db *DBConnection, d *schema.ResourceData) e
 		query = fmt.Sprintf("GRANT ROLE %s TO %s",
 			pq.QuoteIdentifier(roleName),
 			pq.QuoteIdentifier(grantToName))
+		if withAdminOption {
+			query += " WITH ADMIN OPTION"
+		}
 		break
 	case "ROLE":
 		query = fmt.Sprintf("GRANT ROLE %s TO ROLE %s",
@@ -99,8 +121,6 @@ func resourceRedshiftRoleGrantCreate(db *DBConnection, d *schema.ResourceData) e
 		return fmt.Errorf("unsupported grant_to_type: %s", grantToType)
 	}
 
-	log.Printf("[DEBUG] %s\n", query)
-
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("could not grant role: %w", err)
 	}
@@ -116,18 +136,20 @@ func resourceRedshiftRoleGrantCreate(db *DBConnection, d *schema.ResourceData) e
 }
 
 func resourceRedshiftRoleGrantRead(db *DBConnection, d *schema.ResourceData) error {
-	roleName := d.Get(roleGrantRoleNameAttr).(string)
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+
+	roleName := getIdentifier(d, roleGrantRoleNameAttr)
 	grantToType := d.Get(roleGrantGrantToTypeAttr).(string) // Already lowercase from StateFunc
-	grantToName := d.Get(roleGrantGrantToNameAttr).(string)
+	grantToName := getIdentifier(d, roleGrantGrantToNameAttr)
 
-	var exists int
+	var adminOption bool
 	var query string
 
 	switch grantToType {
 	case "USER":
 		// Check SVV_USER_GRANTS for role grants to users
 		query = `
-			SELECT 1
+			SELECT admin_option
 			FROM SVV_USER_GRANTS
 			WHERE LOWER(role_name) = LOWER($1)
 			AND LOWER(user_name) = LOWER($2)
@@ -137,7 +159,7 @@ func resourceRedshiftRoleGrantRead(db *DBConnection, d *schema.ResourceData) err
 		// Check SVV_ROLE_GRANTS for role grants to other roles
 		// Note: role_name is the grantee (child), granted_role_name is the granted role (parent)
 		query = `
-			SELECT 1
+			SELECT false
 			FROM SVV_ROLE_GRANTS
 			WHERE LOWER(granted_role_name) = LOWER($1)
 			AND LOWER(role_name) = LOWER($2)
@@ -147,9 +169,7 @@ func resourceRedshiftRoleGrantRead(db *DBConnection, d *schema.ResourceData) err
 		return fmt.Errorf("unsupported grant_to_type: %s", grantToType)
 	}
 
-	log.Printf("[DEBUG] %s, $1=%s, $2=%s\n", query, roleName, grantToName)
-
-	err := db.QueryRow(query, roleName, grantToName).Scan(&exists)
+	err := db.QueryRow(query, roleName, grantToName).Scan(&adminOption)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("[WARN] Role grant %s to %s %s not found", roleName, grantToType, grantToName)
@@ -159,13 +179,15 @@ func resourceRedshiftRoleGrantRead(db *DBConnection, d *schema.ResourceData) err
 		return fmt.Errorf("error reading role grant: %w", err)
 	}
 
+	d.Set(roleGrantAdminOptionAttr, adminOption)
+
 	return nil
 }
 
 func resourceRedshiftRoleGrantDelete(db *DBConnection, d *schema.ResourceData) error {
-	roleName := d.Get(roleGrantRoleNameAttr).(string)
+	roleName := getIdentifier(d, roleGrantRoleNameAttr)
 	grantToType := d.Get(roleGrantGrantToTypeAttr).(string) // Already lowercase from StateFunc
-	grantToName := d.Get(roleGrantGrantToNameAttr).(string)
+	grantToName := getIdentifier(d, roleGrantGrantToNameAttr)
 
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -192,8 +214,6 @@ func resourceRedshiftRoleGrantDelete(db *DBConnection, d *schema.ResourceData) e
 		return fmt.Errorf("unsupported grant_to_type: %s", grantToType)
 	}
 
-	log.Printf("[DEBUG] %s\n", query)
-
 	if _, err := tx.Exec(query); err != nil {
 		// If the role or grantee doesn't exist, the grant is already gone
 		if strings.Contains(err.Error(), "does not exist") {
@@ -214,9 +234,46 @@ func resourceRedshiftRoleGrantDelete(db *DBConnection, d *schema.ResourceData) e
 	return nil
 }
 
+// roleGrantIDEscaper escapes the separator of the parts of role grant IDs in names, e.g. of roles
+// federated from an identity provider like AWSIDP:role/analyst, as well as the escape character.
+var roleGrantIDEscaper = strings.NewReplacer("%", "%25", ":", "%3A")
+
 func generateRoleGrantID(roleName, grantToType, grantToName string) string {
 	return fmt.Sprintf("role:%s:%s:%s",
-		strings.ToLower(roleName),
+		roleGrantIDEscaper.Replace(strings.ToLower(roleName)),
 		strings.ToLower(grantToType),
-		strings.ToLower(grantToName))
+		roleGrantIDEscaper.Replace(strings.ToLower(grantToName)))
+}
+
+func parseRoleGrantId(roleGrantId string) (roleName, grantToType, grantToName string, err error) {
+	// ID format: "role:rolename:type:targetname", with ":" and "%" in the names percent-encoded
+	parts := strings.Split(roleGrantId, ":")
+	if len(parts) != 4 {
+		return "", "", "", fmt.Errorf("invalid role grant ID format: %s", roleGrantId)
+	}
+	if roleName, err = url.PathUnescape(parts[1]); err != nil {
+		return "", "", "", fmt.Errorf("invalid role name in role grant ID %s: %w", roleGrantId, err)
+	}
+	grantToType = strings.ToUpper(parts[2])
+	if grantToName, err = url.PathUnescape(parts[3]); err != nil {
+		return "", "", "", fmt.Errorf("invalid grantee name in role grant ID %s: %w", roleGrantId, err)
+	}
+	return roleName, grantToType, grantToName, nil
+}
+
+func resourceRedshiftRoleGrantImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	roleName, grantToType, grantToName, err := parseRoleGrantId(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("%w, expected role:<role_name>:<user|role>:<grant_to_name> and colons in names encoded as %%3A", err)
+	}
+	if grantToType != "USER" && grantToType != "ROLE" {
+		return nil, fmt.Errorf("unsupported grant_to_type %q in role grant ID %q", grantToType, d.Id())
+	}
+
+	d.Set(roleGrantRoleNameAttr, roleName)
+	d.Set(roleGrantGrantToTypeAttr, grantToType)
+	d.Set(roleGrantGrantToNameAttr, grantToName)
+	d.Set(preserveCaseAttr, false)
+
+	return []*schema.ResourceData{d}, nil
 }
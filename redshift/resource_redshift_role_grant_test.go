@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -62,6 +63,62 @@ resource "redshift_role_grant" "role" {
 					resource.TestCheckResourceAttr("redshift_role_grant.role", "grant_to_name", secondRoleName),
 				),
 			},
+			{
+				ResourceName:      "redshift_role_grant.user",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "redshift_role_grant.role",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccRedshiftRoleGrant_MixedCaseNames(t *testing.T) {
+	randomObjectName := generateRandomObjectName("acc_test_role_grant")
+	roleName := strings.ToUpper(randomObjectName)
+	userName := strings.ToUpper(fmt.Sprintf("%s_user", randomObjectName))
+
+	// The grant refers to the role and the user by literal names in a different case than the
+	// one stored by Redshift, which must neither fail nor show a diff after the apply.
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+	name = "%[1]s"
+}
+
+resource "redshift_user" "user" {
+	name = "%[2]s"
+}
+
+resource "redshift_role_grant" "user" {
+	role_name     = "%[1]s"
+	grant_to_type = "USER"
+	grant_to_name = "%[2]s"
+
+	depends_on = [redshift_role.role, redshift_user.user]
+}
+`, roleName, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleExists(strings.ToLower(roleName)),
+					resource.TestCheckResourceAttr("redshift_role.role", "name", strings.ToLower(roleName)),
+					testAccCheckRedshiftRoleGrantExists("user", strings.ToLower(userName), strings.ToLower(roleName)),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
 		},
 	})
 }
@@ -237,14 +294,157 @@ func checkRoleGrantExists(client *Client, roleGrantId string) (bool, error) {
 	return true, nil
 }
 
-func parseRoleGrantId(roleGrantId string) (roleName, grantToType, grantToName string, err error) {
-	// ID format: "role:rolename:type:targetname"
-	parts := strings.Split(roleGrantId, ":")
-	if len(parts) != 4 {
-		return "", "", "", fmt.Errorf("invalid role grant ID format: %s", roleGrantId)
+func TestAccRedshiftRoleGrant_AdminOption(t *testing.T) {
+	roleName := generateRandomObjectName("acc_test_role_grant_admin")
+	userName := fmt.Sprintf("%s_user", roleName)
+
+	config := func(withAdminOption bool) string {
+		return fmt.Sprintf(`
+resource "redshift_role" "role" {
+	name = %[1]q
+}
+
+resource "redshift_user" "user" {
+	name = %[2]q
+}
+
+resource "redshift_role_grant" "admin" {
+	role_name         = redshift_role.role.name
+	grant_to_type     = "USER"
+	grant_to_name     = redshift_user.user.name
+	with_admin_option = %[3]t
+}`, roleName, userName, withAdminOption)
 	}
-	roleName = parts[1]
-	grantToType = strings.ToUpper(parts[2])
-	grantToName = parts[3]
-	return roleName, grantToType, grantToName, nil
+	grantID := generateRoleGrantID(roleName, "USER", userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleName),
+					resource.TestCheckResourceAttr("redshift_role_grant.admin", "id", grantID),
+					resource.TestCheckResourceAttr("redshift_role_grant.admin", "with_admin_option", "true"),
+				),
+			},
+			{
+				Config: config(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleName),
+					resource.TestCheckResourceAttr("redshift_role_grant.admin", "id", grantID),
+					resource.TestCheckResourceAttr("redshift_role_grant.admin", "with_admin_option", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceRedshiftRoleGrantImport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftRoleGrant().Schema, map[string]interface{}{})
+	d.SetId("role:analyst:user:alice")
+
+	if _, err := resourceRedshiftRoleGrantImport(t.Context(), d, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for attr, expected := range map[string]string{
+		roleGrantRoleNameAttr:    "analyst",
+		roleGrantGrantToTypeAttr: "USER",
+		roleGrantGrantToNameAttr: "alice",
+	} {
+		if got := d.Get(attr).(string); got != expected {
+			t.Errorf("expected %s to be %q, got %q", attr, expected, got)
+		}
+	}
+
+	for _, id := range []string{"analyst", "role:analyst:user", "role:analyst:user:alice:bob", "role:analyst:group:analysts", "role:analyst%zz:user:alice"} {
+		d.SetId(id)
+		if _, err := resourceRedshiftRoleGrantImport(t.Context(), d, nil); err == nil {
+			t.Errorf("expected an error importing %q", id)
+		}
+	}
+}
+
+func TestRoleGrantID_NamesWithSeparator(t *testing.T) {
+	tests := map[string]struct {
+		roleName, grantToType, grantToName string
+		expectedID                         string
+	}{
+		"plain names": {
+			roleName: "analyst", grantToType: "USER", grantToName: "alice",
+			expectedID: "role:analyst:user:alice",
+		},
+		"identity provider role": {
+			roleName: "AWSIDP:role/foo", grantToType: "USER", grantToName: "alice",
+			expectedID: "role:awsidp%3Arole/foo:user:alice",
+		},
+		"identity provider grantee": {
+			roleName: "analyst", grantToType: "ROLE", grantToName: "AWSIDP:role/foo",
+			expectedID: "role:analyst:role:awsidp%3Arole/foo",
+		},
+		"escape character": {
+			roleName: "100%:role", grantToType: "USER", grantToName: "alice",
+			expectedID: "role:100%25%3Arole:user:alice",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			id := generateRoleGrantID(tt.roleName, tt.grantToType, tt.grantToName)
+			if id != tt.expectedID {
+				t.Errorf("generateRoleGrantID() = %q, want %q", id, tt.expectedID)
+			}
+
+			roleName, grantToType, grantToName, err := parseRoleGrantId(id)
+			if err != nil {
+				t.Fatalf("unexpected error parsing %q: %v", id, err)
+			}
+			if roleName != strings.ToLower(tt.roleName) || grantToType != tt.grantToType || grantToName != strings.ToLower(tt.grantToName) {
+				t.Errorf("parseRoleGrantId(%q) = %q, %q, %q", id, roleName, grantToType, grantToName)
+			}
+		})
+	}
+}
+
+func TestAccRedshiftRoleGrant_IdentityProviderRole(t *testing.T) {
+	roleName := fmt.Sprintf("%s:role/analyst", generateRandomObjectName("tf_acc_idp"))
+	userName := generateRandomObjectName("tf_acc_idp_user")
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+	name   = %[1]q
+	quoted = true
+}
+
+resource "redshift_user" "user" {
+	name = %[2]q
+}
+
+resource "redshift_role_grant" "idp" {
+	role_name     = redshift_role.role.name
+	grant_to_type = "USER"
+	grant_to_name = redshift_user.user.name
+}
+`, roleName, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleName),
+					resource.TestCheckResourceAttr("redshift_role_grant.idp", "id", generateRoleGrantID(roleName, "USER", userName)),
+				),
+			},
+			{
+				ResourceName:      "redshift_role_grant.idp",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
 }
@@ -1,15 +1,21 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 	"text/template"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 var (
@@ -57,6 +63,133 @@ func TestAccRedshiftRole_Basic(t *testing.T) {
 	})
 }
 
+func TestAccRedshiftRole_DeleteDroppedOutOfBand(t *testing.T) {
+	roleName := generateRandomObjectName("tf_acc_role_dropped")
+	config := fmt.Sprintf(`
+resource "redshift_role" "dropped" {
+  name = %[1]q
+}
+`, roleName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeleteAfterDroppedOutOfBand("redshift_role.dropped", "redshift_role", fmt.Sprintf("DROP ROLE %s", pq.QuoteIdentifier(roleName))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftRole_ReservedSysPrefix(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "redshift_role" "reserved" {
+  name = "sys:my_role"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("reserved for Amazon Redshift system roles"),
+			},
+		},
+	})
+}
+
+func TestRedshiftRole_NameValidation(t *testing.T) {
+	validateName := redshiftRole().Schema[roleNameAttr].ValidateFunc
+	tests := map[string]bool{
+		"my_role":                true,
+		"my_sys:role":            true,
+		"sys:dba":                false,
+		"SYS:DBA":                false,
+		"_my_role":               true,
+		"__my_role":              false,
+		strings.Repeat("r", 127): true,
+		strings.Repeat("r", 128): false,
+		strings.Repeat("ü", 63):  true,
+		strings.Repeat("ü", 64):  false,
+		"":                       false,
+	}
+
+	for name, valid := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, errs := validateName(name, roleNameAttr)
+			if valid && len(errs) > 0 {
+				t.Errorf("Expected role name %q to be valid but got %v", name, errs)
+			}
+			if !valid && len(errs) == 0 {
+				t.Errorf("Expected role name %q to be rejected", name)
+			}
+		})
+	}
+}
+
+func TestIdentifierNeedsQuoting(t *testing.T) {
+	tests := map[string]bool{
+		"my_role":          false,
+		"_my_role":         false,
+		"MyRole":           false,
+		"role$1":           false,
+		"rôle":             false,
+		"1role":            true,
+		"$role":            true,
+		"my role":          true,
+		"my-role":          true,
+		"role@example.com": true,
+		"aad:analysts":     true,
+		"select":           true,
+		"SELECT":           true,
+	}
+
+	for name, needsQuoting := range tests {
+		t.Run(name, func(t *testing.T) {
+			if result := identifierNeedsQuoting(name); result != needsQuoting {
+				t.Errorf("Expected identifierNeedsQuoting(%q) to be %v", name, needsQuoting)
+			}
+		})
+	}
+}
+
+func TestAccRedshiftRole_QuotedName(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role@tf_acc_domain.tld"), "-", "_")
+	config := func(quoted bool) string {
+		return fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name   = %q
+  quoted = %t
+}
+`, roleName, quoted)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(false),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("needs to be quoted in SQL"),
+			},
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleExists(roleName),
+					resource.TestCheckResourceAttr("redshift_role.role", "name", roleName),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftRole_Update(t *testing.T) {
 	roleName := generateRandomObjectName("acc_test_u")
 	roleNameUpdate := fmt.Sprintf("%s_updated", roleName)
@@ -71,6 +204,8 @@ resource "redshift_role" "role" {
 name = "%s"
 }`, roleNameUpdate)
 
+	// Renames must update the role in place, the role is tracked by its role_id.
+	var roleID string
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: testAccProviders,
@@ -81,6 +216,7 @@ name = "%s"
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftRoleExists(roleName),
 					resource.TestCheckResourceAttr("redshift_role.role", "name", roleName),
+					testAccCheckResourceIDUnchanged("redshift_role.role", &roleID),
 				),
 			},
 			{
@@ -88,13 +224,21 @@ name = "%s"
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftRoleExists(roleNameUpdate),
 					resource.TestCheckResourceAttr("redshift_role.role", "name", roleNameUpdate),
+					testAccCheckResourceIDUnchanged("redshift_role.role", &roleID),
 				),
 			},
+			{
+				ResourceName:            "redshift_role.role",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"created_outside_terraform"},
+			},
 			{
 				Config: configCreate,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftRoleExists(roleName),
 					resource.TestCheckResourceAttr("redshift_role.role", "name", roleName),
+					testAccCheckResourceIDUnchanged("redshift_role.role", &roleID),
 				),
 			},
 		},
@@ -157,3 +301,413 @@ func checkRoleExists(client *Client, role string) (bool, error) {
 
 	return true, nil
 }
+
+func TestAccRedshiftRole_SystemPermissions(t *testing.T) {
+	roleName := generateRandomObjectName("acc_test_sp")
+
+	config := func(permissions ...string) string {
+		return fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name               = %q
+  system_permissions = %s
+}`, roleName, "["+quotePrivileges(permissions)+"]")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("CREATE USER", "ACCESS SYSTEM TABLE"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleExists(roleName),
+					resource.TestCheckResourceAttr("redshift_role.role", "system_permissions.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_role.role", "system_permissions.*", "CREATE USER"),
+					resource.TestCheckTypeSetElemAttr("redshift_role.role", "system_permissions.*", "ACCESS SYSTEM TABLE"),
+				),
+			},
+			{
+				Config: config("ACCESS SYSTEM TABLE", "DROP USER"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_role.role", "system_permissions.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_role.role", "system_permissions.*", "ACCESS SYSTEM TABLE"),
+					resource.TestCheckTypeSetElemAttr("redshift_role.role", "system_permissions.*", "DROP USER"),
+				),
+			},
+			{
+				Config: config("DROP USER"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_role.role", "system_permissions.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_role.role", "system_permissions.*", "DROP USER"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftRole_SystemPermissionsNotManaged(t *testing.T) {
+	roleName := generateRandomObjectName("acc_test_sp")
+
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %q
+}`, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleExists(roleName),
+					resource.TestCheckResourceAttr("redshift_role.role", "system_permissions.#", "0"),
+				),
+			},
+			{
+				// Permissions granted by other means are read but not revoked.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("GRANT CREATE USER TO ROLE %s", pq.QuoteIdentifier(roleName)))
+						return err
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_role.role", "system_permissions.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_role.role", "system_permissions.*", "CREATE USER"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestCreateRoleSystemPermissionsQueries(t *testing.T) {
+	toSet := func(permissions ...string) *schema.Set {
+		set := schema.NewSet(schema.HashString, nil)
+		for _, permission := range permissions {
+			set.Add(permission)
+		}
+		return set
+	}
+
+	tests := map[string]struct {
+		revoked  *schema.Set
+		granted  *schema.Set
+		expected []string
+	}{
+		"no changes": {
+			revoked: toSet(),
+			granted: toSet(),
+		},
+		"grant only": {
+			revoked:  toSet(),
+			granted:  toSet("DROP USER", "CREATE USER"),
+			expected: []string{`GRANT CREATE USER, DROP USER TO ROLE "admin"`},
+		},
+		"revoke and grant": {
+			revoked: toSet("ACCESS SYSTEM TABLE"),
+			granted: toSet("CREATE SCHEMA"),
+			expected: []string{
+				`REVOKE ACCESS SYSTEM TABLE FROM ROLE "admin"`,
+				`GRANT CREATE SCHEMA TO ROLE "admin"`,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			queries := createRoleSystemPermissionsQueries("admin", tt.revoked, tt.granted)
+			if len(queries) != len(tt.expected) {
+				t.Fatalf("Expected %d queries but got %d: %v", len(tt.expected), len(queries), queries)
+			}
+			for i := range queries {
+				if queries[i] != tt.expected[i] {
+					t.Errorf("Expected query %q but got %q", tt.expected[i], queries[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAccRedshiftRole_Owner(t *testing.T) {
+	roleName := generateRandomObjectName("acc_test_owner")
+	userName := generateRandomObjectName("acc_test_owner_user")
+
+	config := fmt.Sprintf(`
+resource "redshift_user" "owner" {
+  name = %[2]q
+}
+
+resource "redshift_role" "role" {
+  name  = %[1]q
+  owner = redshift_user.owner.name
+}`, roleName, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleExists(roleName),
+					resource.TestCheckResourceAttr("redshift_role.role", "owner", userName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftRole_ExternalID(t *testing.T) {
+	roleName := generateRandomObjectName("acc_test_external_id")
+	config := func(externalID string) string {
+		return fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name        = %[1]q
+  external_id = %[2]q
+}`, roleName, externalID)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("CMDB-'4711'"),
+				Check:  resource.TestCheckResourceAttr("redshift_role.role", "external_id", "CMDB-'4711'"),
+			},
+			{
+				Config: config("CMDB-4712"),
+				Check:  resource.TestCheckResourceAttr("redshift_role.role", "external_id", "CMDB-4712"),
+			},
+			{
+				// A comment set outside of Terraform is read back as drift.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(createRoleCommentQuery(roleName, "CMDB-4713"))
+						return err
+					})
+				},
+				Config:             config("CMDB-4712"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config("CMDB-4712"),
+				Check:  resource.TestCheckResourceAttr("redshift_role.role", "external_id", "CMDB-4712"),
+			},
+			{
+				ResourceName:      "redshift_role.role",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %q
+}`, roleName),
+				Check: resource.TestCheckResourceAttr("redshift_role.role", "external_id", ""),
+			},
+		},
+	})
+}
+
+func TestCreateRoleCommentQuery(t *testing.T) {
+	tests := map[string]struct {
+		comment  string
+		expected string
+	}{
+		"comment": {
+			comment:  "CMDB-4711",
+			expected: `COMMENT ON ROLE "analysts" IS 'CMDB-4711'`,
+		},
+		"quotes are escaped": {
+			comment:  `CMDB 'prod' \ 4711`,
+			expected: `COMMENT ON ROLE "analysts" IS 'CMDB ''prod'' \\ 4711'`,
+		},
+		"empty comment removes it": {
+			comment:  "",
+			expected: `COMMENT ON ROLE "analysts" IS NULL`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := createRoleCommentQuery("analysts", tt.comment); got != tt.expected {
+				t.Errorf("createRoleCommentQuery() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRedshiftRole_CreatedOutsideTerraform(t *testing.T) {
+	tests := map[string]struct {
+		externalManaged bool
+		createdOutside  string
+		requiresNew     bool
+	}{
+		"imported, external managed":     {externalManaged: true, createdOutside: "true", requiresNew: true},
+		"imported, not external managed": {externalManaged: false, createdOutside: "true", requiresNew: false},
+		"created by terraform":           {externalManaged: true, createdOutside: "false", requiresNew: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := map[string]string{
+				roleNameAttr:                     "my_role",
+				roleSystemPermissionsAttr + ".#": "0",
+				roleCreatedOutsideTerraformAttr:  tt.createdOutside,
+			}
+			config := map[string]interface{}{roleNameAttr: "my_role", roleExternalManagedAttr: tt.externalManaged}
+			defaults := map[string]interface{}{preserveCaseAttr: false, roleQuotedAttr: false, roleExternalManagedAttr: false}
+
+			if diff := planUpgradedState(t, redshiftRole(), state, config, defaults); diff.RequiresNew() != tt.requiresNew {
+				t.Errorf("Expected replacement to be %t but got %#v", tt.requiresNew, diff.Attributes)
+			}
+		})
+	}
+}
+
+func TestRedshiftRoleImport_CreatedOutsideTerraform(t *testing.T) {
+	d := redshiftRole().Data(&terraform.InstanceState{ID: "100"})
+
+	imported, err := resourceRedshiftRoleImport(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !imported[0].Get(roleCreatedOutsideTerraformAttr).(bool) {
+		t.Errorf("Expected imported roles to be flagged as created outside of Terraform")
+	}
+}
+
+func TestAccRedshiftRole_ExternalManagedRecreated(t *testing.T) {
+	roleName := generateRandomObjectName("acc_test_ext")
+
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name             = %q
+  external_managed = true
+}`, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleExists(roleName),
+					resource.TestCheckResourceAttr("redshift_role.role", "created_outside_terraform", "false"),
+				),
+			},
+			{
+				// Drop and create the role again outside of Terraform: the role gets replaced.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						if _, err := db.Exec(fmt.Sprintf("DROP ROLE %s", pq.QuoteIdentifier(roleName))); err != nil {
+							return err
+						}
+						_, err := db.Exec(fmt.Sprintf("CREATE ROLE %s", pq.QuoteIdentifier(roleName)))
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleExists(roleName),
+					resource.TestCheckResourceAttr("redshift_role.role", "created_outside_terraform", "false"),
+				),
+			},
+		},
+	})
+}
+
+// roleCatalogDriver answers the queries of readRoleExternalID with the value configured for the catalog table
+// they read from, tables without a value return no row.
+type roleCatalogDriver struct {
+	values map[string]driver.Value
+}
+
+func (d roleCatalogDriver) Open(string) (driver.Conn, error) {
+	return roleCatalogConn(d), nil
+}
+
+type roleCatalogConn struct {
+	values map[string]driver.Value
+}
+
+func (roleCatalogConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (roleCatalogConn) Close() error { return nil }
+
+func (roleCatalogConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (c roleCatalogConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	for table, value := range c.values {
+		if strings.Contains(query, "FROM "+table) {
+			return &flakyRows{values: []driver.Value{value}}, nil
+		}
+	}
+	return &flakyRows{}, nil
+}
+
+func TestReadRoleExternalID(t *testing.T) {
+	tests := map[string]struct {
+		values             map[string]driver.Value
+		expectedExternalID string
+		expectedFound      bool
+	}{
+		"comment": {
+			values:             map[string]driver.Value{"pg_class": int64(1260), "pg_description": "CMDB-4711"},
+			expectedExternalID: "CMDB-4711",
+			expectedFound:      true,
+		},
+		"no comment": {
+			values:        map[string]driver.Value{"pg_class": int64(1260)},
+			expectedFound: true,
+		},
+		"catalog of roles not visible": {
+			values:        map[string]driver.Value{"pg_description": "CMDB-4711"},
+			expectedFound: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			driverName := "redshift-test-role-catalog-" + t.Name()
+			sql.Register(driverName, roleCatalogDriver{tt.values})
+			pool, err := sql.Open(driverName, "")
+			if err != nil {
+				t.Fatalf("unexpected error opening the database: %v", err)
+			}
+			defer pool.Close()
+			db := &DBConnection{DB: pool, client: &Client{ctx: context.Background()}}
+
+			externalID, found, err := readRoleExternalID(db, "100")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if externalID != tt.expectedExternalID || found != tt.expectedFound {
+				t.Errorf("Expected external id %q (found %t) but got %q (found %t)", tt.expectedExternalID, tt.expectedFound, externalID, found)
+			}
+		})
+	}
+}
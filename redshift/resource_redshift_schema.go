@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,8 +20,10 @@ const (
 	schemaNameAttr            = "name"
 	schemaOwnerAttr           = "owner"
 	schemaQuotaAttr           = "quota"
+	schemaQuotaUnitAttr       = "quota_unit"
 	schemaCascadeOnDeleteAttr = "cascade_on_delete"
 	schemaExternalSchemaAttr  = "external_schema"
+	schemaCommentAttr         = "comment"
 	dataCatalogAttr           = "external_schema.0.data_catalog_source.0"
 	hiveMetastoreAttr         = "external_schema.0.hive_metastore_source.0"
 	rdsPostgresAttr           = "external_schema.0.rds_postgres_source.0"
@@ -27,13 +31,20 @@ const (
 	redshiftAttr              = "external_schema.0.redshift_source.0"
 )
 
+// schemaQuotaUnits maps the units of schema quotas to their size in MB, the unit Redshift stores quotas in.
+var schemaQuotaUnits = map[string]int{
+	"MB": 1,
+	"GB": 1024,
+	"TB": 1024 * 1024,
+}
+
 func redshiftSchema() *schema.Resource {
 	return &schema.Resource{
 		Description: `
 A database contains one or more named schemas. Each schema in a database contains tables and other kinds of named objects. By default, a database has a single schema, which is named PUBLIC. You can use schemas to group database objects under a common name. Schemas are similar to file system directories, except that schemas cannot be nested.
 `,
 		CreateContext: ResourceFunc(resourceRedshiftSchemaCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftSchemaRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftSchemaRead),
 		UpdateContext: ResourceFunc(resourceRedshiftSchemaUpdate),
 		DeleteContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftSchemaDelete),
@@ -67,11 +78,18 @@ A database contains one or more named schemas. Each schema in a database contain
 				Type:         schema.TypeInt,
 				Optional:     true,
 				Default:      0,
-				Description:  "The maximum amount of disk space that the specified schema can use. GB is the default unit of measurement.",
+				Description:  "The maximum amount of disk space that the specified schema can use, in the unit of `quota_unit`. `0` means unlimited.",
 				ValidateFunc: validation.IntAtLeast(0),
-				StateFunc: func(val interface{}) string {
-					return fmt.Sprintf("%d", val.(int)*1024)
+				ConflictsWith: []string{
+					schemaExternalSchemaAttr,
 				},
+			},
+			schemaQuotaUnitAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "GB",
+				Description:  "The unit of `quota`, one of `MB`, `GB` or `TB`. If the quota in Redshift is no whole multiple of the unit, e.g. because it was changed outside of Terraform, it is read in `MB` and the configured quota is applied again.",
+				ValidateFunc: validation.StringInSlice(slices.Sorted(maps.Keys(schemaQuotaUnits)), false),
 				ConflictsWith: []string{
 					schemaExternalSchemaAttr,
 				},
@@ -84,6 +102,11 @@ A database contains one or more named schemas. Each schema in a database contain
 					schemaExternalSchemaAttr,
 				},
 			},
+			schemaCommentAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The comment of the schema, e.g. for data catalog metadata. Removing it removes the comment in Redshift.",
+			},
 			schemaExternalSchemaAttr: {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -91,6 +114,7 @@ A database contains one or more named schemas. Each schema in a database contain
 				MaxItems:    1,
 				ConflictsWith: []string{
 					schemaQuotaAttr,
+					schemaQuotaUnitAttr,
 					schemaCascadeOnDeleteAttr,
 				},
 				Elem: &schema.Resource{
@@ -405,20 +429,24 @@ func resourceRedshiftSchemaRead(db *DBConnection, d *schema.ResourceData) error
 }
 
 func resourceRedshiftSchemaReadImpl(db *DBConnection, d *schema.ResourceData) error {
-	var schemaOwner, schemaName, schemaType string
+	var schemaName, schemaType string
+	var schemaOwnerID int
+	var schemaOwner sql.NullString
 
-	// Step 1: get basic schema info
+	// Step 1: get basic schema info. The owner is resolved from pg_namespace, so an owner changed outside of
+	// Terraform shows up as a diff and is reassigned on the next apply.
 	err := db.QueryRow(`
 			SELECT
 				TRIM(svv_all_schemas.schema_name),
+				pg_namespace.nspowner,
 				TRIM(pg_user_info.usename),
 				TRIM(svv_all_schemas.schema_type)
 			FROM svv_all_schemas
 			INNER JOIN pg_namespace ON (svv_all_schemas.database_name = $1 AND svv_all_schemas.schema_name = pg_namespace.nspname)
 	LEFT JOIN pg_user_info
-		ON (svv_all_schemas.database_name = $1 AND pg_user_info.usesysid = svv_all_schemas.schema_owner)
+		ON (pg_user_info.usesysid = pg_namespace.nspowner)
 	WHERE svv_all_schemas.database_name = $1
-	AND pg_namespace.oid = $2`, db.client.config.Database, d.Id()).Scan(&schemaName, &schemaOwner, &schemaType)
+	AND pg_namespace.oid = $2`, db.client.config.Database, d.Id()).Scan(&schemaName, &schemaOwnerID, &schemaOwner, &schemaType)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("[WARN] Redshift Schema (%s) not found", d.Id())
@@ -427,8 +455,24 @@ func resourceRedshiftSchemaReadImpl(db *DBConnection, d *schema.ResourceData) er
 		}
 		return err
 	}
+	if !schemaOwner.Valid {
+		return fmt.Errorf("schema %q is owned by user id %d, which no longer exists: reassign it with ALTER SCHEMA %s OWNER TO <user>", schemaName, schemaOwnerID, pq.QuoteIdentifier(schemaName))
+	}
 	d.Set(schemaNameAttr, schemaName)
-	d.Set(schemaOwnerAttr, schemaOwner)
+	d.Set(schemaOwnerAttr, schemaOwner.String)
+
+	var schemaComment string
+	err = db.QueryRow(`
+	SELECT COALESCE(description, '')
+	FROM pg_description
+	WHERE objoid = $1
+	AND classoid = (SELECT oid FROM pg_class WHERE relname = 'pg_namespace')
+	AND objsubid = 0`, d.Id()).Scan(&schemaComment)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("could not read comment of schema %q: %w", schemaName, err)
+	}
+	d.Set(schemaCommentAttr, schemaComment)
+
 	switch schemaType {
 	case "local":
 		return resourceRedshiftSchemaReadLocal(db, d)
@@ -440,7 +484,7 @@ func resourceRedshiftSchemaReadImpl(db *DBConnection, d *schema.ResourceData) er
 }
 
 func resourceRedshiftSchemaReadLocal(db *DBConnection, d *schema.ResourceData) error {
-	var schemaQuota = 0
+	var schemaQuotaMB = 0
 	isServerless, err := db.client.config.IsServerless(db)
 	if err != nil {
 		return err
@@ -452,19 +496,21 @@ func resourceRedshiftSchemaReadLocal(db *DBConnection, d *schema.ResourceData) e
 			FROM svv_redshift_schema_quota
 			WHERE database_name = $1 
 			  AND schema_name = $2
-		`, db.client.config.Database, d.Get(schemaNameAttr)).Scan(&schemaQuota)
+		`, db.client.config.Database, d.Get(schemaNameAttr)).Scan(&schemaQuotaMB)
 	} else {
 		err = db.QueryRow(`
 			SELECT
 			COALESCE(quota, 0)
 			FROM svv_schema_quota_state
 			WHERE schema_id = $1
-		`, d.Id()).Scan(&schemaQuota)
+		`, d.Id()).Scan(&schemaQuotaMB)
 	}
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
+	schemaQuota, schemaQuotaUnit := schemaQuotaInUnit(schemaQuotaMB, d.Get(schemaQuotaUnitAttr).(string))
 	d.Set(schemaQuotaAttr, schemaQuota)
+	d.Set(schemaQuotaUnitAttr, schemaQuotaUnit)
 	d.Set(schemaExternalSchemaAttr, nil)
 
 	return nil
@@ -584,10 +630,11 @@ func resourceRedshiftSchemaDelete(db *DBConnection, d *schema.ResourceData) erro
 		cascadeOrRestrict = "CASCADE"
 	}
 
-	query := fmt.Sprintf("DROP SCHEMA %s %s", pq.QuoteIdentifier(schemaName), cascadeOrRestrict)
+	query := fmt.Sprintf("DROP SCHEMA IF EXISTS %s %s", pq.QuoteIdentifier(schemaName), cascadeOrRestrict)
 	if _, err := tx.Exec(query); err != nil {
 		return err
 	}
+	tx.invalidateID(idCacheKindSchema, schemaName)
 
 	return tx.Commit()
 }
@@ -608,6 +655,12 @@ func resourceRedshiftSchemaCreate(db *DBConnection, d *schema.ResourceData) erro
 		return err
 	}
 
+	if comment := d.Get(schemaCommentAttr).(string); comment != "" {
+		if _, err := tx.Exec(createSchemaCommentQuery(d.Get(schemaNameAttr).(string), comment)); err != nil {
+			return fmt.Errorf("could not set comment of schema: %w", err)
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -615,20 +668,15 @@ func resourceRedshiftSchemaCreate(db *DBConnection, d *schema.ResourceData) erro
 	return resourceRedshiftSchemaReadImpl(db, d)
 }
 
-func resourceRedshiftSchemaCreateInternal(tx *sql.Tx, d *schema.ResourceData) error {
+func resourceRedshiftSchemaCreateInternal(tx *transaction, d *schema.ResourceData) error {
 	schemaName := d.Get(schemaNameAttr).(string)
-	schemaQuota := d.Get(schemaQuotaAttr).(int)
 	var createOpts []string
 
 	if v, ok := d.GetOk(schemaOwnerAttr); ok {
 		createOpts = append(createOpts, fmt.Sprintf("AUTHORIZATION %s", pq.QuoteIdentifier(v.(string))))
 	}
 
-	quotaValue := "QUOTA UNLIMITED"
-	if schemaQuota > 0 {
-		quotaValue = fmt.Sprintf("QUOTA %d GB", schemaQuota)
-	}
-	createOpts = append(createOpts, quotaValue)
+	createOpts = append(createOpts, fmt.Sprintf("QUOTA %s", formatSchemaQuota(d)))
 
 	query := fmt.Sprintf("CREATE SCHEMA %s %s", pq.QuoteIdentifier(schemaName), strings.Join(createOpts, " "))
 
@@ -636,17 +684,17 @@ func resourceRedshiftSchemaCreateInternal(tx *sql.Tx, d *schema.ResourceData) er
 		return err
 	}
 
-	var schemaOID string
-	if err := tx.QueryRow("SELECT oid FROM pg_namespace WHERE nspname = $1", strings.ToLower(schemaName)).Scan(&schemaOID); err != nil {
+	schemaOID, err := getSchemaIDFromName(tx, schemaName)
+	if err != nil {
 		return err
 	}
 
-	d.SetId(schemaOID)
+	d.SetId(strconv.Itoa(schemaOID))
 
 	return nil
 }
 
-func resourceRedshiftSchemaCreateExternal(tx *sql.Tx, d *schema.ResourceData) error {
+func resourceRedshiftSchemaCreateExternal(tx *transaction, d *schema.ResourceData) error {
 	schemaName := d.Get(schemaNameAttr).(string)
 	query := fmt.Sprintf("CREATE EXTERNAL SCHEMA %s", pq.QuoteIdentifier(schemaName))
 	sourceDbName := d.Get(fmt.Sprintf("%s.0.%s", schemaExternalSchemaAttr, "database_name")).(string)
@@ -672,25 +720,23 @@ func resourceRedshiftSchemaCreateExternal(tx *sql.Tx, d *schema.ResourceData) er
 
 	query = fmt.Sprintf("%s %s", query, configQuery)
 
-	log.Printf("[DEBUG] creating external schema: %s\n", query)
 	if _, err := tx.Exec(query); err != nil {
 		return err
 	}
 
 	if v, ok := d.GetOk(schemaOwnerAttr); ok {
 		query = fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(v.(string)))
-		log.Printf("[DEBUG] setting schema owner: %s\n", query)
 		if _, err := tx.Exec(query); err != nil {
 			return err
 		}
 	}
 
-	var schemaOID string
-	if err := tx.QueryRow("SELECT oid FROM pg_namespace WHERE nspname = $1", strings.ToLower(schemaName)).Scan(&schemaOID); err != nil {
+	schemaOID, err := getSchemaIDFromName(tx, schemaName)
+	if err != nil {
 		return err
 	}
 
-	d.SetId(schemaOID)
+	d.SetId(strconv.Itoa(schemaOID))
 
 	return nil
 }
@@ -803,6 +849,10 @@ func resourceRedshiftSchemaUpdate(db *DBConnection, d *schema.ResourceData) erro
 		return err
 	}
 
+	if err := setSchemaComment(tx, d); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -810,7 +860,7 @@ func resourceRedshiftSchemaUpdate(db *DBConnection, d *schema.ResourceData) erro
 	return resourceRedshiftSchemaReadImpl(db, d)
 }
 
-func setSchemaName(tx *sql.Tx, d *schema.ResourceData) error {
+func setSchemaName(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(schemaNameAttr) {
 		return nil
 	}
@@ -827,11 +877,12 @@ func setSchemaName(tx *sql.Tx, d *schema.ResourceData) error {
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating schema NAME: %w", err)
 	}
+	tx.invalidateID(idCacheKindSchema, oldValue)
 
 	return nil
 }
 
-func setSchemaOwner(tx *sql.Tx, _ *DBConnection, d *schema.ResourceData) error {
+func setSchemaOwner(tx *transaction, _ *DBConnection, d *schema.ResourceData) error {
 	if !d.HasChange(schemaOwnerAttr) {
 		return nil
 	}
@@ -839,23 +890,58 @@ func setSchemaOwner(tx *sql.Tx, _ *DBConnection, d *schema.ResourceData) error {
 	schemaName := d.Get(schemaNameAttr).(string)
 	schemaOwner := d.Get(schemaOwnerAttr).(string)
 
-	_, err := tx.Exec(fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(schemaOwner)))
-	return err
+	if _, err := tx.Exec(fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(schemaOwner))); err != nil {
+		return fmt.Errorf("could not change the owner of schema %q to %q: %w", schemaName, schemaOwner, err)
+	}
+	return nil
 }
 
-func setSchemaQuota(tx *sql.Tx, d *schema.ResourceData) error {
-	if !d.HasChange(schemaQuotaAttr) {
+func setSchemaQuota(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChanges(schemaQuotaAttr, schemaQuotaUnitAttr) {
 		return nil
 	}
 
 	schemaName := d.Get(schemaNameAttr).(string)
+
+	_, err := tx.Exec(fmt.Sprintf("ALTER SCHEMA %s QUOTA %s", pq.QuoteIdentifier(schemaName), formatSchemaQuota(d)))
+	return err
+}
+
+// formatSchemaQuota returns the configured quota as used by CREATE SCHEMA and ALTER SCHEMA, e.g. 10 GB.
+func formatSchemaQuota(d *schema.ResourceData) string {
 	schemaQuota := d.Get(schemaQuotaAttr).(int)
+	if schemaQuota == 0 {
+		return "UNLIMITED"
+	}
+	return fmt.Sprintf("%d %s", schemaQuota, d.Get(schemaQuotaUnitAttr).(string))
+}
 
-	quotaValue := "UNLIMITED"
-	if schemaQuota > 0 {
-		quotaValue = fmt.Sprintf("%d GB", schemaQuota)
+// schemaQuotaInUnit converts a quota in MB, as stored by Redshift, to the given unit.
+// Quotas which are no whole multiple of the unit are returned in MB to keep the difference visible.
+func schemaQuotaInUnit(quotaMB int, unit string) (int, string) {
+	size, ok := schemaQuotaUnits[unit]
+	if !ok || quotaMB%size != 0 {
+		return quotaMB, "MB"
 	}
+	return quotaMB / size, unit
+}
 
-	_, err := tx.Exec(fmt.Sprintf("ALTER SCHEMA %s QUOTA %s", pq.QuoteIdentifier(schemaName), quotaValue))
-	return err
+func setSchemaComment(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChange(schemaCommentAttr) {
+		return nil
+	}
+
+	query := createSchemaCommentQuery(d.Get(schemaNameAttr).(string), d.Get(schemaCommentAttr).(string))
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error updating schema COMMENT: %w", err)
+	}
+	return nil
+}
+
+// createSchemaCommentQuery returns the statement setting the comment of a schema, an empty comment removes it.
+func createSchemaCommentQuery(schemaName, comment string) string {
+	if comment == "" {
+		return fmt.Sprintf("COMMENT ON SCHEMA %s IS NULL", pq.QuoteIdentifier(schemaName))
+	}
+	return fmt.Sprintf("COMMENT ON SCHEMA %s IS '%s'", pq.QuoteIdentifier(schemaName), pqQuoteLiteral(comment))
 }
@@ -32,7 +32,8 @@ func TestAccRedshiftSchema_Basic(t *testing.T) {
 
 					testAccCheckRedshiftSchemaExists("schema_configured"),
 					resource.TestCheckResourceAttr("redshift_schema.schema_configured", "name", "schema_configured"),
-					resource.TestCheckResourceAttr("redshift_schema.schema_configured", "quota", "15360"),
+					resource.TestCheckResourceAttr("redshift_schema.schema_configured", "quota", "15"),
+					resource.TestCheckResourceAttr("redshift_schema.schema_configured", "quota_unit", "GB"),
 					resource.TestCheckResourceAttr("redshift_schema.schema_configured", "cascade_on_delete", "false"),
 
 					testAccCheckRedshiftSchemaExists("wOoOT_I22_@tH15"),
@@ -43,6 +44,28 @@ func TestAccRedshiftSchema_Basic(t *testing.T) {
 	})
 }
 
+func TestAccRedshiftSchema_DeleteDroppedOutOfBand(t *testing.T) {
+	schemaName := generateRandomObjectName("tf_acc_schema_dropped")
+	config := fmt.Sprintf(`
+resource "redshift_schema" "dropped" {
+  name = %[1]q
+}
+`, schemaName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeleteAfterDroppedOutOfBand("redshift_schema.dropped", "redshift_schema", fmt.Sprintf("DROP SCHEMA %s", schemaName)),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftSchema_Update(t *testing.T) {
 
 	var configCreate = `
@@ -84,7 +107,7 @@ resource "redshift_user" "schema_user1" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftSchemaExists("update_schema2"),
 					resource.TestCheckResourceAttr("redshift_schema.update_schema", "name", "update_schema2"),
-					resource.TestCheckResourceAttr("redshift_schema.update_schema", "quota", "10240"),
+					resource.TestCheckResourceAttr("redshift_schema.update_schema", "quota", "10"),
 				),
 			},
 			{
@@ -99,6 +122,174 @@ resource "redshift_user" "schema_user1" {
 	})
 }
 
+func TestAccRedshiftSchema_OwnerDrift(t *testing.T) {
+	schemaName := generateRandomObjectName("tf_acc_schema_owner")
+	ownerName := generateRandomObjectName("tf_acc_schema_owner")
+	otherName := generateRandomObjectName("tf_acc_schema_other")
+	config := fmt.Sprintf(`
+resource "redshift_user" "owner" {
+  name = %[2]q
+}
+
+resource "redshift_user" "other" {
+  name = %[3]q
+}
+
+resource "redshift_schema" "owned" {
+  name  = %[1]q
+  owner = redshift_user.owner.name
+}
+`, schemaName, ownerName, otherName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.owned", "owner", ownerName),
+					testAccCheckRedshiftSchemaOwner(schemaName, ownerName),
+				),
+			},
+			{
+				// An owner changed outside of Terraform shows up as a diff.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", schemaName, otherName))
+						return err
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.owned", "owner", ownerName),
+					testAccCheckRedshiftSchemaOwner(schemaName, ownerName),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftSchemaOwner(schemaName, expectedOwner string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var owner string
+		err = db.QueryRow(`
+			SELECT TRIM(pg_user_info.usename)
+			FROM pg_namespace
+			JOIN pg_user_info ON pg_user_info.usesysid = pg_namespace.nspowner
+			WHERE pg_namespace.nspname = $1`, strings.ToLower(schemaName)).Scan(&owner)
+		if err != nil {
+			return fmt.Errorf("could not read the owner of schema %q: %w", schemaName, err)
+		}
+		if owner != expectedOwner {
+			return fmt.Errorf("expected schema %q to be owned by %q, but it is owned by %q", schemaName, expectedOwner, owner)
+		}
+		return nil
+	}
+}
+
+func TestAccRedshiftSchema_QuotaUnits(t *testing.T) {
+	schemaName := generateRandomObjectName("tf_acc_schema_quota")
+	config := func(quota int, unit string) string {
+		return fmt.Sprintf(`
+resource "redshift_schema" "quota" {
+  name       = %[1]q
+  quota      = %[2]d
+  quota_unit = %[3]q
+}
+`, schemaName, quota, unit)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(500, "MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota", "500"),
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota_unit", "MB"),
+				),
+			},
+			{
+				Config: config(1, "TB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota", "1"),
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota_unit", "TB"),
+				),
+			},
+			{
+				Config: config(2, "GB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota", "2"),
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota_unit", "GB"),
+				),
+			},
+			{
+				// A quota changed outside of Terraform which is no whole number of GB is applied again.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("ALTER SCHEMA %s QUOTA 1536 MB", schemaName))
+						return err
+					})
+				},
+				Config: config(2, "GB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota", "2"),
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota_unit", "GB"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "redshift_schema" "quota" {
+  name = %[1]q
+}
+`, schemaName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.quota", "quota", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestSchemaQuotaInUnit(t *testing.T) {
+	tests := []struct {
+		quotaMB       int
+		unit          string
+		expectedQuota int
+		expectedUnit  string
+	}{
+		{0, "GB", 0, "GB"},
+		{10240, "GB", 10, "GB"},
+		{10240, "MB", 10240, "MB"},
+		{2 * 1024 * 1024, "TB", 2, "TB"},
+		{1536, "GB", 1536, "MB"},
+		{1024 * 1024, "GB", 1024, "GB"},
+		{1536 * 1024, "TB", 1536 * 1024, "MB"},
+		{500, "", 500, "MB"},
+	}
+	for _, tt := range tests {
+		quota, unit := schemaQuotaInUnit(tt.quotaMB, tt.unit)
+		if quota != tt.expectedQuota || unit != tt.expectedUnit {
+			t.Errorf("schemaQuotaInUnit(%d, %q) = %d %s, want %d %s", tt.quotaMB, tt.unit, quota, unit, tt.expectedQuota, tt.expectedUnit)
+		}
+	}
+}
+
 func TestAccRedshiftSchema_UpdateComplex(t *testing.T) {
 	var configCreate = `
 resource "redshift_schema" "update_dl_schema" {
@@ -135,7 +326,7 @@ resource "redshift_user" "schema_dl_user1" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftSchemaExists("update_dl_schema2"),
 					resource.TestCheckResourceAttr("redshift_schema.update_dl_schema", "name", "update_dl_schema2"),
-					resource.TestCheckResourceAttr("redshift_schema.update_dl_schema", "quota", "10240"),
+					resource.TestCheckResourceAttr("redshift_schema.update_dl_schema", "quota", "10"),
 				),
 			},
 			{
@@ -150,6 +341,73 @@ resource "redshift_user" "schema_dl_user1" {
 	})
 }
 
+func TestAccRedshiftSchema_Comment(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_comment"), "-", "_")
+	config := func(comment string) string {
+		return fmt.Sprintf(`
+resource "redshift_schema" "commented" {
+  name    = %[1]q
+  comment = %[2]q
+}
+`, schemaName, comment)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("Sales data, owned by the 'finance' team"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftSchemaExists(schemaName),
+					resource.TestCheckResourceAttr("redshift_schema.commented", "comment", "Sales data, owned by the 'finance' team"),
+				),
+			},
+			{
+				Config: config("Sales data"),
+				Check:  resource.TestCheckResourceAttr("redshift_schema.commented", "comment", "Sales data"),
+			},
+			{
+				Config: config(""),
+				Check:  resource.TestCheckResourceAttr("redshift_schema.commented", "comment", ""),
+			},
+			{
+				ResourceName:      "redshift_schema.commented",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCreateSchemaCommentQuery(t *testing.T) {
+	tests := map[string]struct {
+		comment  string
+		expected string
+	}{
+		"comment": {
+			comment:  "Sales data",
+			expected: `COMMENT ON SCHEMA "my_schema" IS 'Sales data'`,
+		},
+		"quotes are escaped": {
+			comment:  `owned by 'finance' \ reporting`,
+			expected: `COMMENT ON SCHEMA "my_schema" IS 'owned by ''finance'' \\ reporting'`,
+		},
+		"empty comment removes it": {
+			comment:  "",
+			expected: `COMMENT ON SCHEMA "my_schema" IS NULL`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := createSchemaCommentQuery("my_schema", tt.comment); got != tt.expected {
+				t.Errorf("createSchemaCommentQuery() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 // Acceptance test for external redshift schema using AWS Glue Data Catalog
 // The following environment variables must be set, otherwise the test will be skipped:
 //
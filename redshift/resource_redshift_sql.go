@@ -0,0 +1,151 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	sqlCreateAttr  = "create_sql"
+	sqlDestroyAttr = "destroy_sql"
+	sqlReadAttr    = "read_sql"
+	sqlUpdateAttr  = "update_sql"
+	sqlResultAttr  = "result"
+)
+
+func redshiftSQL() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Runs arbitrary SQL statements, for DDL which isn't covered by the other resources, e.g. ` + "`CREATE MODEL`" + `. Prefer the typed resources wherever possible: the provider can't validate the statements, and they are only run as configured.
+
+The statements are run in a transaction. They are not retried on errors, as they aren't necessarily idempotent. Both ` + "`create_sql`" + ` and ` + "`destroy_sql`" + ` must be given, nothing is dropped implicitly.
+`,
+		CreateContext: ResourceFunc(resourceRedshiftSQLCreate),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftSQLRead),
+		UpdateContext: ResourceFunc(resourceRedshiftSQLUpdate),
+		DeleteContext: ResourceFunc(resourceRedshiftSQLDelete),
+		CustomizeDiff: customdiff.All(
+			// Without update_sql a changed create_sql can only be applied by destroying and creating the objects again.
+			customdiff.ForceNewIf(sqlCreateAttr, func(_ context.Context, d *schema.ResourceDiff, _ interface{}) bool {
+				return d.Get(sqlUpdateAttr).(string) == ""
+			}),
+			// The result is only known once the statements and the changed query were run.
+			func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+				if d.Id() != "" && d.HasChanges(sqlCreateAttr, sqlUpdateAttr, sqlReadAttr) {
+					return d.SetNewComputed(sqlResultAttr)
+				}
+				return nil
+			},
+		),
+
+		Schema: map[string]*schema.Schema{
+			sqlCreateAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The SQL run when the resource is created. A change replaces the resource, unless `update_sql` is set.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			sqlDestroyAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The SQL run when the resource is destroyed, e.g. dropping the objects created by `create_sql`.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			sqlReadAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "A query returning a single column, which is run after each apply and refresh. The first value is stored in `result`. If the query returns no row or `NULL`, the objects are considered deleted and the resource is created again.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			sqlUpdateAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The SQL run instead of replacing the resource when `create_sql` or `update_sql` change.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			sqlResultAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The value returned by `read_sql`.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftSQLCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := execSQLStatement(db, d.Get(sqlCreateAttr).(string)); err != nil {
+		return fmt.Errorf("could not run %s: %w", sqlCreateAttr, err)
+	}
+
+	d.SetId(id.UniqueId())
+
+	return resourceRedshiftSQLRead(db, d)
+}
+
+func resourceRedshiftSQLRead(db *DBConnection, d *schema.ResourceData) error {
+	query := d.Get(sqlReadAttr).(string)
+	if query == "" {
+		d.Set(sqlResultAttr, "")
+		return nil
+	}
+
+	var result sql.NullString
+	err := db.QueryRow(query).Scan(&result)
+	switch {
+	case errors.Is(err, sql.ErrNoRows), err == nil && !result.Valid:
+		log.Printf("[WARN] %s of redshift_sql %s returned no value, the resource is created again", sqlReadAttr, d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not run %s: %w", sqlReadAttr, err)
+	}
+
+	d.Set(sqlResultAttr, result.String)
+
+	return nil
+}
+
+func resourceRedshiftSQLUpdate(db *DBConnection, d *schema.ResourceData) error {
+	// Removing update_sql alone changes nothing in the database.
+	if updateSQL := d.Get(sqlUpdateAttr).(string); updateSQL != "" && d.HasChanges(sqlCreateAttr, sqlUpdateAttr) {
+		if err := execSQLStatement(db, updateSQL); err != nil {
+			return fmt.Errorf("could not run %s: %w", sqlUpdateAttr, err)
+		}
+	}
+
+	return resourceRedshiftSQLRead(db, d)
+}
+
+func resourceRedshiftSQLDelete(db *DBConnection, d *schema.ResourceData) error {
+	if err := execSQLStatement(db, d.Get(sqlDestroyAttr).(string)); err != nil {
+		return fmt.Errorf("could not run %s: %w", sqlDestroyAttr, err)
+	}
+
+	return nil
+}
+
+// execSQLStatement runs a statement of the configuration in a transaction.
+func execSQLStatement(db *DBConnection, statement string) error {
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if _, err := tx.Exec(statement); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftSQL_Basic(t *testing.T) {
+	tableName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_sql"), "-", "_")
+	config := func(columns string, withUpdate bool) string {
+		updateSQL := ""
+		if withUpdate {
+			updateSQL = fmt.Sprintf(`update_sql = "ALTER TABLE public.%[1]s ADD COLUMN name varchar(64)"`, tableName)
+		}
+		return fmt.Sprintf(`
+resource "redshift_sql" "table" {
+  create_sql  = "CREATE TABLE public.%[1]s (%[2]s)"
+  destroy_sql = "DROP TABLE public.%[1]s"
+  read_sql    = "SELECT COUNT(*)::varchar FROM pg_table_def WHERE schemaname = 'public' AND tablename = '%[1]s'"
+  %[3]s
+}
+`, tableName, columns, updateSQL)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftSQLTableDestroy(tableName),
+		Steps: []resource.TestStep{
+			{
+				Config: config("id int", false),
+				Check:  resource.TestCheckResourceAttr("redshift_sql.table", "result", "1"),
+			},
+			{
+				// Without update_sql the table is replaced.
+				Config: config("id int, created_at timestamp", false),
+				Check:  resource.TestCheckResourceAttr("redshift_sql.table", "result", "2"),
+			},
+			{
+				Config: config("id int, created_at timestamp, name varchar(64)", true),
+				Check:  resource.TestCheckResourceAttr("redshift_sql.table", "result", "3"),
+			},
+			{
+				// The table dropped outside of Terraform is created again.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("DROP TABLE public.%s", tableName))
+						return err
+					})
+				},
+				Config: config("id int, created_at timestamp, name varchar(64)", true),
+				Check:  resource.TestCheckResourceAttr("redshift_sql.table", "result", "3"),
+			},
+			{
+				// Removing update_sql runs no statement.
+				Config: config("id int, created_at timestamp, name varchar(64)", false),
+				Check:  resource.TestCheckResourceAttr("redshift_sql.table", "result", "3"),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftSQLTableDestroy(tableName string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM pg_tables WHERE schemaname = 'public' AND tablename = $1", tableName).Scan(&count); err != nil {
+			return fmt.Errorf("error checking table %s: %w", tableName, err)
+		}
+		if count > 0 {
+			return fmt.Errorf("table %s still exists after destroy", tableName)
+		}
+		return nil
+	}
+}
+
+func TestRedshiftSQL_ReplaceUnlessUpdateSQL(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "some-id",
+		Attributes: map[string]string{
+			"id":          "some-id",
+			"create_sql":  "CREATE TABLE t (id int)",
+			"destroy_sql": "DROP TABLE t",
+		},
+	}
+
+	tests := map[string]struct {
+		config          map[string]interface{}
+		expectedReplace bool
+	}{
+		"create_sql changed": {
+			config: map[string]interface{}{
+				"create_sql":  "CREATE TABLE t (id bigint)",
+				"destroy_sql": "DROP TABLE t",
+			},
+			expectedReplace: true,
+		},
+		"create_sql changed with update_sql": {
+			config: map[string]interface{}{
+				"create_sql":  "CREATE TABLE t (id int, name varchar)",
+				"destroy_sql": "DROP TABLE t",
+				"update_sql":  "ALTER TABLE t ADD COLUMN name varchar",
+			},
+			expectedReplace: false,
+		},
+		"destroy_sql changed": {
+			config: map[string]interface{}{
+				"create_sql":  "CREATE TABLE t (id int)",
+				"destroy_sql": "DROP TABLE t CASCADE",
+			},
+			expectedReplace: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			diff, err := redshiftSQL().Diff(context.Background(), state, terraform.NewResourceConfigRaw(tt.config), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff.RequiresNew() != tt.expectedReplace {
+				t.Errorf("RequiresNew() = %t, want %t", diff.RequiresNew(), tt.expectedReplace)
+			}
+		})
+	}
+}
+
+func TestRedshiftSQL_ResultComputedOnChange(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "some-id",
+		Attributes: map[string]string{
+			"id":          "some-id",
+			"create_sql":  "CREATE TABLE t (id int)",
+			"destroy_sql": "DROP TABLE t",
+			"read_sql":    "SELECT count(*) FROM t",
+			"result":      "0",
+		},
+	}
+
+	tests := map[string]struct {
+		config           map[string]interface{}
+		expectedComputed bool
+	}{
+		"read_sql changed": {
+			config: map[string]interface{}{
+				"create_sql":  "CREATE TABLE t (id int)",
+				"destroy_sql": "DROP TABLE t",
+				"read_sql":    "SELECT max(id) FROM t",
+			},
+			expectedComputed: true,
+		},
+		"create_sql changed with update_sql": {
+			config: map[string]interface{}{
+				"create_sql":  "CREATE TABLE t (id int, name varchar)",
+				"destroy_sql": "DROP TABLE t",
+				"read_sql":    "SELECT count(*) FROM t",
+				"update_sql":  "ALTER TABLE t ADD COLUMN name varchar",
+			},
+			expectedComputed: true,
+		},
+		"destroy_sql changed": {
+			config: map[string]interface{}{
+				"create_sql":  "CREATE TABLE t (id int)",
+				"destroy_sql": "DROP TABLE t CASCADE",
+				"read_sql":    "SELECT count(*) FROM t",
+			},
+			expectedComputed: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			diff, err := redshiftSQL().Diff(context.Background(), state, terraform.NewResourceConfigRaw(tt.config), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			attr, ok := diff.Attributes[sqlResultAttr]
+			computed := ok && attr.NewComputed
+			if computed != tt.expectedComputed {
+				t.Errorf("result computed = %t, want %t", computed, tt.expectedComputed)
+			}
+		})
+	}
+}
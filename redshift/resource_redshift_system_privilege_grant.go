@@ -0,0 +1,184 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	systemPrivilegeGrantPrivilegeAttr   = "privilege"
+	systemPrivilegeGrantGranteeTypeAttr = "grantee_type"
+	systemPrivilegeGrantGranteeNameAttr = "grantee_name"
+)
+
+// systemPrivilegeGranteeTypes lists the types of grantees system privileges can be granted to,
+// Redshift only grants them to roles.
+var systemPrivilegeGranteeTypes = []string{"role"}
+
+func redshiftSystemPrivilegeGrant() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Grants a system privilege, e.g. ` + "`CREATE USER`" + `, to a role. Use ` + "`for_each`" + ` to grant the same privilege to several roles.
+
+Note: the roles must not set the ` + "`system_permissions`" + ` attribute of the ` + "`redshift_role`" + ` resource, which revokes all system privileges of the role not listed there. Roles which leave it out only read their system privileges.
+
+For more information, see [GRANT documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html).
+`,
+		CreateContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftSystemPrivilegeGrantCreate),
+		),
+		ReadContext: IdempotentResourceFunc(resourceRedshiftSystemPrivilegeGrantRead),
+		DeleteContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftSystemPrivilegeGrantDelete),
+		),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedshiftSystemPrivilegeGrantImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			systemPrivilegeGrantPrivilegeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The system privilege to grant, e.g. `CREATE USER` or `ACCESS SYSTEM TABLE`. The privilege must be given in upper case.",
+				ValidateFunc: validation.StringInSlice(roleSystemPermissions, false),
+			},
+			systemPrivilegeGrantGranteeTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "role",
+				Description:  "The type of the grantee. Redshift only grants system privileges to roles, so `role` is the only valid value.",
+				ValidateFunc: validation.StringInSlice(systemPrivilegeGranteeTypes, false),
+			},
+			systemPrivilegeGrantGranteeNameAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The name of the role to grant the privilege to.",
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
+			},
+			preserveCaseAttr: preserveCaseSchema(),
+		},
+	}
+}
+
+func resourceRedshiftSystemPrivilegeGrantCreate(db *DBConnection, d *schema.ResourceData) error {
+	privilege := d.Get(systemPrivilegeGrantPrivilegeAttr).(string)
+	granteeType := d.Get(systemPrivilegeGrantGranteeTypeAttr).(string)
+	granteeName := getIdentifier(d, systemPrivilegeGrantGranteeNameAttr)
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("GRANT %s TO %s %s", privilege, strings.ToUpper(granteeType), pq.QuoteIdentifier(granteeName))
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not grant system privilege %s to %s %q: %w", privilege, granteeType, granteeName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateSystemPrivilegeGrantID(privilege, granteeType, granteeName))
+
+	return resourceRedshiftSystemPrivilegeGrantRead(db, d)
+}
+
+func resourceRedshiftSystemPrivilegeGrantRead(db *DBConnection, d *schema.ResourceData) error {
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+
+	privilege := d.Get(systemPrivilegeGrantPrivilegeAttr).(string)
+	granteeType := d.Get(systemPrivilegeGrantGranteeTypeAttr).(string)
+	granteeName := getIdentifier(d, systemPrivilegeGrantGranteeNameAttr)
+
+	query := "SELECT 1 FROM svv_system_privileges WHERE identity_type = $1 AND identity_name = $2 AND UPPER(system_privilege) = $3"
+
+	var granted int
+	if err := db.QueryRow(query, granteeType, granteeName, privilege).Scan(&granted); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] system privilege %s of %s %q not found", privilege, granteeType, granteeName)
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error reading system privilege %s of %s %q: %w", privilege, granteeType, granteeName, err)
+	}
+
+	return nil
+}
+
+func resourceRedshiftSystemPrivilegeGrantDelete(db *DBConnection, d *schema.ResourceData) error {
+	privilege := d.Get(systemPrivilegeGrantPrivilegeAttr).(string)
+	granteeType := d.Get(systemPrivilegeGrantGranteeTypeAttr).(string)
+	granteeName := getIdentifier(d, systemPrivilegeGrantGranteeNameAttr)
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("REVOKE %s FROM %s %s", privilege, strings.ToUpper(granteeType), pq.QuoteIdentifier(granteeName))
+
+	if _, err := tx.Exec(query); err != nil {
+		// If the grantee doesn't exist, the grant is already gone
+		if strings.Contains(err.Error(), "does not exist") {
+			log.Printf("[WARN] %s %q does not exist, system privilege already revoked: %v", granteeType, granteeName, err)
+			return nil
+		}
+		return fmt.Errorf("could not revoke system privilege %s from %s %q: %w", privilege, granteeType, granteeName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func generateSystemPrivilegeGrantID(privilege, granteeType, granteeName string) string {
+	return fmt.Sprintf("%s:%s:%s", strings.ToLower(granteeType), strings.ToUpper(privilege), granteeName)
+}
+
+func parseSystemPrivilegeGrantID(id string) (privilege, granteeType, granteeName string, err error) {
+	// ID format: "grantee_type:PRIVILEGE:grantee_name", the grantee name comes last as it may contain colons
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid system privilege grant ID format: %s", id)
+	}
+	return strings.ToUpper(parts[1]), strings.ToLower(parts[0]), parts[2], nil
+}
+
+func resourceRedshiftSystemPrivilegeGrantImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	privilege, granteeType, granteeName, err := parseSystemPrivilegeGrantID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("%w, expected <grantee_type>:<privilege>:<grantee_name>", err)
+	}
+	if !slices.Contains(systemPrivilegeGranteeTypes, granteeType) {
+		return nil, fmt.Errorf("unsupported grantee type %q in system privilege grant ID %q", granteeType, d.Id())
+	}
+	if !slices.Contains(roleSystemPermissions, privilege) {
+		return nil, fmt.Errorf("unsupported system privilege %q in system privilege grant ID %q", privilege, d.Id())
+	}
+
+	d.Set(systemPrivilegeGrantPrivilegeAttr, privilege)
+	d.Set(systemPrivilegeGrantGranteeTypeAttr, granteeType)
+	d.Set(systemPrivilegeGrantGranteeNameAttr, granteeName)
+	d.Set(preserveCaseAttr, false)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,162 @@
+package redshift
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftSystemPrivilegeGrant_Basic(t *testing.T) {
+	roleName := generateRandomObjectName("acc_test_system_privilege_grant")
+	secondRoleName := fmt.Sprintf("%s_second", roleName)
+
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  for_each = toset([%[1]q, %[2]q])
+
+  name = each.key
+}
+
+resource "redshift_system_privilege_grant" "create_user" {
+  for_each = redshift_role.role
+
+  privilege    = "CREATE USER"
+  grantee_name = each.value.name
+}
+`, roleName, secondRoleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftSystemPrivilegeGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftSystemPrivilegeGrantExists("CREATE USER", roleName),
+					testAccCheckRedshiftSystemPrivilegeGrantExists("CREATE USER", secondRoleName),
+					resource.TestCheckResourceAttr(fmt.Sprintf("redshift_system_privilege_grant.create_user[%q]", roleName), "privilege", "CREATE USER"),
+					resource.TestCheckResourceAttr(fmt.Sprintf("redshift_system_privilege_grant.create_user[%q]", roleName), "grantee_type", "role"),
+					resource.TestCheckResourceAttr(fmt.Sprintf("redshift_system_privilege_grant.create_user[%q]", roleName), "grantee_name", roleName),
+				),
+			},
+			{
+				// The roles read the granted privileges without planning to revoke them.
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      fmt.Sprintf("redshift_system_privilege_grant.create_user[%q]", roleName),
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftSystemPrivilegeGrantDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_system_privilege_grant" {
+			continue
+		}
+
+		privilege, _, granteeName, err := parseSystemPrivilegeGrantID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		exists, err := checkSystemPrivilegeGrantExists(client, privilege, granteeName)
+		if err != nil {
+			return fmt.Errorf("error checking system privilege grant: %w", err)
+		}
+
+		if exists {
+			return fmt.Errorf("system privilege grant %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckRedshiftSystemPrivilegeGrantExists(privilege, roleName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+
+		exists, err := checkSystemPrivilegeGrantExists(client, privilege, roleName)
+		if err != nil {
+			return fmt.Errorf("error checking system privilege grant: %w", err)
+		}
+
+		if !exists {
+			return fmt.Errorf("system privilege %s of role %s not found", privilege, roleName)
+		}
+
+		return nil
+	}
+}
+
+func checkSystemPrivilegeGrantExists(client *Client, privilege, roleName string) (bool, error) {
+	db, err := client.Connect()
+	if err != nil {
+		return false, err
+	}
+
+	var resp int
+	query := "SELECT 1 FROM svv_system_privileges WHERE identity_type = 'role' AND identity_name = $1 AND UPPER(system_privilege) = $2"
+	err = db.QueryRow(query, roleName, privilege).Scan(&resp)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("error reading system privileges of role: %w", err)
+	}
+
+	return true, nil
+}
+
+func TestResourceRedshiftSystemPrivilegeGrantImport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftSystemPrivilegeGrant().Schema, map[string]interface{}{})
+	d.SetId("role:ACCESS SYSTEM TABLE:ops:admins")
+
+	if _, err := resourceRedshiftSystemPrivilegeGrantImport(t.Context(), d, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for attr, expected := range map[string]string{
+		systemPrivilegeGrantPrivilegeAttr:   "ACCESS SYSTEM TABLE",
+		systemPrivilegeGrantGranteeTypeAttr: "role",
+		systemPrivilegeGrantGranteeNameAttr: "ops:admins",
+	} {
+		if got := d.Get(attr).(string); got != expected {
+			t.Errorf("expected %s to be %q, got %q", attr, expected, got)
+		}
+	}
+
+	for _, id := range []string{"role", "role:CREATE USER", "role::admins", "role:CREATE USER:", "user:CREATE USER:alice", "role:CREATE EVERYTHING:admins"} {
+		d.SetId(id)
+		if _, err := resourceRedshiftSystemPrivilegeGrantImport(t.Context(), d, nil); err == nil {
+			t.Errorf("expected an error importing %q", id)
+		}
+	}
+}
+
+func TestGenerateSystemPrivilegeGrantID(t *testing.T) {
+	id := generateSystemPrivilegeGrantID("create user", "ROLE", "Admins")
+	if expected := "role:CREATE USER:Admins"; id != expected {
+		t.Errorf("generateSystemPrivilegeGrantID() = %q, want %q", id, expected)
+	}
+
+	privilege, granteeType, granteeName, err := parseSystemPrivilegeGrantID(id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if privilege != "CREATE USER" || granteeType != "role" || granteeName != "Admins" {
+		t.Errorf("parseSystemPrivilegeGrantID(%q) = %q, %q, %q", id, privilege, granteeType, granteeName)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -16,14 +18,18 @@ import (
 )
 
 const (
-	userNameAttr           = "name"
-	userPasswordAttr       = "password"
-	userValidUntilAttr     = "valid_until"
-	userCreateDBAttr       = "create_database"
-	userConnLimitAttr      = "connection_limit"
-	userSyslogAccessAttr   = "syslog_access"
-	userSuperuserAttr      = "superuser"
-	userSessionTimeoutAttr = "session_timeout"
+	userNameAttr            = "name"
+	userPasswordAttr        = "password"
+	userValidUntilAttr      = "valid_until"
+	userCreateDBAttr        = "create_database"
+	userConnLimitAttr       = "connection_limit"
+	userSyslogAccessAttr    = "syslog_access"
+	userSuperuserAttr       = "superuser"
+	userSessionTimeoutAttr  = "session_timeout"
+	userReassignOwnedToAttr = "reassign_owned_to"
+	userGroupsAttr          = "groups"
+	userConfigParamsAttr    = "config_parameters"
+	userSearchPathAttr      = "search_path"
 
 	// defaults
 	defaultUserSyslogAccess          = "RESTRICTED"
@@ -36,18 +42,35 @@ const (
 // See https://docs.aws.amazon.com/redshift/latest/APIReference/API_GetClusterCredentials.html
 var temporaryCredentialsUsernamePrefixRegexp = regexp.MustCompile("^(?:IAMA?:)")
 
+// configParameterNameRegexp matches the names of configuration parameters, which can't be quoted in ALTER USER ... SET.
+var configParameterNameRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_.]*$`)
+
+// md5PasswordHashRegexp matches MD5 password hashes, which are salted with the user name and so only valid for one name.
+var md5PasswordHashRegexp = regexp.MustCompile("^md5[0-9a-f]{32}$")
+
 // Resolve the "real" username by stripping the temporary credentials prefix
 func permanentUsername(username string) string {
 	return temporaryCredentialsUsernamePrefixRegexp.ReplaceAllString(username, "")
 }
 
+// getConnectedUsername returns the name of the user the provider is connected as, without the prefix of temporary credentials.
+func getConnectedUsername(db *DBConnection) (string, error) {
+	username, err := db.client.config.GetUsername(db)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving username: %w", err)
+	}
+	return permanentUsername(username), nil
+}
+
 func redshiftUser() *schema.Resource {
 	return &schema.Resource{
 		Description: `
 Amazon Redshift user accounts can only be created and dropped by a database superuser. Users are authenticated when they login to Amazon Redshift. They can own databases and database objects (for example, tables) and can grant privileges on those objects to users, groups, and schemas to control who has access to which object. Users with CREATE DATABASE rights can create databases and grant privileges to those databases. Superusers have database ownership privileges for all databases.
+
+Redshift clears the password of a user when renaming it. Renaming a user therefore sets the configured password again in the same transaction, so the user can keep logging in. An MD5 hash is salted with the user name, so renaming a user whose password is set as MD5 hash requires the hash for the new name.
 `,
 		CreateContext: ResourceFunc(resourceRedshiftUserCreate),
-		ReadContext:   ResourceFunc(resourceRedshiftUserRead),
+		ReadContext:   IdempotentResourceFunc(resourceRedshiftUserRead),
 		UpdateContext: ResourceFunc(resourceRedshiftUserUpdate),
 		DeleteContext: ResourceFunc(
 			ResourceRetryOnPQErrors(resourceRedshiftUserDelete),
@@ -64,12 +87,23 @@ Amazon Redshift user accounts can only be created and dropped by a database supe
 				return fmt.Errorf("users that are superusers must define a password")
 			}
 
+			if d.Id() != "" && d.HasChange(userNameAttr) && !d.HasChange(userPasswordAttr) && md5PasswordHashRegexp.MatchString(password.(string)) {
+				oldName, newName := d.GetChange(userNameAttr)
+				return fmt.Errorf("the MD5 password hash of user %q is salted with its name and can't be reused after renaming it to %q, set the password to its hash for the new name", oldName, newName)
+			}
+
 			isSyslogAccessKnown := d.NewValueKnown(userSyslogAccessAttr)
 			syslogAccess, hasSyslogAccess := d.GetOk(userSyslogAccessAttr)
 			if isSuperuser && isSyslogAccessKnown && hasSyslogAccess && syslogAccess != defaultUserSuperuserSyslogAccess {
 				return fmt.Errorf("superusers must have syslog access set to %q", defaultUserSuperuserSyslogAccess)
 			}
 
+			if _, hasSearchPath := d.GetOk(userSearchPathAttr); hasSearchPath {
+				if _, ok := d.Get(userConfigParamsAttr).(map[string]interface{})[userSearchPathAttr]; ok {
+					return fmt.Errorf("%q can't be set in both %q and %q, remove it from %q", userSearchPathAttr, userSearchPathAttr, userConfigParamsAttr, userConfigParamsAttr)
+				}
+			}
+
 			return nil
 		},
 
@@ -77,7 +111,7 @@ Amazon Redshift user accounts can only be created and dropped by a database supe
 			userNameAttr: {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The name of the user account to create. The user name can't be `PUBLIC`.",
+				Description: "The name of the user account to create. The user name can't be `PUBLIC`. Changing it renames the user in place, keeping its privileges and group memberships.",
 				ValidateFunc: validation.StringNotInSlice([]string{
 					"public",
 				}, true),
@@ -135,6 +169,46 @@ Amazon Redshift user accounts can only be created and dropped by a database supe
 				Description:  "The maximum time in seconds that a session remains inactive or idle. The range is 60 seconds (one minute) to 1,728,000 seconds (20 days). If no session timeout is set for the user, the cluster setting applies.",
 				ValidateFunc: validation.All(validation.IntAtLeast(60), validation.IntAtMost(1728000)),
 			},
+			userReassignOwnedToAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the user which takes over the ownership of the databases, schemas, tables, views and functions owned by this user when it is dropped. Defaults to the user the provider is connected as.",
+				ValidateFunc: validation.StringNotInSlice([]string{
+					"public",
+				}, true),
+			},
+			userGroupsAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					StateFunc: func(val interface{}) string {
+						return strings.ToLower(val.(string))
+					},
+				},
+				Set:         schema.HashString,
+				Description: "The names of the groups the user is a member of. If not set, the memberships of the user are left untouched, set it to an empty set to remove the user from all groups. Note: this attribute conflicts with the `users` attribute of the `redshift_group` resource and with the `redshift_group_membership` resource.",
+			},
+			userConfigParamsAttr: {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				ValidateFunc:     validateConfigParameters,
+				DiffSuppressFunc: suppressConfigParameterDiff,
+				Description:      "The session defaults of the user, e.g. `query_group`, `search_path` or `statement_timeout`, which are set with `ALTER USER ... SET`. Parameters which are removed from the map are reset to the cluster defaults. If not set or empty, the parameters of the user are left untouched. List values like `search_path` are given separated by commas.",
+			},
+			userSearchPathAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The schemas in the default search path of the user, e.g. `[\"$user\", \"public\"]`. If not set, the cluster default applies. Can't be combined with `search_path` in `config_parameters`.",
+			},
 		},
 	}
 }
@@ -177,7 +251,7 @@ func resourceRedshiftUserCreate(db *DBConnection, d *schema.ResourceData) error
 		v, ok := d.GetOk(opt.hclKey)
 		if !ok {
 			if opt.hclKey == userPasswordAttr {
-				createOpts = append(createOpts, "PASSWORD DISABLE")
+				createOpts = append(createOpts, userPasswordClause(""))
 			}
 
 			if opt.hclKey == userSyslogAccessAttr {
@@ -195,7 +269,7 @@ func resourceRedshiftUserCreate(db *DBConnection, d *schema.ResourceData) error
 		if val != "" {
 			switch opt.hclKey {
 			case userPasswordAttr:
-				createOpts = append(createOpts, fmt.Sprintf("%s '%s'", opt.sqlKey, pqQuoteLiteral(val)))
+				createOpts = append(createOpts, userPasswordClause(val))
 			case userValidUntilAttr:
 				switch {
 				case v.(string) == "", strings.ToLower(v.(string)) == "infinity":
@@ -229,6 +303,10 @@ func resourceRedshiftUserCreate(db *DBConnection, d *schema.ResourceData) error
 		createOpts = append(createOpts, valStr)
 	}
 
+	if groups, ok := d.GetOk(userGroupsAttr); ok && groups.(*schema.Set).Len() > 0 {
+		createOpts = append(createOpts, fmt.Sprintf("IN GROUP %s", setToPgIdentList(groups.(*schema.Set), "")))
+	}
+
 	userName := d.Get(userNameAttr).(string)
 	createStr := strings.Join(createOpts, " ")
 	query := fmt.Sprintf("CREATE USER %s WITH %s", pq.QuoteIdentifier(userName), createStr)
@@ -244,6 +322,19 @@ func resourceRedshiftUserCreate(db *DBConnection, d *schema.ResourceData) error
 
 	d.SetId(usesysid)
 
+	for _, query := range createUserConfigParametersQueries(userName, nil, d.Get(userConfigParamsAttr).(map[string]interface{})) {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("error setting configuration parameters of user %q: %w", userName, err)
+		}
+	}
+
+	if searchPath := d.Get(userSearchPathAttr).([]interface{}); len(searchPath) > 0 {
+		query := createUserSearchPathQuery(userName, searchPath)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("error setting search path of user %q: %w", userName, err)
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -261,8 +352,6 @@ func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) erro
 
 	columns := []string{
 		"user_name",
-		"createdb",
-		"superuser",
 		"syslog_access",
 		`COALESCE(connection_limit::TEXT, 'UNLIMITED')`,
 		"session_timeout",
@@ -270,8 +359,6 @@ func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) erro
 
 	values := []interface{}{
 		&userName,
-		&userCreateDB,
-		&userSuperuser,
 		&userSyslogAccess,
 		&userConnLimit,
 		&userSessionTimeout,
@@ -290,7 +377,7 @@ func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) erro
 		return fmt.Errorf("error reading User: %w", err)
 	}
 
-	err = db.QueryRow("SELECT COALESCE(valuntil, 'infinity') FROM pg_user_info WHERE usesysid = $1", useSysID).Scan(&userValidUntil)
+	err = db.QueryRow("SELECT COALESCE(valuntil, 'infinity'), usesuper, usecreatedb FROM pg_user_info WHERE usesysid = $1", useSysID).Scan(&userValidUntil, &userSuperuser, &userCreateDB)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		log.Printf("[WARN] Redshift User (%s) not found", useSysID)
@@ -325,9 +412,75 @@ func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) erro
 	d.Set(userValidUntilAttr, userValidUntil)
 	d.Set(userSessionTimeoutAttr, userSessionTimeoutNumber)
 
+	groups, err := readUserGroups(db, useSysID)
+	if err != nil {
+		return err
+	}
+	d.Set(userGroupsAttr, groups)
+
+	configParameters, err := readUserConfigParameters(db, useSysID)
+	if err != nil {
+		return err
+	}
+	// The search path is only reported in config_parameters if it isn't managed by search_path.
+	if _, ok := d.GetOk(userSearchPathAttr); ok {
+		d.Set(userSearchPathAttr, parseSearchPath(configParameters[userSearchPathAttr]))
+		delete(configParameters, userSearchPathAttr)
+	}
+	d.Set(userConfigParamsAttr, configParameters)
+
 	return nil
 }
 
+// userConfigSeparator separates the entries of pg_user.useconfig, it is not expected in any parameter value.
+const userConfigSeparator = "\x1f"
+
+func readUserConfigParameters(db *DBConnection, useSysID string) (map[string]string, error) {
+	var rawConfig string
+	query := "SELECT COALESCE(array_to_string(useconfig, chr(31)), '') FROM pg_user WHERE usesysid = $1"
+	if err := db.QueryRow(query, useSysID).Scan(&rawConfig); err != nil {
+		return nil, fmt.Errorf("error reading configuration parameters of user: %w", err)
+	}
+	return parseUserConfig(rawConfig), nil
+}
+
+// parseUserConfig parses the entries of pg_user.useconfig, which are stored as <name>=<value>.
+func parseUserConfig(rawConfig string) map[string]string {
+	configParameters := map[string]string{}
+	if rawConfig == "" {
+		return configParameters
+	}
+	for _, entry := range strings.Split(rawConfig, userConfigSeparator) {
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		configParameters[name] = value
+	}
+	return configParameters
+}
+
+func readUserGroups(db *DBConnection, useSysID string) ([]string, error) {
+	rows, err := db.Query("SELECT g.groname FROM pg_group g JOIN pg_user u ON u.usesysid = ANY(g.grolist) WHERE u.usesysid = $1", useSysID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading groups of user: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []string{}
+	for rows.Next() {
+		var group string
+		if err := rows.Scan(&group); err != nil {
+			return nil, fmt.Errorf("error reading groups of user: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading groups of user: %w", err)
+	}
+	return groups, nil
+}
+
 const redshiftDataApiInfinityDateString = "2038-01-19 03:14:04"
 
 var redshiftDataApiDatetimeRegexp = regexp.MustCompile(`^\d+-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$`)
@@ -352,11 +505,12 @@ func validateAndAdjustValidUntil(validUntil string) (string, error) {
 func resourceRedshiftUserDelete(db *DBConnection, d *schema.ResourceData) error {
 	useSysID := d.Id()
 	userName := d.Get(userNameAttr).(string)
-	rawUsername, err := db.client.config.GetUsername(db)
+	newOwnerName, err := getReassignOwnedTo(db, d)
 	if err != nil {
-		return fmt.Errorf("error retrieving username: %w", err)
+		return err
 	}
-	newOwnerName := permanentUsername(rawUsername)
+
+	schemaNamesQuery := db.catalogQuery(listSchemasQuery)
 
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -364,6 +518,16 @@ func resourceRedshiftUserDelete(db *DBConnection, d *schema.ResourceData) error
 	}
 	defer deferredRollback(tx)
 
+	// The revokes below fail for a missing user, so DROP USER IF EXISTS alone doesn't suffice.
+	var exists int
+	if err := tx.QueryRow("SELECT 1 FROM pg_user_info WHERE usesysid = $1", useSysID).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[WARN] user %q with id %s does not exist, already dropped\n", userName, useSysID)
+			return nil
+		}
+		return fmt.Errorf("could not check if user %q exists: %w", userName, err)
+	}
+
 	// Based on https://github.com/awslabs/amazon-redshift-utils/blob/master/src/AdminViews/v_find_dropuser_objs.sql
 	var reassignOwnerGenerator = `SELECT owner.ddl
 			FROM (
@@ -432,29 +596,11 @@ func resourceRedshiftUserDelete(db *DBConnection, d *schema.ResourceData) error
 		}
 	}
 
-	rows, err = tx.Query("SELECT nspname FROM pg_namespace WHERE nspowner != 1 OR nspname = 'public'")
+	schemaNames, err := listSchemas(tx, schemaNamesQuery)
 	if err != nil {
 		return err
 	}
 
-	var schemaNames []string
-
-	for rows.Next() {
-		var schemaName string
-		if err := rows.Scan(&schemaName); err != nil {
-			_ = rows.Close()
-			return err
-		}
-		schemaNames = append(schemaNames, schemaName)
-	}
-	if err := rows.Err(); err != nil {
-		_ = rows.Close()
-		return err
-	}
-	if err := rows.Close(); err != nil {
-		return err
-	}
-
 	for _, schemaName := range schemaNames {
 		if _, err := tx.Exec(fmt.Sprintf("REVOKE ALL ON ALL TABLES IN SCHEMA %s FROM %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName))); err != nil {
 			return err
@@ -466,9 +612,10 @@ func resourceRedshiftUserDelete(db *DBConnection, d *schema.ResourceData) error
 
 	}
 
-	if _, err := tx.Exec(fmt.Sprintf("DROP USER %s", pq.QuoteIdentifier(userName))); err != nil {
+	if _, err := tx.Exec(fmt.Sprintf("DROP USER IF EXISTS %s", pq.QuoteIdentifier(userName))); err != nil {
 		return err
 	}
+	tx.invalidateID(idCacheKindUser, userName)
 
 	if err := tx.Commit(); err != nil {
 		return err
@@ -478,6 +625,15 @@ func resourceRedshiftUserDelete(db *DBConnection, d *schema.ResourceData) error
 	return nil
 }
 
+// getReassignOwnedTo returns the user which takes over the objects of a dropped user.
+func getReassignOwnedTo(db *DBConnection, d *schema.ResourceData) (string, error) {
+	if newOwnerName, ok := d.GetOk(userReassignOwnedToAttr); ok {
+		return newOwnerName.(string), nil
+	}
+
+	return getConnectedUsername(db)
+}
+
 func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client)
 	if err != nil {
@@ -500,7 +656,7 @@ func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error
 	if err := setUserCreateDB(tx, d); err != nil {
 		return err
 	}
-	if err := setUserSuperuser(tx, d); err != nil {
+	if err := setUserSuperuser(tx, db, d); err != nil {
 		return err
 	}
 
@@ -516,6 +672,18 @@ func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error
 		return err
 	}
 
+	if err := setUserGroups(tx, d); err != nil {
+		return err
+	}
+
+	if err := setUserConfigParameters(tx, d); err != nil {
+		return err
+	}
+
+	if err := setUserSearchPath(tx, d); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -523,7 +691,7 @@ func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error
 	return resourceRedshiftUserReadImpl(db, d)
 }
 
-func setUserName(tx *sql.Tx, d *schema.ResourceData) error {
+func setUserName(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(userNameAttr) {
 		return nil
 	}
@@ -540,31 +708,173 @@ func setUserName(tx *sql.Tx, d *schema.ResourceData) error {
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating User NAME: %w", err)
 	}
+	tx.invalidateID(idCacheKindUser, oldValue)
 
 	return nil
 }
 
-func setUserPassword(tx *sql.Tx, d *schema.ResourceData) error {
-	if !d.HasChange(userPasswordAttr) && !d.HasChange(userNameAttr) {
+func setUserGroups(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChange(userGroupsAttr) {
 		return nil
 	}
 
 	userName := d.Get(userNameAttr).(string)
-	password := d.Get(userPasswordAttr).(string)
+	oldRaw, newRaw := d.GetChange(userGroupsAttr)
+	removedGroups, addedGroups := calculateUserNamesDiff(setToStringList(oldRaw.(*schema.Set)), setToStringList(newRaw.(*schema.Set)))
+
+	for _, query := range createUserGroupsQueries(userName, removedGroups, addedGroups) {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("error updating groups of user %q: %w", userName, err)
+		}
+	}
+	return nil
+}
 
-	passwdTok := "PASSWORD DISABLE"
-	if password != "" {
-		passwdTok = fmt.Sprintf("PASSWORD '%s'", pqQuoteLiteral(password))
+func createUserGroupsQueries(userName string, removedGroups, addedGroups []string) []string {
+	var queries []string
+	for _, group := range removedGroups {
+		queries = append(queries, fmt.Sprintf("ALTER GROUP %s DROP USER %s", pq.QuoteIdentifier(group), pq.QuoteIdentifier(userName)))
+	}
+	for _, group := range addedGroups {
+		queries = append(queries, fmt.Sprintf("ALTER GROUP %s ADD USER %s", pq.QuoteIdentifier(group), pq.QuoteIdentifier(userName)))
 	}
+	return queries
+}
+
+func setUserConfigParameters(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChange(userConfigParamsAttr) {
+		return nil
+	}
+
+	userName := d.Get(userNameAttr).(string)
+	oldRaw, newRaw := d.GetChange(userConfigParamsAttr)
 
-	query := fmt.Sprintf("ALTER USER %s %s", pq.QuoteIdentifier(userName), passwdTok)
+	for _, query := range createUserConfigParametersQueries(userName, oldRaw.(map[string]interface{}), newRaw.(map[string]interface{})) {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("error updating configuration parameters of user %q: %w", userName, err)
+		}
+	}
+	return nil
+}
+
+func setUserSearchPath(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChange(userSearchPathAttr) {
+		return nil
+	}
+
+	userName := d.Get(userNameAttr).(string)
+	query := createUserSearchPathQuery(userName, d.Get(userSearchPathAttr).([]interface{}))
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error updating search path of user %q: %w", userName, err)
+	}
+	return nil
+}
+
+func createUserSearchPathQuery(userName string, searchPath []interface{}) string {
+	if len(searchPath) == 0 {
+		return fmt.Sprintf("ALTER USER %s RESET search_path", pq.QuoteIdentifier(userName))
+	}
+	schemas := make([]string, len(searchPath))
+	for i, schemaName := range searchPath {
+		schemas[i] = pq.QuoteLiteral(schemaName.(string))
+	}
+	return fmt.Sprintf("ALTER USER %s SET search_path TO %s", pq.QuoteIdentifier(userName), strings.Join(schemas, ", "))
+}
+
+// parseSearchPath splits the search path as stored in pg_user.useconfig, e.g. "$user", public.
+func parseSearchPath(searchPath string) []string {
+	if searchPath == "" {
+		return nil
+	}
+	schemas := strings.Split(searchPath, ",")
+	for i, schemaName := range schemas {
+		schemas[i] = strings.Trim(strings.TrimSpace(schemaName), `"`)
+	}
+	return schemas
+}
+
+// createUserConfigParametersQueries returns the statements to change the configuration parameters of a user
+// from oldParameters to newParameters. Only the parameters which are removed or changed are touched.
+func createUserConfigParametersQueries(userName string, oldParameters, newParameters map[string]interface{}) []string {
+	var queries []string
+	for _, name := range slices.Sorted(maps.Keys(oldParameters)) {
+		if _, ok := newParameters[name]; !ok {
+			queries = append(queries, fmt.Sprintf("ALTER USER %s RESET %s", pq.QuoteIdentifier(userName), name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(newParameters)) {
+		value := newParameters[name].(string)
+		if oldValue, ok := oldParameters[name]; ok && normalizeConfigParameterValue(oldValue.(string)) == normalizeConfigParameterValue(value) {
+			continue
+		}
+		queries = append(queries, fmt.Sprintf("ALTER USER %s SET %s TO %s", pq.QuoteIdentifier(userName), name, configParameterValueList(value)))
+	}
+	return queries
+}
+
+// configParameterValueList quotes the elements of a, possibly comma separated, parameter value.
+func configParameterValueList(value string) string {
+	elements := strings.Split(value, ",")
+	for i, element := range elements {
+		elements[i] = pq.QuoteLiteral(strings.Trim(strings.TrimSpace(element), `"`))
+	}
+	return strings.Join(elements, ", ")
+}
+
+// normalizeConfigParameterValue returns the value like Redshift stores it in pg_user.useconfig,
+// apart from the quotes it adds to some elements of list values like search_path.
+func normalizeConfigParameterValue(value string) string {
+	elements := strings.Split(value, ",")
+	for i, element := range elements {
+		elements[i] = strings.Trim(strings.TrimSpace(element), `"`)
+	}
+	return strings.Join(elements, ", ")
+}
+
+func suppressConfigParameterDiff(k, old, new string, _ *schema.ResourceData) bool {
+	// The number of parameters is compared as config_parameters.%, which must not be suppressed.
+	if strings.HasSuffix(k, ".%") {
+		return false
+	}
+	return normalizeConfigParameterValue(old) == normalizeConfigParameterValue(new)
+}
+
+func validateConfigParameters(v interface{}, k string) (ws []string, errs []error) {
+	for name := range v.(map[string]interface{}) {
+		if !configParameterNameRegexp.MatchString(name) {
+			errs = append(errs, fmt.Errorf("%q: invalid configuration parameter name %q, expected lower case letters, digits, underscores and dots", k, name))
+		}
+	}
+	return
+}
+
+// userPasswordClause returns the PASSWORD clause of CREATE USER and ALTER USER. The password is escaped as
+// an SQL string literal, so quotes and backslashes in it can't break out of the literal.
+func userPasswordClause(password string) string {
+	if password == "" {
+		return "PASSWORD DISABLE"
+	}
+	return fmt.Sprintf("PASSWORD '%s'", pqQuoteLiteral(password))
+}
+
+// setUserPassword sets the password when it changes and after a rename, as Redshift clears the password of renamed
+// users and they could no longer log in until it is reset.
+func setUserPassword(tx *transaction, d *schema.ResourceData) error {
+	if !d.HasChange(userPasswordAttr) && !d.HasChange(userNameAttr) {
+		return nil
+	}
+
+	userName := d.Get(userNameAttr).(string)
+	password := d.Get(userPasswordAttr).(string)
+
+	query := fmt.Sprintf("ALTER USER %s %s", pq.QuoteIdentifier(userName), userPasswordClause(password))
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating user password: %w", err)
 	}
 	return nil
 }
 
-func setUserConnLimit(tx *sql.Tx, d *schema.ResourceData) error {
+func setUserConnLimit(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(userConnLimitAttr) {
 		return nil
 	}
@@ -579,7 +889,7 @@ func setUserConnLimit(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setUserSessionTimeout(tx *sql.Tx, d *schema.ResourceData) error {
+func setUserSessionTimeout(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(userSessionTimeoutAttr) {
 		return nil
 	}
@@ -599,7 +909,7 @@ func setUserSessionTimeout(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setUserCreateDB(tx *sql.Tx, d *schema.ResourceData) error {
+func setUserCreateDB(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(userCreateDBAttr) {
 		return nil
 	}
@@ -618,17 +928,19 @@ func setUserCreateDB(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setUserSuperuser(tx *sql.Tx, d *schema.ResourceData) error {
+func setUserSuperuser(tx *transaction, db *DBConnection, d *schema.ResourceData) error {
 	if !d.HasChange(userSuperuserAttr) {
 		return nil
 	}
 
 	superuser := d.Get(userSuperuserAttr).(bool)
+	userName := d.Get(userNameAttr).(string)
 	tok := "NOCREATEUSER"
 	if superuser {
 		tok = "CREATEUSER"
+	} else if err := checkSuperuserRevocable(db, userName); err != nil {
+		return err
 	}
-	userName := d.Get(userNameAttr).(string)
 	query := fmt.Sprintf("ALTER USER %s WITH %s", pq.QuoteIdentifier(userName), tok)
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("error updating user SUPERUSER: %w", err)
@@ -637,7 +949,20 @@ func setUserSuperuser(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setUserValidUntil(tx *sql.Tx, d *schema.ResourceData) error {
+// checkSuperuserRevocable rejects revoking superuser from the user the provider is connected as,
+// which would otherwise fail later with confusing permission errors.
+func checkSuperuserRevocable(db *DBConnection, userName string) error {
+	connectedUser, err := db.client.config.GetUsername(db)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(permanentUsername(connectedUser), userName) {
+		return fmt.Errorf("cannot revoke superuser from %q: the provider is connected as this user and would lose the privileges needed to manage the cluster", userName)
+	}
+	return nil
+}
+
+func setUserValidUntil(tx *transaction, d *schema.ResourceData) error {
 	if !d.HasChange(userValidUntilAttr) {
 		return nil
 	}
@@ -658,7 +983,7 @@ func setUserValidUntil(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setUserSyslogAccess(tx *sql.Tx, d *schema.ResourceData) error {
+func setUserSyslogAccess(tx *transaction, d *schema.ResourceData) error {
 	syslogAccessCurrent := d.Get(userSyslogAccessAttr).(string)
 	syslogAccessComputed := syslogAccessCurrent
 	if syslogAccessComputed == "" {
@@ -0,0 +1,233 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	userRoleUserAttr  = "user"
+	userRoleRolesAttr = "roles"
+)
+
+func redshiftUserRole() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Grants roles to a user. This is a user-centric alternative to granting each role with ` + "`redshift_role_grant`" + ` and ` + "`grant_to_type = \"USER\"`" + `.
+
+Only the listed roles are managed, roles granted to the user otherwise are left untouched. Note: the same role grant must not be managed by both this resource and ` + "`redshift_role_grant`" + `, otherwise the resources revoke each other's grants.
+`,
+		CreateContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftUserRoleCreate),
+		),
+		ReadContext: IdempotentResourceFunc(resourceRedshiftUserRoleRead),
+		UpdateContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftUserRoleUpdate),
+		),
+		DeleteContext: ResourceFunc(
+			ResourceRetryOnPQErrors(resourceRedshiftUserRoleDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			userRoleUserAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The name of the user to grant the roles to.",
+				DiffSuppressFunc: suppressIdentifierCaseDiff,
+			},
+			userRoleRolesAttr: {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The names of the roles to grant to the user. When imported, all roles granted to the user are managed.",
+			},
+			preserveCaseAttr: preserveCaseSchema(),
+		},
+	}
+}
+
+func resourceRedshiftUserRoleCreate(db *DBConnection, d *schema.ResourceData) error {
+	userName := getIdentifier(d, userRoleUserAttr)
+	roleNames := getUserRoleNames(d, d.Get(userRoleRolesAttr))
+
+	if len(roleNames) == 0 {
+		return fmt.Errorf("at least one role must be specified in %q", userRoleRolesAttr)
+	}
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := grantRolesToUser(tx, userName, roleNames); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(userName)
+
+	return resourceRedshiftUserRoleRead(db, d)
+}
+
+func resourceRedshiftUserRoleRead(db *DBConnection, d *schema.ResourceData) error {
+	setDefaultIfUnset(d, preserveCaseAttr, false)
+
+	userName := identifierOf(d, d.Id())
+
+	query := "SELECT role_name FROM svv_user_grants WHERE user_name = $1"
+
+	rows, err := db.Query(query, userName)
+	if err != nil {
+		return fmt.Errorf("could not read roles of user %q: %w", userName, err)
+	}
+	defer rows.Close()
+
+	var grantedRoleNames []string
+	for rows.Next() {
+		var roleName string
+		if err := rows.Scan(&roleName); err != nil {
+			return fmt.Errorf("could not read roles of user %q: %w", userName, err)
+		}
+		grantedRoleNames = append(grantedRoleNames, roleName)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not read roles of user %q: %w", userName, err)
+	}
+
+	// Roles granted to the user by other means are not reported, unless no roles
+	// are known yet, i.e. the resource is being imported.
+	roleNames := filterUserRoleNames(d, grantedRoleNames)
+	if len(roleNames) == 0 {
+		log.Printf("[WARN] None of the roles are granted to user %s", userName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(userRoleUserAttr, userName)
+	d.Set(userRoleRolesAttr, roleNames)
+
+	return nil
+}
+
+func resourceRedshiftUserRoleUpdate(db *DBConnection, d *schema.ResourceData) error {
+	userName := getIdentifier(d, userRoleUserAttr)
+	rawOldRoleNames, rawNewRoleNames := d.GetChange(userRoleRolesAttr)
+	newRoleNames := getUserRoleNames(d, rawNewRoleNames)
+	if len(newRoleNames) == 0 {
+		return fmt.Errorf("at least one role must be specified in %q", userRoleRolesAttr)
+	}
+	revokedRoleNames, grantedRoleNames := calculateUserNamesDiff(getUserRoleNames(d, rawOldRoleNames), newRoleNames)
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := revokeRolesFromUser(tx, userName, revokedRoleNames); err != nil {
+		return err
+	}
+	if err := grantRolesToUser(tx, userName, grantedRoleNames); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftUserRoleRead(db, d)
+}
+
+func resourceRedshiftUserRoleDelete(db *DBConnection, d *schema.ResourceData) error {
+	userName := getIdentifier(d, userRoleUserAttr)
+	roleNames := getUserRoleNames(d, d.Get(userRoleRolesAttr))
+
+	tx, err := startTransaction(db.client)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := revokeRolesFromUser(tx, userName, roleNames); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func grantRolesToUser(tx *transaction, userName string, roleNames []string) error {
+	if len(roleNames) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf("GRANT %s TO %s", buildRoleList(roleNames), pq.QuoteIdentifier(userName))
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not grant roles %s to user %q: %w", strings.Join(roleNames, ", "), userName, err)
+	}
+	return nil
+}
+
+func revokeRolesFromUser(tx *transaction, userName string, roleNames []string) error {
+	if len(roleNames) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf("REVOKE %s FROM %s", buildRoleList(roleNames), pq.QuoteIdentifier(userName))
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not revoke roles %s from user %q: %w", strings.Join(roleNames, ", "), userName, err)
+	}
+	return nil
+}
+
+// buildRoleList returns the roles as used in GRANT ROLE statements, e.g. ROLE "a", ROLE "b".
+func buildRoleList(roleNames []string) string {
+	roles := make([]string, len(roleNames))
+	for i, roleName := range roleNames {
+		roles[i] = fmt.Sprintf("ROLE %s", pq.QuoteIdentifier(roleName))
+	}
+	return strings.Join(roles, ", ")
+}
+
+func getUserRoleNames(d *schema.ResourceData, rawRoleNames interface{}) []string {
+	roleNames := parseUserNames(rawRoleNames)
+	for i, roleName := range roleNames {
+		roleNames[i] = identifierOf(d, roleName)
+	}
+	return roleNames
+}
+
+// filterUserRoleNames returns the managed roles which are granted, as they are configured,
+// or all granted roles if none are managed.
+func filterUserRoleNames(d *schema.ResourceData, grantedRoleNames []string) []string {
+	managedRoleNames := parseUserNames(d.Get(userRoleRolesAttr))
+	if len(managedRoleNames) == 0 {
+		return grantedRoleNames
+	}
+	var roleNames []string
+	for _, managedRoleName := range managedRoleNames {
+		for _, roleName := range grantedRoleNames {
+			if identifierOf(d, managedRoleName) == roleName {
+				roleNames = append(roleNames, managedRoleName)
+				break
+			}
+		}
+	}
+	return roleNames
+}
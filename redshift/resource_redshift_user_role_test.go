@@ -0,0 +1,180 @@
+package redshift
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestAccRedshiftUserRole_Basic(t *testing.T) {
+	userName := generateRandomObjectName("acc_test_user_role")
+	roleNames := []string{
+		fmt.Sprintf("%s_first", userName),
+		fmt.Sprintf("%s_second", userName),
+		fmt.Sprintf("%s_third", userName),
+	}
+
+	config := func(roles string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+	name = %[1]q
+}
+
+resource "redshift_role" "first" {
+	name = %[2]q
+}
+
+resource "redshift_role" "second" {
+	name = %[3]q
+}
+
+resource "redshift_role" "third" {
+	name = %[4]q
+}
+
+resource "redshift_user_role" "user" {
+	user  = redshift_user.user.name
+	roles = [%[5]s]
+}
+`, userName, roleNames[0], roleNames[1], roleNames[2], roles)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("redshift_role.first.name, redshift_role.second.name"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleNames[0]),
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleNames[1]),
+					resource.TestCheckResourceAttr("redshift_user_role.user", "id", userName),
+					resource.TestCheckResourceAttr("redshift_user_role.user", "roles.#", "2"),
+				),
+			},
+			{
+				Config: config("redshift_role.second.name, redshift_role.third.name"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleGrantNotExists("user", userName, roleNames[0]),
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleNames[1]),
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleNames[2]),
+					resource.TestCheckResourceAttr("redshift_user_role.user", "roles.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "redshift_user_role.user",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// A role revoked outside of Terraform has to be granted again.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						_, err := db.Exec(fmt.Sprintf("REVOKE ROLE %s FROM %s", pq.QuoteIdentifier(roleNames[2]), pq.QuoteIdentifier(userName)))
+						return err
+					})
+				},
+				Config:             config("redshift_role.second.name, redshift_role.third.name"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config("redshift_role.second.name, redshift_role.third.name"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftRoleGrantExists("user", userName, roleNames[2]),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftUserRoleDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_user_role" {
+			continue
+		}
+
+		for key, roleName := range rs.Primary.Attributes {
+			if key == "roles.#" || !strings.HasPrefix(key, "roles.") {
+				continue
+			}
+			exists, err := checkRoleGrantExists(client, generateRoleGrantID(roleName, "USER", rs.Primary.ID))
+			if err != nil {
+				return fmt.Errorf("error checking role grant: %w", err)
+			}
+			if exists {
+				return fmt.Errorf("role %s is still granted to user %s after destroy", roleName, rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestFilterUserRoleNames(t *testing.T) {
+	tests := map[string]struct {
+		roles        []interface{}
+		preserveCase bool
+		granted      []string
+		expected     []string
+	}{
+		"managed roles": {
+			roles:    []interface{}{"analyst", "etl"},
+			granted:  []string{"analyst", "auditor", "etl"},
+			expected: []string{"analyst", "etl"},
+		},
+		"revoked role": {
+			roles:    []interface{}{"analyst", "etl"},
+			granted:  []string{"analyst"},
+			expected: []string{"analyst"},
+		},
+		"configured case is kept": {
+			roles:    []interface{}{"Analyst"},
+			granted:  []string{"analyst"},
+			expected: []string{"Analyst"},
+		},
+		"preserved case must match": {
+			roles:        []interface{}{"Analyst"},
+			preserveCase: true,
+			granted:      []string{"analyst"},
+			expected:     nil,
+		},
+		"import": {
+			roles:    []interface{}{},
+			granted:  []string{"analyst", "auditor"},
+			expected: []string{"analyst", "auditor"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftUserRole().Schema, map[string]interface{}{
+				userRoleUserAttr:  "alice",
+				userRoleRolesAttr: tt.roles,
+				preserveCaseAttr:  tt.preserveCase,
+			})
+			roleNames := filterUserRoleNames(d, tt.granted)
+			sort.Strings(roleNames)
+			if !reflect.DeepEqual(roleNames, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, roleNames)
+			}
+		})
+	}
+}
+
+func TestBuildRoleList(t *testing.T) {
+	expected := `ROLE "analyst", ROLE "select"`
+	if roles := buildRoleList([]string{"analyst", "select"}); roles != expected {
+		t.Errorf("expected %q, got %q", expected, roles)
+	}
+}
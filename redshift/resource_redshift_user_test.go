@@ -2,10 +2,12 @@ package redshift
 
 import (
 	"context"
+	"crypto/md5"
 	"database/sql"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -17,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 const testAccRedshiftUserLoginConfig = `
@@ -78,6 +81,69 @@ func TestAccRedshiftUser_Login(t *testing.T) {
 	})
 }
 
+func TestUserPasswordClause(t *testing.T) {
+	tests := map[string]struct {
+		password string
+		expected string
+	}{
+		"disabled":          {"", "PASSWORD DISABLE"},
+		"plain":             {"Foobarbaz1", "PASSWORD 'Foobarbaz1'"},
+		"single quote":      {"Foo'bar1", "PASSWORD 'Foo''bar1'"},
+		"backslash":         {`Foo\bar1`, `PASSWORD 'Foo\\bar1'`},
+		"dollar":            {"Foo$$bar1$", "PASSWORD 'Foo$$bar1$'"},
+		"literal injection": {`x' CREATEUSER --`, `PASSWORD 'x'' CREATEUSER --'`},
+		"escaped quote":     {`Foo\'bar1`, `PASSWORD 'Foo\\''bar1'`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := userPasswordClause(tt.password); got != tt.expected {
+				t.Errorf("userPasswordClause(%q) = %q, want %q", tt.password, got, tt.expected)
+			}
+		})
+	}
+}
+
+// Redshift rejects quotes and backslashes in passwords, while other special characters, e.g. $ which
+// starts dollar quoted strings in PostgreSQL, must make it into the password unchanged.
+func TestAccRedshiftUser_PasswordSpecialCharacters(t *testing.T) {
+	if os.Getenv("REDSHIFT_TEST_ACC_SKIP_USER_LOGIN") != "" {
+		t.Skipf("Skipping user login test as REDSHIFT_TEST_ACC_SKIP_USER_LOGIN is set")
+	}
+	userName := generateRandomObjectName("tf_acc_user_password")
+	password := "Foo$$bar1$;--%&"
+	config := fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name     = %q
+  password = %q
+}
+`, userName, password)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(userName),
+					testAccCheckRedshiftUserCanLogin(userName, password),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name     = %q
+  password = "Foo'bar1"
+}
+`, userName),
+				ExpectError: regexp.MustCompile(`(?i)password`),
+			},
+		},
+	})
+}
+
 const testAccRedshiftUserConfig = `
 resource "redshift_user" "simple" {
   name = "user_simple"
@@ -159,6 +225,28 @@ func TestAccRedshiftUser_Basic(t *testing.T) {
 	})
 }
 
+func TestAccRedshiftUser_DeleteDroppedOutOfBand(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_dropped")
+	config := fmt.Sprintf(`
+resource "redshift_user" "dropped" {
+  name = %[1]q
+}
+`, userName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeleteAfterDroppedOutOfBand("redshift_user.dropped", "redshift_user", fmt.Sprintf("DROP USER %s", pq.QuoteIdentifier(userName))),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftUser_Update(t *testing.T) {
 	// todo: use dynamic names for users
 
@@ -222,6 +310,125 @@ resource "redshift_user" "update_user" {
 	})
 }
 
+func TestAccRedshiftUser_RenameKeepsGrants(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_rename")
+	newUserName := fmt.Sprintf("%s_renamed", userName)
+	schemaName := generateRandomObjectName("tf_acc_schema_rename")
+	config := func(name string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %q
+}
+`, name)
+	}
+
+	var userID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckRedshiftUserDestroy,
+			testAccRedshiftGrantDropSchema(schemaName),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: config(userName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(userName),
+					func(s *terraform.State) error {
+						userID = s.RootModule().Resources["redshift_user.user"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// Grant the user a privilege outside of Terraform, a dependent redshift_grant would be replaced on rename.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						if _, err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s", pq.QuoteIdentifier(schemaName))); err != nil {
+							return err
+						}
+						_, err := db.Exec(fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName)))
+						return err
+					})
+				},
+				Config: config(newUserName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(newUserName),
+					resource.TestCheckResourceAttr("redshift_user.user", "name", newUserName),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["redshift_user.user"].Primary.ID; id != userID {
+							return fmt.Errorf("expected the renamed user to keep the id %s, got %s", userID, id)
+						}
+						return nil
+					},
+					func(*terraform.State) error {
+						client := testAccProvider.Meta().(*Client)
+						db, err := client.Connect()
+						if err != nil {
+							return err
+						}
+						var hasUsage bool
+						if err := db.QueryRow("SELECT has_schema_privilege($1, $2, 'USAGE')", newUserName, schemaName).Scan(&hasUsage); err != nil {
+							return fmt.Errorf("could not read schema privileges of %q: %w", newUserName, err)
+						}
+						if !hasUsage {
+							return fmt.Errorf("expected %q to keep usage on schema %q after the rename", newUserName, schemaName)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// Redshift clears the password when renaming a user, the rename must set it again to keep the user able to log in.
+func TestAccRedshiftUser_RenameResetsPassword(t *testing.T) {
+	if os.Getenv("REDSHIFT_TEST_ACC_SKIP_USER_LOGIN") != "" {
+		t.Skipf("Skipping user login test as REDSHIFT_TEST_ACC_SKIP_USER_LOGIN is set")
+	}
+	userName := generateRandomObjectName("tf_acc_user_rename_pw")
+	newUserName := fmt.Sprintf("%s_renamed", userName)
+	config := func(name, password string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name     = %q
+  password = %q
+}
+`, name, password)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(userName, "Foobarbaz1"),
+				Check:  testAccCheckRedshiftUserCanLogin(userName, "Foobarbaz1"),
+			},
+			{
+				Config: config(newUserName, "Foobarbaz1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(newUserName),
+					testAccCheckRedshiftUserCanLogin(newUserName, "Foobarbaz1"),
+				),
+			},
+			{
+				// md5 followed by the MD5 hash of the password concatenated with the user name.
+				Config: config(newUserName, fmt.Sprintf("md5%x", md5.Sum([]byte("Foobarbaz2"+newUserName)))),
+				Check:  testAccCheckRedshiftUserCanLogin(newUserName, "Foobarbaz2"),
+			},
+			{
+				Config:      config(userName, fmt.Sprintf("md5%x", md5.Sum([]byte("Foobarbaz2"+newUserName)))),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("MD5 password hash of user .* is salted with its name"),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftUser_UpdateToSuperuser(t *testing.T) {
 	// todo: use dynamic names for users
 
@@ -285,6 +492,72 @@ resource "redshift_user" "update_superuser" {
 	})
 }
 
+func TestAccRedshiftUser_ToggleSuperuserAndCreateDatabase(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_toggle")
+	configTemplate := `
+resource "redshift_user" "user" {
+  name            = %[1]q
+  password        = "Foobarbaz1"
+  superuser       = %[2]t
+  create_database = %[3]t
+}
+`
+
+	checkFlags := func(superuser, createDatabase bool) resource.TestCheckFunc {
+		return resource.ComposeTestCheckFunc(
+			resource.TestCheckResourceAttr("redshift_user.user", userSuperuserAttr, strconv.FormatBool(superuser)),
+			resource.TestCheckResourceAttr("redshift_user.user", userCreateDBAttr, strconv.FormatBool(createDatabase)),
+			func(*terraform.State) error {
+				var usesuper, usecreatedb bool
+				withAccGrantConn(t, func(db *DBConnection) error {
+					return db.QueryRow("SELECT usesuper, usecreatedb FROM pg_user WHERE usename = $1", userName).Scan(&usesuper, &usecreatedb)
+				})
+				if usesuper != superuser || usecreatedb != createDatabase {
+					return fmt.Errorf("expected usesuper=%t and usecreatedb=%t, got %t and %t", superuser, createDatabase, usesuper, usecreatedb)
+				}
+				return nil
+			},
+		)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, userName, false, false),
+				Check:  checkFlags(false, false),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, userName, true, true),
+				Check:  checkFlags(true, true),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, userName, false, true),
+				Check:  checkFlags(false, true),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, userName, true, false),
+				Check:  checkFlags(true, false),
+			},
+		},
+	})
+}
+
+func TestCheckSuperuserRevocable(t *testing.T) {
+	config := NewConfig("postgres", "", "db", 1)
+	config.retrievedUsername = "IAMA:admin"
+	db := &DBConnection{client: config.NewClient()}
+
+	if err := checkSuperuserRevocable(db, "admin"); err == nil || !strings.Contains(err.Error(), "provider is connected as this user") {
+		t.Errorf("expected revoking superuser from the connected user to be rejected, got: %v", err)
+	}
+	if err := checkSuperuserRevocable(db, "other_user"); err != nil {
+		t.Errorf("expected revoking superuser from another user to be allowed, got: %v", err)
+	}
+}
+
 func TestAccRedshiftUser_SuperuserRequiresPassword(t *testing.T) {
 	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_superuser"), "-", "_")
 	config := fmt.Sprintf(`
@@ -441,6 +714,332 @@ resource "unknown_string" "password" {}
 	})
 }
 
+func TestAccRedshiftUser_ReassignOwnedTo(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_reassign")
+	newOwnerName := generateRandomObjectName("tf_acc_user_new_owner")
+	schemaName := generateRandomObjectName("tf_acc_schema_reassign")
+	ownerConfig := fmt.Sprintf(`
+resource "redshift_user" "new_owner" {
+  name = %[1]q
+}
+`, newOwnerName)
+	config := ownerConfig + fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name              = %[1]q
+  reassign_owned_to = redshift_user.new_owner.name
+}
+`, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if err := testAccRedshiftGrantDropSchema(schemaName)(s); err != nil {
+				return err
+			}
+			return testAccCheckRedshiftUserDestroy(s)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("redshift_user.user", userReassignOwnedToAttr, newOwnerName),
+			},
+			{
+				// Dropping the user hands its schema and table over to the new owner.
+				PreConfig: func() {
+					withAccGrantConn(t, func(db *DBConnection) error {
+						if err := testAccRedshiftGrantCreateSchemaTables(db, schemaName, "tbl"); err != nil {
+							return err
+						}
+						if _, err := db.Exec(fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName))); err != nil {
+							return err
+						}
+						_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s.tbl OWNER TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName)))
+						return err
+					})
+				},
+				Config: ownerConfig,
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						var schemaOwner, tableOwner string
+						withAccGrantConn(t, func(db *DBConnection) error {
+							return db.QueryRow(`
+SELECT pg_get_userbyid(n.nspowner), pg_get_userbyid(c.relowner)
+FROM pg_namespace n
+JOIN pg_class c ON c.relnamespace = n.oid
+WHERE n.nspname = $1 AND c.relname = 'tbl'`, schemaName).Scan(&schemaOwner, &tableOwner)
+						})
+						if schemaOwner != newOwnerName || tableOwner != newOwnerName {
+							return fmt.Errorf("expected schema and table to be owned by %q but got %q and %q", newOwnerName, schemaOwner, tableOwner)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftUser_Groups(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_groups")
+	groupPrefix := generateRandomObjectName("tf_acc_group_user")
+	configTemplate := fmt.Sprintf(`
+resource "redshift_group" "first" {
+  name = "%[1]s_first"
+
+  lifecycle {
+    ignore_changes = [
+      users
+    ]
+  }
+}
+
+resource "redshift_group" "second" {
+  name = "%[1]s_second"
+
+  lifecycle {
+    ignore_changes = [
+      users
+    ]
+  }
+}
+
+resource "redshift_user" "user" {
+  name   = %[2]q
+  groups = %%s
+}
+`, groupPrefix, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "[redshift_group.first.name]"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckTypeSetElems("redshift_user.user", userGroupsAttr, groupPrefix+"_first"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, "[redshift_group.second.name]"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckTypeSetElems("redshift_user.user", userGroupsAttr, groupPrefix+"_second"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, "[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.user", "groups.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestCreateUserGroupsQueries(t *testing.T) {
+	queries := createUserGroupsQueries("alice", []string{"old_group"}, []string{"new_group", "other_group"})
+	expected := []string{
+		`ALTER GROUP "old_group" DROP USER "alice"`,
+		`ALTER GROUP "new_group" ADD USER "alice"`,
+		`ALTER GROUP "other_group" ADD USER "alice"`,
+	}
+	if strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %v, got %v", expected, queries)
+	}
+}
+
+func TestAccRedshiftUser_ConfigParameters(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_config")
+	configTemplate := fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name              = %q
+  config_parameters = %%s
+}
+`, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, `{
+    query_group = "etl"
+    search_path = "$user, public"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.user", "config_parameters.%", "2"),
+					resource.TestCheckResourceAttr("redshift_user.user", "config_parameters.query_group", "etl"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, `{
+    search_path       = "$user, public"
+    statement_timeout = "60000"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.user", "config_parameters.%", "2"),
+					resource.TestCheckNoResourceAttr("redshift_user.user", "config_parameters.query_group"),
+					resource.TestCheckResourceAttr("redshift_user.user", "config_parameters.statement_timeout", "60000"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, `{
+    search_path = "$user, public"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.user", "config_parameters.%", "1"),
+					resource.TestCheckNoResourceAttr("redshift_user.user", "config_parameters.statement_timeout"),
+				),
+			},
+			{
+				// Without config_parameters the parameters set before are left untouched
+				Config:   fmt.Sprintf(configTemplate, "{}"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestRedshiftUser_ConfigParametersNotManaged(t *testing.T) {
+	state := map[string]string{
+		userNameAttr:                          "alice",
+		userConfigParamsAttr + ".%":           "1",
+		userConfigParamsAttr + ".query_group": "etl",
+	}
+
+	if diff := planUpgradedState(t, redshiftUser(), state, map[string]interface{}{userNameAttr: "alice"}, nil); diff.Attributes[userConfigParamsAttr+".%"] != nil || diff.Attributes[userConfigParamsAttr+".query_group"] != nil {
+		t.Errorf("Expected the parameters set outside of Terraform to be kept but got %#v", diff.Attributes)
+	}
+}
+
+func TestAccRedshiftUser_SearchPath(t *testing.T) {
+	userName := generateRandomObjectName("tf_acc_user_search_path")
+	configTemplate := fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name              = %q
+  config_parameters = { query_group = "etl" }
+  %%s
+}
+`, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, `search_path = ["$user", "public"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.user", "search_path.#", "2"),
+					resource.TestCheckResourceAttr("redshift_user.user", "search_path.0", "$user"),
+					resource.TestCheckResourceAttr("redshift_user.user", "search_path.1", "public"),
+					resource.TestCheckResourceAttr("redshift_user.user", "config_parameters.%", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, `search_path = ["public", "$user"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.user", "search_path.0", "public"),
+					resource.TestCheckResourceAttr("redshift_user.user", "search_path.1", "$user"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.user", "search_path.#", "0"),
+					resource.TestCheckResourceAttr("redshift_user.user", "config_parameters.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestRedshiftUser_SearchPathConflict(t *testing.T) {
+	_, err := redshiftUser().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		userNameAttr:         "alice",
+		userSearchPathAttr:   []interface{}{"public"},
+		userConfigParamsAttr: map[string]interface{}{"search_path": "public"},
+	}), nil)
+	if err == nil || !strings.Contains(err.Error(), `"search_path" can't be set in both`) {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+
+	_, err = redshiftUser().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		userNameAttr:         "alice",
+		userSearchPathAttr:   []interface{}{"public"},
+		userConfigParamsAttr: map[string]interface{}{"query_group": "etl"},
+	}), nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateUserSearchPathQuery(t *testing.T) {
+	expected := `ALTER USER "alice" SET search_path TO '$user', 'Staging', 'public'`
+	if query := createUserSearchPathQuery("alice", []interface{}{"$user", "Staging", "public"}); query != expected {
+		t.Errorf("expected %q, got %q", expected, query)
+	}
+
+	expected = `ALTER USER "alice" RESET search_path`
+	if query := createUserSearchPathQuery("alice", nil); query != expected {
+		t.Errorf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestParseSearchPath(t *testing.T) {
+	expected := []string{"$user", "Staging", "public"}
+	if searchPath := parseSearchPath(`"$user", "Staging", public`); !reflect.DeepEqual(searchPath, expected) {
+		t.Errorf("expected %v, got %v", expected, searchPath)
+	}
+	if searchPath := parseSearchPath(""); searchPath != nil {
+		t.Errorf("expected no schemas, got %v", searchPath)
+	}
+}
+
+func TestCreateUserConfigParametersQueries(t *testing.T) {
+	queries := createUserConfigParametersQueries("alice",
+		map[string]interface{}{"query_group": "etl", "search_path": `"$user", public`, "statement_timeout": "1000"},
+		map[string]interface{}{"search_path": "$user, public", "statement_timeout": "60000", "wlm_query_slot_count": "2"},
+	)
+	expected := []string{
+		`ALTER USER "alice" RESET query_group`,
+		`ALTER USER "alice" SET statement_timeout TO '60000'`,
+		`ALTER USER "alice" SET wlm_query_slot_count TO '2'`,
+	}
+	if strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %v, got %v", expected, queries)
+	}
+
+	queries = createUserConfigParametersQueries("alice", nil, map[string]interface{}{"search_path": "$user, public"})
+	expected = []string{`ALTER USER "alice" SET search_path TO '$user', 'public'`}
+	if strings.Join(queries, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %v, got %v", expected, queries)
+	}
+}
+
+func TestParseUserConfig(t *testing.T) {
+	configParameters := parseUserConfig("query_group=etl" + userConfigSeparator + `search_path="$user", public`)
+	expected := map[string]string{"query_group": "etl", "search_path": `"$user", public`}
+	if !reflect.DeepEqual(configParameters, expected) {
+		t.Errorf("expected %v, got %v", expected, configParameters)
+	}
+
+	if configParameters := parseUserConfig(""); len(configParameters) != 0 {
+		t.Errorf("expected no parameters, got %v", configParameters)
+	}
+}
+
+func TestValidateConfigParameters(t *testing.T) {
+	if _, errs := validateConfigParameters(map[string]interface{}{"query_group": "etl", "enable_result_cache_for_session": "off"}, userConfigParamsAttr); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if _, errs := validateConfigParameters(map[string]interface{}{"query_group; DROP USER alice": "etl"}, userConfigParamsAttr); len(errs) != 1 {
+		t.Errorf("expected an error for an invalid parameter name, got %v", errs)
+	}
+}
+
 func testAccCheckRedshiftUserDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -531,8 +1130,8 @@ func testAccCheckRedshiftUserCanLogin(user string, password string) resource.Tes
 		if !ok {
 			sslMode = "require"
 		}
-		config := NewPqConfig(os.Getenv("REDSHIFT_HOST"), database, user, password, portNum, sslMode,
-			defaultProviderMaxOpenConnections)
+		config := NewPqConfig(os.Getenv("REDSHIFT_HOST"), database, user, password, portNum, sslMode, defaultProviderApplicationName,
+			0, defaultProviderMaxOpenConnections)
 
 		client := config.NewClient()
 		if err != nil {
@@ -1,9 +1,11 @@
 package redshift
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func testCheckTypeSetElems(resourceName, attr string, want ...string) resource.TestCheckFunc {
@@ -21,3 +23,51 @@ func testCheckTypeSetElems(resourceName, attr string, want ...string) resource.T
 
 	return resource.ComposeTestCheckFunc(checks...)
 }
+
+// testAccCheckDeleteAfterDroppedOutOfBand drops the object of the resource with the given query and
+// then runs the delete of the resource on its state, which must succeed for the already dropped object.
+// A plain destroy would not call the delete at all, as the refresh before removes the object from the state.
+func testAccCheckDeleteAfterDroppedOutOfBand(resourceName, resourceType, dropQuery string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(dropQuery); err != nil {
+			return fmt.Errorf("could not drop %s out of band: %w", resourceName, err)
+		}
+
+		res := testAccProvider.ResourcesMap[resourceType]
+		d := res.Data(rs.Primary)
+		if diags := res.DeleteContext(context.Background(), d, client); diags.HasError() {
+			return fmt.Errorf("delete of already dropped %s failed: %v", resourceName, diags)
+		}
+		return nil
+	}
+}
+
+// testAccCheckResourceIDUnchanged records the ID of the resource on its first call and fails when a later
+// call finds a different ID, i.e. when the resource was replaced instead of updated in place.
+func testAccCheckResourceIDUnchanged(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		if *id == "" {
+			*id = rs.Primary.ID
+			return nil
+		}
+		if rs.Primary.ID != *id {
+			return fmt.Errorf("expected %s to keep the id %s, got %s", resourceName, *id, rs.Primary.ID)
+		}
+		return nil
+	}
+}
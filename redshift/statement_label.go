@@ -0,0 +1,94 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Exec prepends the statement label of the connection's client to statements executed outside of a transaction
+// and runs them with the context of the client. Like all statements, they are logged at the debug level.
+// The privileges cached by the client are invalidated, like after committing a transaction.
+func (db *DBConnection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := db.DB.ExecContext(db.client.context(), labelStatement(db.client.statementLabel, query), args...)
+	logExec(query, start, result, err)
+	db.client.grantPrivileges().invalidate()
+	return result, err
+}
+
+// Query runs queries outside of a transaction with the context of the connection's client.
+func (db *DBConnection) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := db.DB.QueryContext(db.client.context(), labelStatement(db.client.statementLabel, query), args...)
+	logQuery(query, start, err)
+	return rows, err
+}
+
+// QueryRow runs queries outside of a transaction with the context of the connection's client.
+func (db *DBConnection) QueryRow(query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := db.DB.QueryRowContext(db.client.context(), labelStatement(db.client.statementLabel, query), args...)
+	logQuery(query, start, row.Err())
+	return row
+}
+
+// labelStatement prepends label as an SQL comment to query, so the statement can be
+// attributed to a resource in stl_query. An empty label leaves query unchanged.
+func labelStatement(label, query string) string {
+	if label == "" {
+		return query
+	}
+	return fmt.Sprintf("/* %s */ %s", label, query)
+}
+
+// resourceStatementLabel returns the label for statements issued for the resource d of type resourceType.
+func resourceStatementLabel(resourceType string, d *schema.ResourceData) string {
+	label := "terraform:resource=" + resourceType
+	if id := d.Id(); id != "" {
+		label += ",id=" + id
+	}
+	// The label must neither terminate the comment it is put in nor open a nested one. Replacing
+	// one sequence can form the other, e.g. in "/*/", so they are replaced until none is left.
+	for strings.Contains(label, "/*") || strings.Contains(label, "*/") {
+		label = commentDelimiterReplacer.Replace(label)
+	}
+	return label
+}
+
+var commentDelimiterReplacer = strings.NewReplacer("/*", "/ *", "*/", "* /")
+
+// withStatementLabels makes the create, update and delete functions of resources label
+// their statements when statement labels are enabled for the provider.
+func withStatementLabels(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for resourceType, r := range resources {
+		if r.CreateContext != nil {
+			r.CreateContext = withStatementLabel(resourceType, r.CreateContext)
+		}
+		if r.UpdateContext != nil {
+			r.UpdateContext = withStatementLabel(resourceType, r.UpdateContext)
+		}
+		if r.DeleteContext != nil {
+			r.DeleteContext = withStatementLabel(resourceType, r.DeleteContext)
+		}
+	}
+	return resources
+}
+
+func withStatementLabel(resourceType string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		client := meta.(*Client)
+		if !client.statementLabelsEnabled() {
+			return fn(ctx, d, meta)
+		}
+
+		labeledClient := *client
+		labeledClient.statementLabel = resourceStatementLabel(resourceType, d)
+		return fn(ctx, d, &labeledClient)
+	}
+}
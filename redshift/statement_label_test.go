@@ -0,0 +1,87 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestLabelStatement(t *testing.T) {
+	query := "GRANT SELECT ON ALL TABLES IN SCHEMA \"public\" TO \"john\""
+
+	if result := labelStatement("", query); result != query {
+		t.Errorf("Expected unlabeled query to be unchanged but got %q", result)
+	}
+
+	expected := "/* terraform:resource=redshift_grant */ " + query
+	if result := labelStatement("terraform:resource=redshift_grant", query); result != expected {
+		t.Errorf("Expected labeled query to be %q but got %q", expected, result)
+	}
+}
+
+func TestResourceStatementLabel(t *testing.T) {
+	tests := map[string]struct {
+		id       string
+		expected string
+	}{
+		"new resource": {
+			expected: "terraform:resource=redshift_grant",
+		},
+		"existing resource": {
+			id:       "un:john_ot:schema_public",
+			expected: "terraform:resource=redshift_grant,id=un:john_ot:schema_public",
+		},
+		"comment terminator in id": {
+			id:       "a*/b",
+			expected: "terraform:resource=redshift_grant,id=a* /b",
+		},
+		"nested comment in id": {
+			id:       "a/*b",
+			expected: "terraform:resource=redshift_grant,id=a/ *b",
+		},
+		"overlapping delimiters in id": {
+			id:       "a/*/b*/*c",
+			expected: "terraform:resource=redshift_grant,id=a/ * /b* / *c",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{})
+			d.SetId(tt.id)
+			if result := resourceStatementLabel("redshift_grant", d); result != tt.expected {
+				t.Errorf("Expected label to be %q but got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWithStatementLabel(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		cfg := NewConfig(proxyDriverName, "", "db", 1)
+		cfg.StatementLabels = enabled
+		client := cfg.NewClient()
+
+		var label string
+		fn := withStatementLabel("redshift_user", func(_ context.Context, _ *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			label = meta.(*Client).statementLabel
+			return nil
+		})
+		d := schema.TestResourceDataRaw(t, redshiftUser().Schema, map[string]interface{}{})
+		d.SetId("100")
+		fn(context.Background(), d, client)
+
+		expected := ""
+		if enabled {
+			expected = "terraform:resource=redshift_user,id=100"
+		}
+		if label != expected {
+			t.Errorf("Expected label to be %q with statement labels enabled=%t but got %q", expected, enabled, label)
+		}
+		if client.statementLabel != "" {
+			t.Errorf("Expected the provider client to stay unlabeled but got %q", client.statementLabel)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package redshift
+
+import (
+	"database/sql"
+	"log"
+	"regexp"
+	"time"
+)
+
+// sqlStringLiteral matches single quoted SQL string literals, including quotes escaped by doubling them.
+var sqlStringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// redactStatement replaces the string literals of query, which may contain passwords or other secrets,
+// so the statement can be logged.
+func redactStatement(query string) string {
+	return sqlStringLiteral.ReplaceAllString(query, "'***'")
+}
+
+// logExec logs a statement run with Exec with its duration and the number of affected rows at the debug level,
+// so TF_LOG=DEBUG shows every statement the provider ran.
+func logExec(query string, start time.Time, result sql.Result, err error) {
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("[DEBUG] SQL exec failed after %s: %s: %v", duration, redactStatement(query), err)
+		return
+	}
+
+	rowsAffected, rowsErr := result.RowsAffected()
+	if rowsErr != nil {
+		log.Printf("[DEBUG] SQL exec took %s: %s", duration, redactStatement(query))
+		return
+	}
+	log.Printf("[DEBUG] SQL exec took %s, %d rows affected: %s", duration, rowsAffected, redactStatement(query))
+}
+
+// logQuery logs a query with the time it took to start returning rows at the debug level.
+func logQuery(query string, start time.Time, err error) {
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("[DEBUG] SQL query failed after %s: %s: %v", duration, redactStatement(query), err)
+		return
+	}
+	log.Printf("[DEBUG] SQL query took %s: %s", duration, redactStatement(query))
+}
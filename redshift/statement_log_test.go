@@ -0,0 +1,160 @@
+package redshift
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRedactStatement(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		expected string
+	}{
+		"no literals": {
+			query:    `GRANT SELECT ON TABLE "public"."t" TO "john"`,
+			expected: `GRANT SELECT ON TABLE "public"."t" TO "john"`,
+		},
+		"password": {
+			query:    `CREATE USER "john" PASSWORD 'Secret123'`,
+			expected: `CREATE USER "john" PASSWORD '***'`,
+		},
+		"escaped quote": {
+			query:    `ALTER USER "john" PASSWORD 'it''s secret' VALID UNTIL 'infinity'`,
+			expected: `ALTER USER "john" PASSWORD '***' VALID UNTIL '***'`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := redactStatement(tt.query); got != tt.expected {
+				t.Errorf("redactStatement() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+const loggingDriverName = "redshift-test-logging"
+
+func TestDBConnection_LogsStatements(t *testing.T) {
+	sql.Register(loggingDriverName, flakyDriver{&atomic.Int32{}})
+	db, err := NewConfig(loggingDriverName, t.Name(), "db", 1).NewClient().Connect()
+	if err != nil {
+		t.Fatalf("unexpected error connecting: %v", err)
+	}
+
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	if _, err := db.Exec("ALTER USER \"john\" PASSWORD 'Secret123'"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var user string
+	if err := db.QueryRow("SELECT current_user").Scan(&user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user != "terraform" {
+		t.Errorf("expected the query to return %q, got %q", "terraform", user)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "Secret123") {
+		t.Errorf("expected the password to be redacted, got: %s", output)
+	}
+	for _, expected := range []string{
+		"[DEBUG] SQL exec took ",
+		"0 rows affected: ALTER USER \"john\" PASSWORD '***'",
+		"[DEBUG] SQL query took ",
+		": SELECT current_user",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected the log to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+const txLoggingDriverName = "redshift-test-tx-logging"
+
+var registerTxLoggingDriver sync.Once
+
+// txLoggingDriver accepts every statement, also in transactions, and answers queries with a single value.
+type txLoggingDriver struct{}
+
+// newTxLoggingClient returns a client whose statements are run by txLoggingDriver.
+func newTxLoggingClient(t *testing.T) *Client {
+	registerTxLoggingDriver.Do(func() { sql.Register(txLoggingDriverName, txLoggingDriver{}) })
+	return NewConfig(txLoggingDriverName, t.Name(), "db", 1).NewClient()
+}
+
+func (txLoggingDriver) Open(string) (driver.Conn, error) {
+	return txLoggingConn{}, nil
+}
+
+type txLoggingConn struct{}
+
+func (txLoggingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (txLoggingConn) Close() error { return nil }
+
+func (txLoggingConn) Begin() (driver.Tx, error) {
+	return txLoggingConn{}, nil
+}
+
+func (txLoggingConn) Commit() error { return nil }
+
+func (txLoggingConn) Rollback() error { return nil }
+
+func (txLoggingConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (txLoggingConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &flakyRows{values: []driver.Value{"reporting"}}, nil
+}
+
+// TestResourceFunc_DoesNotLogLiterals runs the statements of the configuration of a resource and makes sure
+// that their literals only reach the log redacted.
+func TestResourceFunc_DoesNotLogLiterals(t *testing.T) {
+	client := newTxLoggingClient(t)
+
+	d := schema.TestResourceDataRaw(t, redshiftSQL().Schema, map[string]interface{}{
+		sqlCreateAttr:  "ALTER USER \"john\" PASSWORD 'Secret123'",
+		sqlReadAttr:    "SELECT 'reporting' WHERE 'Secret456' IS NOT NULL",
+		sqlDestroyAttr: "ALTER USER \"john\" PASSWORD DISABLE",
+	})
+
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	if diags := ResourceFunc(resourceRedshiftSQLCreate)(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	output := buf.String()
+	for _, literal := range []string{"Secret123", "Secret456"} {
+		if strings.Contains(output, literal) {
+			t.Errorf("expected %q to be redacted, got: %s", literal, output)
+		}
+	}
+	for _, expected := range []string{
+		"1 rows affected: ALTER USER \"john\" PASSWORD '***'",
+		"SELECT '***' WHERE '***' IS NOT NULL",
+	} {
+		if strings.Count(output, expected) != 1 {
+			t.Errorf("expected the log to contain %q once, got: %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// transaction is a database transaction which prepends the statement label of the
+// client it was started for to every statement it executes and runs them with its context.
+type transaction struct {
+	*sql.Tx
+
+	ctx context.Context
+
+	statementLabel string
+
+	// ids is the ID cache of the connection the transaction was started on.
+	ids *idCache
+
+	// idChanges holds the IDs looked up after the first statement changing the database and the
+	// invalidated ones, until the transaction commits. It is nil as long as nothing was changed.
+	idChanges *idCacheChanges
+
+	// grants is the privileges cache of the client the transaction was started for.
+	grants *grantPrivilegesCache
+}
+
+// Commit commits the transaction. Any statement of it may have changed privileges, e.g. by granting them,
+// dropping objects or changing their owner, so the privileges cached by the client are invalidated.
+// The IDs looked up and invalidated in the transaction are applied to the ID cache of the connection.
+func (tx *transaction) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+	tx.grants.invalidate()
+	if tx.idChanges != nil {
+		tx.idChanges.applyTo(tx.ids)
+	}
+	return nil
+}
+
+// lookupID returns the ID of the object of the given kind and name, see idCache.lookup. Once the transaction
+// changed the database, the catalog it sees may differ from the committed one, so IDs are then queried afresh
+// and only added to the ID cache of the connection when the transaction commits.
+func (tx *transaction) lookupID(kind, name string, query func() (int, error)) (int, error) {
+	if tx.idChanges == nil {
+		return tx.ids.lookup(kind, name, query)
+	}
+	return tx.idChanges.ids.lookup(kind, name, query)
+}
+
+// invalidateID invalidates the cached ID of an object the transaction dropped or renamed once it commits.
+func (tx *transaction) invalidateID(kind, name string) {
+	if tx.idChanges == nil {
+		tx.idChanges = newIDCacheChanges()
+	}
+	tx.idChanges.invalidate(kind, name)
+}
+
+func (tx *transaction) Exec(query string, args ...interface{}) (sql.Result, error) {
+	if tx.idChanges == nil {
+		tx.idChanges = newIDCacheChanges()
+	}
+	start := time.Now()
+	result, err := tx.Tx.ExecContext(tx.ctx, labelStatement(tx.statementLabel, query), args...)
+	logExec(query, start, result, err)
+	return result, err
+}
+
+func (tx *transaction) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := tx.Tx.QueryContext(tx.ctx, labelStatement(tx.statementLabel, query), args...)
+	logQuery(query, start, err)
+	return rows, err
+}
+
+func (tx *transaction) QueryRow(query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := tx.Tx.QueryRowContext(tx.ctx, labelStatement(tx.statementLabel, query), args...)
+	logQuery(query, start, row.Err())
+	return row
+}
+
+// startTransaction starts a new DB transaction using the provided client.
+func startTransaction(client *Client) (*transaction, error) {
+	db, err := client.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := client.context()
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+
+	return &transaction{Tx: txn, ctx: ctx, statementLabel: client.statementLabel, ids: db.ids, grants: client.grantPrivileges()}, nil
+}
+
+// deferredRollback can be used to rollback a transaction in a defer.
+// It will log an error if it fails
+func deferredRollback(txn *transaction) {
+	err := txn.Rollback()
+	switch {
+	case errors.Is(err, sql.ErrTxDone):
+		// transaction has already been committed or rolled back
+		log.Printf("[DEBUG]: %v", err)
+	case err != nil:
+		log.Printf("[ERR] could not rollback transaction: %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package redshift
 
 import (
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -178,5 +180,21 @@ var dbGroupValidate = validation.All(
 	validation.StringNotInSlice(reservedWords, true),
 )
 
+// Role names are limited to 127 bytes, see https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_ROLE.html
+var roleNameValidate = validation.All(
+	validation.StringLenBetween(1, 127),
+	validation.StringDoesNotMatch(regexp.MustCompile("^__"), "Role names beginning with two underscores are reserved for Amazon Redshift internal use"),
+	validation.StringDoesNotMatch(regexp.MustCompile("(?i)^sys:"), "Role names beginning with sys: are reserved for Amazon Redshift system roles"),
+)
+
+// Standard identifiers begin with a letter, an underscore or a UTF-8 multibyte character and only contain
+// those, digits and dollar signs, see https://docs.aws.amazon.com/redshift/latest/dg/r_names.html
+var standardIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_\x{80}-\x{10FFFF}][a-zA-Z0-9_$\x{80}-\x{10FFFF}]*$`)
+
+// identifierNeedsQuoting returns whether name is no standard identifier and can only be used as a delimited identifier.
+func identifierNeedsQuoting(name string) bool {
+	return !standardIdentifierRegexp.MatchString(name) || slices.Contains(reservedWords, strings.ToLower(name))
+}
+
 var awsAccountIdRegexp = regexp.MustCompile(`^\d{12}$`)
 var uuidRegex = regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$")